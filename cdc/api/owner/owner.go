@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/logutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.etcd.io/etcd/client/v3/concurrency"
 	"go.uber.org/zap"
@@ -192,7 +193,8 @@ func (h *ownerAPI) handleMoveTable(w http.ResponseWriter, req *http.Request) {
 	}
 
 	err = api.HandleOwnerScheduleTable(
-		req.Context(), h.capture, changefeedID, to, tableID)
+		req.Context(), h.capture, changefeedID, to,
+		spanz.TableIDToComparableSpan(tableID))
 	handleOwnerResp(w, err)
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -157,10 +158,10 @@ func HandleOwnerBalance(
 	}
 }
 
-// HandleOwnerScheduleTable schedule tables
+// HandleOwnerScheduleTable schedule a table span to the target capture
 func HandleOwnerScheduleTable(
 	ctx context.Context, capture capture.Capture,
-	changefeedID model.ChangeFeedID, captureID string, tableID int64,
+	changefeedID model.ChangeFeedID, captureID string, span tablepb.Span,
 ) error {
 	// Use buffered channel to prevent blocking owner.
 	done := make(chan error, 1)
@@ -168,7 +169,7 @@ func HandleOwnerScheduleTable(
 	if err != nil {
 		return errors.Trace(err)
 	}
-	o.ScheduleTable(changefeedID, captureID, tableID, done)
+	o.ScheduleTable(changefeedID, captureID, span, done)
 	select {
 	case <-ctx.Done():
 		return errors.Trace(ctx.Err())
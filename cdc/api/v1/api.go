@@ -30,6 +30,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/tikv/client-go/v2/oracle"
@@ -601,7 +602,8 @@ func (h *OpenAPI) MoveTable(c *gin.Context) {
 	}
 
 	err = api.HandleOwnerScheduleTable(
-		ctx, h.capture, changefeedID, data.CaptureID, data.TableID)
+		ctx, h.capture, changefeedID, data.CaptureID,
+		spanz.TableIDToComparableSpan(data.TableID))
 	if err != nil {
 		_ = c.Error(err)
 		return
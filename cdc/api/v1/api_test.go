@@ -30,9 +30,11 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
 	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -106,6 +108,20 @@ func (p *mockStatusProvider) GetChangeFeedSyncedStatus(ctx context.Context,
 	return args.Get(0).(*model.ChangeFeedSyncedStatusForAPI), args.Error(1)
 }
 
+func (p *mockStatusProvider) GetChangeFeedTableStatuses(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.TableSpanStatus, error) {
+	args := p.Called(ctx)
+	return args.Get(0).([]*model.TableSpanStatus), args.Error(1)
+}
+
+func (p *mockStatusProvider) GetChangeFeedLoadBalancePlan(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) (*model.LoadBalancePlan, error) {
+	args := p.Called(ctx)
+	return args.Get(0).(*model.LoadBalancePlan), args.Error(1)
+}
+
 func (p *mockStatusProvider) IsHealthy(ctx context.Context) (bool, error) {
 	args := p.Called(ctx)
 	return args.Get(0).(bool), args.Error(1)
@@ -729,11 +745,11 @@ func TestMoveTable(t *testing.T) {
 		ScheduleTable(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Do(func(
 			cfID model.ChangeFeedID, toCapture model.CaptureID,
-			tableID model.TableID, done chan<- error,
+			span tablepb.Span, done chan<- error,
 		) {
 			require.EqualValues(t, cfID, changeFeedID)
 			require.EqualValues(t, toCapture, data.CaptureID)
-			require.EqualValues(t, tableID, data.TableID)
+			require.EqualValues(t, span, spanz.TableIDToComparableSpan(data.TableID))
 			close(done)
 		})
 	api := testCase{
@@ -757,11 +773,11 @@ func TestMoveTable(t *testing.T) {
 		ScheduleTable(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Do(func(
 			cfID model.ChangeFeedID, toCapture model.CaptureID,
-			tableID model.TableID, done chan<- error,
+			span tablepb.Span, done chan<- error,
 		) {
 			require.EqualValues(t, cfID, changeFeedID)
 			require.EqualValues(t, toCapture, data.CaptureID)
-			require.EqualValues(t, tableID, data.TableID)
+			require.EqualValues(t, span, spanz.TableIDToComparableSpan(data.TableID))
 			done <- cerror.ErrChangeFeedNotExists.FastGenByArgs(cfID)
 			close(done)
 		})
@@ -62,6 +62,12 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	changefeedGroup.POST("/:changefeed_id/pause", ownerMiddleware, authenticateMiddleware, api.pauseChangefeed)
 	changefeedGroup.GET("/:changefeed_id/status", ownerMiddleware, api.status)
 	changefeedGroup.GET("/:changefeed_id/synced", ownerMiddleware, api.synced)
+	changefeedGroup.GET("/:changefeed_id/tables", ownerMiddleware, api.tables)
+	changefeedGroup.GET("/:changefeed_id/load_balance_plan", ownerMiddleware, api.loadBalancePlan)
+	changefeedGroup.POST("/:changefeed_id/move_table", ownerMiddleware, authenticateMiddleware, api.moveTable)
+	changefeedGroup.POST("/:changefeed_id/rebalance", ownerMiddleware, authenticateMiddleware, api.rebalance)
+	changefeedGroup.POST("/:changefeed_id/export", ownerMiddleware, authenticateMiddleware, api.exportChangefeed)
+	changefeedGroup.POST("/import", ownerMiddleware, authenticateMiddleware, api.importChangefeed)
 
 	// capture apis
 	captureGroup := v2.Group("/captures")
@@ -17,6 +17,7 @@ import (
 	"context"
 	"crypto/tls"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/r3labs/diff"
 	"github.com/tikv/client-go/v2/oracle"
@@ -341,6 +343,11 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 		return nil, nil, cerror.ErrChangefeedUpdateRefused.
 			GenWithStackByArgs(errors.Cause(err).Error())
 	}
+	newInfo.SnapshotBackfill, err = getSnapshotBackfillInfo(
+		oldInfo, newInfo, tableInfos, kvStorage, checkpointTs)
+	if err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+	}
 
 	if configUpdated || sinkURIUpdated {
 		log.Info("config or sink uri updated, check the compatibility",
@@ -429,22 +436,12 @@ func (APIV2HelpersImpl) verifyResumeChangefeedConfig(
 			"invalid checkpoint-ts %v, larger than current tso %v", overrideCheckpointTs, currentTSO)
 	}
 
-	// 1h is enough for resuming a changefeed.
-	gcTTL := int64(60 * 60)
-	err = gc.EnsureChangefeedStartTsSafety(
+	return gc.EnsureChangefeedResumeSafety(
 		ctx,
 		pdClient,
 		gcServiceID,
 		changefeedID,
-		gcTTL, overrideCheckpointTs)
-	if err != nil {
-		if !cerror.ErrStartTsBeforeGC.Equal(err) {
-			return cerror.ErrPDEtcdAPIError.Wrap(err)
-		}
-		return err
-	}
-
-	return nil
+		overrideCheckpointTs)
 }
 
 // getPDClient returns a PDClient given the PD cluster addresses and a credential
@@ -584,3 +581,95 @@ func (h APIV2HelpersImpl) getVerifiedTables(
 
 	return ineligibleTables, eligibleTables, nil
 }
+
+// getSnapshotBackfillInfo returns the physical tables which are newly included
+// by the updated filter and whose existing rows need to be backfilled at
+// checkpointTs. Tables of an unfinished backfill are carried over.
+func getSnapshotBackfillInfo(
+	oldInfo, newInfo *model.ChangeFeedInfo,
+	newTableInfos []*model.TableInfo,
+	kvStorage tidbkv.Storage,
+	checkpointTs uint64,
+) (*model.SnapshotBackfillInfo, error) {
+	if !util.GetOrZero(newInfo.Config.EnableSnapshotBackfill) {
+		return nil, nil
+	}
+
+	oldFilter, err := filter.NewFilter(oldInfo.Config, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	oldTableInfos, _, _, err := entry.VerifyTables(oldFilter, kvStorage, checkpointTs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	oldTables := make(map[model.TableID]struct{})
+	for _, tableInfo := range oldTableInfos {
+		if !tableInfo.IsEligible(oldInfo.Config.ForceReplicate) {
+			continue
+		}
+		for _, id := range getPhysicalTableIDs(tableInfo) {
+			oldTables[id] = struct{}{}
+		}
+	}
+	newTables := make(map[model.TableID]struct{})
+	for _, tableInfo := range newTableInfos {
+		if !tableInfo.IsEligible(newInfo.Config.ForceReplicate) {
+			continue
+		}
+		for _, id := range getPhysicalTableIDs(tableInfo) {
+			newTables[id] = struct{}{}
+		}
+	}
+
+	backfill := &model.SnapshotBackfillInfo{Ts: checkpointTs}
+	tables := make(map[model.TableID]struct{})
+	// The previous backfill may not be finished yet, keep its unfinished
+	// tables, ts and scanned ranges, so that their scans are resumed.
+	if prev := oldInfo.SnapshotBackfill; prev != nil {
+		for _, id := range prev.UnfinishedTableIDs() {
+			if _, ok := newTables[id]; ok {
+				tables[id] = struct{}{}
+				backfill.Ts = prev.Ts
+			}
+		}
+		for _, r := range prev.ScannedRanges {
+			if _, ok := tables[r.TableID]; ok {
+				backfill.ScannedRanges = append(backfill.ScannedRanges, r)
+			}
+		}
+	}
+	for id := range newTables {
+		if _, ok := oldTables[id]; !ok {
+			tables[id] = struct{}{}
+		}
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	for id := range tables {
+		backfill.TableIDs = append(backfill.TableIDs, id)
+	}
+	sort.Slice(backfill.TableIDs, func(i, j int) bool {
+		return backfill.TableIDs[i] < backfill.TableIDs[j]
+	})
+	log.Info("tables newly included by the changefeed will be backfilled",
+		zap.String("namespace", newInfo.Namespace),
+		zap.String("changefeed", newInfo.ID),
+		zap.Uint64("backfillTs", backfill.Ts),
+		zap.Int64s("tableIDs", backfill.TableIDs))
+	return backfill, nil
+}
+
+// getPhysicalTableIDs returns the physical table ids of a table, which are
+// the partition ids for a partitioned table.
+func getPhysicalTableIDs(tableInfo *model.TableInfo) []model.TableID {
+	if pi := tableInfo.GetPartitionInfo(); pi != nil {
+		ids := make([]model.TableID, 0, len(pi.Definitions))
+		for _, partition := range pi.Definitions {
+			ids = append(ids, partition.ID)
+		}
+		return ids
+	}
+	return []model.TableID{tableInfo.ID}
+}
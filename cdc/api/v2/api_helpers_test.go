@@ -24,8 +24,10 @@ import (
 	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 func TestVerifyCreateChangefeedConfig(t *testing.T) {
@@ -162,3 +164,78 @@ func TestVerifyUpdateChangefeedConfig(t *testing.T) {
 	newCfInfo, newUpInfo, err = h.verifyUpdateChangefeedConfig(ctx, cfg, oldInfo, oldUpInfo, storage, 0)
 	require.NotNil(t, err)
 }
+
+func TestGetSnapshotBackfillInfo(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test;")
+	t1 := helper.DDL2Job("create table test.t1(id int primary key)")
+	t2 := helper.DDL2Job("create table test.t2(id int primary key)")
+	t3 := helper.DDL2Job("create table test.t3(id int primary key) " +
+		"partition by range(id) (partition p0 values less than (10), " +
+		"partition p1 values less than (20))")
+	helper.DDL2Job("create table test.t4(id int)")
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	checkpointTs := ver.Ver
+
+	getInfo := func(oldRules, newRules []string, enable bool,
+		prev *model.SnapshotBackfillInfo, ts uint64,
+	) *model.SnapshotBackfillInfo {
+		oldInfo := &model.ChangeFeedInfo{Config: config.GetDefaultReplicaConfig()}
+		oldInfo.Config.Filter.Rules = oldRules
+		oldInfo.SnapshotBackfill = prev
+		newInfo := &model.ChangeFeedInfo{Config: config.GetDefaultReplicaConfig()}
+		newInfo.Config.Filter.Rules = newRules
+		newInfo.Config.EnableSnapshotBackfill = util.AddressOf(enable)
+		f, err := filter.NewFilter(newInfo.Config, "")
+		require.Nil(t, err)
+		tableInfos, _, _, err := entry.VerifyTables(f, helper.Storage(), ts)
+		require.Nil(t, err)
+		info, err := getSnapshotBackfillInfo(oldInfo, newInfo, tableInfos, helper.Storage(), ts)
+		require.Nil(t, err)
+		return info
+	}
+
+	// snapshot backfill is disabled
+	info := getInfo([]string{"test.t1"}, []string{"test.*"}, false, nil, checkpointTs)
+	require.Nil(t, info)
+
+	// no table is newly included
+	info = getInfo([]string{"test.*"}, []string{"test.t1"}, true, nil, checkpointTs)
+	require.Nil(t, info)
+
+	// t2 and all partitions of t3 are newly included, t4 is ineligible
+	info = getInfo([]string{"test.t1"}, []string{"test.*"}, true, nil, checkpointTs)
+	require.NotNil(t, info)
+	require.Equal(t, checkpointTs, info.Ts)
+	partitions := t3.BinlogInfo.TableInfo.GetPartitionInfo().Definitions
+	require.Equal(t, []model.TableID{
+		t2.TableID, partitions[0].ID, partitions[1].ID,
+	}, info.TableIDs)
+
+	// the unfinished backfill is carried over with its scanned ranges
+	scanned := model.SnapshotRange{
+		TableID:  t1.TableID,
+		StartKey: []byte("a"),
+		EndKey:   []byte("b"),
+	}
+	prev := &model.SnapshotBackfillInfo{
+		Ts:            checkpointTs - 10,
+		TableIDs:      []model.TableID{t1.TableID},
+		ScannedRanges: []model.SnapshotRange{scanned},
+	}
+	info = getInfo([]string{"test.t1"}, []string{"test.t1", "test.t2"}, true, prev, checkpointTs)
+	require.NotNil(t, info)
+	require.Equal(t, checkpointTs-10, info.Ts)
+	require.Equal(t, []model.TableID{t1.TableID, t2.TableID}, info.TableIDs)
+	require.Equal(t, []model.SnapshotRange{scanned}, info.ScannedRanges)
+
+	// the finished backfill is dropped
+	prev.ScannedRanges = nil
+	prev.FinishedTableIDs = []model.TableID{t1.TableID}
+	info = getInfo([]string{"test.t1"}, []string{"test.t1", "test.t2"}, true, prev, checkpointTs)
+	require.NotNil(t, info)
+	require.Equal(t, checkpointTs, info.Ts)
+	require.Equal(t, []model.TableID{t2.TableID}, info.TableIDs)
+}
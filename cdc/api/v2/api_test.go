@@ -69,6 +69,9 @@ type mockStatusProvider struct {
 	changefeedInfos        map[model.ChangeFeedID]*model.ChangeFeedInfo
 	changefeedStatuses     map[model.ChangeFeedID]*model.ChangeFeedStatusForAPI
 	changeFeedSyncedStatus *model.ChangeFeedSyncedStatusForAPI
+	tableStatuses          []*model.TableSpanStatus
+	loadBalancePlan        *model.LoadBalancePlan
+	captures               []*model.CaptureInfo
 	err                    error
 }
 
@@ -128,3 +131,27 @@ func (m *mockStatusProvider) GetChangeFeedSyncedStatus(_ context.Context, change
 ) {
 	return m.changeFeedSyncedStatus, m.err
 }
+
+// GetChangeFeedTableStatuses returns a list of mock table span statuses.
+func (m *mockStatusProvider) GetChangeFeedTableStatuses(_ context.Context, changefeedID model.ChangeFeedID) (
+	[]*model.TableSpanStatus,
+	error,
+) {
+	return m.tableStatuses, m.err
+}
+
+// GetCaptures returns a list of mock capture infos.
+func (m *mockStatusProvider) GetCaptures(_ context.Context) (
+	[]*model.CaptureInfo,
+	error,
+) {
+	return m.captures, m.err
+}
+
+// GetChangeFeedLoadBalancePlan returns a mock load balance plan.
+func (m *mockStatusProvider) GetChangeFeedLoadBalancePlan(_ context.Context, changefeedID model.ChangeFeedID) (
+	*model.LoadBalancePlan,
+	error,
+) {
+	return m.loadBalancePlan, m.err
+}
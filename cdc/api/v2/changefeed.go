@@ -15,6 +15,7 @@ package v2
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -29,9 +30,11 @@ import (
 	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -44,6 +47,8 @@ import (
 const (
 	// timeout for pd client
 	timeout = 30 * time.Second
+	// the credential masked by util.MaskSinkURI and util.MaskSinkURICredentials
+	maskedCredential = "xxxxx"
 )
 
 // createChangefeed handles create changefeed request,
@@ -66,11 +71,25 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
 		return
 	}
+	info, err := h.createChangefeedWithConfig(ctx, cfg)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, toAPIModel(info,
+		info.StartTs, info.StartTs,
+		nil, true))
+}
+
+// createChangefeedWithConfig verifies the config and creates the changefeed
+// through the owner, it returns the changefeedInfo that it just created.
+func (h *OpenAPIV2) createChangefeedWithConfig(
+	ctx context.Context, cfg *ChangefeedConfig,
+) (*model.ChangeFeedInfo, error) {
 	if len(cfg.PDAddrs) == 0 {
 		up, err := getCaptureDefaultUpstream(h.capture)
 		if err != nil {
-			_ = c.Error(err)
-			return
+			return nil, err
 		}
 		cfg.PDConfig = getUpstreamPDConfig(up)
 	}
@@ -80,22 +99,19 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	defer cancel()
 	pdClient, err := h.helpers.getPDClient(timeoutCtx, cfg.PDAddrs, credential)
 	if err != nil {
-		_ = c.Error(cerror.WrapError(cerror.ErrAPIGetPDClientFailed, err))
-		return
+		return nil, cerror.WrapError(cerror.ErrAPIGetPDClientFailed, err)
 	}
 	defer pdClient.Close()
 
 	// verify tables todo: del kvstore
 	kvStorage, err := h.helpers.createTiStore(ctx, cfg.PDAddrs, credential)
 	if err != nil {
-		_ = c.Error(cerror.WrapError(cerror.ErrNewStore, err))
-		return
+		return nil, cerror.WrapError(cerror.ErrNewStore, err)
 	}
 	provider := h.capture.StatusProvider()
 	owner, err := h.capture.GetOwner()
 	if err != nil {
-		_ = c.Error(err)
-		return
+		return nil, err
 	}
 	// We should not close kvStorage since all kvStorage in cdc is the same one.
 	// defer kvStorage.Close()
@@ -108,8 +124,7 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 		h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceCreating),
 		kvStorage)
 	if err != nil {
-		_ = c.Error(err)
-		return
+		return nil, err
 	}
 	needRemoveGCSafePoint := false
 	defer func() {
@@ -123,8 +138,10 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 			model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
 		)
 		if err != nil {
-			_ = c.Error(err)
-			return
+			log.Warn("failed to remove the gc safepoint of the changefeed",
+				zap.String("namespace", cfg.Namespace),
+				zap.String("changefeed", cfg.ID),
+				zap.Error(err))
 		}
 	}()
 	upstreamInfo := &model.UpstreamInfo{
@@ -139,23 +156,18 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	// cannot create changefeed if there are running lightning/restore tasks
 	tlsCfg, err := credential.ToTLSConfig()
 	if err != nil {
-		_ = c.Error(err)
-		return
+		return nil, err
 	}
 
 	cli, err := h.helpers.getEtcdClient(ctx, cfg.PDAddrs, tlsCfg)
 	if err != nil {
-		_ = c.Error(err)
-		return
+		return nil, err
 	}
 	err = hasRunningImport(ctx, cli)
 	if err != nil {
 		log.Error("failed to create changefeed", zap.Error(err))
-		_ = c.Error(
-			cerror.ErrUpstreamHasRunningImport.Wrap(err).
-				FastGenByArgs(info.UpstreamID),
-		)
-		return
+		return nil, cerror.ErrUpstreamHasRunningImport.Wrap(err).
+			FastGenByArgs(info.UpstreamID)
 	}
 
 	err = owner.CreateChangefeed(ctx,
@@ -163,16 +175,13 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 		info)
 	if err != nil {
 		needRemoveGCSafePoint = true
-		_ = c.Error(err)
-		return
+		return nil, err
 	}
 
 	log.Info("Create changefeed successfully!",
 		zap.String("id", info.ID),
 		zap.String("changefeed", info.String()))
-	c.JSON(http.StatusOK, toAPIModel(info,
-		info.StartTs, info.StartTs,
-		nil, true))
+	return info, nil
 }
 
 // hasRunningImport checks if there is running import tasks on the
@@ -563,6 +572,14 @@ func (h *OpenAPIV2) getChangeFeed(c *gin.Context) {
 	}
 	detail := toAPIModel(cfInfo, status.ResolvedTs,
 		status.CheckpointTs, taskStatus, true)
+	if cfInfo.Config != nil && cfInfo.Config.PauseSchedule != nil {
+		next, action, err := cfInfo.Config.PauseSchedule.NextTransition(time.Now())
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		detail.NextScheduledTransition = &ScheduledTransition{Time: next, Action: action}
+	}
 	c.JSON(http.StatusOK, detail)
 }
 
@@ -881,15 +898,451 @@ func (h *OpenAPIV2) status(c *gin.Context) {
 		}
 	}
 
+	var snapshotBackfill *SnapshotBackfillStatus
+	if progress := status.SnapshotBackfill; progress != nil {
+		snapshotBackfill = &SnapshotBackfillStatus{
+			Ts:             progress.Ts,
+			Tables:         progress.Tables,
+			FinishedTables: progress.FinishedTables,
+			Finished:       len(progress.FinishedTables) == len(progress.Tables),
+		}
+	}
+
 	c.JSON(http.StatusOK, &ChangefeedStatus{
-		State:        string(info.State),
-		CheckpointTs: status.CheckpointTs,
-		ResolvedTs:   status.ResolvedTs,
-		LastError:    lastError,
-		LastWarning:  lastWarning,
+		State:            string(info.State),
+		CheckpointTs:     status.CheckpointTs,
+		ResolvedTs:       status.ResolvedTs,
+		LastError:        lastError,
+		LastWarning:      lastWarning,
+		RedoLogSize:      status.RedoLogSize,
+		SnapshotBackfill: snapshotBackfill,
+	})
+}
+
+// tables get the replication statuses of all table spans of a changefeed
+// @Summary Get table statuses
+// @Description get the replication statuses of all table spans of a changefeed
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {array} TableSpanStatus
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/tables [get]
+func (h *OpenAPIV2) tables(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+
+	statuses, err := h.capture.StatusProvider().GetChangeFeedTableStatuses(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	items := make([]TableSpanStatus, 0, len(statuses))
+	for _, status := range statuses {
+		sorter := status.Stats.StageCheckpoints["sorter-ingress"]
+		items = append(items, TableSpanStatus{
+			TableID:             status.Span.TableID,
+			StartKey:            spanz.HexKey(status.Span.StartKey),
+			EndKey:              spanz.HexKey(status.Span.EndKey),
+			CaptureID:           status.CaptureID,
+			State:               status.State,
+			CheckpointTs:        status.Checkpoint.CheckpointTs,
+			ResolvedTs:          status.Checkpoint.ResolvedTs,
+			SorterMaxCommitTs:   sorter.CheckpointTs,
+			SorterMaxResolvedTs: sorter.ResolvedTs,
+			SinkRowsPerSecond:   status.Stats.SinkRowsPerSecond,
+			SinkBytesPerSecond:  status.Stats.SinkBytesPerSecond,
+		})
+	}
+	c.JSON(http.StatusOK, &ListResponse[TableSpanStatus]{
+		Total: len(items),
+		Items: items,
+	})
+}
+
+// moveTable moves a table span of a changefeed to the target capture
+// @Summary Move a table
+// @Description move a table span of a changefeed to the target capture
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Param moveTableConfig body MoveTableConfig true "move table config"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/move_table [post]
+func (h *OpenAPIV2) moveTable(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfg := &MoveTableConfig{}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	span, err := getMoveTableSpan(cfg, info)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	captures, err := h.capture.StatusProvider().GetCaptures(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	captureExists := false
+	for _, captureInfo := range captures {
+		if captureInfo.ID == cfg.TargetCaptureID {
+			captureExists = true
+			break
+		}
+	}
+	if !captureExists {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"target capture %s does not exist", cfg.TargetCaptureID))
+		return
+	}
+
+	statuses, err := h.capture.StatusProvider().GetChangeFeedTableStatuses(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	spanExists := false
+	for _, status := range statuses {
+		if status.Span.Eq(&span) {
+			spanExists = true
+			break
+		}
+	}
+	if !spanExists {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"span %s is not replicated by the changefeed, "+
+				"start_key and end_key are required if the table is split into multiple spans",
+			span.String()))
+		return
+	}
+
+	err = api.HandleOwnerScheduleTable(ctx, h.capture, changefeedID, cfg.TargetCaptureID, span)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// getMoveTableSpan returns the span to move. The whole table is moved if
+// no key is given, otherwise the table must be allowed to split into spans.
+func getMoveTableSpan(cfg *MoveTableConfig, info *model.ChangeFeedInfo) (tablepb.Span, error) {
+	if cfg.StartKey == "" && cfg.EndKey == "" {
+		return spanz.TableIDToComparableSpan(cfg.TableID), nil
+	}
+	if info.Config == nil || info.Config.Scheduler == nil ||
+		!info.Config.Scheduler.EnableTableAcrossNodes {
+		return tablepb.Span{}, cerror.ErrAPIInvalidParam.GenWithStack(
+			"moving a span requires enable-table-across-nodes to be enabled")
+	}
+	startKey, err := hex.DecodeString(cfg.StartKey)
+	if err != nil {
+		return tablepb.Span{}, cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid start_key: %s", cfg.StartKey)
+	}
+	endKey, err := hex.DecodeString(cfg.EndKey)
+	if err != nil {
+		return tablepb.Span{}, cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid end_key: %s", cfg.EndKey)
+	}
+	return tablepb.Span{TableID: cfg.TableID, StartKey: startKey, EndKey: endKey}, nil
+}
+
+// rebalance rebalances the tables of a changefeed across all captures
+// @Summary Rebalance tables
+// @Description rebalance the tables of a changefeed across all captures
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/rebalance [post]
+func (h *OpenAPIV2) rebalance(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	// check if the changefeed exists
+	_, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := api.HandleOwnerBalance(ctx, h.capture, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// exportChangefeed exports a stopped changefeed
+// @Summary Export a changefeed
+// @Description Export a stopped changefeed with a GC service safepoint set at its checkpoint, so that it can be imported into another TiCDC cluster sharing the same upstream
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} ExportedChangefeed
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/export [post]
+func (h *OpenAPIV2) exportChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfInfo, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// The checkpoint of a running changefeed keeps advancing, export it
+	// after it is stopped to avoid duplicated events in the downstream.
+	if cfInfo.State != model.StateStopped && cfInfo.State != model.StateFailed {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"can only export a stopped or failed changefeed, current state: %s", cfInfo.State))
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	sinkURI, err := util.MaskSinkURICredentials(cfInfo.SinkURI)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrSinkURIInvalid, err))
+		return
+	}
+
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	up, ok := upManager.Get(cfInfo.UpstreamID)
+	if !ok {
+		_ = c.Error(cerror.ErrUpstreamNotFound.GenWithStackByArgs(cfInfo.UpstreamID))
+		return
+	}
+	gcServiceIDPrefix := h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceExporting)
+	if err := gc.EnsureChangefeedStartTsSafety(
+		ctx,
+		up.PDClient,
+		gcServiceIDPrefix,
+		changefeedID,
+		config.GetGlobalServerConfig().GcTTL,
+		status.CheckpointTs,
+	); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &ExportedChangefeed{
+		UpstreamID:     cfInfo.UpstreamID,
+		Namespace:      cfInfo.Namespace,
+		ID:             cfInfo.ID,
+		SinkURI:        sinkURI,
+		TargetTs:       cfInfo.TargetTs,
+		ReplicaConfig:  ToAPIReplicaConfig(cfInfo.Config),
+		CheckpointTs:   status.CheckpointTs,
+		GCServiceID:    gc.EnsureChangefeedServiceID(gcServiceIDPrefix, changefeedID),
+		CreatorVersion: cfInfo.CreatorVersion,
 	})
 }
 
+// importChangefeed imports a changefeed exported from another TiCDC cluster
+// @Summary Import a changefeed
+// @Description Create a changefeed exported from another TiCDC cluster sharing the same upstream, it starts from the exported checkpoint
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed body ExportedChangefeed true "exported changefeed"
+// @Success 200 {object} ChangeFeedInfo
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/import [post]
+func (h *OpenAPIV2) importChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	exported := &ExportedChangefeed{}
+	if err := c.BindJSON(exported); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if exported.CheckpointTs == 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"checkpoint_ts of the exported changefeed is not set"))
+		return
+	}
+	if isMaskedSinkURI(exported.SinkURI) {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"the credentials in sink_uri of the exported changefeed are masked, " +
+				"please fill them in before importing"))
+		return
+	}
+	up, err := getCaptureDefaultUpstream(h.capture)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if up.ID != exported.UpstreamID {
+		_ = c.Error(cerror.ErrUpstreamMissMatch.GenWithStackByArgs(exported.UpstreamID, up.ID))
+		return
+	}
+
+	cfg := &ChangefeedConfig{
+		Namespace:     exported.Namespace,
+		ID:            exported.ID,
+		SinkURI:       exported.SinkURI,
+		StartTs:       exported.CheckpointTs,
+		TargetTs:      exported.TargetTs,
+		ReplicaConfig: exported.ReplicaConfig,
+		PDConfig:      getUpstreamPDConfig(up),
+	}
+	if cfg.ReplicaConfig == nil {
+		cfg.ReplicaConfig = GetDefaultReplicaConfig()
+	}
+	info, err := h.createChangefeedWithConfig(ctx, cfg)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// The imported changefeed keeps its checkpoint safe by itself from now on.
+	if exported.GCServiceID != "" {
+		if err := gc.RemoveServiceGCSafepoint(ctx, up.PDClient, exported.GCServiceID); err != nil {
+			log.Warn("failed to remove the gc safepoint of the exported changefeed",
+				zap.String("namespace", info.Namespace),
+				zap.String("changefeed", info.ID),
+				zap.String("gcServiceID", exported.GCServiceID),
+				zap.Error(err))
+		}
+	}
+	c.JSON(http.StatusOK, toAPIModel(info,
+		info.StartTs, info.StartTs,
+		nil, true))
+}
+
+// isMaskedSinkURI returns true if the credentials in the sink uri are masked
+// by util.MaskSinkURICredentials.
+func isMaskedSinkURI(sinkURI string) bool {
+	uri, err := url.Parse(sinkURI)
+	if err != nil {
+		// leave the error to the sink uri verification
+		return false
+	}
+	if password, ok := uri.User.Password(); ok && password == maskedCredential {
+		return true
+	}
+	for key, values := range uri.Query() {
+		if !util.IsSensitiveQueryParameter(key) {
+			continue
+		}
+		for _, value := range values {
+			if value == maskedCredential {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadBalancePlan shows the span moves planned by loads of a changefeed
+// @Summary Get load balance plan
+// @Description get a dry run of the span moves planned by loads of a changefeed
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "default"
+// @Success 200 {object} LoadBalancePlan
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/load_balance_plan [get]
+func (h *OpenAPIV2) loadBalancePlan(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	namespace := getNamespaceValueWithDefault(c)
+	changefeedID := model.ChangeFeedID{Namespace: namespace, ID: c.Param(api.APIOpVarChangefeedID)}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+
+	plan, err := h.capture.StatusProvider().GetChangeFeedLoadBalancePlan(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp := &LoadBalancePlan{
+		Enabled:   plan.Enabled,
+		Threshold: plan.Threshold,
+		Captures:  make([]CaptureLoad, 0, len(plan.Captures)),
+		Moves:     make([]LoadBalanceMove, 0, len(plan.Moves)),
+	}
+	for _, load := range plan.Captures {
+		resp.Captures = append(resp.Captures, CaptureLoad{
+			CaptureID:   load.CaptureID,
+			Load:        load.Load,
+			PlannedLoad: load.PlannedLoad,
+		})
+	}
+	for _, move := range plan.Moves {
+		resp.Moves = append(resp.Moves, LoadBalanceMove{
+			TableID:         move.Span.TableID,
+			StartKey:        spanz.HexKey(move.Span.StartKey),
+			EndKey:          spanz.HexKey(move.Span.EndKey),
+			Load:            move.Load,
+			SourceCaptureID: move.From,
+			TargetCaptureID: move.To,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 // synced get the synced status of a changefeed
 // @Summary Get synced status
 // @Description get the synced status of a changefeed
@@ -111,6 +111,23 @@ type SyncedStatusConfig struct {
 	CheckpointInterval int64 `json:"checkpoint_interval"`
 }
 
+// PauseScheduleConfig represents a recurring window during which a changefeed
+// is paused by the owner
+type PauseScheduleConfig struct {
+	// standard cron expression of the pause time
+	PauseAt string `json:"pause_at"`
+	// standard cron expression of the resume time
+	ResumeAt string `json:"resume_at"`
+	// the time zone the cron expressions are evaluated in, the local time zone of the owner is used if it is empty
+	TimeZone string `json:"time_zone"`
+}
+
+// ScheduledTransition is the next transition of a changefeed's pause schedule
+type ScheduledTransition struct {
+	Time   time.Time                  `json:"time"`
+	Action config.PauseScheduleAction `json:"action" swaggertype:"string"`
+}
+
 // MarshalJSON marshal changefeed common info to json
 // we need to set feed state to normal if it is uninitialized and pending to warning
 // to hide the detail of uninitialized and pending state from user
@@ -184,18 +201,22 @@ func (d *JSONDuration) UnmarshalJSON(b []byte) error {
 
 // ReplicaConfig is a duplicate of  config.ReplicaConfig
 type ReplicaConfig struct {
-	MemoryQuota           uint64 `json:"memory_quota"`
-	CaseSensitive         bool   `json:"case_sensitive"`
-	ForceReplicate        bool   `json:"force_replicate"`
-	IgnoreIneligibleTable bool   `json:"ignore_ineligible_table"`
-	CheckGCSafePoint      bool   `json:"check_gc_safe_point"`
-	EnableSyncPoint       *bool  `json:"enable_sync_point,omitempty"`
-	EnableTableMonitor    *bool  `json:"enable_table_monitor,omitempty"`
-	BDRMode               *bool  `json:"bdr_mode,omitempty"`
+	MemoryQuota            uint64 `json:"memory_quota"`
+	CaseSensitive          bool   `json:"case_sensitive"`
+	ForceReplicate         bool   `json:"force_replicate"`
+	IgnoreIneligibleTable  bool   `json:"ignore_ineligible_table"`
+	CheckGCSafePoint       bool   `json:"check_gc_safe_point"`
+	EnableSyncPoint        *bool  `json:"enable_sync_point,omitempty"`
+	EnableTableMonitor     *bool  `json:"enable_table_monitor,omitempty"`
+	BDRMode                *bool  `json:"bdr_mode,omitempty"`
+	EnableSnapshotBackfill *bool  `json:"enable_snapshot_backfill,omitempty"`
 
 	SyncPointInterval  *JSONDuration `json:"sync_point_interval,omitempty" swaggertype:"string"`
 	SyncPointRetention *JSONDuration `json:"sync_point_retention,omitempty" swaggertype:"string"`
 
+	SortDiskQuota *uint64 `json:"sort_disk_quota,omitempty"`
+	Priority      string  `json:"priority,omitempty"`
+
 	Filter                       *FilterConfig              `json:"filter"`
 	Mounter                      *MounterConfig             `json:"mounter"`
 	Sink                         *SinkConfig                `json:"sink"`
@@ -204,6 +225,7 @@ type ReplicaConfig struct {
 	Integrity                    *IntegrityConfig           `json:"integrity"`
 	ChangefeedErrorStuckDuration *JSONDuration              `json:"changefeed_error_stuck_duration,omitempty"`
 	SyncedStatus                 *SyncedStatusConfig        `json:"synced_status,omitempty"`
+	PauseSchedule                *PauseScheduleConfig       `json:"pause_schedule,omitempty"`
 
 	// Deprecated: we don't use this field since v8.0.0.
 	SQLMode string `json:"sql_mode,omitempty"`
@@ -232,6 +254,11 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 		res.SyncPointRetention = &c.SyncPointRetention.duration
 	}
 	res.BDRMode = c.BDRMode
+	res.EnableSnapshotBackfill = c.EnableSnapshotBackfill
+	res.SortDiskQuota = c.SortDiskQuota
+	if c.Priority != "" {
+		res.Priority = util.AddressOf(config.ChangefeedPriority(c.Priority))
+	}
 
 	if c.Filter != nil {
 		var efs []*config.EventFilterRule
@@ -258,7 +285,11 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			Storage:               c.Consistent.Storage,
 			UseFileBackend:        c.Consistent.UseFileBackend,
 			Compression:           c.Consistent.Compression,
+			CompressionLevel:      c.Consistent.CompressionLevel,
+			EncryptionMasterKey:   c.Consistent.EncryptionMasterKey,
 			FlushConcurrency:      c.Consistent.FlushConcurrency,
+			RetentionTimeInSec:    c.Consistent.RetentionTimeInSec,
+			RetentionSize:         c.Consistent.RetentionSize,
 		}
 		if c.Consistent.MemoryUsage != nil {
 			res.Consistent.MemoryUsage = &config.ConsistentMemoryUsage{
@@ -285,6 +316,16 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				Columns: selector.Columns,
 			})
 		}
+		var columnMasks []*config.ColumnMask
+		for _, mask := range c.Sink.ColumnMasks {
+			columnMasks = append(columnMasks, &config.ColumnMask{
+				Matcher: mask.Matcher,
+				Columns: mask.Columns,
+				Action:  mask.Action,
+				Salt:    mask.Salt,
+				Length:  mask.Length,
+			})
+		}
 		var csvConfig *config.CSVConfig
 		if c.Sink.CSVConfig != nil {
 			csvConfig = &config.CSVConfig{
@@ -349,9 +390,10 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			if c.Sink.KafkaConfig.LargeMessageHandle != nil {
 				oldConfig := c.Sink.KafkaConfig.LargeMessageHandle
 				largeMessageHandle = &config.LargeMessageHandleConfig{
-					LargeMessageHandleOption:      oldConfig.LargeMessageHandleOption,
-					LargeMessageHandleCompression: oldConfig.LargeMessageHandleCompression,
-					ClaimCheckStorageURI:          oldConfig.ClaimCheckStorageURI,
+					LargeMessageHandleOption:           oldConfig.LargeMessageHandleOption,
+					LargeMessageHandleCompression:      oldConfig.LargeMessageHandleCompression,
+					LargeMessageHandleCompressionLevel: oldConfig.LargeMessageHandleCompressionLevel,
+					ClaimCheckStorageURI:               oldConfig.ClaimCheckStorageURI,
 				}
 			}
 
@@ -404,6 +446,8 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				LargeMessageHandle:           largeMessageHandle,
 				GlueSchemaRegistryConfig:     glueSchemaRegistryConfig,
 				OutputRawChangeEvent:         c.Sink.KafkaConfig.OutputRawChangeEvent,
+				EnableTransaction:            c.Sink.KafkaConfig.EnableTransaction,
+				TransactionMarkerTopic:       c.Sink.KafkaConfig.TransactionMarkerTopic,
 			}
 		}
 		var mysqlConfig *config.MySQLConfig
@@ -437,6 +481,10 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				FileCleanupCronSpec:  c.Sink.CloudStorageConfig.FileCleanupCronSpec,
 				FlushConcurrency:     c.Sink.CloudStorageConfig.FlushConcurrency,
 				OutputRawChangeEvent: c.Sink.CloudStorageConfig.OutputRawChangeEvent,
+				Compression:          c.Sink.CloudStorageConfig.Compression,
+				CompressionLevel:     c.Sink.CloudStorageConfig.CompressionLevel,
+				EncryptionMasterKey:  c.Sink.CloudStorageConfig.EncryptionMasterKey,
+				TableFormat:          c.Sink.CloudStorageConfig.TableFormat,
 			}
 		}
 		var debeziumConfig *config.DebeziumConfig
@@ -451,12 +499,21 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 				OutputOldValue: c.Sink.OpenProtocolConfig.OutputOldValue,
 			}
 		}
+		var deadLetterQueueConfig *config.DeadLetterQueueConfig
+		if c.Sink.DeadLetterQueueConfig != nil {
+			deadLetterQueueConfig = &config.DeadLetterQueueConfig{
+				URI:           c.Sink.DeadLetterQueueConfig.URI,
+				Protocol:      c.Sink.DeadLetterQueueConfig.Protocol,
+				MaxErrorCount: c.Sink.DeadLetterQueueConfig.MaxErrorCount,
+			}
+		}
 
 		res.Sink = &config.SinkConfig{
 			DispatchRules:                    dispatchRules,
 			Protocol:                         c.Sink.Protocol,
 			CSVConfig:                        csvConfig,
 			ColumnSelectors:                  columnSelectors,
+			ColumnMasks:                      columnMasks,
 			SchemaRegistry:                   c.Sink.SchemaRegistry,
 			EncoderConcurrency:               c.Sink.EncoderConcurrency,
 			Terminator:                       c.Sink.Terminator,
@@ -474,6 +531,7 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			SafeMode:                         c.Sink.SafeMode,
 			OpenProtocol:                     openProtocolConfig,
 			Debezium:                         debeziumConfig,
+			DeadLetterQueue:                  deadLetterQueueConfig,
 		}
 
 		if c.Sink.TxnAtomicity != nil {
@@ -512,6 +570,8 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			EnableTableAcrossNodes: c.Scheduler.EnableTableAcrossNodes,
 			RegionThreshold:        c.Scheduler.RegionThreshold,
 			WriteKeyThreshold:      c.Scheduler.WriteKeyThreshold,
+			EnableLoadBalance:      c.Scheduler.EnableLoadBalance,
+			LoadBalanceThreshold:   c.Scheduler.LoadBalanceThreshold,
 		}
 	}
 	if c.Integrity != nil {
@@ -529,6 +589,13 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 			CheckpointInterval:  c.SyncedStatus.CheckpointInterval,
 		}
 	}
+	if c.PauseSchedule != nil {
+		res.PauseSchedule = &config.PauseScheduleConfig{
+			PauseAt:  c.PauseSchedule.PauseAt,
+			ResumeAt: c.PauseSchedule.ResumeAt,
+			TimeZone: c.PauseSchedule.TimeZone,
+		}
+	}
 	return res
 }
 
@@ -537,14 +604,20 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 	cloned := c.Clone()
 
 	res := &ReplicaConfig{
-		MemoryQuota:           cloned.MemoryQuota,
-		CaseSensitive:         cloned.CaseSensitive,
-		ForceReplicate:        cloned.ForceReplicate,
-		IgnoreIneligibleTable: cloned.IgnoreIneligibleTable,
-		CheckGCSafePoint:      cloned.CheckGCSafePoint,
-		EnableSyncPoint:       cloned.EnableSyncPoint,
-		EnableTableMonitor:    cloned.EnableTableMonitor,
-		BDRMode:               cloned.BDRMode,
+		MemoryQuota:            cloned.MemoryQuota,
+		CaseSensitive:          cloned.CaseSensitive,
+		ForceReplicate:         cloned.ForceReplicate,
+		IgnoreIneligibleTable:  cloned.IgnoreIneligibleTable,
+		CheckGCSafePoint:       cloned.CheckGCSafePoint,
+		EnableSyncPoint:        cloned.EnableSyncPoint,
+		EnableTableMonitor:     cloned.EnableTableMonitor,
+		BDRMode:                cloned.BDRMode,
+		EnableSnapshotBackfill: cloned.EnableSnapshotBackfill,
+		SortDiskQuota:          cloned.SortDiskQuota,
+	}
+
+	if cloned.Priority != nil {
+		res.Priority = string(*cloned.Priority)
 	}
 
 	if cloned.SyncPointInterval != nil {
@@ -588,6 +661,16 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				Columns: selector.Columns,
 			})
 		}
+		var columnMasks []*ColumnMask
+		for _, mask := range cloned.Sink.ColumnMasks {
+			columnMasks = append(columnMasks, &ColumnMask{
+				Matcher: mask.Matcher,
+				Columns: mask.Columns,
+				Action:  mask.Action,
+				Salt:    mask.Salt,
+				Length:  mask.Length,
+			})
+		}
 		var csvConfig *CSVConfig
 		if cloned.Sink.CSVConfig != nil {
 			csvConfig = &CSVConfig{
@@ -619,9 +702,10 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			if cloned.Sink.KafkaConfig.LargeMessageHandle != nil {
 				oldConfig := cloned.Sink.KafkaConfig.LargeMessageHandle
 				largeMessageHandle = &LargeMessageHandleConfig{
-					LargeMessageHandleOption:      oldConfig.LargeMessageHandleOption,
-					LargeMessageHandleCompression: oldConfig.LargeMessageHandleCompression,
-					ClaimCheckStorageURI:          oldConfig.ClaimCheckStorageURI,
+					LargeMessageHandleOption:           oldConfig.LargeMessageHandleOption,
+					LargeMessageHandleCompression:      oldConfig.LargeMessageHandleCompression,
+					LargeMessageHandleCompressionLevel: oldConfig.LargeMessageHandleCompressionLevel,
+					ClaimCheckStorageURI:               oldConfig.ClaimCheckStorageURI,
 				}
 			}
 
@@ -674,6 +758,8 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				LargeMessageHandle:           largeMessageHandle,
 				GlueSchemaRegistryConfig:     glueSchemaRegistryConfig,
 				OutputRawChangeEvent:         cloned.Sink.KafkaConfig.OutputRawChangeEvent,
+				EnableTransaction:            cloned.Sink.KafkaConfig.EnableTransaction,
+				TransactionMarkerTopic:       cloned.Sink.KafkaConfig.TransactionMarkerTopic,
 			}
 		}
 		var mysqlConfig *MySQLConfig
@@ -739,6 +825,10 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				FileCleanupCronSpec:  cloned.Sink.CloudStorageConfig.FileCleanupCronSpec,
 				FlushConcurrency:     cloned.Sink.CloudStorageConfig.FlushConcurrency,
 				OutputRawChangeEvent: cloned.Sink.CloudStorageConfig.OutputRawChangeEvent,
+				Compression:          cloned.Sink.CloudStorageConfig.Compression,
+				CompressionLevel:     cloned.Sink.CloudStorageConfig.CompressionLevel,
+				EncryptionMasterKey:  cloned.Sink.CloudStorageConfig.EncryptionMasterKey,
+				TableFormat:          cloned.Sink.CloudStorageConfig.TableFormat,
 			}
 		}
 		var debeziumConfig *DebeziumConfig
@@ -753,12 +843,21 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				OutputOldValue: cloned.Sink.OpenProtocol.OutputOldValue,
 			}
 		}
+		var deadLetterQueueConfig *DeadLetterQueueConfig
+		if cloned.Sink.DeadLetterQueue != nil {
+			deadLetterQueueConfig = &DeadLetterQueueConfig{
+				URI:           cloned.Sink.DeadLetterQueue.URI,
+				Protocol:      cloned.Sink.DeadLetterQueue.Protocol,
+				MaxErrorCount: cloned.Sink.DeadLetterQueue.MaxErrorCount,
+			}
+		}
 		res.Sink = &SinkConfig{
 			Protocol:                         cloned.Sink.Protocol,
 			SchemaRegistry:                   cloned.Sink.SchemaRegistry,
 			DispatchRules:                    dispatchRules,
 			CSVConfig:                        csvConfig,
 			ColumnSelectors:                  columnSelectors,
+			ColumnMasks:                      columnMasks,
 			EncoderConcurrency:               cloned.Sink.EncoderConcurrency,
 			Terminator:                       cloned.Sink.Terminator,
 			DateSeparator:                    cloned.Sink.DateSeparator,
@@ -775,6 +874,7 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			SafeMode:                         cloned.Sink.SafeMode,
 			DebeziumConfig:                   debeziumConfig,
 			OpenProtocolConfig:               openProtocolConfig,
+			DeadLetterQueueConfig:            deadLetterQueueConfig,
 		}
 
 		if cloned.Sink.TxnAtomicity != nil {
@@ -815,7 +915,11 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			Storage:               cloned.Consistent.Storage,
 			UseFileBackend:        cloned.Consistent.UseFileBackend,
 			Compression:           cloned.Consistent.Compression,
+			CompressionLevel:      cloned.Consistent.CompressionLevel,
+			EncryptionMasterKey:   cloned.Consistent.EncryptionMasterKey,
 			FlushConcurrency:      cloned.Consistent.FlushConcurrency,
+			RetentionTimeInSec:    cloned.Consistent.RetentionTimeInSec,
+			RetentionSize:         cloned.Consistent.RetentionSize,
 		}
 		if cloned.Consistent.MemoryUsage != nil {
 			res.Consistent.MemoryUsage = &ConsistentMemoryUsage{
@@ -834,6 +938,8 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			EnableTableAcrossNodes: cloned.Scheduler.EnableTableAcrossNodes,
 			RegionThreshold:        cloned.Scheduler.RegionThreshold,
 			WriteKeyThreshold:      cloned.Scheduler.WriteKeyThreshold,
+			EnableLoadBalance:      cloned.Scheduler.EnableLoadBalance,
+			LoadBalanceThreshold:   cloned.Scheduler.LoadBalanceThreshold,
 		}
 	}
 
@@ -852,6 +958,13 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			CheckpointInterval:  cloned.SyncedStatus.CheckpointInterval,
 		}
 	}
+	if cloned.PauseSchedule != nil {
+		res.PauseSchedule = &PauseScheduleConfig{
+			PauseAt:  cloned.PauseSchedule.PauseAt,
+			ResumeAt: cloned.PauseSchedule.ResumeAt,
+			TimeZone: cloned.PauseSchedule.TimeZone,
+		}
+	}
 	return res
 }
 
@@ -941,34 +1054,36 @@ type Table struct {
 // SinkConfig represents sink config for a changefeed
 // This is a duplicate of config.SinkConfig
 type SinkConfig struct {
-	Protocol                         *string             `json:"protocol,omitempty"`
-	SchemaRegistry                   *string             `json:"schema_registry,omitempty"`
-	CSVConfig                        *CSVConfig          `json:"csv,omitempty"`
-	DispatchRules                    []*DispatchRule     `json:"dispatchers,omitempty"`
-	ColumnSelectors                  []*ColumnSelector   `json:"column_selectors,omitempty"`
-	TxnAtomicity                     *string             `json:"transaction_atomicity,omitempty"`
-	EncoderConcurrency               *int                `json:"encoder_concurrency,omitempty"`
-	Terminator                       *string             `json:"terminator,omitempty"`
-	DateSeparator                    *string             `json:"date_separator,omitempty"`
-	EnablePartitionSeparator         *bool               `json:"enable_partition_separator,omitempty"`
-	FileIndexWidth                   *int                `json:"file_index_width,omitempty"`
-	EnableKafkaSinkV2                *bool               `json:"enable_kafka_sink_v2,omitempty"`
-	OnlyOutputUpdatedColumns         *bool               `json:"only_output_updated_columns,omitempty"`
-	DeleteOnlyOutputHandleKeyColumns *bool               `json:"delete_only_output_handle_key_columns"`
-	ContentCompatible                *bool               `json:"content_compatible"`
-	SafeMode                         *bool               `json:"safe_mode,omitempty"`
-	KafkaConfig                      *KafkaConfig        `json:"kafka_config,omitempty"`
-	PulsarConfig                     *PulsarConfig       `json:"pulsar_config,omitempty"`
-	MySQLConfig                      *MySQLConfig        `json:"mysql_config,omitempty"`
-	CloudStorageConfig               *CloudStorageConfig `json:"cloud_storage_config,omitempty"`
-	AdvanceTimeoutInSec              *uint               `json:"advance_timeout,omitempty"`
-	SendBootstrapIntervalInSec       *int64              `json:"send_bootstrap_interval_in_sec,omitempty"`
-	SendBootstrapInMsgCount          *int32              `json:"send_bootstrap_in_msg_count,omitempty"`
-	SendBootstrapToAllPartition      *bool               `json:"send_bootstrap_to_all_partition,omitempty"`
-	SendAllBootstrapAtStart          *bool               `json:"send-all-bootstrap-at-start,omitempty"`
-	DebeziumDisableSchema            *bool               `json:"debezium_disable_schema,omitempty"`
-	DebeziumConfig                   *DebeziumConfig     `json:"debezium,omitempty"`
-	OpenProtocolConfig               *OpenProtocolConfig `json:"open,omitempty"`
+	Protocol                         *string                `json:"protocol,omitempty"`
+	SchemaRegistry                   *string                `json:"schema_registry,omitempty"`
+	CSVConfig                        *CSVConfig             `json:"csv,omitempty"`
+	DispatchRules                    []*DispatchRule        `json:"dispatchers,omitempty"`
+	ColumnSelectors                  []*ColumnSelector      `json:"column_selectors,omitempty"`
+	ColumnMasks                      []*ColumnMask          `json:"column_masks,omitempty"`
+	TxnAtomicity                     *string                `json:"transaction_atomicity,omitempty"`
+	EncoderConcurrency               *int                   `json:"encoder_concurrency,omitempty"`
+	Terminator                       *string                `json:"terminator,omitempty"`
+	DateSeparator                    *string                `json:"date_separator,omitempty"`
+	EnablePartitionSeparator         *bool                  `json:"enable_partition_separator,omitempty"`
+	FileIndexWidth                   *int                   `json:"file_index_width,omitempty"`
+	EnableKafkaSinkV2                *bool                  `json:"enable_kafka_sink_v2,omitempty"`
+	OnlyOutputUpdatedColumns         *bool                  `json:"only_output_updated_columns,omitempty"`
+	DeleteOnlyOutputHandleKeyColumns *bool                  `json:"delete_only_output_handle_key_columns"`
+	ContentCompatible                *bool                  `json:"content_compatible"`
+	SafeMode                         *bool                  `json:"safe_mode,omitempty"`
+	KafkaConfig                      *KafkaConfig           `json:"kafka_config,omitempty"`
+	PulsarConfig                     *PulsarConfig          `json:"pulsar_config,omitempty"`
+	MySQLConfig                      *MySQLConfig           `json:"mysql_config,omitempty"`
+	CloudStorageConfig               *CloudStorageConfig    `json:"cloud_storage_config,omitempty"`
+	AdvanceTimeoutInSec              *uint                  `json:"advance_timeout,omitempty"`
+	SendBootstrapIntervalInSec       *int64                 `json:"send_bootstrap_interval_in_sec,omitempty"`
+	SendBootstrapInMsgCount          *int32                 `json:"send_bootstrap_in_msg_count,omitempty"`
+	SendBootstrapToAllPartition      *bool                  `json:"send_bootstrap_to_all_partition,omitempty"`
+	SendAllBootstrapAtStart          *bool                  `json:"send-all-bootstrap-at-start,omitempty"`
+	DebeziumDisableSchema            *bool                  `json:"debezium_disable_schema,omitempty"`
+	DebeziumConfig                   *DebeziumConfig        `json:"debezium,omitempty"`
+	OpenProtocolConfig               *OpenProtocolConfig    `json:"open,omitempty"`
+	DeadLetterQueueConfig            *DeadLetterQueueConfig `json:"dead_letter_queue,omitempty"`
 }
 
 // CSVConfig denotes the csv config
@@ -986,9 +1101,10 @@ type CSVConfig struct {
 // LargeMessageHandleConfig denotes the large message handling config
 // This is the same as config.LargeMessageHandleConfig
 type LargeMessageHandleConfig struct {
-	LargeMessageHandleOption      string `json:"large_message_handle_option"`
-	LargeMessageHandleCompression string `json:"large_message_handle_compression"`
-	ClaimCheckStorageURI          string `json:"claim_check_storage_uri"`
+	LargeMessageHandleOption           string `json:"large_message_handle_option"`
+	LargeMessageHandleCompression      string `json:"large_message_handle_compression"`
+	LargeMessageHandleCompressionLevel int    `json:"large_message_handle_compression_level,omitempty"`
+	ClaimCheckStorageURI               string `json:"claim_check_storage_uri"`
 }
 
 // DispatchRule represents partition rule for a table
@@ -1008,6 +1124,16 @@ type ColumnSelector struct {
 	Columns []string `json:"columns,omitempty"`
 }
 
+// ColumnMask represents a column mask rule for tables.
+// This is a duplicate of config.ColumnMask
+type ColumnMask struct {
+	Matcher []string `json:"matcher,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+	Action  string   `json:"action,omitempty"`
+	Salt    string   `json:"salt,omitempty"`
+	Length  int      `json:"length,omitempty"`
+}
+
 // ConsistentConfig represents replication consistency config for a changefeed
 // This is a duplicate of config.ConsistentConfig
 type ConsistentConfig struct {
@@ -1020,7 +1146,11 @@ type ConsistentConfig struct {
 	Storage               string `json:"storage,omitempty"`
 	UseFileBackend        bool   `json:"use_file_backend"`
 	Compression           string `json:"compression,omitempty"`
+	CompressionLevel      int    `json:"compression_level,omitempty"`
+	EncryptionMasterKey   string `json:"encryption_master_key,omitempty"`
 	FlushConcurrency      int    `json:"flush_concurrency,omitempty"`
+	RetentionTimeInSec    int64  `json:"retention_time,omitempty"`
+	RetentionSize         int64  `json:"retention_size,omitempty"`
 
 	MemoryUsage *ConsistentMemoryUsage `json:"memory_usage"`
 }
@@ -1040,6 +1170,12 @@ type ChangefeedSchedulerConfig struct {
 	RegionThreshold int `toml:"region_threshold" json:"region_threshold"`
 	// WriteKeyThreshold is the written keys threshold of splitting a table.
 	WriteKeyThreshold int `toml:"write_key_threshold" json:"write_key_threshold"`
+	// EnableLoadBalance set true to place and rebalance spans by their
+	// write and sink load instead of the number of spans on each TiCDC node.
+	EnableLoadBalance bool `toml:"enable_load_balance" json:"enable_load_balance"`
+	// LoadBalanceThreshold is the ratio by which the load of a TiCDC node may
+	// exceed the average load before its spans are moved away.
+	LoadBalanceThreshold float64 `toml:"load_balance_threshold" json:"load_balance_threshold"`
 }
 
 // IntegrityConfig is the config for integrity check
@@ -1084,6 +1220,8 @@ type ChangeFeedInfo struct {
 	CheckpointTs   uint64                    `json:"checkpoint_ts"`
 	CheckpointTime model.JSONTime            `json:"checkpoint_time"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+
+	NextScheduledTransition *ScheduledTransition `json:"next_scheduled_transition,omitempty"`
 }
 
 // SyncedStatus describes the detail of a changefeed's synced status
@@ -1260,6 +1398,8 @@ type KafkaConfig struct {
 	LargeMessageHandle           *LargeMessageHandleConfig `json:"large_message_handle,omitempty"`
 	GlueSchemaRegistryConfig     *GlueSchemaRegistryConfig `json:"glue_schema_registry_config,omitempty"`
 	OutputRawChangeEvent         *bool                     `json:"output_raw_change_event,omitempty"`
+	EnableTransaction            *bool                     `json:"enable_transaction,omitempty"`
+	TransactionMarkerTopic       *string                   `json:"transaction_marker_topic,omitempty"`
 }
 
 // MySQLConfig represents a MySQL sink configuration
@@ -1291,6 +1431,10 @@ type CloudStorageConfig struct {
 	FileCleanupCronSpec  *string `json:"file_cleanup_cron_spec,omitempty"`
 	FlushConcurrency     *int    `json:"flush_concurrency,omitempty"`
 	OutputRawChangeEvent *bool   `json:"output_raw_change_event,omitempty"`
+	Compression          *string `json:"compression,omitempty"`
+	CompressionLevel     *int    `json:"compression_level,omitempty"`
+	EncryptionMasterKey  *string `json:"encryption_master_key,omitempty"`
+	TableFormat          *string `json:"table_format,omitempty"`
 }
 
 // ChangefeedStatus holds common information of a changefeed in cdc
@@ -1300,6 +1444,105 @@ type ChangefeedStatus struct {
 	CheckpointTs uint64        `json:"checkpoint_ts"`
 	LastError    *RunningError `json:"last_error,omitempty"`
 	LastWarning  *RunningError `json:"last_warning,omitempty"`
+	// RedoLogSize is the total size in bytes of the redo log files
+	// retained in the external storage.
+	RedoLogSize int64 `json:"redo_log_size,omitempty"`
+	// SnapshotBackfill is the progress of the snapshot backfill of
+	// the tables newly included by the last update.
+	SnapshotBackfill *SnapshotBackfillStatus `json:"snapshot_backfill,omitempty"`
+}
+
+// SnapshotBackfillStatus is the progress of a snapshot backfill.
+type SnapshotBackfillStatus struct {
+	// Ts is the checkpoint ts when the tables are included.
+	Ts uint64 `json:"ts"`
+	// Tables are the physical table IDs to backfill.
+	Tables []int64 `json:"tables"`
+	// FinishedTables are the tables whose existing rows are all sent.
+	FinishedTables []int64 `json:"finished_tables"`
+	Finished       bool    `json:"finished"`
+}
+
+// TableSpanStatus is the replication status of a table span.
+type TableSpanStatus struct {
+	TableID int64 `json:"table_id"`
+	// StartKey and EndKey are the hex encoded keys of the span.
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+	// CaptureID is the capture that is currently replicating the span.
+	CaptureID string `json:"capture_id"`
+	// State is the replication state of the span in the scheduler,
+	// it is one of Absent, Prepare, Commit, Replicating and Removing.
+	State        string `json:"state"`
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	ResolvedTs   uint64 `json:"resolved_ts"`
+	// SorterMaxCommitTs and SorterMaxResolvedTs are the max commit ts and
+	// the max resolved ts received by the sorter.
+	SorterMaxCommitTs   uint64 `json:"sorter_max_commit_ts"`
+	SorterMaxResolvedTs uint64 `json:"sorter_max_resolved_ts"`
+	SinkRowsPerSecond   uint64 `json:"sink_rows_per_second"`
+	SinkBytesPerSecond  uint64 `json:"sink_bytes_per_second"`
+}
+
+// LoadBalancePlan is the span moves planned by loads of a changefeed,
+// loads are in rows per second.
+type LoadBalancePlan struct {
+	// Enabled is true if spans are balanced by loads for the changefeed.
+	Enabled bool `json:"enabled"`
+	// Threshold is the ratio by which the load of a capture may exceed
+	// the average load.
+	Threshold float64           `json:"threshold"`
+	Captures  []CaptureLoad     `json:"captures"`
+	Moves     []LoadBalanceMove `json:"moves"`
+}
+
+// CaptureLoad is the load of a capture before and after the planned moves.
+// Loads are weighted by priorities, and include loads of other changefeeds
+// whose priorities are not lower than the changefeed.
+type CaptureLoad struct {
+	CaptureID   string `json:"capture_id"`
+	Load        uint64 `json:"load"`
+	PlannedLoad uint64 `json:"planned_load"`
+}
+
+// LoadBalanceMove is a span move planned by loads.
+type LoadBalanceMove struct {
+	TableID int64 `json:"table_id"`
+	// StartKey and EndKey are the hex encoded keys of the span.
+	StartKey        string `json:"start_key"`
+	EndKey          string `json:"end_key"`
+	Load            uint64 `json:"load"`
+	SourceCaptureID string `json:"source_capture_id"`
+	TargetCaptureID string `json:"target_capture_id"`
+}
+
+// MoveTableConfig is the config to move a table span to the target capture.
+type MoveTableConfig struct {
+	TableID int64 `json:"table_id"`
+	// StartKey and EndKey are the hex encoded keys of the span to move,
+	// they are only required when the table is split into multiple spans.
+	StartKey        string `json:"start_key,omitempty"`
+	EndKey          string `json:"end_key,omitempty"`
+	TargetCaptureID string `json:"target_capture_id"`
+}
+
+// ExportedChangefeed is a changefeed exported from a TiCDC cluster, it can be
+// imported into another TiCDC cluster that shares the same upstream.
+type ExportedChangefeed struct {
+	UpstreamID uint64 `json:"upstream_id"`
+	Namespace  string `json:"namespace"`
+	ID         string `json:"changefeed_id"`
+	// the credentials in the sink uri are masked, they must be filled in
+	// before the changefeed is imported
+	SinkURI       string         `json:"sink_uri"`
+	TargetTs      uint64         `json:"target_ts"`
+	ReplicaConfig *ReplicaConfig `json:"replica_config"`
+	// the imported changefeed starts from the checkpoint ts
+	CheckpointTs uint64 `json:"checkpoint_ts"`
+	// the GC service safepoint that keeps the data after the checkpoint ts
+	// until the changefeed is imported, it is removed after the import
+	GCServiceID    string `json:"gc_service_id"`
+	CreatorVersion string `json:"creator_version"`
 }
 
 // GlueSchemaRegistryConfig represents a glue schema registry configuration
@@ -1324,3 +1567,10 @@ type OpenProtocolConfig struct {
 type DebeziumConfig struct {
 	OutputOldValue bool `json:"output_old_value"`
 }
+
+// DeadLetterQueueConfig represents the dead letter queue of a MySQL sink
+type DeadLetterQueueConfig struct {
+	URI           string `json:"uri"`
+	Protocol      string `json:"protocol,omitempty"`
+	MaxErrorCount int64  `json:"max_error_count,omitempty"`
+}
@@ -87,6 +87,10 @@ var defaultAPIConfig = &ReplicaConfig{
 			Scheduler.RegionThreshold,
 		WriteKeyThreshold: config.GetDefaultReplicaConfig().
 			Scheduler.WriteKeyThreshold,
+		EnableLoadBalance: config.GetDefaultReplicaConfig().
+			Scheduler.EnableLoadBalance,
+		LoadBalanceThreshold: config.GetDefaultReplicaConfig().
+			Scheduler.LoadBalanceThreshold,
 	},
 	Integrity: &IntegrityConfig{
 		IntegrityCheckLevel:   config.GetDefaultReplicaConfig().Integrity.IntegrityCheckLevel,
@@ -153,6 +157,7 @@ func TestToAPIReplicaConfig(t *testing.T) {
 	cfg.Mounter = &config.MounterConfig{WorkerNum: 11}
 	cfg.Scheduler = &config.ChangefeedSchedulerConfig{
 		EnableTableAcrossNodes: true, RegionThreshold: 10001, WriteKeyThreshold: 10001,
+		EnableLoadBalance: true, LoadBalanceThreshold: 0.3,
 	}
 	cfg2 := ToAPIReplicaConfig(cfg).ToInternalReplicaConfig()
 	require.Equal(t, "", cfg2.Sink.DispatchRules[0].DispatcherRule)
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/processor"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/factory"
+	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/cdc/vars"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -508,6 +509,7 @@ func (c *captureImpl) campaignOwner(ctx context.Context, globalVars *vars.Global
 		globalVars := *globalVars
 		newGlobalVars := &globalVars
 		newGlobalVars.OwnerRevision = ownerRev
+		newGlobalVars.CaptureLoads = scheduler.NewCaptureLoads()
 
 		log.Info("campaign owner successfully",
 			zap.String("captureID", c.info.ID),
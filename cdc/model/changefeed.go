@@ -14,10 +14,12 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
 	"math"
 	"net/url"
 	"regexp"
+	"sort"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -25,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/tikv/client-go/v2/oracle"
@@ -173,6 +176,160 @@ type ChangeFeedInfo struct {
 	CreatorVersion string `json:"creator-version"`
 	// Epoch is the epoch of a changefeed, changes on every restart.
 	Epoch uint64 `json:"epoch"`
+
+	// SnapshotBackfill records the tables newly included by the last update,
+	// whose existing rows are scanned and sent to the sink.
+	SnapshotBackfill *SnapshotBackfillInfo `json:"snapshot-backfill,omitempty"`
+
+	// PausedBySchedule is true if the changefeed is paused by its pause
+	// schedule, the schedule only resumes a changefeed paused by itself.
+	PausedBySchedule bool `json:"paused-by-schedule,omitempty"`
+}
+
+// SnapshotBackfillInfo records the tables which need a snapshot backfill.
+type SnapshotBackfillInfo struct {
+	// Ts is the checkpoint ts of the changefeed when the tables are included.
+	Ts uint64 `json:"ts"`
+	// TableIDs are the physical table IDs to backfill.
+	TableIDs []TableID `json:"table-ids"`
+	// ScannedRanges are the record key ranges of the unfinished tables whose
+	// snapshots have been flushed to the sink, a table added again resumes
+	// its snapshot scan from the rest of its ranges.
+	ScannedRanges []SnapshotRange `json:"scanned-ranges,omitempty"`
+	// FinishedTableIDs are the tables whose snapshots are fully flushed.
+	FinishedTableIDs []TableID `json:"finished-table-ids,omitempty"`
+}
+
+// SnapshotRange is the record key range [StartKey, EndKey) of a table,
+// the keys are not in memcomparable format.
+type SnapshotRange struct {
+	TableID  TableID `json:"table-id"`
+	StartKey []byte  `json:"start-key"`
+	EndKey   []byte  `json:"end-key"`
+}
+
+// Clone returns a deep copy of the range.
+func (r SnapshotRange) Clone() SnapshotRange {
+	return SnapshotRange{
+		TableID:  r.TableID,
+		StartKey: append([]byte{}, r.StartKey...),
+		EndKey:   append([]byte{}, r.EndKey...),
+	}
+}
+
+// MergeSnapshotRanges sorts the ranges by table and start key, and merges
+// the overlapping or adjacent ones. The input slice is not modified.
+func MergeSnapshotRanges(ranges []SnapshotRange) []SnapshotRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sorted := append([]SnapshotRange{}, ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].TableID != sorted[j].TableID {
+			return sorted[i].TableID < sorted[j].TableID
+		}
+		return bytes.Compare(sorted[i].StartKey, sorted[j].StartKey) < 0
+	})
+	merged := []SnapshotRange{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if r.TableID != last.TableID || bytes.Compare(r.StartKey, last.EndKey) > 0 {
+			merged = append(merged, r)
+			continue
+		}
+		if bytes.Compare(r.EndKey, last.EndKey) > 0 {
+			last.EndKey = r.EndKey
+		}
+	}
+	return merged
+}
+
+// ShouldBackfill returns true if the snapshot of the table should be scanned
+// when the table is added, that is the table is not finished yet.
+func (b *SnapshotBackfillInfo) ShouldBackfill(tableID TableID) bool {
+	if b == nil {
+		return false
+	}
+	return containsTableID(b.TableIDs, tableID) && !containsTableID(b.FinishedTableIDs, tableID)
+}
+
+// UnfinishedTableIDs returns the tables whose snapshots are not fully flushed.
+func (b *SnapshotBackfillInfo) UnfinishedTableIDs() []TableID {
+	if b == nil {
+		return nil
+	}
+	var tableIDs []TableID
+	for _, id := range b.TableIDs {
+		if !containsTableID(b.FinishedTableIDs, id) {
+			tableIDs = append(tableIDs, id)
+		}
+	}
+	return tableIDs
+}
+
+// UnscannedRanges returns the parts of the record key range [startKey, endKey)
+// of the table which are not scanned yet.
+func (b *SnapshotBackfillInfo) UnscannedRanges(
+	tableID TableID, startKey, endKey []byte,
+) []SnapshotRange {
+	var ranges []SnapshotRange
+	next := startKey
+	for _, r := range b.ScannedRanges {
+		if r.TableID != tableID || bytes.Compare(r.EndKey, next) <= 0 ||
+			bytes.Compare(r.StartKey, endKey) >= 0 {
+			continue
+		}
+		if bytes.Compare(r.StartKey, next) > 0 {
+			ranges = append(ranges, SnapshotRange{TableID: tableID, StartKey: next, EndKey: r.StartKey})
+		}
+		next = r.EndKey
+	}
+	if bytes.Compare(next, endKey) < 0 {
+		ranges = append(ranges, SnapshotRange{TableID: tableID, StartKey: next, EndKey: endKey})
+	}
+	return ranges
+}
+
+// MergeScannedRanges adds the scanned ranges of the unfinished tables, and
+// a table is finished once its whole record key range is scanned. It returns
+// true if the info is changed.
+func (b *SnapshotBackfillInfo) MergeScannedRanges(ranges []SnapshotRange) bool {
+	var added []SnapshotRange
+	for _, r := range ranges {
+		if !b.ShouldBackfill(r.TableID) ||
+			len(b.UnscannedRanges(r.TableID, r.StartKey, r.EndKey)) == 0 {
+			continue
+		}
+		added = append(added, r.Clone())
+	}
+	if len(added) == 0 {
+		return false
+	}
+	b.ScannedRanges = MergeSnapshotRanges(append(b.ScannedRanges, added...))
+	for _, id := range b.UnfinishedTableIDs() {
+		startKey, endKey := spanz.GetTableRange(id)
+		if len(b.UnscannedRanges(id, startKey, endKey)) == 0 {
+			b.FinishedTableIDs = append(b.FinishedTableIDs, id)
+		}
+	}
+	// Ranges of the finished tables are no longer needed.
+	scanned := b.ScannedRanges[:0]
+	for _, r := range b.ScannedRanges {
+		if b.ShouldBackfill(r.TableID) {
+			scanned = append(scanned, r)
+		}
+	}
+	b.ScannedRanges = scanned
+	return true
+}
+
+func containsTableID(tableIDs []TableID, tableID TableID) bool {
+	for _, id := range tableIDs {
+		if id == tableID {
+			return true
+		}
+	}
+	return false
 }
 
 const changeFeedIDMaxLen = 128
@@ -361,8 +518,9 @@ func (info *ChangeFeedInfo) RmUnusedFields() {
 		info.rmMQOnlyFields()
 	} else {
 		// remove schema registry for MQ downstream with
-		// protocol other than avro
-		if util.GetOrZero(info.Config.Sink.Protocol) != config.ProtocolAvro.String() {
+		// protocol other than avro and protobuf
+		protocol := util.GetOrZero(info.Config.Sink.Protocol)
+		if protocol != config.ProtocolAvro.String() && protocol != config.ProtocolProtobuf.String() {
 			info.Config.Sink.SchemaRegistry = nil
 		}
 	}
@@ -371,12 +529,18 @@ func (info *ChangeFeedInfo) RmUnusedFields() {
 		info.rmStorageOnlyFields()
 	}
 
-	if !sink.IsMySQLCompatibleScheme(uri.Scheme) {
-		info.rmDBOnlyFields()
-	} else {
+	switch {
+	case sink.IsMySQLCompatibleScheme(uri.Scheme):
 		// remove fields only being used by MQ and Storage downstream
 		info.Config.Sink.Protocol = nil
 		info.Config.Sink.Terminator = nil
+	case sink.IsPostgresScheme(uri.Scheme):
+		// PostgreSQL downstream only honors the safe mode among DB fields.
+		info.rmMySQLOnlyFields()
+		info.Config.Sink.Protocol = nil
+		info.Config.Sink.Terminator = nil
+	default:
+		info.rmDBOnlyFields()
 	}
 }
 
@@ -403,12 +567,16 @@ func (info *ChangeFeedInfo) rmStorageOnlyFields() {
 }
 
 func (info *ChangeFeedInfo) rmDBOnlyFields() {
+	info.rmMySQLOnlyFields()
+	info.Config.Sink.SafeMode = nil
+}
+
+func (info *ChangeFeedInfo) rmMySQLOnlyFields() {
 	info.Config.EnableSyncPoint = nil
 	info.Config.BDRMode = nil
 	info.Config.SyncPointInterval = nil
 	info.Config.SyncPointRetention = nil
 	info.Config.Consistent = nil
-	info.Config.Sink.SafeMode = nil
 	info.Config.Sink.MySQLConfig = nil
 }
 
@@ -583,6 +751,19 @@ func (info *ChangeFeedInfo) fixScheduler(inheritV66 bool) {
 type ChangeFeedStatusForAPI struct {
 	ResolvedTs   uint64 `json:"resolved-ts"`
 	CheckpointTs uint64 `json:"checkpoint-ts"`
+	// RedoLogSize is the total size of the retained redo log files,
+	// it is 0 if redo log is disabled.
+	RedoLogSize int64 `json:"redo-log-size,omitempty"`
+	// SnapshotBackfill is the progress of the snapshot backfill,
+	// it is nil if there is no snapshot backfill.
+	SnapshotBackfill *SnapshotBackfillProgress `json:"snapshot-backfill,omitempty"`
+}
+
+// SnapshotBackfillProgress is the progress of a snapshot backfill.
+type SnapshotBackfillProgress struct {
+	Ts             uint64    `json:"ts"`
+	Tables         []TableID `json:"tables"`
+	FinishedTables []TableID `json:"finished-tables"`
 }
 
 // ChangeFeedSyncedStatusForAPI uses to transfer the synced status of changefeed for API.
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
@@ -1128,3 +1129,68 @@ func TestGetTs(t *testing.T) {
 	status := &ChangeFeedStatus{CheckpointTs: checkpointTs}
 	require.Equal(t, info.GetCheckpointTs(status), checkpointTs)
 }
+
+func TestSnapshotBackfillInfo(t *testing.T) {
+	t.Parallel()
+
+	var info *SnapshotBackfillInfo
+	require.False(t, info.ShouldBackfill(1))
+
+	info = &SnapshotBackfillInfo{Ts: 100, TableIDs: []TableID{1, 2}}
+	require.True(t, info.ShouldBackfill(1))
+	require.True(t, info.ShouldBackfill(2))
+	require.False(t, info.ShouldBackfill(3))
+	require.Equal(t, []TableID{1, 2}, info.UnfinishedTableIDs())
+
+	startKey, endKey := spanz.GetTableRange(1)
+	midKey := append(append([]byte{}, startKey...), 'm')
+	require.Equal(t, []SnapshotRange{
+		{TableID: 1, StartKey: startKey, EndKey: endKey},
+	}, info.UnscannedRanges(1, startKey, endKey))
+
+	// ranges of unknown tables are ignored
+	require.False(t, info.MergeScannedRanges([]SnapshotRange{
+		{TableID: 3, StartKey: startKey, EndKey: endKey},
+	}))
+
+	require.True(t, info.MergeScannedRanges([]SnapshotRange{
+		{TableID: 1, StartKey: midKey, EndKey: endKey},
+	}))
+	require.True(t, info.ShouldBackfill(1))
+	require.Equal(t, []SnapshotRange{
+		{TableID: 1, StartKey: startKey, EndKey: midKey},
+	}, info.UnscannedRanges(1, startKey, endKey))
+	// a re-added range is not a change
+	require.False(t, info.MergeScannedRanges([]SnapshotRange{
+		{TableID: 1, StartKey: midKey, EndKey: endKey},
+	}))
+
+	// the table is finished once its whole range is scanned
+	require.True(t, info.MergeScannedRanges([]SnapshotRange{
+		{TableID: 1, StartKey: startKey, EndKey: midKey},
+	}))
+	require.False(t, info.ShouldBackfill(1))
+	require.Equal(t, []TableID{1}, info.FinishedTableIDs)
+	require.Equal(t, []TableID{2}, info.UnfinishedTableIDs())
+	require.Empty(t, info.ScannedRanges)
+}
+
+func TestMergeSnapshotRanges(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, MergeSnapshotRanges(nil))
+	ranges := []SnapshotRange{
+		{TableID: 2, StartKey: []byte("a"), EndKey: []byte("b")},
+		{TableID: 1, StartKey: []byte("c"), EndKey: []byte("d")},
+		{TableID: 1, StartKey: []byte("a"), EndKey: []byte("c")},
+		{TableID: 1, StartKey: []byte("e"), EndKey: []byte("f")},
+		{TableID: 1, StartKey: []byte("b"), EndKey: []byte("c")},
+	}
+	require.Equal(t, []SnapshotRange{
+		{TableID: 1, StartKey: []byte("a"), EndKey: []byte("d")},
+		{TableID: 1, StartKey: []byte("e"), EndKey: []byte("f")},
+		{TableID: 2, StartKey: []byte("a"), EndKey: []byte("b")},
+	}, MergeSnapshotRanges(ranges))
+	// the input is not modified
+	require.Equal(t, TableID(2), ranges[0].TableID)
+}
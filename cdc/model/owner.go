@@ -32,6 +32,8 @@ type AdminJob struct {
 	Type                  AdminJobType
 	Error                 *RunningError
 	OverwriteCheckpointTs uint64
+	// Scheduled is true if the job is pushed by the pause schedule.
+	Scheduled bool
 }
 
 // All AdminJob types
@@ -95,6 +97,9 @@ type TaskPosition struct {
 	Error *RunningError `json:"error"`
 	// Warning when module error happens
 	Warning *RunningError `json:"warning"`
+	// ScannedSnapshotRanges are the snapshot ranges scanned and flushed by
+	// the processor, the owner merges them into the snapshot backfill.
+	ScannedSnapshotRanges []SnapshotRange `json:"scanned-snapshot-ranges,omitempty"`
 }
 
 // Marshal returns the json marshal format of a TaskStatus
@@ -139,6 +144,12 @@ func (tp *TaskPosition) Clone() *TaskPosition {
 			Message: tp.Warning.Message,
 		}
 	}
+	if len(tp.ScannedSnapshotRanges) > 0 {
+		ret.ScannedSnapshotRanges = make([]SnapshotRange, 0, len(tp.ScannedSnapshotRanges))
+		for _, r := range tp.ScannedSnapshotRanges {
+			ret.ScannedSnapshotRanges = append(ret.ScannedSnapshotRanges, r.Clone())
+		}
+	}
 	return ret
 }
 
@@ -298,3 +309,46 @@ type ProcInfoSnap struct {
 	CfID      ChangeFeedID `json:"changefeed-id"`
 	CaptureID string       `json:"capture-id"`
 }
+
+// TableSpanStatus holds the replication status of a table span,
+// it is used to transfer the status of table spans for API.
+type TableSpanStatus struct {
+	Span tablepb.Span `json:"span"`
+	// CaptureID is the capture that is currently replicating the span.
+	CaptureID CaptureID `json:"capture-id"`
+	// State is the replication state of the span in the scheduler.
+	State      string             `json:"state"`
+	Checkpoint tablepb.Checkpoint `json:"checkpoint"`
+	Stats      tablepb.Stats      `json:"stats"`
+}
+
+// LoadBalancePlan holds the span moves planned by loads of a changefeed,
+// it is used to show a dry run of the load balance for API.
+type LoadBalancePlan struct {
+	// Enabled is true if spans are balanced by loads for the changefeed.
+	Enabled bool `json:"enabled"`
+	// Threshold is the ratio by which the load of a capture may exceed
+	// the average load.
+	Threshold float64           `json:"threshold"`
+	Captures  []CaptureLoad     `json:"captures"`
+	Moves     []LoadBalanceMove `json:"moves"`
+}
+
+// CaptureLoad is the load of a capture before and after the planned moves.
+// Loads are weighted by priorities, and include loads of other changefeeds
+// whose priorities are not lower than the changefeed.
+type CaptureLoad struct {
+	CaptureID   CaptureID `json:"capture-id"`
+	Load        uint64    `json:"load"`
+	PlannedLoad uint64    `json:"planned-load"`
+}
+
+// LoadBalanceMove is a span move planned by loads.
+type LoadBalanceMove struct {
+	Span tablepb.Span `json:"span"`
+	// Load is the load of the span in rows per second, weighted by the
+	// priority of the changefeed.
+	Load uint64    `json:"load"`
+	From CaptureID `json:"from"`
+	To   CaptureID `json:"to"`
+}
@@ -84,7 +84,8 @@ func newScheduler(
 	ownerRev := globalVars.OwnerRevision
 	captureID := globalVars.CaptureInfo.ID
 	ret, err := scheduler.NewScheduler(
-		ctx, captureID, changeFeedID, messageServer, messageRouter, ownerRev, epoch, up, cfg, redoMetaManager)
+		ctx, captureID, changeFeedID, messageServer, messageRouter, ownerRev, epoch, up, cfg, redoMetaManager,
+		globalVars.CaptureLoads)
 	return ret, errors.Trace(err)
 }
 
@@ -723,6 +724,7 @@ LOOP2:
 	// create scheduler
 	cfg := *c.cfg
 	cfg.ChangefeedSettings = cfInfo.Config.Scheduler
+	cfg.Priority = cfInfo.Config.GetPriority()
 	epoch := cfInfo.Epoch
 	c.scheduler, err = c.newScheduler(ctx, c.id, c.upstream, epoch, &cfg, c.redoMetaMgr, c.globalVars)
 	if err != nil {
@@ -741,6 +743,13 @@ LOOP2:
 		zap.Uint64("checkpointTs", checkpointTs),
 		zap.Uint64("resolvedTs", c.resolvedTs),
 		zap.String("info", cfInfo.String()))
+	if tableIDs := cfInfo.SnapshotBackfill.UnfinishedTableIDs(); len(tableIDs) > 0 {
+		log.Info("changefeed has tables to backfill from snapshots",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Uint64("backfillTs", cfInfo.SnapshotBackfill.Ts),
+			zap.Int64s("tableIDs", tableIDs))
+	}
 
 	return nil
 }
@@ -1012,6 +1021,31 @@ func (c *changefeed) GetInfoProvider() scheduler.InfoProvider {
 	return nil
 }
 
+// getSnapshotBackfillProgress returns the progress of the snapshot backfill,
+// or nil if there is no snapshot backfill.
+func (c *changefeed) getSnapshotBackfillProgress() *model.SnapshotBackfillProgress {
+	if c.latestInfo == nil || c.latestInfo.SnapshotBackfill == nil {
+		return nil
+	}
+	return snapshotBackfillProgress(c.latestInfo.SnapshotBackfill)
+}
+
+// snapshotBackfillProgress calculates the progress of the snapshot backfill.
+// A table has finished the backfill once its whole snapshot is flushed.
+func snapshotBackfillProgress(backfill *model.SnapshotBackfillInfo) *model.SnapshotBackfillProgress {
+	progress := &model.SnapshotBackfillProgress{
+		Ts:             backfill.Ts,
+		Tables:         append([]model.TableID{}, backfill.TableIDs...),
+		FinishedTables: []model.TableID{},
+	}
+	for _, tableID := range backfill.TableIDs {
+		if !backfill.ShouldBackfill(tableID) {
+			progress.FinishedTables = append(progress.FinishedTables, tableID)
+		}
+	}
+	return progress
+}
+
 // checkUpstream returns skip = true if the upstream is still in initializing phase,
 // and returns an error if the upstream is unavailable.
 func (c *changefeed) checkUpstream() (skip bool, err error) {
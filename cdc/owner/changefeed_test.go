@@ -26,6 +26,7 @@ import (
 	timodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/puller"
 	credo "github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
@@ -183,7 +184,7 @@ func (m *mockScheduler) Tick(
 }
 
 // MoveTable is used to trigger manual table moves.
-func (m *mockScheduler) MoveTable(tableID model.TableID, target model.CaptureID) {}
+func (m *mockScheduler) MoveTable(span tablepb.Span, target model.CaptureID) {}
 
 // Rebalance is used to trigger manual workload rebalances.
 func (m *mockScheduler) Rebalance() {}
@@ -218,7 +219,7 @@ func createChangefeed4Test(globalVars *vars.GlobalVars,
 	})
 	tester.MustApplyPatches()
 	cf := newChangefeed4Test(model.DefaultChangeFeedID(changefeedInfo.ID),
-		state.Info, state.Status, NewFeedStateManager(up, state), up,
+		state.Info, state.Status, NewFeedStateManager(up, state, ""), up,
 		// new ddl puller
 		func(ctx context.Context,
 			up *upstream.Upstream,
@@ -697,3 +698,23 @@ func TestBarrierAdvance(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshotBackfillProgress(t *testing.T) {
+	backfill := &model.SnapshotBackfillInfo{
+		Ts:               10,
+		TableIDs:         []model.TableID{1, 2, 3},
+		FinishedTableIDs: []model.TableID{1, 3},
+	}
+	progress := snapshotBackfillProgress(backfill)
+	require.Equal(t, &model.SnapshotBackfillProgress{
+		Ts:             10,
+		Tables:         []model.TableID{1, 2, 3},
+		FinishedTables: []model.TableID{1, 3},
+	}, progress)
+
+	// no table is finished before its whole snapshot is flushed.
+	backfill.FinishedTableIDs = nil
+	backfill.ScannedRanges = []model.SnapshotRange{{TableID: 2}}
+	progress = snapshotBackfillProgress(backfill)
+	require.Empty(t, progress.FinishedTables)
+}
@@ -37,6 +37,11 @@ type ChangefeedState interface {
 	TakeProcessorErrors() []*model.RunningError
 	// CleanUpTaskPositions removes the task positions of the changefeed.
 	CleanUpTaskPositions()
+	// MergeScannedSnapshotRanges merges the snapshot ranges scanned by processors
+	// into the snapshot backfill of the changefeed.
+	MergeScannedSnapshotRanges()
+	// SetPausedBySchedule records whether the changefeed is paused by its pause schedule.
+	SetPausedBySchedule(bool)
 	// UpdateChangefeedState returns the task status of the changefeed.
 	UpdateChangefeedState(model.FeedState, model.AdminJobType, uint64)
 }
@@ -21,7 +21,9 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/tikv/client-go/v2/oracle"
@@ -91,15 +93,24 @@ type feedStateManager struct {
 	checkpointTsAdvanced time.Time
 
 	changefeedErrorStuckDuration time.Duration
+
+	// pauseScheduleCheckedAt is the last time the pause schedule of
+	// the changefeed is checked, transitions reached after it are handled
+	// in the next tick.
+	pauseScheduleCheckedAt time.Time
+	// resumingGCServiceID is the GC service ID used to keep the checkpoint
+	// safe when the changefeed is resumed by its pause schedule.
+	resumingGCServiceID string
 }
 
 // NewFeedStateManager creates feedStateManager and initialize the exponential backoff
 func NewFeedStateManager(up *upstream.Upstream,
-	state ChangefeedState,
+	state ChangefeedState, resumingGCServiceID string,
 ) FeedStateManager {
 	m := new(feedStateManager)
 	m.upstream = up
 	m.state = state
+	m.resumingGCServiceID = resumingGCServiceID
 
 	m.errBackoff = backoff.NewExponentialBackOff()
 	m.errBackoff.InitialInterval = defaultBackoffInitInterval
@@ -165,6 +176,7 @@ func (m *feedStateManager) Tick(resolvedTs model.Ts,
 		}
 	}()
 
+	m.checkPauseSchedule(info)
 	if m.handleAdminJob() {
 		// `handleAdminJob` returns true means that some admin jobs are pending
 		// skip to the next tick until all the admin jobs is handled
@@ -236,6 +248,7 @@ func (m *feedStateManager) Tick(resolvedTs model.Ts,
 			warnings := m.state.TakeProcessorWarnings()
 			m.HandleWarning(warnings...)
 		}
+		m.state.MergeScannedSnapshotRanges()
 	}
 	return
 }
@@ -295,6 +308,7 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		m.shouldBeRunning = false
 		jobsPending = true
 		m.patchState(model.StateStopped)
+		m.state.SetPausedBySchedule(job.Scheduled)
 	case model.AdminRemove:
 		m.shouldBeRunning = false
 		m.shouldBeRemoved = true
@@ -316,6 +330,10 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 				zap.String("changefeedState", string(m.state.GetChangefeedInfo().State)), zap.Any("job", job))
 			return
 		}
+		// A resume from the API has ensured the checkpoint is safe from GC.
+		if job.Scheduled && !m.ensureResumeSafety() {
+			return
+		}
 		m.shouldBeRunning = true
 		// when the changefeed is manually resumed, we must reset the backoff
 		m.resetErrRetry()
@@ -323,6 +341,7 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		jobsPending = true
 		m.patchState(model.StateNormal)
 		m.state.ResumeChangefeed(job.OverwriteCheckpointTs)
+		m.state.SetPausedBySchedule(false)
 
 	case model.AdminFinish:
 		switch m.state.GetChangefeedInfo().State {
@@ -347,6 +366,102 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 	return
 }
 
+// checkPauseSchedule pushes an admin job to pause or resume the changefeed if
+// a transition of its pause schedule is reached since the last check. The
+// scheduled pause goes through the same path as a manual one, so the GC
+// safepoint is held in the same way while the changefeed is stopped. Only a
+// changefeed paused by the schedule is resumed by it.
+func (m *feedStateManager) checkPauseSchedule(info *model.ChangeFeedInfo) {
+	if info == nil || info.Config == nil || info.Config.PauseSchedule == nil {
+		m.pauseScheduleCheckedAt = time.Time{}
+		return
+	}
+	now := m.upstream.PDClock.CurrentTime()
+	checkedAt := m.pauseScheduleCheckedAt
+	m.pauseScheduleCheckedAt = now
+	if checkedAt.IsZero() {
+		// Transitions missed before the changefeed is ticked at the first
+		// time, e.g. during an owner switch, are not replayed.
+		return
+	}
+
+	var action config.PauseScheduleAction
+	for {
+		next, nextAction, err := info.Config.PauseSchedule.NextTransition(checkedAt)
+		if err != nil {
+			log.Warn("invalid pause schedule, ignore it",
+				zap.String("namespace", m.state.GetID().Namespace),
+				zap.String("changefeed", m.state.GetID().ID),
+				zap.Error(err))
+			return
+		}
+		if next.After(now) {
+			break
+		}
+		action, checkedAt = nextAction, next
+	}
+
+	var jobType model.AdminJobType
+	switch action {
+	case config.PauseScheduleActionPause:
+		switch info.State {
+		case model.StateNormal, model.StateWarning, model.StatePending:
+			jobType = model.AdminStop
+		default:
+			return
+		}
+	case config.PauseScheduleActionResume:
+		// A changefeed paused manually, failed or finished needs to be
+		// handled manually.
+		if info.State != model.StateStopped || !info.PausedBySchedule {
+			return
+		}
+		jobType = model.AdminResume
+	default:
+		return
+	}
+	log.Info("pause schedule of the changefeed is reached",
+		zap.String("namespace", m.state.GetID().Namespace),
+		zap.String("changefeed", m.state.GetID().ID),
+		zap.String("action", string(action)),
+		zap.Time("scheduledAt", checkedAt))
+	m.pushAdminJob(&model.AdminJob{
+		CfID:      m.state.GetID(),
+		Type:      jobType,
+		Scheduled: true,
+	})
+}
+
+// ensureResumeSafety checks the checkpoint of the changefeed is still safe
+// from GC before a scheduled resume, like a resume from the API does. The
+// changefeed stays stopped with the error recorded if the check fails.
+func (m *feedStateManager) ensureResumeSafety() bool {
+	checkpointTs := m.state.GetChangefeedInfo().GetCheckpointTs(m.state.GetChangefeedStatus())
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	err := gc.EnsureChangefeedResumeSafety(ctx, m.upstream.PDClient,
+		m.resumingGCServiceID, m.state.GetID(), checkpointTs)
+	if err == nil {
+		return true
+	}
+	log.Warn("changefeed can not be resumed by the pause schedule",
+		zap.String("namespace", m.state.GetID().Namespace),
+		zap.String("changefeed", m.state.GetID().ID),
+		zap.Uint64("checkpointTs", checkpointTs),
+		zap.Error(err))
+	code, ok := cerrors.RFCCode(err)
+	if !ok {
+		code = cerrors.ErrOwnerUnknown.RFCCode()
+	}
+	m.state.SetError(&model.RunningError{
+		Time:    time.Now(),
+		Addr:    config.GetGlobalServerConfig().AdvertiseAddr,
+		Code:    string(code),
+		Message: err.Error(),
+	})
+	return false
+}
+
 func (m *feedStateManager) popAdminJob() *model.AdminJob {
 	if len(m.adminJobQueue) == 0 {
 		return nil
@@ -37,6 +37,8 @@ type mockPD struct {
 	pd.Client
 
 	getTs func() (int64, int64, error)
+
+	updateServiceGCSafePoint func(safePoint uint64) (uint64, error)
 }
 
 func (p *mockPD) GetTS(_ context.Context) (int64, int64, error) {
@@ -46,6 +48,15 @@ func (p *mockPD) GetTS(_ context.Context) (int64, int64, error) {
 	return 1, 2, nil
 }
 
+func (p *mockPD) UpdateServiceGCSafePoint(
+	_ context.Context, _ string, _ int64, safePoint uint64,
+) (uint64, error) {
+	if p.updateServiceGCSafePoint != nil {
+		return p.updateServiceGCSafePoint(safePoint)
+	}
+	return 0, nil
+}
+
 // newFeedStateManager4Test creates feedStateManager for test
 func newFeedStateManager4Test(
 	initialIntervalInMs, maxIntervalInMs, maxElapsedTimeInMs int,
@@ -1166,3 +1177,129 @@ func TestUpdateChangefeedWithChangefeedErrorStuckDuration(t *testing.T) {
 	require.False(t, manager.ShouldRunning())
 	require.Equal(t, state.Info.State, model.StateFailed)
 }
+
+type mockClock struct {
+	pdutil.Clock
+
+	now time.Time
+}
+
+func (c *mockClock) CurrentTime() time.Time {
+	return c.now
+}
+
+func TestPauseSchedule(t *testing.T) {
+	_, changefeedInfo := vars.NewGlobalVarsAndChangefeedInfo4Test()
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	clock := &mockClock{now: time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)}
+	manager.upstream.PDClock = clock
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		model.DefaultChangeFeedID(changefeedInfo.ID))
+	manager.state = state
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{
+			PauseSchedule: &config.PauseScheduleConfig{
+				PauseAt:  "0 1 * * *",
+				ResumeAt: "0 3 * * *",
+				TimeZone: "UTC",
+			},
+		}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+
+	// the pause time is not reached.
+	clock.now = time.Date(2024, 1, 1, 0, 59, 0, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+
+	// the changefeed is paused like a manual pause.
+	clock.now = time.Date(2024, 1, 1, 1, 0, 1, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.False(t, manager.ShouldRemoved())
+	require.Equal(t, model.StateStopped, state.Info.State)
+	require.Equal(t, model.AdminStop, state.Info.AdminJobType)
+	require.Equal(t, model.AdminStop, state.Status.AdminJobType)
+	require.True(t, state.Info.PausedBySchedule)
+
+	clock.now = time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+
+	// the changefeed is resumed.
+	clock.now = time.Date(2024, 1, 1, 3, 0, 1, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, model.AdminNone, state.Info.AdminJobType)
+	require.False(t, state.Info.PausedBySchedule)
+
+	// a failed changefeed is not resumed by the schedule.
+	clock.now = time.Date(2024, 1, 2, 1, 0, 1, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateStopped, state.Info.State)
+	manager.patchState(model.StateFailed)
+	tester.MustApplyPatches()
+	clock.now = time.Date(2024, 1, 2, 3, 0, 1, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.Equal(t, model.StateFailed, state.Info.State)
+
+	// a changefeed paused manually is not resumed by the schedule.
+	manager.PushAdminJob(&model.AdminJob{CfID: state.ID, Type: model.AdminResume})
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateNormal, state.Info.State)
+	clock.now = time.Date(2024, 1, 3, 0, 30, 0, 0, time.UTC)
+	manager.PushAdminJob(&model.AdminJob{CfID: state.ID, Type: model.AdminStop})
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateStopped, state.Info.State)
+	require.False(t, state.Info.PausedBySchedule)
+	clock.now = time.Date(2024, 1, 3, 3, 0, 1, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.Equal(t, model.StateStopped, state.Info.State)
+
+	// a scheduled resume fails if the checkpoint may be GCed.
+	manager.PushAdminJob(&model.AdminJob{CfID: state.ID, Type: model.AdminResume})
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	clock.now = time.Date(2024, 1, 4, 1, 0, 1, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateStopped, state.Info.State)
+	require.True(t, state.Info.PausedBySchedule)
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.CheckpointTs = 10
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.upstream.PDClient.(*mockPD).updateServiceGCSafePoint = func(safePoint uint64) (uint64, error) {
+		return safePoint, nil
+	}
+	clock.now = time.Date(2024, 1, 4, 3, 0, 1, 0, time.UTC)
+	manager.Tick(0, state.Status, state.Info)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.Equal(t, model.StateStopped, state.Info.State)
+	require.NotNil(t, state.Info.Error)
+	require.Equal(t, string(cerror.ErrStartTsBeforeGC.RFCCode()), state.Info.Error.Code)
+}
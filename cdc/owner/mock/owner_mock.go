@@ -12,6 +12,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/pingcap/tiflow/cdc/model"
 	owner "github.com/pingcap/tiflow/cdc/owner"
+	tablepb "github.com/pingcap/tiflow/cdc/processor/tablepb"
 	scheduler "github.com/pingcap/tiflow/cdc/scheduler"
 )
 
@@ -113,15 +114,15 @@ func (mr *MockOwnerMockRecorder) RebalanceTables(cfID, done interface{}) *gomock
 }
 
 // ScheduleTable mocks base method.
-func (m *MockOwner) ScheduleTable(cfID model.ChangeFeedID, toCapture model.CaptureID, tableID model.TableID, done chan<- error) {
+func (m *MockOwner) ScheduleTable(cfID model.ChangeFeedID, toCapture model.CaptureID, span tablepb.Span, done chan<- error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "ScheduleTable", cfID, toCapture, tableID, done)
+	m.ctrl.Call(m, "ScheduleTable", cfID, toCapture, span, done)
 }
 
 // ScheduleTable indicates an expected call of ScheduleTable.
-func (mr *MockOwnerMockRecorder) ScheduleTable(cfID, toCapture, tableID, done interface{}) *gomock.Call {
+func (mr *MockOwnerMockRecorder) ScheduleTable(cfID, toCapture, span, done interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleTable", reflect.TypeOf((*MockOwner)(nil).ScheduleTable), cfID, toCapture, tableID, done)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleTable", reflect.TypeOf((*MockOwner)(nil).ScheduleTable), cfID, toCapture, span, done)
 }
 
 // UpdateChangefeed mocks base method.
@@ -110,6 +110,21 @@ func (mr *MockStatusProviderMockRecorder) GetChangeFeedInfo(ctx, changefeedID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedInfo", reflect.TypeOf((*MockStatusProvider)(nil).GetChangeFeedInfo), ctx, changefeedID)
 }
 
+// GetChangeFeedLoadBalancePlan mocks base method.
+func (m *MockStatusProvider) GetChangeFeedLoadBalancePlan(ctx context.Context, changefeedID model.ChangeFeedID) (*model.LoadBalancePlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangeFeedLoadBalancePlan", ctx, changefeedID)
+	ret0, _ := ret[0].(*model.LoadBalancePlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeFeedLoadBalancePlan indicates an expected call of GetChangeFeedLoadBalancePlan.
+func (mr *MockStatusProviderMockRecorder) GetChangeFeedLoadBalancePlan(ctx, changefeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedLoadBalancePlan", reflect.TypeOf((*MockStatusProvider)(nil).GetChangeFeedLoadBalancePlan), ctx, changefeedID)
+}
+
 // GetChangeFeedStatus mocks base method.
 func (m *MockStatusProvider) GetChangeFeedStatus(ctx context.Context, changefeedID model.ChangeFeedID) (*model.ChangeFeedStatusForAPI, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +155,21 @@ func (mr *MockStatusProviderMockRecorder) GetChangeFeedSyncedStatus(ctx, changef
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedSyncedStatus", reflect.TypeOf((*MockStatusProvider)(nil).GetChangeFeedSyncedStatus), ctx, changefeedID)
 }
 
+// GetChangeFeedTableStatuses mocks base method.
+func (m *MockStatusProvider) GetChangeFeedTableStatuses(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.TableSpanStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangeFeedTableStatuses", ctx, changefeedID)
+	ret0, _ := ret[0].([]*model.TableSpanStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeFeedTableStatuses indicates an expected call of GetChangeFeedTableStatuses.
+func (mr *MockStatusProviderMockRecorder) GetChangeFeedTableStatuses(ctx, changefeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedTableStatuses", reflect.TypeOf((*MockStatusProvider)(nil).GetChangeFeedTableStatuses), ctx, changefeedID)
+}
+
 // GetProcessors mocks base method.
 func (m *MockStatusProvider) GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error) {
 	m.ctrl.T.Helper()
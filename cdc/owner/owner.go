@@ -24,12 +24,14 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/cdc/vars"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/tikv/client-go/v2/oracle"
@@ -61,7 +63,7 @@ type ownerJob struct {
 	// for ScheduleTable only
 	TargetCaptureID model.CaptureID
 	// for ScheduleTable only
-	TableID model.TableID
+	Span tablepb.Span
 
 	// for Admin Job only
 	AdminJob *model.AdminJob
@@ -86,7 +88,7 @@ type Owner interface {
 	RebalanceTables(cfID model.ChangeFeedID, done chan<- error)
 	ScheduleTable(
 		cfID model.ChangeFeedID, toCapture model.CaptureID,
-		tableID model.TableID, done chan<- error,
+		span tablepb.Span, done chan<- error,
 	)
 	DrainCapture(query *scheduler.Query, done chan<- error)
 	WriteDebugInfo(w io.Writer, done chan<- error)
@@ -224,7 +226,8 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 				up = o.upstreamManager.AddUpstream(upstreamInfo)
 			}
 			cfReactor = o.newChangefeed(changefeedID, changefeedState.Info, changefeedState.Status,
-				NewFeedStateManager(up, changefeedState),
+				NewFeedStateManager(up, changefeedState,
+					o.globalVars.EtcdClient.GetEnsureGCServiceID(gc.EnsureGCServiceResuming)),
 				up, o.cfg, o.globalVars)
 			o.changefeeds[changefeedID] = cfReactor
 		}
@@ -380,17 +383,17 @@ func (o *ownerImpl) RebalanceTables(cfID model.ChangeFeedID, done chan<- error)
 	})
 }
 
-// ScheduleTable moves a table from a capture to another capture
+// ScheduleTable moves a table span from a capture to another capture
 // `done` must be buffered to prevent blocking owner.
 func (o *ownerImpl) ScheduleTable(
-	cfID model.ChangeFeedID, toCapture model.CaptureID, tableID model.TableID,
+	cfID model.ChangeFeedID, toCapture model.CaptureID, span tablepb.Span,
 	done chan<- error,
 ) {
 	o.pushOwnerJob(&ownerJob{
 		Tp:              ownerJobTypeScheduleTable,
 		ChangefeedID:    cfID,
 		TargetCaptureID: toCapture,
-		TableID:         tableID,
+		Span:            span,
 		done:            done,
 	})
 }
@@ -616,7 +619,7 @@ func (o *ownerImpl) handleJobs(ctx context.Context) {
 		case ownerJobTypeScheduleTable:
 			// Scheduler is created lazily, it is nil before initialization.
 			if cfReactor.scheduler != nil {
-				cfReactor.scheduler.MoveTable(job.TableID, job.TargetCaptureID)
+				cfReactor.scheduler.MoveTable(job.Span, job.TargetCaptureID)
 			}
 		case ownerJobTypeDrainCapture:
 			o.handleDrainCaptures(ctx, job.scheduleQuery, job.done)
@@ -672,6 +675,10 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 		ret := &model.ChangeFeedStatusForAPI{}
 		ret.ResolvedTs = cfReactor.resolvedTs
 		ret.CheckpointTs = cfReactor.latestStatus.CheckpointTs
+		if cfReactor.redoMetaMgr != nil && cfReactor.redoMetaMgr.Enabled() {
+			ret.RedoLogSize = cfReactor.redoMetaMgr.GetLogSize()
+		}
+		ret.SnapshotBackfill = cfReactor.getSnapshotBackfillProgress()
 		query.Data = ret
 	case QueryChangeFeedSyncedStatus:
 		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
@@ -727,6 +734,45 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 			return errors.Trace(err)
 		}
 		query.Data = ret
+	case QueryChangeFeedTableStatuses:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+
+		provider := cfReactor.GetInfoProvider()
+		if provider == nil {
+			// The scheduler has not been initialized yet.
+			query.Data = make([]*model.TableSpanStatus, 0)
+			return nil
+		}
+
+		ret, err := provider.GetTableSpanStatuses()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		query.Data = ret
+	case QueryChangeFeedLoadBalancePlan:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+
+		provider := cfReactor.GetInfoProvider()
+		if provider == nil {
+			// The scheduler has not been initialized yet.
+			query.Data = &model.LoadBalancePlan{
+				Captures: make([]model.CaptureLoad, 0),
+				Moves:    make([]model.LoadBalanceMove, 0),
+			}
+			return nil
+		}
+
+		ret, err := provider.GetLoadBalancePlan()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		query.Data = ret
 	case QueryProcessors:
 		var ret []*model.ProcInfoSnap
 		for cfID, cfReactor := range o.changefeeds {
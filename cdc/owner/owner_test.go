@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/sink/observer"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -290,7 +291,7 @@ func TestAdminJob(t *testing.T) {
 	owner.RebalanceTables(model.DefaultChangeFeedID("test-changefeed2"), done2)
 	done3 := make(chan error, 1)
 	owner.ScheduleTable(model.DefaultChangeFeedID("test-changefeed3"),
-		"test-caputre1", 10, done3)
+		"test-caputre1", spanz.TableIDToComparableSpan(10), done3)
 	done4 := make(chan error, 1)
 	var buf bytes.Buffer
 	owner.WriteDebugInfo(&buf, done4)
@@ -317,7 +318,7 @@ func TestAdminJob(t *testing.T) {
 			Tp:              ownerJobTypeScheduleTable,
 			ChangefeedID:    model.DefaultChangeFeedID("test-changefeed3"),
 			TargetCaptureID: "test-caputre1",
-			TableID:         10,
+			Span:            spanz.TableIDToComparableSpan(10),
 		}, {
 			Tp:              ownerJobTypeDebugInfo,
 			debugInfoWriter: &buf,
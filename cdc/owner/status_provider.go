@@ -44,6 +44,14 @@ type StatusProvider interface {
 	// GetAllTaskStatuses returns the task statuses for the specified changefeed.
 	GetAllTaskStatuses(ctx context.Context, changefeedID model.ChangeFeedID) (map[model.CaptureID]*model.TaskStatus, error)
 
+	// GetChangeFeedTableStatuses returns the replication statuses of all
+	// table spans of the specified changefeed.
+	GetChangeFeedTableStatuses(ctx context.Context, changefeedID model.ChangeFeedID) ([]*model.TableSpanStatus, error)
+
+	// GetChangeFeedLoadBalancePlan returns the span moves planned by loads
+	// of the specified changefeed without scheduling them.
+	GetChangeFeedLoadBalancePlan(ctx context.Context, changefeedID model.ChangeFeedID) (*model.LoadBalancePlan, error)
+
 	// GetProcessors returns the statuses of all processors
 	GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error)
 
@@ -82,6 +90,12 @@ const (
 	QueryAllChangeFeedSCheckpointTs
 	// QueryExists is the type of query check if a changefeed exists
 	QueryExists
+	// QueryChangeFeedTableStatuses is the type of query the statuses of all
+	// table spans of a changefeed.
+	QueryChangeFeedTableStatuses
+	// QueryChangeFeedLoadBalancePlan is the type of query the span moves
+	// planned by loads of a changefeed.
+	QueryChangeFeedLoadBalancePlan
 )
 
 // Query wraps query command and return results.
@@ -162,6 +176,32 @@ func (p *ownerStatusProvider) GetAllTaskStatuses(ctx context.Context,
 	return query.Data.(map[model.CaptureID]*model.TaskStatus), nil
 }
 
+func (p *ownerStatusProvider) GetChangeFeedTableStatuses(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.TableSpanStatus, error) {
+	query := &Query{
+		Tp:           QueryChangeFeedTableStatuses,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.([]*model.TableSpanStatus), nil
+}
+
+func (p *ownerStatusProvider) GetChangeFeedLoadBalancePlan(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) (*model.LoadBalancePlan, error) {
+	query := &Query{
+		Tp:           QueryChangeFeedLoadBalancePlan,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.(*model.LoadBalancePlan), nil
+}
+
 func (p *ownerStatusProvider) GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error) {
 	query := &Query{
 		Tp: QueryProcessors,
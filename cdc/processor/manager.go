@@ -17,12 +17,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"runtime"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/priority"
+	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/vars"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -39,6 +42,9 @@ const (
 	commandTpUnknown commandTp = iota
 	commandTpWriteDebugInfo
 	processorLogsWarnDuration = 1 * time.Second
+	// sinkTaskSlotsPerCPU is the number of sink tasks that can run concurrently
+	// on each CPU, shared by all processors of the capture.
+	sinkTaskSlotsPerCPU = 4
 )
 
 type command struct {
@@ -80,6 +86,10 @@ type managerImpl struct {
 	) *processor
 	cfg        *config.SchedulerConfig
 	globalVars *vars.GlobalVars
+	// sharedPullers is shared by all processors of the capture.
+	sharedPullers *puller.SharedPullers
+	// priorityArbiter is shared by all processors of the capture.
+	priorityArbiter *priority.Arbiter
 
 	metricProcessorCloseDuration prometheus.Observer
 }
@@ -102,6 +112,8 @@ func NewManager(
 		metricProcessorCloseDuration: processorCloseDuration,
 		cfg:                          cfg,
 		globalVars:                   globalVars,
+		sharedPullers:                puller.NewSharedPullers(),
+		priorityArbiter:              priority.NewArbiter(runtime.GOMAXPROCS(0) * sinkTaskSlotsPerCPU),
 	}
 }
 
@@ -136,6 +148,8 @@ func (m *managerImpl) Tick(stdCtx context.Context, state orchestrator.ReactorSta
 				m.captureInfo, changefeedID, up, m.liveness,
 				currentChangefeedEpoch, &cfg, m.globalVars.EtcdClient,
 				m.globalVars)
+			p.sharedPullers = m.sharedPullers
+			p.priorityArbiter = m.priorityArbiter
 			m.processors[changefeedID] = p
 		}
 		if currentChangefeedEpoch != p.changefeedEpoch {
@@ -165,7 +179,9 @@ func (m *managerImpl) Tick(stdCtx context.Context, state orchestrator.ReactorSta
 			// patchProcessorErr have already patched its error to tell the owner
 			// manager can just close the processor and continue to tick other processors
 			m.closeProcessor(changefeedID)
+			continue
 		}
+		patchScannedSnapshotRanges(p.captureInfo, changefeedState, p.takeScannedSnapshotRanges())
 	}
 	// check if the processors in memory is leaked
 	if len(globalState.Changefeeds)-inactiveChangefeedCount != len(m.processors) {
@@ -277,6 +293,25 @@ func patchProcessorWarning(captureInfo *model.CaptureInfo,
 		})
 }
 
+// patchScannedSnapshotRanges reports the flushed snapshot ranges to the owner,
+// the owner takes them from the task position once they are merged.
+func patchScannedSnapshotRanges(captureInfo *model.CaptureInfo,
+	changefeed *orchestrator.ChangefeedReactorState, ranges []model.SnapshotRange,
+) {
+	if len(ranges) == 0 {
+		return
+	}
+	changefeed.PatchTaskPosition(captureInfo.ID,
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			if position == nil {
+				position = &model.TaskPosition{}
+			}
+			position.ScannedSnapshotRanges = model.MergeSnapshotRanges(
+				append(position.ScannedSnapshotRanges, ranges...))
+			return position, true, nil
+		})
+}
+
 func (m *managerImpl) closeProcessor(changefeedID model.ChangeFeedID) {
 	processor, exist := m.processors[changefeedID]
 	if exist {
@@ -28,6 +28,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// yieldQuotaDivisor limits the usable part of a yielded quota.
+const yieldQuotaDivisor = 4
+
 // MemConsumeRecord is used to trace memory usage.
 type MemConsumeRecord struct {
 	ResolvedTs model.ResolvedTs
@@ -39,6 +42,9 @@ type MemQuota struct {
 	changefeedID model.ChangeFeedID
 	// totalBytes is the total memory quota for one changefeed.
 	totalBytes uint64
+	// yield tells whether the quota should be yielded to changefeeds with
+	// higher priorities. It can be nil.
+	yield func() bool
 
 	// usedBytes is the memory usage of one changefeed.
 	usedBytes atomic.Uint64
@@ -102,11 +108,22 @@ func NewMemQuota(changefeedID model.ChangeFeedID, totalBytes uint64, comp string
 	return m
 }
 
+// SetYield sets a function to tell whether the quota should be yielded. If so,
+// TryAcquire can only use 1/yieldQuotaDivisor of the quota.
+// It must be called before the quota is used.
+func (m *MemQuota) SetYield(yield func() bool) {
+	m.yield = yield
+}
+
 // TryAcquire returns true if the memory quota is available, otherwise returns false.
 func (m *MemQuota) TryAcquire(nBytes uint64) bool {
+	totalBytes := m.totalBytes
+	if m.yield != nil && m.yield() {
+		totalBytes /= yieldQuotaDivisor
+	}
 	for {
 		usedBytes := m.usedBytes.Load()
-		if usedBytes+nBytes > m.totalBytes {
+		if usedBytes+nBytes > totalBytes {
 			return false
 		}
 		if m.usedBytes.CompareAndSwap(usedBytes, usedBytes+nBytes) {
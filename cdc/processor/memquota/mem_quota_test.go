@@ -34,6 +34,20 @@ func TestMemQuotaTryAcquire(t *testing.T) {
 	require.False(t, m.TryAcquire(1))
 }
 
+func TestMemQuotaTryAcquireYield(t *testing.T) {
+	t.Parallel()
+
+	m := NewMemQuota(model.DefaultChangeFeedID("1"), 100, "")
+	defer m.Close()
+	yield := true
+	m.SetYield(func() bool { return yield })
+
+	require.True(t, m.TryAcquire(25))
+	require.False(t, m.TryAcquire(1))
+	yield = false
+	require.True(t, m.TryAcquire(75))
+}
+
 func TestMemQuotaForceAcquire(t *testing.T) {
 	t.Parallel()
 
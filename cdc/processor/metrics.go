@@ -16,6 +16,7 @@ package processor
 import (
 	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -75,5 +76,6 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(processorCloseDuration)
 	registry.MustRegister(processorMemoryGauge)
 	sinkmanager.InitMetrics(registry)
+	sourcemanager.InitMetrics(registry)
 	memquota.InitMetrics(registry)
 }
@@ -0,0 +1,182 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priority
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"go.uber.org/zap"
+)
+
+// Arbiter arbitrates sink task slots among changefeeds on one capture.
+//
+// All changefeeds of the capture share a fixed number of slots, and every sink
+// task holds one slot until it finishes. A changefeed that fails to acquire a
+// slot becomes a waiter. A free slot is reserved for each waiter ahead of a
+// changefeed, i.e., waiters with higher priorities or waiters with the same
+// priority but slower tables, so that checkpoints of critical changefeeds are
+// never delayed by best-effort ones.
+type Arbiter struct {
+	mu      sync.Mutex
+	slots   int
+	used    int
+	waiters map[*Handle]model.Ts
+	// waiting counts waiters of each priority level.
+	waiting []atomic.Int64
+}
+
+// NewArbiter creates an Arbiter with the given number of slots.
+func NewArbiter(slots int) *Arbiter {
+	return &Arbiter{
+		slots:   slots,
+		waiters: make(map[*Handle]model.Ts),
+		waiting: make([]atomic.Int64, len(config.ChangefeedPriorities)),
+	}
+}
+
+// Register registers a changefeed into the arbiter.
+func (a *Arbiter) Register(changefeedID model.ChangeFeedID, priority config.ChangefeedPriority) *Handle {
+	log.Info("changefeed registers into priority arbiter",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("priority", string(priority)))
+	return &Handle{arbiter: a, changefeedID: changefeedID, level: priority.Level()}
+}
+
+// waitersAhead returns the number of waiters that should get slots before h.
+// It must be called with a.mu held.
+func (a *Arbiter) waitersAhead(h *Handle, slowestTs model.Ts) int {
+	ahead := 0
+	for waiter, ts := range a.waiters {
+		if waiter == h {
+			continue
+		}
+		if waiter.level > h.level || (waiter.level == h.level && ts < slowestTs) {
+			ahead++
+		}
+	}
+	return ahead
+}
+
+// wait records h as a waiter. It must be called with a.mu held.
+func (a *Arbiter) wait(h *Handle, slowestTs model.Ts) {
+	if _, ok := a.waiters[h]; !ok {
+		a.waiting[h.level].Add(1)
+	}
+	a.waiters[h] = slowestTs
+}
+
+// stopWaiting removes h from waiters. It must be called with a.mu held.
+func (a *Arbiter) stopWaiting(h *Handle) {
+	if _, ok := a.waiters[h]; ok {
+		a.waiting[h.level].Add(-1)
+		delete(a.waiters, h)
+	}
+}
+
+// Handle is the registration of a changefeed in an Arbiter.
+// A nil Handle always acquires slots and never yields.
+type Handle struct {
+	arbiter      *Arbiter
+	changefeedID model.ChangeFeedID
+	level        int
+
+	// Fields below are protected by arbiter.mu.
+	held   int
+	closed bool
+}
+
+// TryAcquire tries to acquire a slot for a sink task. slowestTs is the commit
+// ts of the slowest table of the changefeed. If it fails, the changefeed
+// waits for slots until it acquires one or calls Idle.
+func (h *Handle) TryAcquire(slowestTs model.Ts) bool {
+	if h == nil {
+		return true
+	}
+	a := h.arbiter
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	if a.slots-a.used <= a.waitersAhead(h, slowestTs) {
+		a.wait(h, slowestTs)
+		return false
+	}
+	a.used++
+	h.held++
+	a.stopWaiting(h)
+	return true
+}
+
+// Release releases a slot acquired by TryAcquire.
+func (h *Handle) Release() {
+	if h == nil {
+		return
+	}
+	a := h.arbiter
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if h.closed || h.held == 0 {
+		return
+	}
+	a.used--
+	h.held--
+}
+
+// Idle tells the arbiter that the changefeed doesn't wait for slots.
+func (h *Handle) Idle() {
+	if h == nil {
+		return
+	}
+	h.arbiter.mu.Lock()
+	defer h.arbiter.mu.Unlock()
+	h.arbiter.stopWaiting(h)
+}
+
+// ShouldYield returns true if any changefeed with a higher priority is
+// waiting for slots.
+func (h *Handle) ShouldYield() bool {
+	if h == nil {
+		return false
+	}
+	for level := h.level + 1; level < len(h.arbiter.waiting); level++ {
+		if h.arbiter.waiting[level].Load() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Close unregisters the changefeed from the arbiter, and releases all its
+// slots.
+func (h *Handle) Close() {
+	if h == nil {
+		return
+	}
+	a := h.arbiter
+	a.mu.Lock()
+	a.used -= h.held
+	h.held = 0
+	h.closed = true
+	a.stopWaiting(h)
+	a.mu.Unlock()
+	log.Info("changefeed unregisters from priority arbiter",
+		zap.String("namespace", h.changefeedID.Namespace),
+		zap.String("changefeed", h.changefeedID.ID))
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priority
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArbiter(t *testing.T) {
+	t.Parallel()
+
+	a := NewArbiter(2)
+	critical := a.Register(model.DefaultChangeFeedID("critical"), config.ChangefeedPriorityCritical)
+	normal := a.Register(model.DefaultChangeFeedID("normal"), config.ChangefeedPriorityNormal)
+	bestEffort := a.Register(model.DefaultChangeFeedID("best-effort"), config.ChangefeedPriorityBestEffort)
+
+	require.True(t, bestEffort.TryAcquire(10))
+	require.True(t, bestEffort.TryAcquire(10))
+	require.False(t, bestEffort.ShouldYield())
+
+	// Only changefeeds with lower priorities yield to waiters.
+	require.False(t, normal.TryAcquire(10))
+	require.True(t, bestEffort.ShouldYield())
+	require.False(t, normal.ShouldYield())
+	require.False(t, critical.ShouldYield())
+
+	// The released slot is reserved for the waiter.
+	bestEffort.Release()
+	require.False(t, bestEffort.TryAcquire(10))
+	require.True(t, normal.TryAcquire(10))
+	require.False(t, bestEffort.ShouldYield())
+
+	// Waiters with slower tables go first among the same priority.
+	bestEffort.Idle()
+	require.False(t, critical.TryAcquire(10))
+	other := a.Register(model.DefaultChangeFeedID("other"), config.ChangefeedPriorityCritical)
+	require.False(t, other.TryAcquire(5))
+	require.True(t, normal.ShouldYield())
+	bestEffort.Release()
+	require.False(t, critical.TryAcquire(10))
+	require.True(t, other.TryAcquire(5))
+	require.True(t, normal.ShouldYield())
+	critical.Idle()
+	require.False(t, normal.ShouldYield())
+
+	// Closing a changefeed releases all its slots.
+	require.False(t, normal.TryAcquire(10))
+	other.Close()
+	require.True(t, normal.TryAcquire(10))
+	normal.Close()
+	require.True(t, critical.TryAcquire(10))
+	require.True(t, critical.TryAcquire(10))
+	require.False(t, critical.TryAcquire(10))
+	critical.Close()
+	require.False(t, bestEffort.ShouldYield())
+	require.False(t, critical.TryAcquire(10))
+
+	var h *Handle
+	require.True(t, h.TryAcquire(10))
+	require.False(t, h.ShouldYield())
+	h.Release()
+	h.Idle()
+	h.Close()
+}
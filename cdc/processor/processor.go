@@ -25,11 +25,14 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tiflow/cdc/async"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/priority"
 	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/redo"
@@ -91,8 +94,14 @@ type processor struct {
 	redo component[redo.DMLManager]
 
 	sourceManager component[*sourcemanager.SourceManager]
+	// sharedPullers is used by sourceManager if shared puller is enabled.
+	sharedPullers *puller.SharedPullers
 
 	sinkManager component[*sinkmanager.SinkManager]
+	// priorityArbiter is used to arbitrate resources among changefeeds of the
+	// capture, priority is the registration of the changefeed.
+	priorityArbiter *priority.Arbiter
+	priority        *priority.Handle
 
 	initialized *atomic.Bool
 	initializer *async.Initializer
@@ -223,16 +232,61 @@ func (p *processor) AddTableSpan(
 			zap.Bool("isPrepare", isPrepare))
 	}
 
+	ranges, err := p.unscannedSnapshotRanges(span)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
 	table := p.sinkManager.r.AddTable(
 		span, startTs, p.latestInfo.TargetTs)
 	if p.redo.r.Enabled() {
 		p.redo.r.AddTable(span, startTs)
 	}
 
-	p.sourceManager.r.AddTable(span, p.getTableName(ctx, span.TableID), startTs, table.GetReplicaTs)
+	tableName := p.getTableName(ctx, span.TableID)
+	if len(ranges) > 0 {
+		log.Info("table will be backfilled from its snapshot",
+			zap.String("captureID", p.captureInfo.ID),
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Uint64("startTs", startTs),
+			zap.Int("ranges", len(ranges)))
+		p.sourceManager.r.AddTableWithSnapshot(span, tableName, startTs, table.GetReplicaTs, ranges)
+		return true, nil
+	}
+	p.sourceManager.r.AddTable(span, tableName, startTs, table.GetReplicaTs)
 	return true, nil
 }
 
+// unscannedSnapshotRanges returns the record key ranges of the span whose
+// snapshots are not flushed yet, if the table needs a snapshot backfill.
+func (p *processor) unscannedSnapshotRanges(span tablepb.Span) ([]model.SnapshotRange, error) {
+	backfill := p.latestInfo.SnapshotBackfill
+	if !backfill.ShouldBackfill(span.TableID) {
+		return nil, nil
+	}
+	_, startKey, err := codec.DecodeBytes(span.StartKey, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	_, endKey, err := codec.DecodeBytes(span.EndKey, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return backfill.UnscannedRanges(span.TableID, startKey, endKey), nil
+}
+
+// takeScannedSnapshotRanges returns the snapshot ranges flushed to the sink
+// since the last call.
+func (p *processor) takeScannedSnapshotRanges() []model.SnapshotRange {
+	if !p.initialized.Load() {
+		return nil
+	}
+	return p.sourceManager.r.TakeScannedSnapshotRanges(func(span tablepb.Span) model.Ts {
+		return p.sinkManager.r.GetTableStats(span).CheckpointTs
+	})
+}
+
 // RemoveTableSpan implements TableExecutor interface.
 func (p *processor) RemoveTableSpan(span tablepb.Span) bool {
 	if !p.checkReadyForMessages() {
@@ -389,9 +443,11 @@ func (p *processor) getStatsFromSourceManagerAndSinkManager(
 	now := p.upstream.PDClock.CurrentTime()
 
 	stats := tablepb.Stats{
-		RegionCount: pullerStats.RegionCount,
-		CurrentTs:   oracle.ComposeTS(oracle.GetPhysical(now), 0),
-		BarrierTs:   sinkStats.BarrierTs,
+		RegionCount:        pullerStats.RegionCount,
+		CurrentTs:          oracle.ComposeTS(oracle.GetPhysical(now), 0),
+		BarrierTs:          sinkStats.BarrierTs,
+		SinkRowsPerSecond:  sinkStats.RowsPerSecond,
+		SinkBytesPerSecond: sinkStats.BytesPerSecond,
 		StageCheckpoints: map[string]tablepb.Checkpoint{
 			"puller-ingress": {
 				CheckpointTs: pullerStats.CheckpointTsIngress,
@@ -648,7 +704,10 @@ func (p *processor) lazyInitImpl(etcdCtx context.Context) (err error) {
 	p.redo.changefeedID = p.changefeedID
 	p.redo.spawn(prcCtx)
 
-	sortEngine, err := p.globalVars.SortEngineFactory.Create(p.changefeedID)
+	// The sort engine may take a part of the memory quota of the changefeed
+	// to sort events in memory, and the sink manager uses the rest.
+	sorterQuota := p.globalVars.SortEngineFactory.MemoryQuota(cfConfig.MemoryQuota)
+	sortEngine, err := p.globalVars.SortEngineFactory.Create(p.changefeedID, cfConfig.MemoryQuota)
 	log.Info("Processor creates sort engine",
 		zap.String("namespace", p.changefeedID.Namespace),
 		zap.String("changefeed", p.changefeedID.ID),
@@ -656,6 +715,16 @@ func (p *processor) lazyInitImpl(etcdCtx context.Context) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if quota := util.GetOrZero(cfConfig.SortDiskQuota); quota > 0 {
+		if engine, ok := sortEngine.(sorter.DiskQuotaEngine); ok {
+			engine.SetDiskQuota(quota)
+		} else {
+			log.Warn("Sort engine doesn't support disk quota, ignore it",
+				zap.String("namespace", p.changefeedID.Namespace),
+				zap.String("changefeed", p.changefeedID.ID),
+				zap.Uint64("quota", quota))
+		}
+	}
 
 	isMysqlBackend, err := isMysqlCompatibleBackend(p.latestInfo.SinkURI)
 	if err != nil {
@@ -665,14 +734,18 @@ func (p *processor) lazyInitImpl(etcdCtx context.Context) (err error) {
 		p.changefeedID, p.upstream, p.mg.r,
 		sortEngine, util.GetOrZero(cfConfig.BDRMode),
 		util.GetOrZero(cfConfig.EnableTableMonitor),
-		isMysqlBackend)
+		isMysqlBackend, p.sharedPullers)
 	p.sourceManager.name = "SourceManager"
 	p.sourceManager.changefeedID = p.changefeedID
 	p.sourceManager.spawn(prcCtx)
 
+	if p.priorityArbiter != nil {
+		p.priority = p.priorityArbiter.Register(p.changefeedID, cfConfig.GetPriority())
+	}
 	p.sinkManager.r = sinkmanager.New(
-		p.changefeedID, p.latestInfo.SinkURI, cfConfig, p.upstream,
-		p.ddlHandler.r.schemaStorage, p.redo.r, p.sourceManager.r, isMysqlBackend)
+		p.changefeedID, p.latestInfo.SinkURI, cfConfig, sorterQuota, p.upstream,
+		p.ddlHandler.r.schemaStorage, p.redo.r, p.sourceManager.r, isMysqlBackend,
+		p.priority)
 	p.sinkManager.name = "SinkManager"
 	p.sinkManager.changefeedID = p.changefeedID
 	p.sinkManager.spawn(prcCtx)
@@ -875,6 +948,8 @@ func (p *processor) Close() error {
 
 	p.sinkManager.stop()
 	p.sinkManager.r = nil
+	p.priority.Close()
+	p.priority = nil
 	p.sourceManager.stop()
 	p.sourceManager.r = nil
 	p.redo.stop()
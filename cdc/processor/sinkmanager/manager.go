@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/memquota"
+	"github.com/pingcap/tiflow/cdc/processor/priority"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -61,6 +62,9 @@ type TableStats struct {
 	ResolvedTs   model.Ts
 	LastSyncedTs model.Ts
 	BarrierTs    model.Ts
+	// RowsPerSecond and BytesPerSecond are the throughput of the table sink.
+	RowsPerSecond  uint64
+	BytesPerSecond uint64
 }
 
 // SinkManager is the implementation of SinkManager.
@@ -115,6 +119,9 @@ type SinkManager struct {
 	redoWorkerAvailable chan struct{}
 	// redoMemQuota is used to control the total memory usage of the redo.
 	redoMemQuota *memquota.MemQuota
+	// priority is used to yield resources to changefeeds with higher priorities
+	// on the same capture. It can be nil.
+	priority *priority.Handle
 
 	// To control lifetime of all sub-goroutines.
 	managerCtx    context.Context
@@ -137,16 +144,19 @@ type SinkManager struct {
 	metricsTableSinkFlushLagDuration prometheus.Observer
 }
 
-// New creates a new sink manager.
+// New creates a new sink manager. sorterMemoryQuota is the part of the memory
+// quota of the changefeed taken by the sort engine.
 func New(
 	changefeedID model.ChangeFeedID,
 	sinkURI string,
 	config *pconfig.ReplicaConfig,
+	sorterMemoryQuota uint64,
 	up *upstream.Upstream,
 	schemaStorage entry.SchemaStorage,
 	redoDMLMgr redo.DMLManager,
 	sourceManager *sourcemanager.SourceManager,
 	isMysqlBackend bool,
+	priorityHandle *priority.Handle,
 ) *SinkManager {
 	m := &SinkManager{
 		changefeedID:        changefeedID,
@@ -161,6 +171,7 @@ func New(
 		sinkWorkerAvailable: make(chan struct{}, 1),
 		sinkRetry:           retry.NewInfiniteErrorRetry(),
 		isMysqlBackend:      isMysqlBackend,
+		priority:            priorityHandle,
 		metricsTableSinkTotalRows: tablesinkmetrics.TotalRowsCountCounter.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 
@@ -168,7 +179,7 @@ func New(
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 	}
 
-	totalQuota := config.MemoryQuota
+	totalQuota := config.MemoryQuota - sorterMemoryQuota
 	if redoDMLMgr != nil && redoDMLMgr.Enabled() {
 		m.redoDMLMgr = redoDMLMgr
 		m.redoProgressHeap = newTableProgresses()
@@ -189,6 +200,12 @@ func New(
 		m.sinkMemQuota = memquota.NewMemQuota(changefeedID, totalQuota, "sink")
 		m.redoMemQuota = memquota.NewMemQuota(changefeedID, 0, "redo")
 	}
+	if priorityHandle != nil {
+		// A yielded changefeed fetches less data in each sink task, so that
+		// its tasks finish sooner and release slots to waiting changefeeds
+		// with higher priorities.
+		m.sinkMemQuota.SetYield(priorityHandle.ShouldYield)
+	}
 
 	m.ready = make(chan struct{})
 	return m
@@ -276,6 +293,15 @@ func (m *SinkManager) Run(ctx context.Context, warnings ...chan<- error) (err er
 		case err = <-redoErrors:
 			return errors.Trace(err)
 		case err = <-sinkFactoryErrors:
+			// Transactions written to the dead letter queue don't break the
+			// sink, just report them as warnings.
+			if cerror.ErrDeadLetterQueueWritten.Equal(err) {
+				select {
+				case <-m.managerCtx.Done():
+				case warnings[0] <- err:
+				}
+				continue
+			}
 			log.Warn("Sink manager backend sink fails",
 				zap.String("namespace", m.changefeedID.Namespace),
 				zap.String("changefeed", m.changefeedID.ID),
@@ -508,6 +534,15 @@ func (m *SinkManager) getUpperBound(tableSinkUpperBoundTs model.Ts) sorter.Posit
 // generateSinkTasks generates tasks to fetch data from the source manager.
 func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 	dispatchTasks := func() error {
+		// Sink task slots are shared by all changefeeds of the capture, and
+		// the slowest table decides the order among changefeeds with the
+		// same priority.
+		var slowestTs model.Ts
+		if slowest := m.sinkProgressHeap.peek(); slowest != nil {
+			slowestTs = slowest.nextLowerBoundPos.CommitTs
+		}
+		waitingSlot := false
+
 		tables := make([]*tableSinkWrapper, 0, sinkWorkerNum)
 		progs := make([]*progress, 0, sinkWorkerNum)
 
@@ -603,8 +638,15 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 				continue
 			}
 
+			// No available slot, skip this round directly.
+			if !m.priority.TryAcquire(slowestTs) {
+				waitingSlot = true
+				break LOOP
+			}
+
 			// No available memory, skip this round directly.
 			if !m.sinkMemQuota.TryAcquire(requestMemSize) {
+				m.priority.Release()
 				break LOOP
 			}
 
@@ -620,6 +662,7 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 				getUpperBound: m.getUpperBound,
 				tableSink:     tableSink,
 				callback: func(lastWrittenPos sorter.Position) {
+					m.priority.Release()
 					p := &progress{
 						span:              tableSink.span,
 						nextLowerBoundPos: lastWrittenPos.Next(),
@@ -646,6 +689,7 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 					zap.Any("lowerBound", lowerBound),
 					zap.Any("currentUpperBound", upperBound))
 			default:
+				m.priority.Release()
 				m.sinkMemQuota.Refund(requestMemSize)
 				log.Debug("MemoryQuotaTracing: refund memory for table sink task",
 					zap.String("namespace", m.changefeedID.Namespace),
@@ -655,6 +699,9 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 				break LOOP
 			}
 		}
+		if !waitingSlot {
+			m.priority.Idle()
+		}
 		// Some progresses are not handled, return them back.
 		for ; i < len(progs); i++ {
 			m.sinkProgressHeap.push(progs[i])
@@ -1059,11 +1106,14 @@ func (m *SinkManager) GetTableStats(span tablepb.Span) TableStats {
 			zap.Uint64("upperbound", sinkUpperBound),
 			zap.Any("checkpointTs", checkpointTs))
 	}
+	rowsPerSecond, bytesPerSecond := tableSink.getThroughput(time.Now())
 	return TableStats{
-		CheckpointTs: checkpointTs.ResolvedMark(),
-		ResolvedTs:   resolvedTs,
-		LastSyncedTs: lastSyncedTs,
-		BarrierTs:    tableSink.barrierTs.Load(),
+		CheckpointTs:   checkpointTs.ResolvedMark(),
+		ResolvedTs:     resolvedTs,
+		LastSyncedTs:   lastSyncedTs,
+		BarrierTs:      tableSink.barrierTs.Load(),
+		RowsPerSecond:  rowsPerSecond,
+		BytesPerSecond: bytesPerSecond,
 	}
 }
 
@@ -93,6 +93,17 @@ func (p *tableProgresses) pop() *progress {
 	return heap.Pop(p.heap).(*progress)
 }
 
+// peek returns the slowest progress without removing it, or nil if there is
+// no progress.
+func (p *tableProgresses) peek() *progress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.heap.Len() == 0 {
+		return nil
+	}
+	return p.heap.heap[0]
+}
+
 func (p *tableProgresses) len() int {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -48,6 +48,8 @@ func TestTableProgresses(t *testing.T) {
 	})
 
 	require.Equal(t, p.len(), 3)
+	require.Equal(t, spanz.TableIDToComparableSpan(1), p.peek().span)
+	require.Equal(t, p.len(), 3, "peek doesn't remove the progress")
 
 	pg := p.pop()
 	require.Equal(t, spanz.TableIDToComparableSpan(1), pg.span, "table1 is the slowest table")
@@ -57,4 +59,5 @@ func TestTableProgresses(t *testing.T) {
 	require.Equal(t, spanz.TableIDToComparableSpan(2), pg.span, "table3 is the slowest table")
 
 	require.Equal(t, p.len(), 0, "all tables are popped")
+	require.Nil(t, p.peek())
 }
@@ -37,6 +37,10 @@ import (
 
 var tableSinkWrapperVersion uint64 = 0
 
+// throughputSampleInterval is the minimal interval between two samples
+// of the table sink throughput.
+const throughputSampleInterval = 5 * time.Second
+
 // tableSinkWrapper is a wrapper of TableSink, it is used in SinkManager to manage TableSink.
 // Because in the SinkManager, we write data to TableSink and RedoManager concurrently,
 // so current sink node can not be reused.
@@ -89,6 +93,19 @@ type tableSinkWrapper struct {
 	// events in the range (rangeEventCounts[i-1].lastPos, rangeEventCounts[i].lastPos].
 	rangeEventCounts   []rangeEventCount
 	rangeEventCountsMu sync.Mutex
+
+	// writtenRows and writtenBytes are the total rows and bytes appended
+	// to the table sink. They are used to calculate the throughput.
+	writtenRows  atomic.Uint64
+	writtenBytes atomic.Uint64
+	throughput   struct {
+		sync.Mutex
+		sampled        time.Time
+		rows           uint64
+		bytes          uint64
+		rowsPerSecond  uint64
+		bytesPerSecond uint64
+	}
 }
 
 // GetReplicaTs returns the replicate ts of the table sink.
@@ -135,6 +152,7 @@ func newTableSinkWrapper(
 	res.tableSink.checkpointTs = model.NewResolvedTs(startTs)
 	res.tableSink.resolvedTs = model.NewResolvedTs(startTs)
 	res.tableSink.advanced = time.Now()
+	res.throughput.sampled = time.Now()
 
 	res.receivedSorterResolvedTs.Store(startTs)
 	res.barrierTs.Store(startTs)
@@ -192,9 +210,36 @@ func (t *tableSinkWrapper) appendRowChangedEvents(events ...*model.RowChangedEve
 		return tablesink.NewSinkInternalError(errors.New("table sink cleared"))
 	}
 	t.tableSink.s.AppendRowChangedEvents(events...)
+
+	var size int
+	for _, e := range events {
+		size += e.ApproximateBytes()
+	}
+	t.writtenRows.Add(uint64(len(events)))
+	t.writtenBytes.Add(uint64(size))
 	return nil
 }
 
+// getThroughput returns the rows and bytes written to the table sink per
+// second. The throughput is re-sampled at most once per
+// throughputSampleInterval, otherwise the last sampled values are returned.
+func (t *tableSinkWrapper) getThroughput(now time.Time) (rowsPerSecond, bytesPerSecond uint64) {
+	t.throughput.Lock()
+	defer t.throughput.Unlock()
+
+	elapsed := now.Sub(t.throughput.sampled)
+	if elapsed < throughputSampleInterval {
+		return t.throughput.rowsPerSecond, t.throughput.bytesPerSecond
+	}
+	rows, bytes := t.writtenRows.Load(), t.writtenBytes.Load()
+	t.throughput.rowsPerSecond = uint64(float64(rows-t.throughput.rows) / elapsed.Seconds())
+	t.throughput.bytesPerSecond = uint64(float64(bytes-t.throughput.bytes) / elapsed.Seconds())
+	t.throughput.sampled = now
+	t.throughput.rows = rows
+	t.throughput.bytes = bytes
+	return t.throughput.rowsPerSecond, t.throughput.bytesPerSecond
+}
+
 func (t *tableSinkWrapper) updateBarrierTs(ts model.Ts) {
 	util.MustCompareAndMonotonicIncrease(&t.barrierTs, ts)
 }
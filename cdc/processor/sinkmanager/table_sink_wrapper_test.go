@@ -375,3 +375,41 @@ func TestTableSinkWrapperSinkInner(t *testing.T) {
 	isStuck, _ = wrapper.sinkMaybeStuck(100 * time.Millisecond)
 	require.True(t, isStuck)
 }
+
+func TestTableSinkWrapperGetThroughput(t *testing.T) {
+	t.Parallel()
+
+	wrapper, _ := createTableSinkWrapper(
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1))
+	start := wrapper.throughput.sampled
+
+	events := []*model.RowChangedEvent{
+		{CommitTs: 1, PhysicalTableID: 1},
+		{CommitTs: 2, PhysicalTableID: 1},
+	}
+	size := uint64(0)
+	for _, e := range events {
+		size += uint64(e.ApproximateBytes())
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, wrapper.appendRowChangedEvents(events...))
+	}
+
+	// Not re-sampled within the sample interval.
+	rows, bytes := wrapper.getThroughput(start.Add(time.Second))
+	require.Equal(t, uint64(0), rows)
+	require.Equal(t, uint64(0), bytes)
+
+	rows, bytes = wrapper.getThroughput(start.Add(throughputSampleInterval))
+	require.Equal(t, uint64(10)/uint64(throughputSampleInterval.Seconds()), rows)
+	require.Equal(t, 5*size/uint64(throughputSampleInterval.Seconds()), bytes)
+
+	// The last sampled throughput is kept until the next sample.
+	rows, bytes = wrapper.getThroughput(start.Add(throughputSampleInterval + time.Second))
+	require.Equal(t, uint64(10)/uint64(throughputSampleInterval.Seconds()), rows)
+	require.Equal(t, 5*size/uint64(throughputSampleInterval.Seconds()), bytes)
+
+	rows, bytes = wrapper.getThroughput(start.Add(2 * throughputSampleInterval))
+	require.Equal(t, uint64(0), rows)
+	require.Equal(t, uint64(0), bytes)
+}
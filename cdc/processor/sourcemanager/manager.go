@@ -14,10 +14,16 @@
 package sourcemanager
 
 import (
+	"bytes"
 	"context"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	tidbkv "github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/kv/sharedconn"
@@ -27,13 +33,55 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/txnutil"
 	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
-const defaultMaxBatchSize = 256
+const (
+	defaultMaxBatchSize = 256
+	// snapshotBatchRows is the max number of rows in a snapshot batch, rows
+	// of a batch are read at the same ts and committed in one transaction.
+	snapshotBatchRows = 10240
+	// snapshotScanConcurrency is the max number of tables whose snapshots
+	// are scanned at the same time.
+	snapshotScanConcurrency = 4
+	// diskQuotaCheckInterval is the interval to report the disk usage of the engine.
+	diskQuotaCheckInterval = 10 * time.Second
+	// throttleCheckInterval is the interval to check whether paused spans
+	// can be resumed.
+	throttleCheckInterval = time.Second
+)
+
+// snapshotScan is a snapshot scan of a table.
+type snapshotScan struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu sync.Mutex
+	// fenceTs holds back the resolved ts sent to the engine, so that a batch
+	// being scanned is committed after all the resolved ts of the span.
+	fenceTs model.Ts
+	// pullerResolvedTs is the last resolved ts from the puller.
+	pullerResolvedTs model.Ts
+	// resolvedTs is the last resolved ts sent to the engine.
+	resolvedTs model.Ts
+	// batches are the scanned batches whose ranges are not taken yet.
+	batches []snapshotBatch
+	// finished is true once all ranges are scanned.
+	finished bool
+}
+
+// snapshotBatch is a range of the snapshot committed at commitTs.
+type snapshotBatch struct {
+	model.SnapshotRange
+	commitTs model.Ts
+}
 
 // SourceManager is the manager of the source engine and puller.
 type SourceManager struct {
@@ -48,6 +96,20 @@ type SourceManager struct {
 	mg entry.MounterGroup
 	// engine is the source engine.
 	engine sorter.SortEngine
+	// diskQuota is not nil if the engine can limit its disk usage.
+	diskQuota sorter.DiskQuotaEngine
+	// throttle pauses spans if the engine runs out of its disk quota.
+	throttle struct {
+		sync.Mutex
+		spans      *spanz.HashMap[*spanThrottle]
+		tableNames *spanz.HashMap[string]
+		// count is the number of throttled spans, it's checked without
+		// the lock when events are added.
+		count  atomic.Int64
+		notify chan struct{}
+	}
+	// subscriptionMu serializes pausing and resuming spans with RemoveTable.
+	subscriptionMu sync.Mutex
 	// Used to indicate whether the changefeed is in BDR mode.
 	bdrMode bool
 
@@ -55,6 +117,35 @@ type SourceManager struct {
 
 	enableTableMonitor bool
 	puller             *puller.MultiplexingPuller
+
+	// shared is the shared puller of the capture, it's nil if the changefeed
+	// pulls spans by itself. If it's not nil, puller is only used to catch up
+	// spans before they join shared streams.
+	sharedPullers *puller.SharedPullers
+	shared        *puller.SharedPuller
+	// pullerMu serializes subscribing and unsubscribing spans if shared is used.
+	pullerMu   sync.Mutex
+	sharedMu   sync.RWMutex
+	sharedSubs *spanz.HashMap[*sharedSubscription]
+	// caughtUp collects subscriptions whose catch up pullers can be stopped,
+	// and subscriptions detached from shared streams.
+	caughtUp struct {
+		sync.Mutex
+		subs     []*sharedSubscription
+		detached []*sharedSubscription
+		notify   chan struct{}
+	}
+
+	// snapshotCtx is canceled when the source manager is closed,
+	// it stops all in-flight snapshot scans.
+	snapshotCtx    context.Context
+	snapshotCancel context.CancelFunc
+	snapshotWg     sync.WaitGroup
+	snapshotTokens chan struct{}
+	snapshotMu     sync.RWMutex
+	snapshotScans  *spanz.HashMap[*snapshotScan]
+	// errCh is used to report snapshot scan errors to Run.
+	errCh chan error
 }
 
 // New creates a new source manager.
@@ -66,8 +157,9 @@ func New(
 	bdrMode bool,
 	enableTableMonitor bool,
 	safeModeAtStart bool,
+	sharedPullers *puller.SharedPullers,
 ) *SourceManager {
-	return newSourceManager(changefeedID, up, mg, engine, bdrMode, enableTableMonitor, safeModeAtStart)
+	return newSourceManager(changefeedID, up, mg, engine, bdrMode, enableTableMonitor, safeModeAtStart, sharedPullers)
 }
 
 // NewForTest creates a new source manager for testing.
@@ -78,7 +170,7 @@ func NewForTest(
 	engine sorter.SortEngine,
 	bdrMode bool,
 ) *SourceManager {
-	return &SourceManager{
+	mgr := &SourceManager{
 		ready:        make(chan struct{}),
 		changefeedID: changefeedID,
 		up:           up,
@@ -86,6 +178,16 @@ func NewForTest(
 		engine:       engine,
 		bdrMode:      bdrMode,
 	}
+	mgr.initSnapshotScan()
+	mgr.initThrottle()
+	return mgr
+}
+
+func (m *SourceManager) initSnapshotScan() {
+	m.snapshotCtx, m.snapshotCancel = context.WithCancel(context.Background())
+	m.snapshotTokens = make(chan struct{}, snapshotScanConcurrency)
+	m.snapshotScans = spanz.NewHashMap[*snapshotScan]()
+	m.errCh = make(chan error, 1)
 }
 
 func isOldUpdateKVEntry(raw *model.RawKVEntry, getReplicaTs func() model.Ts) bool {
@@ -100,6 +202,7 @@ func newSourceManager(
 	bdrMode bool,
 	enableTableMonitor bool,
 	safeModeAtStart bool,
+	sharedPullers *puller.SharedPullers,
 ) *SourceManager {
 	mgr := &SourceManager{
 		ready:              make(chan struct{}),
@@ -111,6 +214,8 @@ func newSourceManager(
 		enableTableMonitor: enableTableMonitor,
 		safeModeAtStart:    safeModeAtStart,
 	}
+	mgr.initSnapshotScan()
+	mgr.initThrottle()
 
 	serverConfig := config.GetGlobalServerConfig()
 	grpcPool := sharedconn.NewConnAndClientPool(mgr.up.SecurityConfig, kv.GetGlobalGrpcMetrics())
@@ -122,27 +227,19 @@ func newSourceManager(
 
 	// consume add raw kv entry to the engine.
 	// It will be called by the puller when new raw kv entry is received.
-	consume := func(ctx context.Context, raw *model.RawKVEntry, spans []tablepb.Span, shouldSplitKVEntry model.ShouldSplitKVEntry) error {
+	consume := func(_ context.Context, raw *model.RawKVEntry, spans []tablepb.Span, shouldSplitKVEntry model.ShouldSplitKVEntry) error {
 		if len(spans) > 1 {
 			log.Panic("DML puller subscribes multiple spans",
 				zap.String("namespace", mgr.changefeedID.Namespace),
 				zap.String("changefeed", mgr.changefeedID.ID))
 		}
-		if raw != nil {
-			if shouldSplitKVEntry(raw) {
-				deleteKVEntry, insertKVEntry, err := model.SplitUpdateKVEntry(raw)
-				if err != nil {
-					return err
-				}
-				deleteEvent := model.NewPolymorphicEvent(deleteKVEntry)
-				insertEvent := model.NewPolymorphicEvent(insertKVEntry)
-				mgr.engine.Add(spans[0], deleteEvent, insertEvent)
-			} else {
-				pEvent := model.NewPolymorphicEvent(raw)
-				mgr.engine.Add(spans[0], pEvent)
-			}
+		if raw == nil {
+			return nil
 		}
-		return nil
+		if mgr.shared != nil {
+			return mgr.consumeCatchUp(spans[0], raw)
+		}
+		return mgr.add(spans[0], raw, shouldSplitKVEntry)
 	}
 	slots, hasher := mgr.engine.SlotsAndHasher()
 
@@ -155,27 +252,361 @@ func newSourceManager(
 		hasher,
 		int(serverConfig.KVClient.FrontierConcurrent))
 
+	if sharedPullers != nil && serverConfig.KVClient.EnableSharedPuller {
+		mgr.sharedPullers = sharedPullers
+		mgr.shared = sharedPullers.Acquire(up, bdrMode, slots, hasher)
+		mgr.sharedSubs = spanz.NewHashMap[*sharedSubscription]()
+		mgr.caughtUp.notify = make(chan struct{}, 1)
+	}
 	return mgr
 }
 
+// add adds a raw kv entry to the engine.
+func (m *SourceManager) add(
+	span tablepb.Span, raw *model.RawKVEntry, shouldSplitKVEntry model.ShouldSplitKVEntry,
+) error {
+	if raw.OpType == model.OpTypeResolved {
+		m.addResolved(span, raw.CRTs)
+		return nil
+	}
+	if m.shouldThrottle() && m.throttleEvent(span, raw, shouldSplitKVEntry) {
+		return nil
+	}
+	if shouldSplitKVEntry(raw) {
+		deleteKVEntry, insertKVEntry, err := model.SplitUpdateKVEntry(raw)
+		if err != nil {
+			return err
+		}
+		deleteEvent := model.NewPolymorphicEvent(deleteKVEntry)
+		insertEvent := model.NewPolymorphicEvent(insertKVEntry)
+		m.engine.Add(span, deleteEvent, insertEvent)
+	} else {
+		pEvent := model.NewPolymorphicEvent(raw)
+		m.engine.Add(span, pEvent)
+	}
+	return nil
+}
+
+func (m *SourceManager) waitDiskQuota(ctx context.Context) error {
+	if m.diskQuota == nil {
+		return nil
+	}
+	return m.diskQuota.WaitDiskQuota(ctx)
+}
+
 // AddTable adds a table to the source manager. Start puller and register table to the engine.
 func (m *SourceManager) AddTable(span tablepb.Span, tableName string, startTs model.Ts, getReplicaTs func() model.Ts) {
 	// Add table to the engine first, so that the engine can receive the events from the puller.
 	m.engine.AddTable(span, startTs)
+	m.subscribe(span, tableName, startTs, getReplicaTs)
+}
+
+// AddTableWithSnapshot adds a table to the source manager like AddTable, and
+// also scans the rows of the table in the given record key ranges. The ranges
+// are scanned in batches along with the puller, every batch is read at a fresh
+// ts and committed at the ts as one transaction, so that it overwrites the
+// incremental changes committed before.
+func (m *SourceManager) AddTableWithSnapshot(
+	span tablepb.Span, tableName string, startTs model.Ts, getReplicaTs func() model.Ts,
+	ranges []model.SnapshotRange,
+) {
+	m.engine.AddTable(span, startTs)
+
+	ctx, cancel := context.WithCancel(m.snapshotCtx)
+	scan := &snapshotScan{
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		fenceTs:    math.MaxUint64,
+		resolvedTs: startTs,
+	}
+	m.snapshotMu.Lock()
+	m.snapshotScans.ReplaceOrInsert(span, scan)
+	m.snapshotMu.Unlock()
+	m.subscribe(span, tableName, startTs, getReplicaTs)
+
+	m.snapshotWg.Add(1)
+	go func() {
+		defer m.snapshotWg.Done()
+		defer close(scan.done)
+		defer cancel()
+
+		err := m.scanSnapshot(ctx, span, scan, ranges)
+		// The scan is canceled if the table is removed or the manager is closed.
+		if err != nil && ctx.Err() == nil {
+			select {
+			case m.errCh <- err:
+			default:
+			}
+		}
+	}()
+}
 
+func (m *SourceManager) subscribe(
+	span tablepb.Span, tableName string, startTs model.Ts, getReplicaTs func() model.Ts,
+) {
 	shouldSplitKVEntry := func(raw *model.RawKVEntry) bool {
 		return m.safeModeAtStart && isOldUpdateKVEntry(raw, getReplicaTs)
 	}
+	if m.diskQuota != nil {
+		m.throttle.Lock()
+		m.throttle.tableNames.ReplaceOrInsert(span, tableName)
+		m.throttle.Unlock()
+	}
+	m.subscribeSpan(span, tableName, startTs, shouldSplitKVEntry)
+}
 
+func (m *SourceManager) subscribeSpan(
+	span tablepb.Span, tableName string, startTs model.Ts, shouldSplitKVEntry model.ShouldSplitKVEntry,
+) {
+	if m.shared != nil {
+		m.subscribeShared(span, tableName, startTs, shouldSplitKVEntry)
+		return
+	}
 	// Only nil in unit tests.
 	if m.puller != nil {
 		m.puller.Subscribe([]tablepb.Span{span}, startTs, tableName, shouldSplitKVEntry)
 	}
 }
 
+func (m *SourceManager) unsubscribeSpan(span tablepb.Span) {
+	if m.shared != nil {
+		m.unsubscribeShared(span)
+		return
+	}
+	// Only nil in unit tests.
+	if m.puller != nil {
+		m.puller.Unsubscribe([]tablepb.Span{span})
+	}
+}
+
+// scanSnapshot scans the ranges of the span batch by batch.
+func (m *SourceManager) scanSnapshot(
+	ctx context.Context, span tablepb.Span, scan *snapshotScan, ranges []model.SnapshotRange,
+) error {
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case m.snapshotTokens <- struct{}{}:
+	}
+	defer func() { <-m.snapshotTokens }()
+
+	gauge := snapshotBackfillTableGauge.
+		WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+	gauge.Inc()
+	defer gauge.Dec()
+
+	log.Info("start to scan table snapshot",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Int("ranges", len(ranges)))
+	start := time.Now()
+
+	rows := 0
+	for _, r := range ranges {
+		next := r.StartKey
+		for bytes.Compare(next, r.EndKey) < 0 {
+			n, end, err := m.scanSnapshotBatch(ctx, span, scan, r.TableID, next, r.EndKey)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			rows += n
+			next = end
+		}
+	}
+	scan.mu.Lock()
+	scan.finished = true
+	scan.mu.Unlock()
+
+	log.Info("table snapshot scanned",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Int("rows", rows),
+		zap.Duration("cost", time.Since(start)))
+	return nil
+}
+
+// scanSnapshotBatch reads at most snapshotBatchRows rows from startKey at a
+// fresh ts, and adds them to the engine as a transaction committed at the ts.
+// The rows are added as deletes followed by inserts, so that the rows already
+// replicated from incremental changes are overwritten. It returns the number
+// of rows and the key the next batch starts from.
+func (m *SourceManager) scanSnapshotBatch(
+	ctx context.Context, span tablepb.Span, scan *snapshotScan,
+	tableID model.TableID, startKey, endKey []byte,
+) (int, []byte, error) {
+	// The scan has its own goroutine, so it can simply wait for sinks to
+	// clean events if the engine runs out of its disk quota.
+	if err := m.waitDiskQuota(ctx); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	// Hold back the resolved ts before getting the commit ts, so that no
+	// resolved ts sent to the engine can exceed the commit ts.
+	scan.mu.Lock()
+	scan.fenceTs = scan.resolvedTs
+	scan.mu.Unlock()
+	physical, logical, err := m.up.PDClient.GetTS(ctx)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	commitTs := oracle.ComposeTS(physical, logical)
+	scan.mu.Lock()
+	scan.fenceTs = commitTs - 1
+	m.advanceSnapshotScan(span, scan)
+	scan.mu.Unlock()
+
+	snap := m.up.KVStorage.GetSnapshot(tidbkv.NewVersion(commitTs))
+	iter, err := snap.Iter(startKey, endKey)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	defer iter.Close()
+
+	rowCounter := snapshotBackfillRowCount.
+		WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+	rows := 0
+	nextKey := endKey
+	events := make([]*model.PolymorphicEvent, 0, defaultMaxBatchSize)
+	flush := func() {
+		if len(events) > 0 {
+			m.engine.Add(span, events...)
+			events = events[:0]
+		}
+	}
+	for iter.Valid() {
+		if rows >= snapshotBatchRows {
+			nextKey = append([]byte{}, iter.Key()...)
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, nil, errors.Trace(err)
+		}
+		value := append([]byte{}, iter.Value()...)
+		deleteKVEntry, insertKVEntry, err := model.SplitUpdateKVEntry(&model.RawKVEntry{
+			OpType:   model.OpTypePut,
+			Key:      append([]byte{}, iter.Key()...),
+			Value:    value,
+			OldValue: value,
+			StartTs:  commitTs - 1,
+			CRTs:     commitTs,
+		})
+		if err != nil {
+			return 0, nil, errors.Trace(err)
+		}
+		events = append(events,
+			model.NewPolymorphicEvent(deleteKVEntry), model.NewPolymorphicEvent(insertKVEntry))
+		if len(events) >= defaultMaxBatchSize {
+			flush()
+		}
+		rows++
+		if err := iter.Next(); err != nil {
+			return 0, nil, errors.Trace(err)
+		}
+	}
+	flush()
+	rowCounter.Add(float64(rows))
+
+	scan.mu.Lock()
+	defer scan.mu.Unlock()
+	scan.batches = append(scan.batches, snapshotBatch{
+		SnapshotRange: model.SnapshotRange{TableID: tableID, StartKey: startKey, EndKey: nextKey},
+		commitTs:      commitTs,
+	})
+	scan.fenceTs = math.MaxUint64
+	m.advanceSnapshotScan(span, scan)
+	return rows, nextKey, nil
+}
+
+// addResolved sends the resolved ts of the span to the engine. The resolved
+// ts is held back while a snapshot batch of the span is being scanned.
+func (m *SourceManager) addResolved(span tablepb.Span, resolvedTs model.Ts) {
+	if m.throttle.count.Load() > 0 {
+		resolvedTs = m.throttleResolved(span, resolvedTs)
+	}
+	m.snapshotMu.RLock()
+	scan, ok := m.snapshotScans.Get(span)
+	m.snapshotMu.RUnlock()
+	if !ok {
+		m.engine.Add(span, model.NewResolvedPolymorphicEvent(0, resolvedTs))
+		return
+	}
+	scan.mu.Lock()
+	defer scan.mu.Unlock()
+	if resolvedTs > scan.pullerResolvedTs {
+		scan.pullerResolvedTs = resolvedTs
+	}
+	m.advanceSnapshotScan(span, scan)
+}
+
+// advanceSnapshotScan sends the resolved ts from the puller to the engine
+// up to the fence of the scan. It must be called with scan.mu held.
+func (m *SourceManager) advanceSnapshotScan(span tablepb.Span, scan *snapshotScan) {
+	resolvedTs := scan.pullerResolvedTs
+	if resolvedTs > scan.fenceTs {
+		resolvedTs = scan.fenceTs
+	}
+	if resolvedTs > scan.resolvedTs {
+		scan.resolvedTs = resolvedTs
+		m.engine.Add(span, model.NewResolvedPolymorphicEvent(0, resolvedTs))
+	}
+}
+
+// TakeScannedSnapshotRanges returns the ranges of the snapshot batches which
+// are flushed to the sink, that is committed no later than the checkpoint of
+// their tables. A range is returned only once.
+func (m *SourceManager) TakeScannedSnapshotRanges(
+	getCheckpointTs func(tablepb.Span) model.Ts,
+) []model.SnapshotRange {
+	m.snapshotMu.Lock()
+	defer m.snapshotMu.Unlock()
+
+	var ranges []model.SnapshotRange
+	var finished []tablepb.Span
+	m.snapshotScans.Range(func(span tablepb.Span, scan *snapshotScan) bool {
+		checkpointTs := getCheckpointTs(span)
+		scan.mu.Lock()
+		defer scan.mu.Unlock()
+		pending := scan.batches[:0]
+		for _, batch := range scan.batches {
+			if batch.commitTs <= checkpointTs {
+				ranges = append(ranges, batch.SnapshotRange)
+			} else {
+				pending = append(pending, batch)
+			}
+		}
+		scan.batches = pending
+		if scan.finished && len(scan.batches) == 0 {
+			finished = append(finished, span)
+		}
+		return true
+	})
+	// Resolved ts of the finished spans no longer need to be held back.
+	for _, span := range finished {
+		m.snapshotScans.Delete(span)
+	}
+	return model.MergeSnapshotRanges(ranges)
+}
+
 // RemoveTable removes a table from the source manager. Stop puller and unregister table from the engine.
 func (m *SourceManager) RemoveTable(span tablepb.Span) {
-	m.puller.Unsubscribe([]tablepb.Span{span})
+	m.subscriptionMu.Lock()
+	// A paused span is unsubscribed already.
+	if !m.isSpanPaused(span) {
+		m.unsubscribeSpan(span)
+	}
+	m.removeThrottle(span)
+	m.subscriptionMu.Unlock()
+
+	m.snapshotMu.Lock()
+	scan, scanning := m.snapshotScans.Get(span)
+	m.snapshotScans.Delete(span)
+	m.snapshotMu.Unlock()
+	if scanning {
+		scan.cancel()
+		// Wait for the scan to stop adding events into the engine.
+		<-scan.done
+	}
 	m.engine.RemoveTable(span)
 }
 
@@ -200,6 +631,9 @@ func (m *SourceManager) CleanByTable(span tablepb.Span, upperBound sorter.Positi
 
 // GetTablePullerStats returns the puller stats of the table.
 func (m *SourceManager) GetTablePullerStats(span tablepb.Span) puller.Stats {
+	if m.shared != nil {
+		return m.sharedPullerStats(span)
+	}
 	return m.puller.Stats(span)
 }
 
@@ -209,13 +643,71 @@ func (m *SourceManager) GetTableSorterStats(span tablepb.Span) sorter.TableStats
 }
 
 // Run implements util.Runnable.
-func (m *SourceManager) Run(ctx context.Context, _ ...chan<- error) error {
+func (m *SourceManager) Run(ctx context.Context, warnings ...chan<- error) error {
 	close(m.ready)
 	// Only nil in unit tests.
 	if m.puller == nil {
 		return nil
 	}
-	return m.puller.Run(ctx)
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return m.puller.Run(ctx)
+	})
+	if m.shared != nil {
+		g.Go(func() error {
+			return m.runShared(ctx)
+		})
+	}
+	if m.diskQuota != nil {
+		g.Go(func() error {
+			return m.checkDiskQuota(ctx, warnings...)
+		})
+		g.Go(func() error {
+			return m.runThrottle(ctx)
+		})
+	}
+	g.Go(func() error {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case err := <-m.errCh:
+			return err
+		}
+	})
+	return g.Wait()
+}
+
+// checkDiskQuota updates the disk usage metric periodically, and reports
+// a warning if the engine exceeds its disk quota.
+func (m *SourceManager) checkDiskQuota(ctx context.Context, warnings ...chan<- error) error {
+	diskUsage := sorter.DiskUsage().WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+	defer sorter.DiskUsage().DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+
+	ticker := time.NewTicker(diskQuotaCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+		usage, quota := m.diskQuota.DiskUsage()
+		diskUsage.Set(float64(usage))
+		if quota == 0 || usage <= quota {
+			continue
+		}
+		log.Warn("Sort engine exceeds the disk quota",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Uint64("usage", usage),
+			zap.Uint64("quota", quota))
+		if len(warnings) > 0 {
+			select {
+			case warnings[0] <- cerror.ErrSorterDiskQuotaExceeded.GenWithStackByArgs(usage, quota):
+			default:
+			}
+		}
+	}
 }
 
 // WaitForReady implements util.Runnable.
@@ -235,6 +727,12 @@ func (m *SourceManager) Close() {
 
 	start := time.Now()
 
+	m.snapshotCancel()
+	m.snapshotWg.Wait()
+	if m.shared != nil {
+		m.closeShared()
+	}
+
 	log.Info("All pullers have been closed",
 		zap.String("namespace", m.changefeedID.Namespace),
 		zap.String("changefeed", m.changefeedID.ID),
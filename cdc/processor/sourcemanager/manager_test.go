@@ -0,0 +1,113 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourcemanager
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/hybrid"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/memory"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+// diskQuotaEngine is a sort engine whose disk quota can be exceeded manually.
+type diskQuotaEngine struct {
+	*hybrid.EventSorter
+	exceeded atomic.Bool
+}
+
+func (e *diskQuotaEngine) DiskQuotaExceeded() bool {
+	return e.exceeded.Load()
+}
+
+func TestSourceManagerThrottle(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	engine := &diskQuotaEngine{
+		EventSorter: hybrid.New(changefeedID, memory.New(context.Background()), 1024),
+	}
+	m := NewForTest(changefeedID, nil, nil, engine, false)
+	defer m.Close()
+	span := spanz.TableIDToComparableSpan(1)
+	m.AddTable(span, "t", 1, func() model.Ts { return 0 })
+	resolved := make(chan model.Ts, 8)
+	m.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolved <- ts })
+
+	add := func(commitTs model.Ts) {
+		raw := &model.RawKVEntry{
+			OpType:  model.OpTypePut,
+			Key:     []byte("key"),
+			Value:   []byte("value"),
+			StartTs: commitTs - 1,
+			CRTs:    commitTs,
+		}
+		require.Nil(t, m.add(span, raw, func(*model.RawKVEntry) bool { return false }))
+	}
+
+	add(5)
+	// The span is throttled at the max commit ts received by the engine,
+	// events committed after it are dropped without blocking the caller.
+	engine.exceeded.Store(true)
+	add(7)
+	add(4)
+	m.addResolved(span, 6)
+	require.Equal(t, model.Ts(5), <-resolved)
+
+	// The span is paused after it's resolved to the pause ts, and it's
+	// resumed after the engine has disk quota again.
+	m.pauseAndResumeSpans()
+	require.True(t, m.isSpanPaused(span))
+	m.pauseAndResumeSpans()
+	require.True(t, m.isSpanPaused(span))
+	engine.exceeded.Store(false)
+	m.pauseAndResumeSpans()
+	require.False(t, m.isSpanPaused(span))
+	require.Equal(t, int64(0), m.throttle.count.Load())
+
+	// The dropped event is pulled again after the span is resubscribed
+	// from the pause ts.
+	add(7)
+	m.addResolved(span, 8)
+	require.Equal(t, model.Ts(8), <-resolved)
+
+	iter := m.engine.FetchByTable(span, sorter.Position{}, sorter.GenCommitFence(8))
+	var commitTs []model.Ts
+	for {
+		event, _, err := iter.Next()
+		require.Nil(t, err)
+		if event == nil {
+			break
+		}
+		commitTs = append(commitTs, event.CRTs)
+	}
+	require.Nil(t, iter.Close())
+	require.Equal(t, []model.Ts{4, 5, 7}, commitTs)
+
+	// A paused span can be removed. It's throttled at the max resolved ts
+	// received by the engine, which is larger than the max commit ts.
+	engine.exceeded.Store(true)
+	add(9)
+	m.addResolved(span, 10)
+	m.pauseAndResumeSpans()
+	require.True(t, m.isSpanPaused(span))
+	m.RemoveTable(span)
+	require.Equal(t, int64(0), m.throttle.count.Load())
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourcemanager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// snapshotBackfillRowCount is the metric that counts rows scanned
+	// by snapshot backfill.
+	snapshotBackfillRowCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "sourcemanager",
+		Name:      "snapshot_backfill_row_count",
+		Help:      "The number of rows scanned by snapshot backfill",
+	}, []string{"namespace", "changefeed"})
+
+	// snapshotBackfillTableGauge is the number of tables being backfilled.
+	snapshotBackfillTableGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sourcemanager",
+		Name:      "snapshot_backfill_table_count",
+		Help:      "The number of tables being scanned by snapshot backfill",
+	}, []string{"namespace", "changefeed"})
+)
+
+// InitMetrics registers all metrics in this file.
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(snapshotBackfillRowCount)
+	registry.MustRegister(snapshotBackfillTableGauge)
+}
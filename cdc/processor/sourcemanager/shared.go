@@ -0,0 +1,340 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourcemanager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/puller"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+// sharedSubscription is a span subscribed from the shared puller.
+// If the shared stream of the span starts before the changefeed needs,
+// KV entries committed in (startTs, handoffTs] are caught up by the own
+// puller of the changefeed before it fully joins the shared stream.
+// If the changefeed falls too far behind the shared stream, it's detached
+// from the stream and pulls the span by its own puller since then.
+type sharedSubscription struct {
+	id                 puller.SharedConsumerID
+	span               tablepb.Span
+	tableName          string
+	startTs            model.Ts
+	handoffTs          model.Ts
+	shouldSplitKVEntry model.ShouldSplitKVEntry
+
+	mu sync.Mutex
+	// catchingUp is true until the catch up puller resolves handoffTs.
+	catchingUp bool
+	// catchUpSubscribed is true until the span is unsubscribed from
+	// the catch up puller.
+	catchUpSubscribed bool
+	catchUpResolvedTs model.Ts
+	sharedResolvedTs  model.Ts
+	// detached is true once the subscription is detached from the shared
+	// stream, and pulling is true once the own puller pulls the span after
+	// that. Entries from the catch up puller in between are dropped.
+	detached bool
+	pulling  bool
+	// resolvedTs is the last resolved ts sent to the engine.
+	resolvedTs model.Ts
+}
+
+func (m *SourceManager) subscribeShared(
+	span tablepb.Span, tableName string, startTs model.Ts, shouldSplitKVEntry model.ShouldSplitKVEntry,
+) {
+	m.pullerMu.Lock()
+	defer m.pullerMu.Unlock()
+
+	sub := &sharedSubscription{
+		span:               span,
+		tableName:          tableName,
+		startTs:            startTs,
+		shouldSplitKVEntry: shouldSplitKVEntry,
+		resolvedTs:         startTs,
+	}
+	m.sharedMu.Lock()
+	m.sharedSubs.ReplaceOrInsert(span, sub)
+	m.sharedMu.Unlock()
+
+	// Hold the lock so that events from the shared stream are handled
+	// after the subscription is set up.
+	sub.mu.Lock()
+	sub.id, sub.handoffTs = m.shared.Subscribe(span, startTs, tableName, func(raw *model.RawKVEntry) {
+		m.consumeShared(sub, raw)
+	}, func() {
+		m.detachShared(sub)
+	})
+	sub.catchingUp = sub.handoffTs > startTs
+	sub.catchUpSubscribed = sub.catchingUp
+	sub.mu.Unlock()
+
+	if sub.catchingUp {
+		log.Info("span catches up before joining the shared stream",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.String("tableName", tableName),
+			zap.Stringer("span", &span),
+			zap.Uint64("startTs", startTs),
+			zap.Uint64("handoffTs", sub.handoffTs))
+		m.puller.Subscribe([]tablepb.Span{span}, startTs, tableName, shouldSplitKVEntry)
+	}
+}
+
+func (m *SourceManager) unsubscribeShared(span tablepb.Span) {
+	m.pullerMu.Lock()
+	defer m.pullerMu.Unlock()
+
+	m.sharedMu.Lock()
+	sub, ok := m.sharedSubs.Get(span)
+	m.sharedSubs.Delete(span)
+	m.sharedMu.Unlock()
+	if !ok {
+		return
+	}
+
+	m.shared.Unsubscribe(span, sub.id)
+	if sub.stopCatchUp() {
+		m.puller.Unsubscribe([]tablepb.Span{span})
+	}
+}
+
+// consumeShared handles an event from the shared stream.
+func (m *SourceManager) consumeShared(sub *sharedSubscription, raw *model.RawKVEntry) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if raw.OpType == model.OpTypeResolved {
+		sub.sharedResolvedTs = raw.CRTs
+		m.advanceShared(sub)
+		return
+	}
+	if err := m.add(sub.span, raw, sub.shouldSplitKVEntry); err != nil {
+		select {
+		case m.errCh <- err:
+		default:
+		}
+	}
+}
+
+// consumeCatchUp handles an event from the catch up puller.
+func (m *SourceManager) consumeCatchUp(span tablepb.Span, raw *model.RawKVEntry) error {
+	m.sharedMu.RLock()
+	sub, ok := m.sharedSubs.Get(span)
+	m.sharedMu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	sub.mu.Lock()
+	if sub.detached {
+		defer sub.mu.Unlock()
+		if !sub.pulling {
+			return nil
+		}
+		if raw.OpType == model.OpTypeResolved {
+			if raw.CRTs > sub.resolvedTs {
+				sub.resolvedTs = raw.CRTs
+				m.addResolved(span, raw.CRTs)
+			}
+			return nil
+		}
+		return m.add(span, raw, sub.shouldSplitKVEntry)
+	}
+	if !sub.catchingUp {
+		sub.mu.Unlock()
+		return nil
+	}
+	if raw.OpType != model.OpTypeResolved {
+		defer sub.mu.Unlock()
+		// Entries committed after handoffTs come from the shared stream.
+		if raw.CRTs > sub.handoffTs {
+			return nil
+		}
+		return m.add(span, raw, sub.shouldSplitKVEntry)
+	}
+	sub.catchUpResolvedTs = raw.CRTs
+	sub.catchingUp = raw.CRTs < sub.handoffTs
+	m.advanceShared(sub)
+	caughtUp := !sub.catchingUp
+	sub.mu.Unlock()
+
+	if caughtUp {
+		// The span can't be unsubscribed in the callback of the puller,
+		// so it's done in runShared.
+		m.caughtUp.Lock()
+		m.caughtUp.subs = append(m.caughtUp.subs, sub)
+		m.caughtUp.Unlock()
+		select {
+		case m.caughtUp.notify <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// detachShared is called after the subscription is detached from the shared
+// stream. The span is pulled by the own puller in runShared.
+func (m *SourceManager) detachShared(sub *sharedSubscription) {
+	sub.mu.Lock()
+	sub.detached = true
+	sub.catchingUp = false
+	sub.mu.Unlock()
+
+	m.caughtUp.Lock()
+	m.caughtUp.detached = append(m.caughtUp.detached, sub)
+	m.caughtUp.Unlock()
+	select {
+	case m.caughtUp.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pullDetached pulls the span of a detached subscription by the own puller,
+// from the last resolved ts sent to the engine. Entries committed after the
+// resolved ts may be sent to the engine again, like the ones resent after a
+// region is reconnected. It must be called with m.pullerMu held.
+func (m *SourceManager) pullDetached(sub *sharedSubscription) {
+	m.sharedMu.RLock()
+	current, ok := m.sharedSubs.Get(sub.span)
+	m.sharedMu.RUnlock()
+	if !ok || current != sub {
+		// The span is already unsubscribed.
+		return
+	}
+
+	m.shared.Unsubscribe(sub.span, sub.id)
+	if sub.stopCatchUp() {
+		m.puller.Unsubscribe([]tablepb.Span{sub.span})
+	}
+	sub.mu.Lock()
+	startTs := sub.resolvedTs
+	sub.pulling = true
+	sub.catchUpSubscribed = true
+	sub.mu.Unlock()
+	m.puller.Subscribe([]tablepb.Span{sub.span}, startTs, sub.tableName, sub.shouldSplitKVEntry)
+	log.Warn("span is detached from the shared stream, pull it by the changefeed itself",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Stringer("span", &sub.span),
+		zap.Uint64("startTs", startTs))
+}
+
+// advanceShared sends the resolved ts of the subscription to the engine.
+// It must be called with sub.mu held.
+func (m *SourceManager) advanceShared(sub *sharedSubscription) {
+	// Entries committed before handoffTs come from the catch up puller, and
+	// later ones come from the shared stream.
+	resolvedTs := sub.sharedResolvedTs
+	if sub.catchingUp && sub.catchUpResolvedTs < resolvedTs {
+		resolvedTs = sub.catchUpResolvedTs
+	}
+	if resolvedTs > sub.resolvedTs {
+		sub.resolvedTs = resolvedTs
+		m.addResolved(sub.span, resolvedTs)
+	}
+}
+
+// stopCatchUp returns true if the catch up puller of the subscription
+// should be unsubscribed by the caller.
+func (sub *sharedSubscription) stopCatchUp() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	subscribed := sub.catchUpSubscribed
+	sub.catchingUp = false
+	sub.catchUpSubscribed = false
+	return subscribed
+}
+
+func (m *SourceManager) runShared(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-m.shared.Done():
+			return errors.Trace(m.shared.Err())
+		case <-m.caughtUp.notify:
+			m.caughtUp.Lock()
+			subs := m.caughtUp.subs
+			detached := m.caughtUp.detached
+			m.caughtUp.subs = nil
+			m.caughtUp.detached = nil
+			m.caughtUp.Unlock()
+
+			m.pullerMu.Lock()
+			for _, sub := range detached {
+				m.pullDetached(sub)
+			}
+			for _, sub := range subs {
+				if sub.isDetached() {
+					continue
+				}
+				if sub.stopCatchUp() {
+					m.puller.Unsubscribe([]tablepb.Span{sub.span})
+					log.Info("span joins the shared stream",
+						zap.String("namespace", m.changefeedID.Namespace),
+						zap.String("changefeed", m.changefeedID.ID),
+						zap.Stringer("span", &sub.span),
+						zap.Uint64("handoffTs", sub.handoffTs))
+				}
+			}
+			m.pullerMu.Unlock()
+		}
+	}
+}
+
+func (sub *sharedSubscription) isDetached() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.detached
+}
+
+func (m *SourceManager) sharedPullerStats(span tablepb.Span) puller.Stats {
+	m.sharedMu.RLock()
+	sub, ok := m.sharedSubs.Get(span)
+	m.sharedMu.RUnlock()
+	if !ok {
+		return puller.Stats{}
+	}
+	sub.mu.Lock()
+	ownPuller := sub.catchingUp || sub.pulling
+	sub.mu.Unlock()
+	if ownPuller {
+		return m.puller.Stats(span)
+	}
+	return m.shared.Stats(span)
+}
+
+// closeShared removes all subscriptions from the shared puller and
+// releases it.
+func (m *SourceManager) closeShared() {
+	m.sharedMu.Lock()
+	subs := make([]*sharedSubscription, 0, m.sharedSubs.Len())
+	m.sharedSubs.Range(func(_ tablepb.Span, sub *sharedSubscription) bool {
+		subs = append(subs, sub)
+		return true
+	})
+	m.sharedSubs = spanz.NewHashMap[*sharedSubscription]()
+	m.sharedMu.Unlock()
+
+	for _, sub := range subs {
+		m.shared.Unsubscribe(sub.span, sub.id)
+	}
+	m.sharedPullers.Release(m.shared)
+}
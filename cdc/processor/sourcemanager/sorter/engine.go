@@ -14,6 +14,8 @@
 package sorter
 
 import (
+	"context"
+
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 )
@@ -82,6 +84,28 @@ type SortEngine interface {
 	SlotsAndHasher() (slotCount int, hasher func(tablepb.Span, int) int)
 }
 
+// DiskQuotaEngine is a SortEngine which can limit the disk space used by
+// one changefeed. It's optional, callers should check it by type assertion.
+type DiskQuotaEngine interface {
+	// SetDiskQuota sets the disk quota in bytes. 0 means unlimited.
+	SetDiskQuota(quota uint64)
+
+	// DiskUsage returns the estimated disk usage and the quota in bytes.
+	DiskUsage() (usage uint64, quota uint64)
+
+	// DiskQuotaExceeded returns true if the disk usage exceeds the quota.
+	//
+	// NOTE: it returns false if there are no resolved events which can be
+	// cleaned, otherwise no one can make progress.
+	DiskQuotaExceeded() bool
+
+	// WaitDiskQuota blocks until the disk usage drops below the quota.
+	//
+	// NOTE: it won't block if there are no resolved events which can be
+	// cleaned, otherwise no one can make progress.
+	WaitDiskQuota(ctx context.Context) error
+}
+
 // EventIterator is an iterator to fetch events from SortEngine.
 // It's unnecessary to be thread-safe.
 type EventIterator interface {
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/hybrid"
 	epebble "github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/pebble"
 	"github.com/pingcap/tiflow/pkg/config"
 	"go.uber.org/atomic"
@@ -35,6 +36,8 @@ type sortEngineType int
 const (
 	// pebbleEngine details are in package document of pkg/sorter/pebble.
 	pebbleEngine sortEngineType = iota + 1
+	// hybridEngine details are in package document of pkg/sorter/hybrid.
+	hybridEngine
 
 	metricsCollectInterval = 15 * time.Second
 )
@@ -51,6 +54,9 @@ type SortEngineFactory struct {
 	engineType      sortEngineType
 	dir             string
 	memQuotaInBytes uint64
+	// memoryPercentage is the percentage of the memory quota of a changefeed
+	// used by its hybrid engine.
+	memoryPercentage uint64
 
 	mu      sync.Mutex
 	engines map[model.ChangeFeedID]sorter.SortEngine
@@ -58,7 +64,7 @@ type SortEngineFactory struct {
 	wg     sync.WaitGroup
 	closed chan struct{}
 
-	// Following fields are valid if engineType is pebbleEngine.
+	// Following fields are valid if engineType is pebbleEngine or hybridEngine.
 	pebbleConfig *config.DBConfig
 	dbs          []*pebble.DB
 	cache        *pebble.Cache
@@ -68,14 +74,25 @@ type SortEngineFactory struct {
 	dbInitialized *atomic.Bool
 }
 
-// Create creates a SortEngine. If an engine with same ID already exists,
-// it will be returned directly.
-func (f *SortEngineFactory) Create(ID model.ChangeFeedID) (e sorter.SortEngine, err error) {
+// MemoryQuota returns the bytes taken by the engine of a changefeed from its
+// memory quota, which is not 0 only if events are sorted in memory.
+func (f *SortEngineFactory) MemoryQuota(changefeedQuota uint64) uint64 {
+	if f.engineType != hybridEngine {
+		return 0
+	}
+	return changefeedQuota * f.memoryPercentage / 100
+}
+
+// Create creates a SortEngine, memoryQuota is the changefeed's memory quota.
+// If an engine with same ID already exists, it will be returned directly.
+func (f *SortEngineFactory) Create(
+	ID model.ChangeFeedID, memoryQuota uint64,
+) (e sorter.SortEngine, err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	switch f.engineType {
-	case pebbleEngine:
+	case pebbleEngine, hybridEngine:
 		exists := false
 		if e, exists = f.engines[ID]; exists {
 			return e, nil
@@ -88,6 +105,9 @@ func (f *SortEngineFactory) Create(ID model.ChangeFeedID) (e sorter.SortEngine,
 			f.dbInitialized.Store(true)
 		}
 		e = epebble.New(ID, f.dbs)
+		if f.engineType == hybridEngine {
+			e = hybrid.New(ID, e, f.MemoryQuota(memoryQuota))
+		}
 		f.engines[ID] = e
 	default:
 		log.Panic("not implemented")
@@ -134,17 +154,34 @@ func (f *SortEngineFactory) Close() (err error) {
 
 // NewForPebble will create a SortEngineFactory for the pebble implementation.
 func NewForPebble(dir string, memQuotaInBytes uint64, cfg *config.DBConfig) *SortEngineFactory {
+	return newForPebbleDBs(pebbleEngine, dir, memQuotaInBytes, 0, cfg)
+}
+
+// NewForHybrid will create a SortEngineFactory for the hybrid implementation.
+// Every engine keeps events in memory up to memoryPercentage of the memory
+// quota of its changefeed, and spills the overflow to pebble.
+func NewForHybrid(
+	dir string, memQuotaInBytes uint64, memoryPercentage uint64, cfg *config.DBConfig,
+) *SortEngineFactory {
+	return newForPebbleDBs(hybridEngine, dir, memQuotaInBytes, memoryPercentage, cfg)
+}
+
+func newForPebbleDBs(
+	engineType sortEngineType, dir string,
+	memQuotaInBytes uint64, memoryPercentage uint64, cfg *config.DBConfig,
+) *SortEngineFactory {
 	factoryMu.Lock()
 	defer factoryMu.Unlock()
 	if factory == nil {
 		factory = &SortEngineFactory{
-			engineType:      pebbleEngine,
-			dir:             dir,
-			memQuotaInBytes: memQuotaInBytes,
-			engines:         make(map[model.ChangeFeedID]sorter.SortEngine),
-			closed:          make(chan struct{}),
-			pebbleConfig:    cfg,
-			dbInitialized:   atomic.NewBool(false),
+			engineType:       engineType,
+			dir:              dir,
+			memQuotaInBytes:  memQuotaInBytes,
+			memoryPercentage: memoryPercentage,
+			engines:          make(map[model.ChangeFeedID]sorter.SortEngine),
+			closed:           make(chan struct{}),
+			pebbleConfig:     cfg,
+			dbInitialized:    atomic.NewBool(false),
 		}
 		factory.startMetricsCollector()
 	}
@@ -169,7 +206,7 @@ func (f *SortEngineFactory) startMetricsCollector() {
 }
 
 func (f *SortEngineFactory) collectMetrics() {
-	if f.engineType == pebbleEngine && f.dbInitialized.Load() {
+	if f.dbInitialized.Load() {
 		for i, db := range f.dbs {
 			stats := db.Metrics()
 			id := strconv.Itoa(i + 1)
@@ -0,0 +1,20 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hybrid is an EventSortEngine implementation with two tiers:
+//  1. events are sorted in memory until the memory budget of the changefeed
+//     is used up;
+//  2. the overflow is spilled to another EventSortEngine, which is pebble
+//     based generally;
+//  3. events from both tiers are merged when they are fetched.
+package hybrid
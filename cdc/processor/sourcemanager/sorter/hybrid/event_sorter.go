@@ -0,0 +1,537 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hybrid
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+var (
+	_ sorter.SortEngine      = (*EventSorter)(nil)
+	_ sorter.DiskQuotaEngine = (*EventSorter)(nil)
+	_ sorter.EventIterator   = (*EventIter)(nil)
+)
+
+// EventSorter keeps events in memory up to the memory budget of a changefeed,
+// and spills the overflow to the disk tier.
+type EventSorter struct {
+	// Read-only fields.
+	changefeedID model.ChangeFeedID
+	memQuota     *memquota.MemQuota
+	disk         sorter.SortEngine
+
+	// Following fields are protected by mu.
+	mu         sync.RWMutex
+	onResolves []func(tablepb.Span, model.Ts)
+	tables     *spanz.HashMap[*tableState]
+}
+
+// New creates an EventSorter instance. disk is owned by the returned sorter.
+func New(ID model.ChangeFeedID, disk sorter.SortEngine, memoryBudgetInBytes uint64) *EventSorter {
+	s := &EventSorter{
+		changefeedID: ID,
+		memQuota:     memquota.NewMemQuota(ID, memoryBudgetInBytes, "sorter"),
+		disk:         disk,
+		tables:       spanz.NewHashMap[*tableState](),
+	}
+	disk.OnResolve(s.onDiskResolve)
+	return s
+}
+
+// IsTableBased implements sorter.SortEngine.
+func (s *EventSorter) IsTableBased() bool {
+	return true
+}
+
+// AddTable implements sorter.SortEngine.
+func (s *EventSorter) AddTable(span tablepb.Span, startTs model.Ts) {
+	s.mu.Lock()
+	if _, exists := s.tables.Get(span); exists {
+		s.mu.Unlock()
+		log.Warn("add an exist table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+		return
+	}
+	s.tables.ReplaceOrInsert(span, &tableState{
+		memResolvedTs:         startTs,
+		resolvedTs:            startTs,
+		maxReceivedResolvedTs: startTs,
+	})
+	s.mu.Unlock()
+	s.disk.AddTable(span, startTs)
+}
+
+// RemoveTable implements sorter.SortEngine.
+func (s *EventSorter) RemoveTable(span tablepb.Span) {
+	s.mu.Lock()
+	state, exists := s.tables.Get(span)
+	if !exists {
+		s.mu.Unlock()
+		log.Warn("remove an unexist table",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+		return
+	}
+	s.tables.Delete(span)
+	s.mu.Unlock()
+
+	state.mu.Lock()
+	memBytes := eventsSize(state.resolved) + eventsSize(state.unresolved)
+	state.resolved, state.unresolved = nil, nil
+	spilled := state.spilled
+	state.mu.Unlock()
+
+	s.memQuota.Refund(memBytes)
+	if spilled {
+		upperBound := sorter.Position{CommitTs: math.MaxUint64, StartTs: math.MaxUint64 - 1}
+		if err := s.disk.CleanByTable(span, upperBound); err != nil {
+			log.Warn("clean spilled events of a removed table fails",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.Stringer("span", &span),
+				zap.Error(err))
+		}
+	}
+	s.disk.RemoveTable(span)
+}
+
+// Add implements sorter.SortEngine.
+//
+// Panics if the table doesn't exist.
+func (s *EventSorter) Add(span tablepb.Span, events ...*model.PolymorphicEvent) {
+	state := s.getTable(span, "add events into an non-existent table")
+
+	var toDisk []*model.PolymorphicEvent
+	state.mu.Lock()
+	for _, event := range events {
+		if event.IsResolved() {
+			state.resolveInMemory(event.CRTs)
+			// The disk tier only needs resolved ts if it has unresolved events.
+			if state.spilledMaxCommitTs > state.diskResolvedTs {
+				toDisk = append(toDisk, event)
+			}
+			continue
+		}
+		if event.CRTs > state.maxReceivedCommitTs {
+			state.maxReceivedCommitTs = event.CRTs
+		}
+		if size := eventSize(event); s.memQuota.TryAcquire(size) {
+			heap.Push(&state.unresolved, memEvent{event: event, size: size})
+			continue
+		}
+		state.spilled = true
+		if event.CRTs > state.spilledMaxCommitTs {
+			state.spilledMaxCommitTs = event.CRTs
+		}
+		toDisk = append(toDisk, event)
+	}
+	resolvedTs, hasNewResolved := state.advance()
+	state.mu.Unlock()
+
+	if len(toDisk) > 0 {
+		sorter.SpilledEventCount().
+			WithLabelValues(s.changefeedID.Namespace, s.changefeedID.ID).
+			Add(float64(len(toDisk)))
+		s.disk.Add(span, toDisk...)
+	}
+	if hasNewResolved {
+		s.resolve(span, resolvedTs)
+	}
+}
+
+// onDiskResolve is called after events in the disk tier are resolved.
+func (s *EventSorter) onDiskResolve(span tablepb.Span, diskResolvedTs model.Ts) {
+	s.mu.RLock()
+	state, exists := s.tables.Get(span)
+	s.mu.RUnlock()
+	if !exists {
+		return
+	}
+
+	state.mu.Lock()
+	if diskResolvedTs > state.diskResolvedTs {
+		state.diskResolvedTs = diskResolvedTs
+	}
+	resolvedTs, hasNewResolved := state.advance()
+	state.mu.Unlock()
+	if hasNewResolved {
+		s.resolve(span, resolvedTs)
+	}
+}
+
+func (s *EventSorter) resolve(span tablepb.Span, resolvedTs model.Ts) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, onResolve := range s.onResolves {
+		onResolve(span, resolvedTs)
+	}
+}
+
+// OnResolve implements sorter.SortEngine.
+func (s *EventSorter) OnResolve(action func(tablepb.Span, model.Ts)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onResolves = append(s.onResolves, action)
+}
+
+// FetchByTable implements sorter.SortEngine.
+func (s *EventSorter) FetchByTable(span tablepb.Span, lowerBound, upperBound sorter.Position) sorter.EventIterator {
+	s.mu.RLock()
+	state, exists := s.tables.Get(span)
+	s.mu.RUnlock()
+	if !exists {
+		return &EventIter{}
+	}
+
+	state.mu.Lock()
+	if upperBound.CommitTs > state.resolvedTs {
+		state.mu.Unlock()
+		log.Panic("fetch unresolved events",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Uint64("upperBound", upperBound.CommitTs),
+			zap.Uint64("lowerBound", lowerBound.CommitTs),
+			zap.Uint64("resolved", state.resolvedTs))
+	}
+	startIdx := sort.Search(len(state.resolved), func(idx int) bool {
+		x := state.resolved[idx].event
+		return x.CRTs > lowerBound.CommitTs ||
+			x.CRTs == lowerBound.CommitTs && x.StartTs >= lowerBound.StartTs
+	})
+	endIdx := sort.Search(len(state.resolved), func(idx int) bool {
+		x := state.resolved[idx].event
+		return x.CRTs > upperBound.CommitTs ||
+			x.CRTs == upperBound.CommitTs && x.StartTs > upperBound.StartTs
+	})
+	iter := &EventIter{memEvents: state.resolved[startIdx:endIdx]}
+	diskUpperBound, fetchDisk := state.diskBound(upperBound)
+	state.mu.Unlock()
+
+	if fetchDisk && lowerBound.Compare(diskUpperBound) <= 0 {
+		iter.disk = s.disk.FetchByTable(span, lowerBound, diskUpperBound)
+	}
+	return iter
+}
+
+// FetchAllTables implements sorter.SortEngine.
+func (s *EventSorter) FetchAllTables(lowerBound sorter.Position) sorter.EventIterator {
+	log.Panic("FetchAllTables should never be called",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID))
+	return nil
+}
+
+// CleanByTable implements sorter.SortEngine.
+func (s *EventSorter) CleanByTable(span tablepb.Span, upperBound sorter.Position) error {
+	s.mu.RLock()
+	state, exists := s.tables.Get(span)
+	s.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	state.mu.Lock()
+	idx := sort.Search(len(state.resolved), func(idx int) bool {
+		x := state.resolved[idx].event
+		return x.CRTs > upperBound.CommitTs ||
+			x.CRTs == upperBound.CommitTs && x.StartTs > upperBound.StartTs
+	})
+	memBytes := eventsSize(state.resolved[:idx])
+	state.resolved = state.resolved[idx:]
+	diskUpperBound, cleanDisk := state.diskBound(upperBound)
+	state.mu.Unlock()
+
+	s.memQuota.Refund(memBytes)
+	if cleanDisk {
+		return s.disk.CleanByTable(span, diskUpperBound)
+	}
+	return nil
+}
+
+// CleanAllTables implements sorter.SortEngine.
+func (s *EventSorter) CleanAllTables(upperBound sorter.Position) error {
+	log.Panic("CleanAllTables should never be called",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID))
+	return nil
+}
+
+// GetStatsByTable implements sorter.SortEngine.
+//
+// Panics if the table doesn't exist.
+func (s *EventSorter) GetStatsByTable(span tablepb.Span) sorter.TableStats {
+	state := s.getTable(span, "Get stats from an non-existent table")
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	maxCommitTs := state.maxReceivedCommitTs
+	maxResolvedTs := state.maxReceivedResolvedTs
+	if maxCommitTs < maxResolvedTs {
+		// In case, there is no write for the table,
+		// we use maxResolvedTs as maxCommitTs to make the stats meaningful.
+		maxCommitTs = maxResolvedTs
+	}
+	return sorter.TableStats{
+		ReceivedMaxCommitTs:   maxCommitTs,
+		ReceivedMaxResolvedTs: maxResolvedTs,
+	}
+}
+
+// Close implements sorter.SortEngine.
+func (s *EventSorter) Close() error {
+	s.mu.Lock()
+	s.tables = spanz.NewHashMap[*tableState]()
+	s.mu.Unlock()
+
+	s.memQuota.Close()
+	sorter.SpilledEventCount().DeleteLabelValues(s.changefeedID.Namespace, s.changefeedID.ID)
+	return s.disk.Close()
+}
+
+// SetDiskQuota implements sorter.DiskQuotaEngine.
+// The quota only limits the disk tier.
+func (s *EventSorter) SetDiskQuota(quota uint64) {
+	if disk, ok := s.disk.(sorter.DiskQuotaEngine); ok {
+		disk.SetDiskQuota(quota)
+	}
+}
+
+// DiskUsage implements sorter.DiskQuotaEngine.
+func (s *EventSorter) DiskUsage() (usage uint64, quota uint64) {
+	if disk, ok := s.disk.(sorter.DiskQuotaEngine); ok {
+		return disk.DiskUsage()
+	}
+	return 0, 0
+}
+
+// DiskQuotaExceeded implements sorter.DiskQuotaEngine.
+func (s *EventSorter) DiskQuotaExceeded() bool {
+	if disk, ok := s.disk.(sorter.DiskQuotaEngine); ok {
+		return disk.DiskQuotaExceeded()
+	}
+	return false
+}
+
+// WaitDiskQuota implements sorter.DiskQuotaEngine.
+func (s *EventSorter) WaitDiskQuota(ctx context.Context) error {
+	if disk, ok := s.disk.(sorter.DiskQuotaEngine); ok {
+		return disk.WaitDiskQuota(ctx)
+	}
+	return nil
+}
+
+// SlotsAndHasher implements sorter.SortEngine.
+func (s *EventSorter) SlotsAndHasher() (slotCount int, hasher func(tablepb.Span, int) int) {
+	return s.disk.SlotsAndHasher()
+}
+
+func (s *EventSorter) getTable(span tablepb.Span, msg string) *tableState {
+	s.mu.RLock()
+	state, exists := s.tables.Get(span)
+	s.mu.RUnlock()
+	if !exists {
+		log.Panic(msg,
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span))
+	}
+	return state
+}
+
+type tableState struct {
+	// All following fields are protected by mu.
+	mu sync.Mutex
+
+	// Events in the memory tier.
+	unresolved eventHeap
+	resolved   []memEvent
+
+	// memResolvedTs is the max resolved ts received.
+	memResolvedTs model.Ts
+	// spilled indicates whether any event is spilled to the disk tier.
+	spilled bool
+	// spilledMaxCommitTs is the max commit ts of spilled events.
+	spilledMaxCommitTs model.Ts
+	// diskResolvedTs is the resolved ts of the disk tier.
+	diskResolvedTs model.Ts
+	// resolvedTs indicates events in both tiers are ready for fetching.
+	resolvedTs model.Ts
+
+	// For statistics.
+	maxReceivedCommitTs   model.Ts
+	maxReceivedResolvedTs model.Ts
+}
+
+// resolveInMemory moves events committed before resolvedTs in the memory tier
+// to the sorted list.
+func (t *tableState) resolveInMemory(resolvedTs model.Ts) {
+	if resolvedTs <= t.memResolvedTs {
+		return
+	}
+	t.memResolvedTs = resolvedTs
+	t.maxReceivedResolvedTs = resolvedTs
+	for t.unresolved.Len() > 0 && t.unresolved[0].event.CRTs <= resolvedTs {
+		t.resolved = append(t.resolved, heap.Pop(&t.unresolved).(memEvent))
+	}
+}
+
+// advance calculates the resolved ts of the table.
+func (t *tableState) advance() (model.Ts, bool) {
+	resolvedTs := t.memResolvedTs
+	// If the disk tier has unresolved events, they can be committed before
+	// memResolvedTs, so the table is resolved at most to diskResolvedTs.
+	if t.spilledMaxCommitTs > t.diskResolvedTs && t.diskResolvedTs < resolvedTs {
+		resolvedTs = t.diskResolvedTs
+	}
+	if resolvedTs > t.resolvedTs {
+		t.resolvedTs = resolvedTs
+		return resolvedTs, true
+	}
+	return 0, false
+}
+
+// diskBound limits upperBound to the resolved ts of the disk tier. There are
+// no spilled events committed between diskResolvedTs and resolvedTs, so the
+// limited bound covers all events that upperBound covers.
+func (t *tableState) diskBound(upperBound sorter.Position) (sorter.Position, bool) {
+	if !t.spilled || t.diskResolvedTs == 0 {
+		return sorter.Position{}, false
+	}
+	if upperBound.CommitTs > t.diskResolvedTs {
+		return sorter.GenCommitFence(t.diskResolvedTs), true
+	}
+	return upperBound, true
+}
+
+// EventIter implements sorter.EventIterator. It merges events from the memory
+// tier and the disk tier.
+type EventIter struct {
+	memEvents []memEvent
+	disk      sorter.EventIterator
+	diskEvent *model.PolymorphicEvent
+}
+
+// Next implements sorter.EventIterator.
+func (s *EventIter) Next() (event *model.PolymorphicEvent, txnFinished sorter.Position, err error) {
+	if event, err = s.next(); err != nil || event == nil {
+		return
+	}
+
+	var next *model.PolymorphicEvent
+	if next, err = s.peek(); err != nil {
+		return
+	}
+	if next == nil || next.CRTs != event.CRTs || next.StartTs != event.StartTs {
+		txnFinished.CommitTs = event.CRTs
+		txnFinished.StartTs = event.StartTs
+	}
+	return
+}
+
+// peek returns the next event without consuming it.
+func (s *EventIter) peek() (*model.PolymorphicEvent, error) {
+	if s.diskEvent == nil && s.disk != nil {
+		event, _, err := s.disk.Next()
+		if err != nil {
+			return nil, err
+		}
+		s.diskEvent = event
+	}
+	if len(s.memEvents) == 0 {
+		return s.diskEvent, nil
+	}
+	if s.diskEvent == nil || !model.ComparePolymorphicEvents(s.diskEvent, s.memEvents[0].event) {
+		return s.memEvents[0].event, nil
+	}
+	return s.diskEvent, nil
+}
+
+func (s *EventIter) next() (*model.PolymorphicEvent, error) {
+	event, err := s.peek()
+	if err != nil || event == nil {
+		return nil, err
+	}
+	if event == s.diskEvent {
+		s.diskEvent = nil
+		return event, nil
+	}
+	s.memEvents = s.memEvents[1:]
+	// Events in the memory tier are fetched again if the sink fails, so only
+	// their copies can be mounted.
+	rawKV := *event.RawKV
+	return model.NewPolymorphicEvent(&rawKV), nil
+}
+
+// Close implements sorter.EventIterator.
+func (s *EventIter) Close() error {
+	s.memEvents = nil
+	if s.disk != nil {
+		return s.disk.Close()
+	}
+	return nil
+}
+
+func eventSize(event *model.PolymorphicEvent) uint64 {
+	return uint64(event.RawKV.ApproximateDataSize())
+}
+
+// memEvent is an event in the memory tier with the memory quota acquired
+// for it, which is refunded after the event is cleaned.
+type memEvent struct {
+	event *model.PolymorphicEvent
+	size  uint64
+}
+
+func eventsSize(events []memEvent) (size uint64) {
+	for _, event := range events {
+		size += event.size
+	}
+	return
+}
+
+type eventHeap []memEvent
+
+func (h eventHeap) Len() int { return len(h) }
+func (h eventHeap) Less(i, j int) bool {
+	return model.ComparePolymorphicEvents(h[i].event, h[j].event)
+}
+func (h eventHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *eventHeap) Push(x any) {
+	*h = append(*h, x.(memEvent))
+}
+
+func (h *eventHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
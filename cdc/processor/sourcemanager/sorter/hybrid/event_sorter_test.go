@@ -0,0 +1,185 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hybrid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/memory"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvent(startTs, commitTs model.Ts) *model.PolymorphicEvent {
+	// The size of every event is 10 bytes.
+	return model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte("key"),
+		Value:   []byte("value12"),
+		StartTs: startTs,
+		CRTs:    commitTs,
+	})
+}
+
+type fetched struct {
+	startTs     model.Ts
+	commitTs    model.Ts
+	txnFinished bool
+}
+
+func fetchAll(t *testing.T, s *EventSorter, span tablepb.Span, upperBound model.Ts) []fetched {
+	iter := s.FetchByTable(span, sorter.Position{}, sorter.GenCommitFence(upperBound))
+	defer iter.Close()
+	var res []fetched
+	for {
+		event, txnFinished, err := iter.Next()
+		require.Nil(t, err)
+		if event == nil {
+			return res
+		}
+		res = append(res, fetched{event.StartTs, event.CRTs, txnFinished.Valid()})
+	}
+}
+
+func TestEventSorterInMemory(t *testing.T) {
+	t.Parallel()
+
+	s := New(model.DefaultChangeFeedID("test"), memory.New(context.Background()), 1024)
+	defer s.Close()
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span, 1)
+	resolved := make(chan model.Ts, 8)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolved <- ts })
+
+	s.Add(span, newEvent(4, 5), newEvent(2, 3), newEvent(6, 7))
+	require.Equal(t, uint64(30), s.memQuota.GetUsedBytes())
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 5))
+	require.Equal(t, model.Ts(5), <-resolved)
+
+	require.Equal(t, []fetched{{2, 3, true}, {4, 5, true}}, fetchAll(t, s, span, 5))
+	require.Equal(t, sorter.TableStats{ReceivedMaxCommitTs: 7, ReceivedMaxResolvedTs: 5},
+		s.GetStatsByTable(span))
+
+	require.Nil(t, s.CleanByTable(span, sorter.GenCommitFence(5)))
+	require.Equal(t, uint64(10), s.memQuota.GetUsedBytes())
+	s.RemoveTable(span)
+	require.Equal(t, uint64(0), s.memQuota.GetUsedBytes())
+}
+
+func TestEventSorterSpill(t *testing.T) {
+	t.Parallel()
+
+	// Only 2 events can be kept in memory.
+	s := New(model.DefaultChangeFeedID("test"), memory.New(context.Background()), 20)
+	defer s.Close()
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span, 1)
+	resolved := make(chan model.Ts, 8)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolved <- ts })
+
+	// Events of the transaction committed at 5 are in both tiers.
+	s.Add(span, newEvent(4, 5), newEvent(2, 3), newEvent(4, 5), newEvent(8, 9), newEvent(6, 7))
+	require.Equal(t, uint64(20), s.memQuota.GetUsedBytes())
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 8))
+	require.Equal(t, model.Ts(8), <-resolved)
+
+	require.Equal(t, []fetched{
+		{2, 3, true},
+		{4, 5, false},
+		{4, 5, true},
+		{6, 7, true},
+	}, fetchAll(t, s, span, 8))
+
+	// Cleaning events refunds the memory budget.
+	require.Nil(t, s.CleanByTable(span, sorter.GenCommitFence(5)))
+	require.Equal(t, uint64(0), s.memQuota.GetUsedBytes())
+	require.Equal(t, []fetched{{6, 7, true}}, fetchAll(t, s, span, 8))
+
+	// New events are kept in memory after the budget is refunded.
+	s.Add(span, newEvent(10, 11), model.NewResolvedPolymorphicEvent(0, 12))
+	require.Equal(t, model.Ts(12), <-resolved)
+	require.Equal(t, uint64(10), s.memQuota.GetUsedBytes())
+	require.Equal(t, []fetched{{6, 7, true}, {8, 9, true}, {10, 11, true}}, fetchAll(t, s, span, 12))
+}
+
+func TestEventSorterFetchTwice(t *testing.T) {
+	t.Parallel()
+
+	s := New(model.DefaultChangeFeedID("test"), memory.New(context.Background()), 1024)
+	defer s.Close()
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span, 1)
+	resolved := make(chan model.Ts, 8)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolved <- ts })
+
+	s.Add(span, newEvent(2, 3), newEvent(4, 5), model.NewResolvedPolymorphicEvent(0, 5))
+	require.Equal(t, model.Ts(5), <-resolved)
+
+	fetch := func() {
+		iter := s.FetchByTable(span, sorter.Position{}, sorter.GenCommitFence(5))
+		defer iter.Close()
+		for {
+			event, _, err := iter.Next()
+			require.Nil(t, err)
+			if event == nil {
+				return
+			}
+			require.Nil(t, event.Row)
+			require.Equal(t, []byte("value12"), event.RawKV.Value)
+			// Mount the event as DecodeEvent does.
+			event.Row = &model.RowChangedEvent{}
+			event.RawKV.Value = nil
+			event.RawKV.OldValue = nil
+		}
+	}
+	// Events are fetched again if the sink fails.
+	fetch()
+	fetch()
+}
+
+func TestEventSorterRefundAfterMount(t *testing.T) {
+	t.Parallel()
+
+	s := New(model.DefaultChangeFeedID("test"), memory.New(context.Background()), 1024)
+	defer s.Close()
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span, 1)
+	resolved := make(chan model.Ts, 8)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolved <- ts })
+
+	events := []*model.PolymorphicEvent{newEvent(2, 3), newEvent(4, 5)}
+	s.Add(span, events...)
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 5))
+	require.Equal(t, model.Ts(5), <-resolved)
+	require.Equal(t, uint64(20), s.memQuota.GetUsedBytes())
+
+	// The quota acquired for events is refunded even if their values are
+	// cleared after they are added.
+	for _, event := range events {
+		event.RawKV.Value = nil
+	}
+	require.Nil(t, s.CleanByTable(span, sorter.GenCommitFence(3)))
+	require.Equal(t, uint64(10), s.memQuota.GetUsedBytes())
+	require.Nil(t, s.CleanByTable(span, sorter.GenCommitFence(5)))
+	require.Equal(t, uint64(0), s.memQuota.GetUsedBytes())
+
+	s.Add(span, newEvent(6, 7))
+	require.Equal(t, uint64(10), s.memQuota.GetUsedBytes())
+	s.RemoveTable(span)
+	require.Equal(t, uint64(0), s.memQuota.GetUsedBytes())
+}
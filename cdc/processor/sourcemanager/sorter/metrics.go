@@ -74,6 +74,24 @@ var (
 		Help:      "The amount of pending data stored on-disk by the sorter",
 	}, []string{"id"})
 
+	// spilledEventCount is the metric that records events spilled to disk
+	// by the hybrid sorter.
+	spilledEventCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "spilled_event_count",
+		Help:      "The number of events spilled to disk by the hybrid sorter",
+	}, []string{"namespace", "changefeed"})
+
+	// diskUsageGauge is the metric that records the estimated disk usage
+	// of a changefeed, which is limited by the changefeed disk quota.
+	diskUsageGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "changefeed_disk_usage_bytes",
+		Help:      "The estimated disk usage of a changefeed in the sorter",
+	}, []string{"namespace", "changefeed"})
+
 	dbIteratorGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "ticdc",
 		Subsystem: "db",
@@ -142,6 +160,16 @@ func OnDiskDataSize() *prometheus.GaugeVec {
 	return onDiskDataSizeGauge
 }
 
+// SpilledEventCount returns spilledEventCount.
+func SpilledEventCount() *prometheus.CounterVec {
+	return spilledEventCount
+}
+
+// DiskUsage returns diskUsageGauge.
+func DiskUsage() *prometheus.GaugeVec {
+	return diskUsageGauge
+}
+
 // IteratorGauge returns dbIteratorGauge.
 func IteratorGauge() *prometheus.GaugeVec {
 	return dbIteratorGauge
@@ -176,6 +204,8 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(sorterIterReadDurationHistogram)
 	registry.MustRegister(inMemoryDataSizeGauge)
 	registry.MustRegister(onDiskDataSizeGauge)
+	registry.MustRegister(spilledEventCount)
+	registry.MustRegister(diskUsageGauge)
 	registry.MustRegister(dbIteratorGauge)
 
 	// TODO: Seems these things belong to pebble instead of engine.
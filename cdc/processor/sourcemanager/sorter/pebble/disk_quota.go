@@ -0,0 +1,162 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+)
+
+// diskQuota tracks the disk usage of one EventSorter.
+//
+// The usage is estimated by the size of keys and values written into pebble,
+// so space amplification of LSM trees is not counted in.
+type diskQuota struct {
+	quota atomic.Uint64
+	usage atomic.Uint64
+	// resolved is the part of usage which has been resolved, so it can be
+	// released after sinks consume and clean it.
+	resolved atomic.Uint64
+
+	mu sync.Mutex
+	// notify is closed and re-created when the usage is released or
+	// the quota is changed.
+	notify chan struct{}
+}
+
+type usageRecord struct {
+	resolvedTs model.Ts
+	bytes      uint64
+}
+
+// tableUsage is the disk usage of one table.
+type tableUsage struct {
+	mu sync.Mutex
+	// pending is the size of events written after the last resolved ts.
+	pending uint64
+	// records are sizes of events written before resolved timestamps.
+	records []usageRecord
+	// released is set after all data of the table is cleaned, so that
+	// stale events of the table won't be counted in again.
+	released bool
+}
+
+func newDiskQuota() *diskQuota {
+	return &diskQuota{notify: make(chan struct{})}
+}
+
+func (q *diskQuota) setQuota(quota uint64) {
+	q.quota.Store(quota)
+	q.broadcast()
+}
+
+func (q *diskQuota) exceeded() bool {
+	quota := q.quota.Load()
+	return quota > 0 && q.usage.Load() > quota && q.resolved.Load() > 0
+}
+
+// wait blocks until the quota isn't exceeded, ctx is canceled or closed is closed.
+func (q *diskQuota) wait(ctx context.Context, closed <-chan struct{}) error {
+	for {
+		q.mu.Lock()
+		notify := q.notify
+		q.mu.Unlock()
+		if !q.exceeded() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-closed:
+			return nil
+		case <-notify:
+		}
+	}
+}
+
+func (q *diskQuota) broadcast() {
+	q.mu.Lock()
+	close(q.notify)
+	q.notify = make(chan struct{})
+	q.mu.Unlock()
+}
+
+// add counts n bytes written into the table.
+func (q *diskQuota) add(t *tableUsage, n uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.released {
+		return
+	}
+	t.pending += n
+	q.usage.Add(n)
+}
+
+// resolve marks all pending bytes of the table as resolved at resolvedTs.
+func (q *diskQuota) resolve(t *tableUsage, resolvedTs model.Ts) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.released || t.pending == 0 {
+		return
+	}
+	t.records = append(t.records, usageRecord{resolvedTs: resolvedTs, bytes: t.pending})
+	q.resolved.Add(t.pending)
+	t.pending = 0
+}
+
+// release releases bytes of the table which are cleaned until upperBound.
+// If upperBound is nil, all bytes of the table are released.
+func (q *diskQuota) release(t *tableUsage, upperBound *sorter.Position) {
+	t.mu.Lock()
+	var resolved uint64
+	i := 0
+	for ; i < len(t.records); i++ {
+		record := t.records[i]
+		if upperBound != nil && !cleanedAll(record.resolvedTs, *upperBound) {
+			break
+		}
+		resolved += record.bytes
+	}
+	t.records = t.records[i:]
+	released := resolved
+	if upperBound == nil {
+		released += t.pending
+		t.pending = 0
+		t.released = true
+	}
+	t.mu.Unlock()
+
+	if released == 0 {
+		return
+	}
+	q.usage.Add(^(released - 1))
+	if resolved > 0 {
+		q.resolved.Add(^(resolved - 1))
+	}
+	q.broadcast()
+}
+
+// cleanedAll checks whether all events with commit ts less than or equal to
+// resolvedTs are cleaned by upperBound.
+func cleanedAll(resolvedTs model.Ts, upperBound sorter.Position) bool {
+	if resolvedTs < upperBound.CommitTs {
+		return true
+	}
+	return resolvedTs == upperBound.CommitTs && upperBound.IsCommitFence()
+}
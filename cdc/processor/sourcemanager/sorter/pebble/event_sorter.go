@@ -14,6 +14,7 @@
 package pebble
 
 import (
+	"context"
 	"math"
 	"strconv"
 	"sync"
@@ -33,8 +34,9 @@ import (
 )
 
 var (
-	_ sorter.SortEngine    = (*EventSorter)(nil)
-	_ sorter.EventIterator = (*EventIter)(nil)
+	_ sorter.SortEngine      = (*EventSorter)(nil)
+	_ sorter.DiskQuotaEngine = (*EventSorter)(nil)
+	_ sorter.EventIterator   = (*EventIter)(nil)
 )
 
 var pebbleWriteOptions = pebble.WriteOptions{Sync: false}
@@ -46,6 +48,7 @@ type EventSorter struct {
 	dbs          []*pebble.DB
 	channs       []*chann.DrainableChann[eventWithTableID]
 	serde        encoding.MsgPackGenSerde
+	diskQuota    *diskQuota
 
 	// To manage background goroutines.
 	wg     sync.WaitGroup
@@ -79,6 +82,7 @@ func New(ID model.ChangeFeedID, dbs []*pebble.DB) *EventSorter {
 		changefeedID: ID,
 		dbs:          dbs,
 		channs:       channs,
+		diskQuota:    newDiskQuota(),
 		closed:       make(chan struct{}),
 		tables:       spanz.NewHashMap[*tableState](),
 	}
@@ -130,8 +134,19 @@ func (s *EventSorter) RemoveTable(span tablepb.Span) {
 			zap.Stringer("span", &span))
 		return
 	}
+	state, _ := s.tables.Get(span)
 	s.tables.Delete(span)
 	s.mu.Unlock()
+
+	// Data of a removed table will never be fetched, so clean it to free the disk
+	// space of the changefeed.
+	if err := s.cleanTable(state, span); err != nil {
+		log.Warn("clean removed table fails",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Error(err))
+	}
 }
 
 // Add implements sorter.SortEngine.
@@ -163,7 +178,7 @@ func (s *EventSorter) Add(span tablepb.Span, events ...*model.PolymorphicEvent)
 				state.maxReceivedCommitTs.Store(maxCommitTs)
 			}
 		}
-		state.ch.In() <- eventWithTableID{uniqueID: state.uniqueID, span: span, event: event}
+		state.ch.In() <- eventWithTableID{uniqueID: state.uniqueID, span: span, event: event, state: state}
 	}
 }
 
@@ -301,6 +316,26 @@ func (s *EventSorter) Close() error {
 	return err
 }
 
+// SetDiskQuota implements sorter.DiskQuotaEngine.
+func (s *EventSorter) SetDiskQuota(quota uint64) {
+	s.diskQuota.setQuota(quota)
+}
+
+// DiskUsage implements sorter.DiskQuotaEngine.
+func (s *EventSorter) DiskUsage() (usage uint64, quota uint64) {
+	return s.diskQuota.usage.Load(), s.diskQuota.quota.Load()
+}
+
+// DiskQuotaExceeded implements sorter.DiskQuotaEngine.
+func (s *EventSorter) DiskQuotaExceeded() bool {
+	return s.diskQuota.exceeded()
+}
+
+// WaitDiskQuota implements sorter.DiskQuotaEngine.
+func (s *EventSorter) WaitDiskQuota(ctx context.Context) error {
+	return s.diskQuota.wait(ctx, s.closed)
+}
+
 // SlotsAndHasher implements sorter.SortEngine.
 func (s *EventSorter) SlotsAndHasher() (slotCount int, hasher func(tablepb.Span, int) int) {
 	return len(s.dbs), spanz.HashTableSpan
@@ -356,6 +391,7 @@ type eventWithTableID struct {
 	uniqueID uint32
 	span     tablepb.Span
 	event    *model.PolymorphicEvent
+	state    *tableState
 }
 
 type tableState struct {
@@ -365,6 +401,8 @@ type tableState struct {
 	// For statistics.
 	maxReceivedCommitTs   atomic.Uint64
 	maxReceivedResolvedTs atomic.Uint64
+	// For the disk quota.
+	usage tableUsage
 
 	// Following fields are protected by mu.
 	mu      sync.RWMutex
@@ -450,6 +488,7 @@ func (s *EventSorter) handleEvents(
 	encodeItemAndBatch := func(batch *pebble.Batch, newResolved *spanz.HashMap[model.Ts], item eventWithTableID) {
 		if item.event.IsResolved() {
 			newResolved.ReplaceOrInsert(item.span, item.event.CRTs)
+			s.diskQuota.resolve(&item.state.usage, item.event.CRTs)
 			return
 		}
 		key := encoding.EncodeKey(item.uniqueID, uint64(item.span.TableID), item.event)
@@ -464,6 +503,7 @@ func (s *EventSorter) handleEvents(
 				zap.String("namespace", s.changefeedID.Namespace),
 				zap.String("changefeed", s.changefeedID.ID))
 		}
+		s.diskQuota.add(&item.state.usage, uint64(len(key)+len(value)))
 	}
 
 	// Batch item and commit until batch size is larger than batchCommitSize,
@@ -509,9 +549,11 @@ func (s *EventSorter) cleanTable(
 ) error {
 	var toClean sorter.Position
 	var start, end []byte
+	var released *sorter.Position
 
 	if len(upperBound) == 1 {
 		toClean = upperBound[0]
+		released = &toClean
 	} else {
 		toClean = sorter.Position{CommitTs: math.MaxUint64, StartTs: math.MaxUint64 - 1}
 	}
@@ -540,6 +582,7 @@ func (s *EventSorter) cleanTable(
 
 	sorter.RangeCleanCount().Inc()
 	state.cleaned = toClean
+	s.diskQuota.release(&state.usage, released)
 	return nil
 }
 
@@ -14,6 +14,7 @@
 package pebble
 
 import (
+	"context"
 	"path/filepath"
 	"sort"
 	"testing"
@@ -182,3 +183,50 @@ func TestCleanData(t *testing.T) {
 	require.NoError(t, s.CleanByTable(spanz.TableIDToComparableSpan(2), sorter.Position{}))
 	require.Nil(t, s.CleanByTable(span, sorter.Position{}))
 }
+
+func TestDiskQuota(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), t.Name())
+	db, err := OpenPebble(1, dbPath, &config.DBConfig{Count: 1}, nil, nil)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
+	s := New(cf, []*pebble.DB{db})
+	defer s.Close()
+	s.SetDiskQuota(1)
+
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span, 1)
+	resolvedTs := make(chan model.Ts, 1)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolvedTs <- ts })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Unresolved events shouldn't block callers.
+	s.Add(span, model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte{1},
+		StartTs: 1,
+		CRTs:    2,
+	}))
+	require.Eventually(t, func() bool {
+		usage, _ := s.DiskUsage()
+		return usage > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.WaitDiskQuota(ctx))
+	require.False(t, s.DiskQuotaExceeded())
+
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 2))
+	require.Equal(t, model.Ts(2), <-resolvedTs)
+	require.Error(t, s.WaitDiskQuota(ctx))
+	require.True(t, s.DiskQuotaExceeded())
+
+	// Cleaning resolved events releases the quota.
+	require.NoError(t, s.CleanByTable(span, sorter.GenCommitFence(2)))
+	usage, quota := s.DiskUsage()
+	require.Equal(t, uint64(0), usage)
+	require.Equal(t, uint64(1), quota)
+	require.NoError(t, s.WaitDiskQuota(context.Background()))
+	require.False(t, s.DiskQuotaExceeded())
+}
@@ -0,0 +1,197 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourcemanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+// spanThrottle holds back a span after the engine runs out of its disk quota.
+// Events committed after pauseTs are dropped, and the span is paused once it's
+// resolved to pauseTs. After the engine has disk quota again, the span is
+// resubscribed from pauseTs, so that the dropped events are pulled again.
+// Pullers are never blocked, so other spans on the same puller worker or the
+// same shared stream keep moving.
+type spanThrottle struct {
+	// pauseTs is no less than the commit ts of all events added before the
+	// span is throttled.
+	pauseTs            model.Ts
+	shouldSplitKVEntry model.ShouldSplitKVEntry
+	// resolved is true once the span is resolved to pauseTs, then it can be
+	// unsubscribed without missing any events.
+	resolved bool
+	// paused is true after the span is unsubscribed.
+	paused bool
+}
+
+func (m *SourceManager) initThrottle() {
+	if diskQuota, ok := m.engine.(sorter.DiskQuotaEngine); ok {
+		m.diskQuota = diskQuota
+	}
+	m.throttle.spans = spanz.NewHashMap[*spanThrottle]()
+	m.throttle.tableNames = spanz.NewHashMap[string]()
+	m.throttle.notify = make(chan struct{}, 1)
+}
+
+// shouldThrottle returns true if events may need to be throttled.
+func (m *SourceManager) shouldThrottle() bool {
+	return m.diskQuota != nil &&
+		(m.throttle.count.Load() > 0 || m.diskQuota.DiskQuotaExceeded())
+}
+
+// throttleEvent returns true if the event should be dropped. It throttles the
+// span if the engine runs out of its disk quota. Events of a span must be
+// added one by one.
+func (m *SourceManager) throttleEvent(
+	span tablepb.Span, raw *model.RawKVEntry, shouldSplitKVEntry model.ShouldSplitKVEntry,
+) bool {
+	m.throttle.Lock()
+	defer m.throttle.Unlock()
+	t, ok := m.throttle.spans.Get(span)
+	if !ok {
+		if !m.diskQuota.DiskQuotaExceeded() {
+			return false
+		}
+		// The max commit ts received by the engine covers all added events.
+		stats := m.engine.GetStatsByTable(span)
+		t = &spanThrottle{
+			pauseTs:            stats.ReceivedMaxCommitTs,
+			shouldSplitKVEntry: shouldSplitKVEntry,
+		}
+		m.throttle.spans.ReplaceOrInsert(span, t)
+		m.throttle.count.Add(1)
+		log.Info("span is throttled because the sort engine exceeds the disk quota",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Uint64("pauseTs", t.pauseTs))
+	}
+	return raw.CRTs > t.pauseTs
+}
+
+// throttleResolved limits the resolved ts of a throttled span to its pauseTs.
+func (m *SourceManager) throttleResolved(span tablepb.Span, resolvedTs model.Ts) model.Ts {
+	m.throttle.Lock()
+	defer m.throttle.Unlock()
+	t, ok := m.throttle.spans.Get(span)
+	if !ok {
+		return resolvedTs
+	}
+	if resolvedTs < t.pauseTs {
+		return resolvedTs
+	}
+	if !t.resolved {
+		t.resolved = true
+		select {
+		case m.throttle.notify <- struct{}{}:
+		default:
+		}
+	}
+	return t.pauseTs
+}
+
+func (m *SourceManager) isSpanPaused(span tablepb.Span) bool {
+	m.throttle.Lock()
+	defer m.throttle.Unlock()
+	t, ok := m.throttle.spans.Get(span)
+	return ok && t.paused
+}
+
+func (m *SourceManager) removeThrottle(span tablepb.Span) {
+	m.throttle.Lock()
+	defer m.throttle.Unlock()
+	if _, ok := m.throttle.spans.Get(span); ok {
+		m.throttle.spans.Delete(span)
+		m.throttle.count.Add(-1)
+	}
+	m.throttle.tableNames.Delete(span)
+}
+
+// runThrottle pauses and resumes throttled spans.
+func (m *SourceManager) runThrottle(ctx context.Context) error {
+	ticker := time.NewTicker(throttleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-m.throttle.notify:
+		case <-ticker.C:
+		}
+		m.pauseAndResumeSpans()
+	}
+}
+
+// pauseAndResumeSpans unsubscribes throttled spans which are resolved to their
+// pauseTs, and resubscribes paused spans if the engine has disk quota again.
+func (m *SourceManager) pauseAndResumeSpans() {
+	m.subscriptionMu.Lock()
+	defer m.subscriptionMu.Unlock()
+
+	type resumed struct {
+		span      tablepb.Span
+		tableName string
+		throttle  *spanThrottle
+	}
+	exceeded := m.diskQuota.DiskQuotaExceeded()
+	var toPause []tablepb.Span
+	var toResume []resumed
+	m.throttle.Lock()
+	m.throttle.spans.Range(func(span tablepb.Span, t *spanThrottle) bool {
+		if t.resolved && !t.paused {
+			t.paused = true
+			toPause = append(toPause, span)
+		} else if t.paused && !exceeded {
+			toResume = append(toResume, resumed{
+				span:      span,
+				tableName: m.throttle.tableNames.GetV(span),
+				throttle:  t,
+			})
+		}
+		return true
+	})
+	// Events of resumed spans are no longer dropped. No events are received
+	// before they are subscribed again.
+	for _, r := range toResume {
+		m.throttle.spans.Delete(r.span)
+		m.throttle.count.Add(-1)
+	}
+	m.throttle.Unlock()
+
+	for _, span := range toPause {
+		m.unsubscribeSpan(span)
+		log.Info("span is paused because the sort engine exceeds the disk quota",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span))
+	}
+	for _, r := range toResume {
+		m.subscribeSpan(r.span, r.tableName, r.throttle.pauseTs, r.throttle.shouldSplitKVEntry)
+		log.Info("span is resumed from the pause ts",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.String("tableName", r.tableName),
+			zap.Stringer("span", &r.span),
+			zap.Uint64("pauseTs", r.throttle.pauseTs))
+	}
+}
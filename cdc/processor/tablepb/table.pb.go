@@ -184,6 +184,10 @@ type Stats struct {
 	StageCheckpoints map[string]Checkpoint `protobuf:"bytes,3,rep,name=stage_checkpoints,json=stageCheckpoints,proto3" json:"stage_checkpoints" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	// The barrier timestamp of the table.
 	BarrierTs Ts `protobuf:"varint,4,opt,name=barrier_ts,json=barrierTs,proto3,casttype=Ts" json:"barrier_ts,omitempty"`
+	// Number of rows written to the sink per second.
+	SinkRowsPerSecond uint64 `protobuf:"varint,5,opt,name=sink_rows_per_second,json=sinkRowsPerSecond,proto3" json:"sink_rows_per_second,omitempty"`
+	// Number of bytes written to the sink per second.
+	SinkBytesPerSecond uint64 `protobuf:"varint,6,opt,name=sink_bytes_per_second,json=sinkBytesPerSecond,proto3" json:"sink_bytes_per_second,omitempty"`
 }
 
 func (m *Stats) Reset()         { *m = Stats{} }
@@ -247,6 +251,20 @@ func (m *Stats) GetBarrierTs() Ts {
 	return 0
 }
 
+func (m *Stats) GetSinkRowsPerSecond() uint64 {
+	if m != nil {
+		return m.SinkRowsPerSecond
+	}
+	return 0
+}
+
+func (m *Stats) GetSinkBytesPerSecond() uint64 {
+	if m != nil {
+		return m.SinkBytesPerSecond
+	}
+	return 0
+}
+
 // TableStatus is the running status of a table.
 // TODO rename to TableStatus.
 type TableStatus struct {
@@ -337,52 +355,53 @@ func init() {
 func init() { proto.RegisterFile("processor/tablepb/table.proto", fileDescriptor_ae83c9c6cf5ef75c) }
 
 var fileDescriptor_ae83c9c6cf5ef75c = []byte{
-	// 706 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xbf, 0x6f, 0xd3, 0x4e,
-	0x1c, 0xb5, 0x9d, 0x5f, 0xcd, 0x27, 0xf9, 0x56, 0xee, 0x7d, 0xdb, 0x52, 0x22, 0x91, 0x98, 0xa8,
-	0x40, 0xd5, 0x22, 0x07, 0xca, 0x82, 0xba, 0x35, 0x2d, 0xa0, 0xaa, 0x42, 0x42, 0x4e, 0x60, 0x60,
-	0x89, 0x1c, 0xfb, 0x70, 0xad, 0x86, 0xb3, 0xe5, 0xbb, 0xb4, 0xca, 0xc6, 0x88, 0xb2, 0xd0, 0x09,
-	0xb1, 0x44, 0xea, 0x9f, 0xd3, 0xb1, 0x23, 0x03, 0x8a, 0x20, 0x15, 0x33, 0x7b, 0x27, 0x74, 0x77,
-	0x6e, 0xdc, 0x04, 0x86, 0xd0, 0x25, 0x39, 0xdf, 0x7b, 0x9f, 0xa7, 0xf7, 0xde, 0x9d, 0x0e, 0xee,
-	0x84, 0x51, 0xe0, 0x60, 0x4a, 0x83, 0xa8, 0xc6, 0xec, 0x76, 0x07, 0x87, 0x6d, 0xf9, 0x6f, 0x86,
-	0x51, 0xc0, 0x02, 0xb4, 0x1a, 0xfa, 0xc4, 0x73, 0xec, 0xd0, 0x64, 0xfe, 0xbb, 0x4e, 0x70, 0x6c,
-	0x3a, 0xae, 0x63, 0x8e, 0x27, 0xcc, 0x78, 0xa2, 0xb4, 0xe8, 0x05, 0x5e, 0x20, 0x06, 0x6a, 0x7c,
-	0x25, 0x67, 0xab, 0x9f, 0x54, 0x48, 0x37, 0x42, 0x9b, 0xa0, 0xc7, 0x30, 0x27, 0x98, 0x2d, 0xdf,
-	0x5d, 0x51, 0x0d, 0x75, 0x2d, 0x55, 0x5f, 0x1e, 0x0d, 0x2b, 0xb9, 0x26, 0xdf, 0xdb, 0xdb, 0xbd,
-	0x4c, 0x96, 0x56, 0x4e, 0xf0, 0xf6, 0x5c, 0xb4, 0x0a, 0x79, 0xca, 0xec, 0x88, 0xb5, 0x0e, 0x71,
-	0x6f, 0x45, 0x33, 0xd4, 0xb5, 0x62, 0x3d, 0x77, 0x39, 0xac, 0xa4, 0xf6, 0x71, 0xcf, 0x9a, 0x13,
-	0xc8, 0x3e, 0xee, 0x21, 0x03, 0x72, 0x98, 0xb8, 0x82, 0x93, 0x9a, 0xe4, 0x64, 0x31, 0x71, 0xf7,
-	0x71, 0x6f, 0xab, 0xf8, 0xf1, 0xb4, 0xa2, 0x7c, 0x39, 0xad, 0x28, 0x1f, 0xbe, 0x19, 0x4a, 0xf5,
-	0x44, 0x05, 0xd8, 0x39, 0xc0, 0xce, 0x61, 0x18, 0xf8, 0x84, 0xa1, 0x0d, 0xf8, 0xcf, 0x19, 0x7f,
-	0xb5, 0x18, 0x15, 0xe6, 0xd2, 0xf5, 0xec, 0xe5, 0xb0, 0xa2, 0x35, 0xa9, 0x55, 0x4c, 0xc0, 0x26,
-	0x45, 0x0f, 0xa0, 0x10, 0x61, 0x1a, 0x74, 0x8e, 0xb0, 0xcb, 0xa9, 0xda, 0x04, 0x15, 0xae, 0xa0,
-	0x26, 0x45, 0x0f, 0x61, 0xbe, 0x63, 0x53, 0xd6, 0xa2, 0x3d, 0xe2, 0x48, 0x6e, 0x6a, 0x52, 0x96,
-	0xa3, 0x0d, 0x01, 0x36, 0x69, 0xf5, 0xa7, 0x06, 0x99, 0x06, 0xb3, 0x19, 0x45, 0x77, 0xa1, 0x18,
-	0x61, 0xcf, 0x0f, 0x48, 0xcb, 0x09, 0xba, 0x84, 0x49, 0x33, 0x56, 0x41, 0xee, 0xed, 0xf0, 0x2d,
-	0x74, 0x0f, 0xc0, 0xe9, 0x46, 0x11, 0x96, 0x6e, 0x27, 0x2d, 0xe4, 0x63, 0xa4, 0x49, 0x11, 0x83,
-	0x05, 0xca, 0x6c, 0x0f, 0xb7, 0x92, 0x00, 0xdc, 0x44, 0x6a, 0xad, 0xb0, 0xb9, 0x6d, 0xce, 0x72,
-	0xa0, 0xa6, 0x70, 0xc4, 0x7f, 0x3d, 0x9c, 0xf4, 0x45, 0x9f, 0x11, 0x16, 0xf5, 0xea, 0xe9, 0xb3,
-	0x61, 0x45, 0xb1, 0x74, 0x3a, 0x05, 0x72, 0x73, 0x6d, 0x3b, 0x8a, 0x7c, 0x1c, 0x71, 0x73, 0xe9,
-	0x49, 0x73, 0x31, 0xd2, 0xa4, 0xa5, 0x2e, 0x2c, 0xfd, 0x55, 0x17, 0xe9, 0x90, 0xe2, 0x07, 0xc9,
-	0x63, 0xe7, 0x2d, 0xbe, 0x44, 0xcf, 0x21, 0x73, 0x64, 0x77, 0xba, 0x58, 0x24, 0x2d, 0x6c, 0x3e,
-	0x9a, 0xcd, 0x7b, 0x22, 0x6c, 0xc9, 0xf1, 0x2d, 0xed, 0xa9, 0x5a, 0xfd, 0xa5, 0x41, 0x41, 0xdc,
-	0x32, 0x1e, 0xad, 0x4b, 0x6f, 0x72, 0x27, 0x77, 0x21, 0x4d, 0x43, 0x9b, 0xac, 0x64, 0x84, 0x9b,
-	0xf5, 0x19, 0x9b, 0x0c, 0x6d, 0x12, 0x57, 0x26, 0xa6, 0x79, 0x28, 0xca, 0x6c, 0x26, 0x43, 0xcd,
-	0xcf, 0x1a, 0x6a, 0x6c, 0x1d, 0x5b, 0x72, 0x1c, 0xbd, 0x01, 0x48, 0x8e, 0x57, 0x5c, 0xb1, 0x1b,
-	0x34, 0x14, 0x3b, 0xbb, 0xa6, 0x84, 0x5e, 0x48, 0x7f, 0xf2, 0x04, 0x0b, 0x9b, 0x1b, 0xff, 0x70,
-	0x61, 0x62, 0x35, 0x39, 0xbf, 0xfe, 0x59, 0x03, 0x48, 0x6c, 0xa3, 0x2a, 0xe4, 0x5e, 0x93, 0x43,
-	0x12, 0x1c, 0x13, 0x5d, 0x29, 0x2d, 0xf5, 0x07, 0xc6, 0x42, 0x02, 0xc6, 0x00, 0x32, 0x20, 0xbb,
-	0xdd, 0xa6, 0x98, 0x30, 0x5d, 0x2d, 0x2d, 0xf6, 0x07, 0x86, 0x9e, 0x50, 0xe4, 0x3e, 0xba, 0x0f,
-	0xf9, 0x57, 0x11, 0x0e, 0xed, 0xc8, 0x27, 0x9e, 0xae, 0x95, 0x6e, 0xf5, 0x07, 0xc6, 0xff, 0x09,
-	0x69, 0x0c, 0xa1, 0x55, 0x98, 0x93, 0x1f, 0xd8, 0xd5, 0x53, 0xa5, 0xe5, 0xfe, 0xc0, 0x40, 0xd3,
-	0x34, 0xec, 0xa2, 0x75, 0x28, 0x58, 0x38, 0xec, 0xf8, 0x8e, 0xcd, 0xb8, 0x5e, 0xba, 0x74, 0xbb,
-	0x3f, 0x30, 0x96, 0xae, 0x75, 0x9d, 0x80, 0x5c, 0xb1, 0xc1, 0x82, 0x90, 0xb7, 0xa1, 0x67, 0xa6,
-	0x15, 0xaf, 0x10, 0x9e, 0x52, 0xac, 0xb1, 0xab, 0x67, 0xa7, 0x53, 0xc6, 0x40, 0xfd, 0xe5, 0xf9,
-	0x8f, 0xb2, 0x72, 0x36, 0x2a, 0xab, 0xe7, 0xa3, 0xb2, 0xfa, 0x7d, 0x54, 0x56, 0x4f, 0x2e, 0xca,
-	0xca, 0xf9, 0x45, 0x59, 0xf9, 0x7a, 0x51, 0x56, 0xde, 0xd6, 0x3c, 0x9f, 0x1d, 0x74, 0xdb, 0xa6,
-	0x13, 0xbc, 0xaf, 0xc5, 0xd5, 0xd7, 0x64, 0xf5, 0x35, 0xc7, 0x75, 0x6a, 0x7f, 0x3c, 0xd7, 0xed,
-	0xac, 0x78, 0x6d, 0x9f, 0xfc, 0x0e, 0x00, 0x00, 0xff, 0xff, 0xa3, 0xeb, 0x06, 0x9b, 0xca, 0x05,
-	0x00, 0x00,
+	// 735 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9d, 0x55, 0xbf, 0x6f, 0xd3, 0x40,
+	0x14, 0x4e, 0xe2, 0xfc, 0x68, 0xce, 0xa1, 0x72, 0x8f, 0xa6, 0x94, 0x48, 0xb4, 0x21, 0x2a, 0x50,
+	0xb5, 0xc8, 0x86, 0xb0, 0xa0, 0x6e, 0x4d, 0x0b, 0xa8, 0x42, 0x48, 0x95, 0x53, 0x18, 0x58, 0x2c,
+	0xc7, 0x3e, 0x52, 0x2b, 0xc1, 0xb6, 0xee, 0x2e, 0xad, 0xb2, 0x31, 0x22, 0x16, 0x3a, 0x21, 0x16,
+	0x24, 0xfe, 0x9c, 0xb2, 0x75, 0x64, 0x40, 0x15, 0x94, 0x3f, 0x80, 0x9d, 0x89, 0x77, 0x77, 0x6e,
+	0xdc, 0x04, 0x86, 0xd0, 0xe1, 0xe2, 0xf3, 0xfb, 0xbe, 0xef, 0xe5, 0x7b, 0xef, 0x9e, 0x6d, 0x74,
+	0x23, 0xa6, 0x91, 0x47, 0x18, 0x8b, 0xa8, 0xc5, 0xdd, 0x4e, 0x9f, 0xc4, 0x1d, 0x75, 0x35, 0x21,
+	0xce, 0x23, 0xbc, 0x12, 0x07, 0x61, 0xd7, 0x73, 0x63, 0x93, 0x07, 0xaf, 0xfa, 0xd1, 0xa1, 0xe9,
+	0xf9, 0x9e, 0x39, 0x52, 0x98, 0x89, 0xa2, 0x36, 0xdf, 0x8d, 0xba, 0x91, 0x14, 0x58, 0x62, 0xa7,
+	0xb4, 0x8d, 0xf7, 0x59, 0x94, 0x6f, 0xc7, 0x6e, 0x88, 0xef, 0xa3, 0x19, 0xc9, 0x74, 0x02, 0x7f,
+	0x31, 0x5b, 0xcf, 0xae, 0x6a, 0xad, 0x85, 0xb3, 0xd3, 0xe5, 0xd2, 0x9e, 0x88, 0xed, 0x6c, 0xff,
+	0x4e, 0xb7, 0x76, 0x49, 0xf2, 0x76, 0x7c, 0xbc, 0x82, 0xca, 0x8c, 0xbb, 0x94, 0x3b, 0x3d, 0x32,
+	0x5c, 0xcc, 0x81, 0xa6, 0xd2, 0x2a, 0x01, 0x51, 0x7b, 0x4a, 0x86, 0xf6, 0x8c, 0x44, 0x60, 0x87,
+	0xeb, 0xa8, 0x44, 0x42, 0x5f, 0x72, 0xb4, 0x71, 0x4e, 0x11, 0xe2, 0x70, 0xdd, 0xa8, 0xbc, 0xfd,
+	0xbc, 0x9c, 0xf9, 0x08, 0xeb, 0xcd, 0xb7, 0x7a, 0xa6, 0x71, 0x94, 0x45, 0x68, 0x6b, 0x9f, 0x78,
+	0xbd, 0x38, 0x0a, 0x42, 0x8e, 0xd7, 0xd1, 0x15, 0x6f, 0x74, 0xe7, 0x70, 0x26, 0xcd, 0xe5, 0x5b,
+	0x45, 0x48, 0x92, 0xdb, 0x63, 0x76, 0x25, 0x05, 0xf7, 0x18, 0xbe, 0x83, 0x74, 0x4a, 0x58, 0xd4,
+	0x3f, 0x20, 0xbe, 0xa0, 0xe6, 0xc6, 0xa8, 0xe8, 0x1c, 0x02, 0xe2, 0x5d, 0x34, 0xdb, 0x77, 0x19,
+	0x77, 0xd8, 0x30, 0xf4, 0x14, 0x57, 0x1b, 0x4f, 0x2b, 0xd0, 0xb6, 0x04, 0xf7, 0x58, 0xe3, 0x8b,
+	0x86, 0x0a, 0x6d, 0xee, 0x72, 0x86, 0x6f, 0xa2, 0x0a, 0x25, 0xdd, 0x20, 0x0a, 0x1d, 0x2f, 0x1a,
+	0x84, 0x5c, 0x99, 0xb1, 0x75, 0x15, 0xdb, 0x12, 0x21, 0x7c, 0x0b, 0x21, 0x6f, 0x40, 0x29, 0x51,
+	0x6e, 0xc7, 0x2d, 0x94, 0x13, 0x04, 0x1c, 0x70, 0x34, 0x07, 0x2d, 0xea, 0x12, 0x27, 0x2d, 0x40,
+	0x98, 0xd0, 0x56, 0xf5, 0xe6, 0xa6, 0x39, 0xcd, 0x81, 0x9a, 0xd2, 0x91, 0xf8, 0xed, 0x92, 0xb4,
+	0x5f, 0xec, 0x51, 0xc8, 0xe9, 0xb0, 0x95, 0x3f, 0x3e, 0x5d, 0xce, 0xd8, 0x06, 0x9b, 0x00, 0x85,
+	0xb9, 0x8e, 0x4b, 0x69, 0x40, 0xa8, 0x30, 0x97, 0x1f, 0x37, 0x97, 0x20, 0x60, 0xce, 0x42, 0xf3,
+	0x2c, 0x08, 0x7b, 0x0e, 0x8d, 0x0e, 0x99, 0x13, 0x03, 0x99, 0x11, 0x2f, 0x0a, 0xfd, 0xc5, 0x82,
+	0x2c, 0x77, 0x4e, 0x60, 0x36, 0x40, 0xbb, 0x84, 0xb6, 0x25, 0x00, 0xd3, 0x53, 0x95, 0x82, 0xce,
+	0x90, 0x93, 0x31, 0x45, 0x51, 0x2a, 0xb0, 0x00, 0x5b, 0x02, 0x1b, 0x49, 0x6a, 0x03, 0x54, 0xfd,
+	0xa7, 0x77, 0x6c, 0x20, 0x4d, 0x0c, 0x8b, 0x68, 0x6d, 0xd9, 0x16, 0x5b, 0xfc, 0x18, 0x15, 0x0e,
+	0xdc, 0xfe, 0x80, 0xc8, 0x6e, 0xea, 0xcd, 0x7b, 0xd3, 0xf5, 0x27, 0x4d, 0x6c, 0x2b, 0xf9, 0x46,
+	0xee, 0x61, 0xb6, 0xf1, 0x2b, 0x87, 0x74, 0x39, 0xc9, 0xa2, 0x7d, 0x03, 0x76, 0x99, 0xb9, 0xdf,
+	0x46, 0x79, 0x06, 0x8f, 0x8c, 0xec, 0x86, 0xde, 0x5c, 0x9b, 0xf2, 0xb4, 0x40, 0x91, 0x1c, 0x8b,
+	0x54, 0x8b, 0xa2, 0xe0, 0x78, 0xb8, 0x2a, 0x6a, 0x76, 0xda, 0xa2, 0x46, 0xd6, 0x89, 0xad, 0xe4,
+	0xf8, 0x05, 0xcc, 0xdb, 0xa8, 0x52, 0x39, 0xc6, 0x97, 0xe8, 0x50, 0xe2, 0xec, 0x42, 0x26, 0xfc,
+	0x44, 0xf9, 0x53, 0x53, 0xa2, 0x37, 0xd7, 0xff, 0x63, 0x28, 0x93, 0x6c, 0x4a, 0xbf, 0xf6, 0x21,
+	0x87, 0x50, 0x6a, 0x1b, 0x37, 0x50, 0xe9, 0x79, 0xd8, 0x0b, 0xa3, 0xc3, 0xd0, 0xc8, 0xd4, 0xaa,
+	0xef, 0x3e, 0xd5, 0xe7, 0x52, 0x30, 0x01, 0xe0, 0x9d, 0x51, 0xdc, 0xec, 0x30, 0x78, 0x50, 0x8c,
+	0x6c, 0x6d, 0x1e, 0x28, 0x46, 0x4a, 0x51, 0x71, 0x7c, 0x1b, 0x95, 0x77, 0x29, 0x89, 0x5d, 0x0a,
+	0xa6, 0x8c, 0x5c, 0xed, 0x1a, 0x90, 0xae, 0xa6, 0xa4, 0x11, 0x04, 0xef, 0xa8, 0x19, 0x75, 0x43,
+	0x7c, 0x43, 0xab, 0x2d, 0x00, 0x0d, 0x4f, 0xd2, 0x88, 0x8f, 0xd7, 0x90, 0x6e, 0x93, 0xb8, 0x1f,
+	0x78, 0x2e, 0x17, 0xf9, 0xf2, 0xb5, 0xeb, 0x40, 0xac, 0x5e, 0xe8, 0x75, 0x0a, 0x8a, 0x8c, 0x6d,
+	0x1e, 0xc5, 0xa2, 0x1b, 0x46, 0x61, 0x32, 0xe3, 0x39, 0x22, 0xaa, 0x94, 0x7b, 0xf8, 0xdb, 0xe2,
+	0x64, 0x95, 0x09, 0xd0, 0x7a, 0x76, 0xf2, 0x63, 0x29, 0x73, 0x7c, 0xb6, 0x94, 0x3d, 0x81, 0xf5,
+	0x1d, 0xd6, 0xd1, 0xcf, 0xa5, 0xcc, 0x09, 0xac, 0xaf, 0xb0, 0x5e, 0x5a, 0xdd, 0x80, 0xef, 0x0f,
+	0x3a, 0xa6, 0x17, 0xbd, 0xb6, 0x92, 0xd6, 0x5b, 0xaa, 0xf5, 0x16, 0xb4, 0xde, 0xfa, 0xeb, 0x93,
+	0xd0, 0x29, 0xca, 0x37, 0xfa, 0x83, 0x3f, 0x4b, 0xdc, 0x6f, 0xac, 0x2e, 0x06, 0x00, 0x00,
 }
 
 func (m *Span) Marshal() (dAtA []byte, err error) {
@@ -485,6 +504,16 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SinkBytesPerSecond != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.SinkBytesPerSecond))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.SinkRowsPerSecond != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.SinkRowsPerSecond))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.BarrierTs != 0 {
 		i = encodeVarintTable(dAtA, i, uint64(m.BarrierTs))
 		i--
@@ -663,6 +692,12 @@ func (m *Stats) Size() (n int) {
 	if m.BarrierTs != 0 {
 		n += 1 + sovTable(uint64(m.BarrierTs))
 	}
+	if m.SinkRowsPerSecond != 0 {
+		n += 1 + sovTable(uint64(m.SinkRowsPerSecond))
+	}
+	if m.SinkBytesPerSecond != 0 {
+		n += 1 + sovTable(uint64(m.SinkBytesPerSecond))
+	}
 	return n
 }
 
@@ -1152,6 +1187,44 @@ func (m *Stats) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SinkRowsPerSecond", wireType)
+			}
+			m.SinkRowsPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SinkRowsPerSecond |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SinkBytesPerSecond", wireType)
+			}
+			m.SinkBytesPerSecond = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SinkBytesPerSecond |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTable(dAtA[iNdEx:])
@@ -0,0 +1,477 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/kv"
+	"github.com/pingcap/tiflow/cdc/kv/sharedconn"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/txnutil"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/tikv/client-go/v2/tikv"
+	"go.uber.org/zap"
+)
+
+// SharedConsumerID identifies a consumer of a SharedPuller.
+type SharedConsumerID uint64
+
+// defaultSharedConsumerBufferBytes is the max bytes of KV entries buffered
+// for a consumer. A consumer which falls further behind the shared stream
+// is detached, so that it can't make the capture run out of memory.
+const defaultSharedConsumerBufferBytes = 64 * 1024 * 1024
+
+// sharedConsumer buffers KV entries of a consumer, so that a slow consumer
+// never blocks the shared stream and other consumers of it.
+type sharedConsumer struct {
+	// handoffTs is the commit ts after which KV entries are sent to the consumer.
+	handoffTs   model.Ts
+	consume     func(*model.RawKVEntry)
+	detach      func()
+	bufferBytes int64
+
+	mu           sync.Mutex
+	pending      []*model.RawKVEntry
+	pendingBytes int64
+	// detached is true once the buffer is full, entries are dropped after that.
+	detached bool
+	notify   chan struct{}
+	stopped  chan struct{}
+	done     chan struct{}
+}
+
+func newSharedConsumer(
+	handoffTs model.Ts, consume func(*model.RawKVEntry), detach func(), bufferBytes int64,
+) *sharedConsumer {
+	c := &sharedConsumer{
+		handoffTs:   handoffTs,
+		consume:     consume,
+		detach:      detach,
+		bufferBytes: bufferBytes,
+		notify:      make(chan struct{}, 1),
+		stopped:     make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// push appends an entry to the buffer of the consumer without blocking.
+// If the buffer is full, the consumer is detached and the buffered entries
+// are dropped.
+func (c *sharedConsumer) push(raw *model.RawKVEntry) {
+	c.mu.Lock()
+	if c.detached {
+		c.mu.Unlock()
+		return
+	}
+	n := len(c.pending)
+	if raw.OpType == model.OpTypeResolved && n > 0 &&
+		c.pending[n-1].OpType == model.OpTypeResolved {
+		// Only the latest one of successive resolved ts is useful.
+		c.pending[n-1] = raw
+	} else {
+		size := raw.ApproximateDataSize()
+		if raw.OpType != model.OpTypeResolved && c.pendingBytes+size > c.bufferBytes {
+			c.detached = true
+			c.pending = nil
+			c.pendingBytes = 0
+		} else {
+			c.pending = append(c.pending, raw)
+			c.pendingBytes += size
+		}
+	}
+	c.mu.Unlock()
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *sharedConsumer) run() {
+	defer close(c.done)
+	for {
+		select {
+		case <-c.stopped:
+			return
+		case <-c.notify:
+		}
+		c.mu.Lock()
+		pending := c.pending
+		detached := c.detached
+		c.pending = nil
+		c.pendingBytes = 0
+		c.mu.Unlock()
+		for _, raw := range pending {
+			select {
+			case <-c.stopped:
+				return
+			default:
+			}
+			c.consume(raw)
+		}
+		if detached {
+			// All entries taken before the consumer is detached are consumed,
+			// and no more entries will be sent to it.
+			c.detach()
+			<-c.stopped
+			return
+		}
+	}
+}
+
+// stop stops the consumer and waits until consume returns. Buffered entries
+// are dropped.
+func (c *sharedConsumer) stop() {
+	close(c.stopped)
+	<-c.done
+}
+
+// sharedStream is a span subscribed by a SharedPuller.
+type sharedStream struct {
+	startTs model.Ts
+	// maxCommitTs is the max commit ts of KV entries fanned out.
+	maxCommitTs atomic.Uint64
+
+	// The lock is held while fanning out events, so that consumers can't
+	// join or leave the stream in the middle of it. Events are only pushed
+	// into buffers of consumers under the lock.
+	sync.RWMutex
+	consumers map[SharedConsumerID]*sharedConsumer
+}
+
+// SharedPuller pulls spans of an upstream for all changefeeds on a capture.
+// Each span is subscribed only once, and its raw KV entries are fanned out to
+// all changefeeds that subscribe it.
+type SharedPuller struct {
+	key    sharedPullerKey
+	client *kv.SharedClient
+	puller *MultiplexingPuller
+	// consumerBufferBytes is the max bytes buffered for each consumer.
+	consumerBufferBytes int64
+
+	// mu serializes Subscribe and Unsubscribe.
+	mu             sync.Mutex
+	nextConsumerID SharedConsumerID
+	streams        struct {
+		sync.RWMutex
+		m *spanz.HashMap[*sharedStream]
+	}
+
+	// Fields below are managed by SharedPullers.
+	refs   int
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+func newSharedPuller(
+	key sharedPullerKey,
+	up *upstream.Upstream,
+	workerCount int,
+	inputChannelIndexer func(tablepb.Span, int) int,
+) *SharedPuller {
+	// The changefeed ID is only used in logs and metrics.
+	changefeedID := model.ChangeFeedID{
+		Namespace: model.DefaultNamespace,
+		ID:        fmt.Sprintf("shared-puller-%d", key.upstreamID),
+	}
+	if key.filterLoop {
+		changefeedID.ID += "-bdr"
+	}
+	serverConfig := config.GetGlobalServerConfig()
+	grpcPool := sharedconn.NewConnAndClientPool(up.SecurityConfig, kv.GetGlobalGrpcMetrics())
+	client := kv.NewSharedClient(
+		changefeedID, serverConfig, key.filterLoop,
+		up.PDClient, grpcPool, up.RegionCache, up.PDClock,
+		txnutil.NewLockerResolver(up.KVStorage.(tikv.Storage), changefeedID),
+	)
+	return newSharedPullerWithClient(
+		key, changefeedID, client, up.PDClock, workerCount, inputChannelIndexer,
+		int(serverConfig.KVClient.FrontierConcurrent))
+}
+
+func newSharedPullerWithClient(
+	key sharedPullerKey,
+	changefeedID model.ChangeFeedID,
+	client *kv.SharedClient,
+	pdClock pdutil.Clock,
+	workerCount int,
+	inputChannelIndexer func(tablepb.Span, int) int,
+	resolvedTsAdvancerCount int,
+) *SharedPuller {
+	p := &SharedPuller{
+		key:                 key,
+		client:              client,
+		consumerBufferBytes: defaultSharedConsumerBufferBytes,
+		done:                make(chan struct{}),
+	}
+	p.streams.m = spanz.NewHashMap[*sharedStream]()
+	p.puller = NewMultiplexingPuller(
+		changefeedID, client, pdClock, p.fanOut,
+		workerCount, inputChannelIndexer, resolvedTsAdvancerCount)
+	return p
+}
+
+// Subscribe subscribes the span for a consumer which needs all KV entries
+// committed after startTs. KV entries committed after the returned handoff ts
+// and resolved ts of the shared stream are sent to consume. If the handoff ts
+// is greater than startTs, the consumer has joined a stream which started
+// earlier, and it must catch up entries committed in (startTs, handoffTs]
+// by itself. consume is called in a goroutine of the consumer, it receives
+// its own copy of each entry, but the byte slices in the entry are shared by
+// all consumers of the span and must not be modified in place.
+//
+// If the consumer falls too far behind the shared stream, it's detached:
+// detach is called in the goroutine of the consumer after the consumed
+// entries, and no more entries are sent to it. The consumer must then pull
+// the span by itself from its last resolved ts, and Unsubscribe the span.
+func (p *SharedPuller) Subscribe(
+	span tablepb.Span, startTs model.Ts, tableName string,
+	consume func(*model.RawKVEntry), detach func(),
+) (SharedConsumerID, model.Ts) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextConsumerID++
+	id := p.nextConsumerID
+
+	p.streams.RLock()
+	stream, ok := p.streams.m.Get(span)
+	p.streams.RUnlock()
+	if ok {
+		stream.Lock()
+		defer stream.Unlock()
+		// All KV entries fanned out before the consumer joins are committed
+		// before maxCommitTs, and later ones are sent to the consumer.
+		handoffTs := stream.startTs
+		if maxCommitTs := stream.maxCommitTs.Load(); maxCommitTs > handoffTs {
+			handoffTs = maxCommitTs
+		}
+		if startTs > handoffTs {
+			handoffTs = startTs
+		}
+		stream.consumers[id] = newSharedConsumer(handoffTs, consume, detach, p.consumerBufferBytes)
+		log.Info("consumer joins a shared stream",
+			zap.Uint64("upstreamID", p.key.upstreamID),
+			zap.String("tableName", tableName),
+			zap.Stringer("span", &span),
+			zap.Uint64("startTs", startTs),
+			zap.Uint64("handoffTs", handoffTs),
+			zap.Int("consumers", len(stream.consumers)))
+		return id, handoffTs
+	}
+
+	stream = &sharedStream{
+		startTs: startTs,
+		consumers: map[SharedConsumerID]*sharedConsumer{
+			id: newSharedConsumer(startTs, consume, detach, p.consumerBufferBytes),
+		},
+	}
+	p.streams.Lock()
+	p.streams.m.ReplaceOrInsert(span, stream)
+	p.streams.Unlock()
+	// Old update entries are split by consumers if necessary.
+	p.puller.Subscribe([]tablepb.Span{span}, startTs, tableName,
+		func(*model.RawKVEntry) bool { return false })
+	log.Info("shared stream is created",
+		zap.Uint64("upstreamID", p.key.upstreamID),
+		zap.String("tableName", tableName),
+		zap.Stringer("span", &span),
+		zap.Uint64("startTs", startTs))
+	return id, startTs
+}
+
+// Unsubscribe removes a consumer of the span. The span is unsubscribed after
+// all its consumers are removed. consume of the consumer is never called
+// after Unsubscribe returns.
+func (p *SharedPuller) Unsubscribe(span tablepb.Span, id SharedConsumerID) {
+	if c := p.removeConsumer(span, id); c != nil {
+		// Wait for the consumer out of p.mu, so that a slow consumer doesn't
+		// block others from subscribing or unsubscribing spans.
+		c.stop()
+	}
+}
+
+func (p *SharedPuller) removeConsumer(span tablepb.Span, id SharedConsumerID) *sharedConsumer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.streams.RLock()
+	stream, ok := p.streams.m.Get(span)
+	p.streams.RUnlock()
+	if !ok {
+		return nil
+	}
+	stream.Lock()
+	c := stream.consumers[id]
+	delete(stream.consumers, id)
+	remaining := len(stream.consumers)
+	stream.Unlock()
+	if remaining > 0 {
+		return c
+	}
+
+	p.streams.Lock()
+	p.streams.m.Delete(span)
+	p.streams.Unlock()
+	p.puller.Unsubscribe([]tablepb.Span{span})
+	log.Info("shared stream is removed",
+		zap.Uint64("upstreamID", p.key.upstreamID),
+		zap.Stringer("span", &span))
+	return c
+}
+
+// fanOut sends an event of a span to all consumers of the span. Each consumer
+// gets a copy of the event, so that they can't affect each other.
+func (p *SharedPuller) fanOut(
+	_ context.Context, raw *model.RawKVEntry, spans []tablepb.Span, _ model.ShouldSplitKVEntry,
+) error {
+	if raw == nil {
+		return nil
+	}
+	p.streams.RLock()
+	stream, ok := p.streams.m.Get(spans[0])
+	p.streams.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	stream.RLock()
+	defer stream.RUnlock()
+	isResolved := raw.OpType == model.OpTypeResolved
+	if !isResolved {
+		for {
+			maxCommitTs := stream.maxCommitTs.Load()
+			if raw.CRTs <= maxCommitTs || stream.maxCommitTs.CompareAndSwap(maxCommitTs, raw.CRTs) {
+				break
+			}
+		}
+	}
+	for _, c := range stream.consumers {
+		if isResolved || raw.CRTs > c.handoffTs {
+			entry := *raw
+			c.push(&entry)
+		}
+	}
+	return nil
+}
+
+// Stats returns the stats of the shared stream of the span.
+func (p *SharedPuller) Stats(span tablepb.Span) Stats {
+	return p.puller.Stats(span)
+}
+
+// Done returns a channel which is closed after the shared puller exits.
+func (p *SharedPuller) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error which makes the shared puller exit.
+// It must be called after Done is closed.
+func (p *SharedPuller) Err() error {
+	return p.err
+}
+
+type sharedPullerKey struct {
+	upstreamID uint64
+	filterLoop bool
+}
+
+// SharedPullers holds shared pullers of a capture. There is one shared puller
+// for each upstream, and changefeeds in BDR mode share another one because
+// they filter out loop-back events in TiKV.
+type SharedPullers struct {
+	mu      sync.Mutex
+	pullers map[sharedPullerKey]*SharedPuller
+}
+
+// NewSharedPullers creates a SharedPullers.
+func NewSharedPullers() *SharedPullers {
+	return &SharedPullers{pullers: make(map[sharedPullerKey]*SharedPuller)}
+}
+
+// Acquire returns the shared puller of the upstream, and starts it if it's
+// not running. The returned puller must be released by Release.
+func (s *SharedPullers) Acquire(
+	up *upstream.Upstream,
+	filterLoop bool,
+	workerCount int,
+	inputChannelIndexer func(tablepb.Span, int) int,
+) *SharedPuller {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sharedPullerKey{upstreamID: up.ID, filterLoop: filterLoop}
+	p, ok := s.pullers[key]
+	if !ok {
+		p = newSharedPuller(key, up, workerCount, inputChannelIndexer)
+		s.start(p)
+	}
+	p.refs++
+	return p
+}
+
+func (s *SharedPullers) start(p *SharedPuller) {
+	s.pullers[p.key] = p
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+	go func() {
+		err := p.puller.Run(ctx)
+		if err == nil {
+			err = context.Canceled
+		}
+		log.Info("shared puller exits",
+			zap.Uint64("upstreamID", p.key.upstreamID),
+			zap.Bool("filterLoop", p.key.filterLoop),
+			zap.Error(err))
+
+		// A failed puller can't be acquired any more.
+		s.mu.Lock()
+		if s.pullers[p.key] == p {
+			delete(s.pullers, p.key)
+		}
+		s.mu.Unlock()
+		p.err = err
+		close(p.done)
+	}()
+}
+
+// Release releases a shared puller returned by Acquire. The puller is
+// stopped after it's released by all its users.
+func (s *SharedPullers) Release(p *SharedPuller) {
+	s.mu.Lock()
+	p.refs--
+	if p.refs > 0 {
+		s.mu.Unlock()
+		return
+	}
+	if s.pullers[p.key] == p {
+		delete(s.pullers, p.key)
+	}
+	s.mu.Unlock()
+
+	p.cancel()
+	<-p.done
+	p.client.Close()
+}
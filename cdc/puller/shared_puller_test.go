@@ -0,0 +1,206 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package puller
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/kv"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func newSharedPullerForTest() *SharedPuller {
+	cfg := &config.ServerConfig{Debug: &config.DebugConfig{Puller: &config.PullerConfig{LogRegionDetails: false}}}
+	client := kv.NewSharedClient(model.ChangeFeedID{}, cfg, false, nil, nil, nil, nil, nil)
+	return newSharedPullerWithClient(
+		sharedPullerKey{upstreamID: 1}, model.ChangeFeedID{}, client, pdutil.NewClock4Test(),
+		1, func(tablepb.Span, int) int { return 0 }, 1)
+}
+
+func TestSharedPullerFanOut(t *testing.T) {
+	p := newSharedPullerForTest()
+	defer p.client.Close()
+
+	span := spanz.ToSpan([]byte("t_a"), []byte("t_e"))
+	span.TableID = 1
+	var mu sync.Mutex
+	received := make(map[string][]*model.RawKVEntry)
+	consume := func(name string) func(*model.RawKVEntry) {
+		return func(raw *model.RawKVEntry) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[name] = append(received[name], raw)
+		}
+	}
+	fanOut := func(raw *model.RawKVEntry) {
+		require.Nil(t, p.fanOut(context.Background(), raw, []tablepb.Span{span}, nil))
+	}
+
+	// The first consumer creates the shared stream.
+	idA, handoffTs := p.Subscribe(span, 100, "t", consume("a"), nil)
+	require.Equal(t, model.Ts(100), handoffTs)
+	require.Equal(t, 1, p.puller.subscriptions.n.Len())
+	fanOut(&model.RawKVEntry{OpType: model.OpTypePut, CRTs: 105})
+
+	// A consumer starts before the stream must catch up to the max commit ts.
+	idB, handoffTs := p.Subscribe(span, 90, "t", consume("b"), nil)
+	require.Equal(t, model.Ts(105), handoffTs)
+	// A consumer starts after the stream joins it directly.
+	idC, handoffTs := p.Subscribe(span, 200, "t", consume("c"), nil)
+	require.Equal(t, model.Ts(200), handoffTs)
+	require.Equal(t, 1, p.puller.subscriptions.n.Len())
+
+	fanOut(&model.RawKVEntry{OpType: model.OpTypePut, CRTs: 103})
+	fanOut(&model.RawKVEntry{OpType: model.OpTypePut, CRTs: 110})
+	fanOut(&model.RawKVEntry{OpType: model.OpTypePut, CRTs: 201})
+	fanOut(&model.RawKVEntry{OpType: model.OpTypeResolved, CRTs: 150})
+
+	crts := func(name string) []model.Ts {
+		mu.Lock()
+		defer mu.Unlock()
+		var res []model.Ts
+		for _, raw := range received[name] {
+			res = append(res, raw.CRTs)
+		}
+		return res
+	}
+	require.Eventually(t, func() bool {
+		return len(crts("a")) == 5 && len(crts("b")) == 3 && len(crts("c")) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []model.Ts{105, 103, 110, 201, 150}, crts("a"))
+	require.Equal(t, []model.Ts{110, 201, 150}, crts("b"))
+	require.Equal(t, []model.Ts{201, 150}, crts("c"))
+
+	// The span is unsubscribed after all consumers leave.
+	p.Unsubscribe(span, idA)
+	p.Unsubscribe(span, idB)
+	fanOut(&model.RawKVEntry{OpType: model.OpTypePut, CRTs: 202})
+	require.Eventually(t, func() bool {
+		return len(crts("c")) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, crts("a"), 5)
+	require.Equal(t, 1, p.puller.subscriptions.n.Len())
+	p.Unsubscribe(span, idC)
+	require.Equal(t, 0, p.puller.subscriptions.n.Len())
+
+	// A new stream starts from the start ts of its first consumer.
+	_, handoffTs = p.Subscribe(span, 300, "t", consume("d"), nil)
+	require.Equal(t, model.Ts(300), handoffTs)
+}
+
+func TestSharedPullerSlowConsumer(t *testing.T) {
+	p := newSharedPullerForTest()
+	defer p.client.Close()
+
+	span := spanz.ToSpan([]byte("t_a"), []byte("t_e"))
+	span.TableID = 1
+	block := make(chan struct{})
+	slowCh := make(chan *model.RawKVEntry, 16)
+	fastCh := make(chan *model.RawKVEntry, 16)
+	idSlow, _ := p.Subscribe(span, 100, "t", func(raw *model.RawKVEntry) {
+		<-block
+		slowCh <- raw
+	}, nil)
+	idFast, _ := p.Subscribe(span, 100, "t", func(raw *model.RawKVEntry) {
+		// Consumers can't affect each other by modifying entries.
+		raw.Value = nil
+		fastCh <- raw
+	}, nil)
+
+	// The slow consumer doesn't block the shared stream and other consumers.
+	for _, crts := range []model.Ts{101, 102} {
+		raw := &model.RawKVEntry{OpType: model.OpTypePut, CRTs: crts, Value: []byte("v")}
+		require.Nil(t, p.fanOut(context.Background(), raw, []tablepb.Span{span}, nil))
+	}
+	for _, crts := range []model.Ts{101, 102} {
+		raw := <-fastCh
+		require.Equal(t, crts, raw.CRTs)
+		require.Nil(t, raw.Value)
+	}
+
+	close(block)
+	for _, crts := range []model.Ts{101, 102} {
+		raw := <-slowCh
+		require.Equal(t, crts, raw.CRTs)
+		require.Equal(t, []byte("v"), raw.Value)
+	}
+	p.Unsubscribe(span, idSlow)
+	p.Unsubscribe(span, idFast)
+	require.Equal(t, 0, p.puller.subscriptions.n.Len())
+}
+
+func TestSharedPullerDetachConsumer(t *testing.T) {
+	p := newSharedPullerForTest()
+	defer p.client.Close()
+	p.consumerBufferBytes = 2 * (&model.RawKVEntry{OpType: model.OpTypePut, Value: []byte("v")}).ApproximateDataSize()
+
+	span := spanz.ToSpan([]byte("t_a"), []byte("t_e"))
+	span.TableID = 1
+	block := make(chan struct{})
+	slowCh := make(chan *model.RawKVEntry, 16)
+	detached := make(chan struct{})
+	idSlow, _ := p.Subscribe(span, 100, "t", func(raw *model.RawKVEntry) {
+		<-block
+		slowCh <- raw
+	}, func() { close(detached) })
+	fastCh := make(chan *model.RawKVEntry, 16)
+	idFast, _ := p.Subscribe(span, 100, "t", func(raw *model.RawKVEntry) {
+		fastCh <- raw
+	}, nil)
+
+	fanOut := func(raw *model.RawKVEntry) {
+		require.Nil(t, p.fanOut(context.Background(), raw, []tablepb.Span{span}, nil))
+	}
+	// The first entry is taken by the slow consumer, it blocks the consumer
+	// until the buffer of the consumer is full.
+	fanOut(&model.RawKVEntry{OpType: model.OpTypePut, CRTs: 101, Value: []byte("v")})
+	require.Equal(t, model.Ts(101), (<-fastCh).CRTs)
+	stream, ok := p.streams.m.Get(span)
+	require.True(t, ok)
+	require.Eventually(t, func() bool {
+		c := stream.consumers[idSlow]
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.pending) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	for _, crts := range []model.Ts{102, 103, 104} {
+		fanOut(&model.RawKVEntry{OpType: model.OpTypePut, CRTs: crts, Value: []byte("v")})
+		require.Equal(t, crts, (<-fastCh).CRTs)
+	}
+	fanOut(&model.RawKVEntry{OpType: model.OpTypeResolved, CRTs: 104})
+	require.Equal(t, model.Ts(104), (<-fastCh).CRTs)
+
+	// The buffered entries and the resolved ts are dropped, only the entry
+	// taken before is consumed before the consumer is detached.
+	close(block)
+	select {
+	case <-detached:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the slow consumer should be detached")
+	}
+	require.Len(t, slowCh, 1)
+	require.Equal(t, model.Ts(101), (<-slowCh).CRTs)
+
+	p.Unsubscribe(span, idSlow)
+	p.Unsubscribe(span, idFast)
+	require.Equal(t, 0, p.puller.subscriptions.n.Len())
+}
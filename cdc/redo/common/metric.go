@@ -84,6 +84,14 @@ var (
 			Name:      "worker_busy_ratio",
 			Help:      "Busy ratio (X ms in 1s) for redo bgUpdateLog worker.",
 		}, []string{"namespace", "changefeed"})
+
+	// RedoLogSizeGauge records the total size of redo log files retained in storage.
+	RedoLogSizeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "log_size_bytes",
+		Help:      "Total size of redo log files retained in the external storage",
+	}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file
@@ -95,4 +103,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(RedoWriteLogDurationHistogram)
 	registry.MustRegister(RedoFlushLogDurationHistogram)
 	registry.MustRegister(RedoWorkerBusyRatio)
+	registry.MustRegister(RedoLogSizeGauge)
 }
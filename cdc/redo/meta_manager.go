@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/uuid"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -44,6 +46,9 @@ type MetaManager interface {
 	UpdateMeta(checkpointTs, resolvedTs model.Ts)
 	// GetFlushedMeta returns the flushed meta.
 	GetFlushedMeta() common.LogMeta
+	// GetLogSize returns the total size in bytes of the redo log files
+	// retained in the external storage, which is refreshed after each GC.
+	GetLogSize() int64
 	// Cleanup deletes all redo logs, which are only called from the owner
 	// when changefeed is deleted.
 	Cleanup(ctx context.Context) error
@@ -74,6 +79,10 @@ type metaManager struct {
 	lastFlushTime          time.Time
 	cfg                    *config.ConsistentConfig
 	metricFlushLogDuration prometheus.Observer
+	metricLogSize          prometheus.Gauge
+
+	// logSize is the total size of log files retained after the last GC.
+	logSize atomic.Int64
 
 	flushIntervalInMs int64
 }
@@ -145,6 +154,8 @@ func (m *metaManager) preStart(ctx context.Context) error {
 
 	m.metricFlushLogDuration = common.RedoFlushLogDurationHistogram.
 		WithLabelValues(m.changeFeedID.Namespace, m.changeFeedID.ID)
+	m.metricLogSize = common.RedoLogSizeGauge.
+		WithLabelValues(m.changeFeedID.Namespace, m.changeFeedID.ID)
 
 	err = m.preCleanupExtStorage(ctx)
 	if err != nil {
@@ -211,6 +222,11 @@ func (m *metaManager) GetFlushedMeta() common.LogMeta {
 	return common.LogMeta{CheckpointTs: checkpointTs, ResolvedTs: resolvedTs}
 }
 
+// GetLogSize returns the total size of retained redo log files.
+func (m *metaManager) GetLogSize() int64 {
+	return m.logSize.Load()
+}
+
 // initMeta will read the meta file from external storage and
 // use it to initialize the meta field of the metaManager.
 func (m *metaManager) initMeta(ctx context.Context) error {
@@ -316,15 +332,22 @@ func (m *metaManager) preCleanupExtStorage(ctx context.Context) error {
 	return nil
 }
 
-// shouldRemoved remove the file which maxCommitTs in file name less than checkPointTs, since
-// all event ts < checkPointTs already sent to sink, the log is not needed any more for recovery
-func (m *metaManager) shouldRemoved(path string, checkPointTs uint64) bool {
+// logFile is a redo log file of the changefeed found in the external storage.
+type logFile struct {
+	path     string
+	commitTs uint64
+	size     int64
+}
+
+// parseLogFile returns the maxCommitTs in the file name if the path is a
+// redo log file which belongs to this changefeed.
+func (m *metaManager) parseLogFile(path string) (uint64, bool) {
 	changefeedMatcher := getChangefeedMatcher(m.changeFeedID)
 	if !strings.Contains(path, changefeedMatcher) {
-		return false
+		return 0, false
 	}
 	if filepath.Ext(path) != redo.LogEXT {
-		return false
+		return 0, false
 	}
 
 	commitTs, fileType, err := redo.ParseLogFileName(path)
@@ -333,7 +356,7 @@ func (m *metaManager) shouldRemoved(path string, checkPointTs uint64) bool {
 			zap.String("namespace", m.changeFeedID.Namespace),
 			zap.String("changefeed", m.changeFeedID.ID),
 			zap.String("path", path), zap.Error(err))
-		return false
+		return 0, false
 	}
 	if fileType != redo.RedoDDLLogFileType && fileType != redo.RedoRowLogFileType {
 		log.Panic("unknown file type",
@@ -341,10 +364,90 @@ func (m *metaManager) shouldRemoved(path string, checkPointTs uint64) bool {
 			zap.String("changefeed", m.changeFeedID.ID),
 			zap.String("path", path), zap.Any("fileType", fileType))
 	}
+	return commitTs, true
+}
 
-	// if commitTs == checkPointTs, the DDL may be executed in the owner,
-	// so we should not delete it.
-	return commitTs < checkPointTs
+// selectExpiredLogs returns the log files which can be removed. A file is only
+// a candidate if its maxCommitTs is less than checkPointTs, since all events
+// ts < checkPointTs are already sent to sink and the file is not needed any
+// more for recovery. If commitTs == checkPointTs, the DDL may be executed in
+// the owner, so it must not be deleted.
+//
+// Candidates are retained from the newest to the oldest as long as they are
+// within the configured retention time and retention size. Once a file falls
+// out of the retention window, all older files are expired too, so that the
+// retained logs always cover a continuous range before the checkpoint.
+func (m *metaManager) selectExpiredLogs(files []logFile, checkPointTs uint64) []logFile {
+	candidates := make([]logFile, 0, len(files))
+	for _, f := range files {
+		if f.commitTs < checkPointTs {
+			candidates = append(candidates, f)
+		}
+	}
+	if m.cfg.RetentionTimeInSec <= 0 && m.cfg.RetentionSize <= 0 {
+		return candidates
+	}
+
+	retentionTs := uint64(0)
+	if m.cfg.RetentionTimeInSec > 0 {
+		physical := oracle.ExtractPhysical(checkPointTs) - m.cfg.RetentionTimeInSec*1000
+		if physical > 0 {
+			retentionTs = oracle.ComposeTS(physical, 0)
+		}
+	}
+	retentionSize := m.cfg.RetentionSize * redo.Megabyte
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].commitTs > candidates[j].commitTs
+	})
+	var retainedSize int64
+	for i, f := range candidates {
+		if f.commitTs < retentionTs ||
+			(retentionSize > 0 && retainedSize+f.size > retentionSize) {
+			return candidates[i:]
+		}
+		retainedSize += f.size
+	}
+	return nil
+}
+
+// gcLogs removes the expired log files and refreshes the retained log size.
+func (m *metaManager) gcLogs(ctx context.Context, checkPointTs uint64) error {
+	var files []logFile
+	var totalSize int64
+	err := m.extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
+		path = strings.TrimPrefix(path, "/")
+		if commitTs, ok := m.parseLogFile(path); ok {
+			files = append(files, logFile{path: path, commitTs: commitTs, size: size})
+			totalSize += size
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+
+	expired := m.selectExpiredLogs(files, checkPointTs)
+	toRemoveFiles := make([]string, 0, len(expired))
+	for _, f := range expired {
+		toRemoveFiles = append(toRemoveFiles, f.path)
+		totalSize -= f.size
+	}
+	if err := util.DeleteFilesInExtStorage(ctx, m.extStorage, toRemoveFiles); err != nil {
+		return errors.Trace(err)
+	}
+
+	m.logSize.Store(totalSize)
+	if m.metricLogSize != nil {
+		m.metricLogSize.Set(float64(totalSize))
+	}
+	log.Debug("redo manager GC finished",
+		zap.String("namespace", m.changeFeedID.Namespace),
+		zap.String("changefeed", m.changeFeedID.ID),
+		zap.Uint64("checkpointTs", checkPointTs),
+		zap.Int("removedFiles", len(toRemoveFiles)),
+		zap.Int64("retainedSize", totalSize))
+	return nil
 }
 
 // deleteAllLogs delete all redo logs and leave a deleted mark.
@@ -480,6 +583,8 @@ func (m *metaManager) Cleanup(ctx context.Context) error {
 		DeleteLabelValues(m.changeFeedID.Namespace, m.changeFeedID.ID)
 	common.RedoWorkerBusyRatio.
 		DeleteLabelValues(m.changeFeedID.Namespace, m.changeFeedID.ID)
+	common.RedoLogSizeGauge.
+		DeleteLabelValues(m.changeFeedID.Namespace, m.changeFeedID.ID)
 	return m.deleteAllLogs(ctx)
 }
 
@@ -505,7 +610,8 @@ func (m *metaManager) bgFlushMeta(egCtx context.Context) (err error) {
 	}
 }
 
-// bgGC cleans stale files before the flushed checkpoint in background.
+// bgGC cleans stale files before the flushed checkpoint minus the retention
+// window in background.
 func (m *metaManager) bgGC(egCtx context.Context) error {
 	ticker := time.NewTicker(time.Duration(redo.DefaultGCIntervalInMs) * time.Millisecond)
 	defer ticker.Stop()
@@ -528,10 +634,7 @@ func (m *metaManager) bgGC(egCtx context.Context) error {
 				zap.Uint64("checkpointTs", ckpt),
 				zap.String("namespace", m.changeFeedID.Namespace),
 				zap.String("changefeed", m.changeFeedID.ID))
-			err := util.RemoveFilesIf(egCtx, m.extStorage, func(path string) bool {
-				return m.shouldRemoved(path, ckpt)
-			}, nil)
-			if err != nil {
+			if err := m.gcLogs(egCtx, ckpt); err != nil {
 				log.Warn("redo manager log GC fail",
 					zap.String("namespace", m.changeFeedID.Namespace),
 					zap.String("changefeed", m.changeFeedID.ID), zap.Error(err))
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/uuid"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -332,3 +333,100 @@ func TestGCAndCleanup(t *testing.T) {
 	})
 	require.Equal(t, 1, cnt)
 }
+
+func TestSelectExpiredLogs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tsAt := func(secondsAgo int) uint64 {
+		return oracle.GoTimeToTS(now.Add(-time.Duration(secondsAgo) * time.Second))
+	}
+	checkpointTs := tsAt(0)
+	files := []logFile{
+		{path: "f1", commitTs: tsAt(300), size: redo.Megabyte},
+		{path: "f2", commitTs: tsAt(200), size: redo.Megabyte},
+		{path: "f3", commitTs: tsAt(100), size: redo.Megabyte},
+		{path: "f4", commitTs: tsAt(10), size: redo.Megabyte},
+		{path: "f5", commitTs: checkpointTs, size: redo.Megabyte},
+		{path: "f6", commitTs: checkpointTs + 1, size: redo.Megabyte},
+	}
+	getPaths := func(files []logFile) []string {
+		paths := make([]string, 0, len(files))
+		for _, f := range files {
+			paths = append(paths, f.path)
+		}
+		return paths
+	}
+
+	cases := []struct {
+		retentionTime int64
+		retentionSize int64
+		expected      []string
+	}{
+		// No retention, all files below the checkpoint are removed.
+		{expected: []string{"f1", "f2", "f3", "f4"}},
+		{retentionTime: 150, expected: []string{"f2", "f1"}},
+		{retentionTime: 1000, expected: []string{}},
+		{retentionSize: 2, expected: []string{"f2", "f1"}},
+		{retentionSize: 100, expected: []string{}},
+		// Both limits are applied.
+		{retentionTime: 250, retentionSize: 1, expected: []string{"f3", "f2", "f1"}},
+		{retentionTime: 50, retentionSize: 100, expected: []string{"f3", "f2", "f1"}},
+	}
+	for _, c := range cases {
+		m := &metaManager{cfg: &config.ConsistentConfig{
+			RetentionTimeInSec: c.retentionTime,
+			RetentionSize:      c.retentionSize,
+		}}
+		expired := m.selectExpiredLogs(files, checkpointTs)
+		require.Equal(t, c.expected, getPaths(expired), "case: %+v", c)
+	}
+}
+
+func TestGCWithRetention(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeedID := model.DefaultChangeFeedID("test-changefeed")
+	extStorage, uri, err := util.GetTestExtStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	// write 10 log files, each of which is 1KiB.
+	for i := 1; i <= 10; i++ {
+		fileName := fmt.Sprintf(redo.RedoLogFileFormatV1, "test-capture", changefeedID.ID,
+			redo.RedoRowLogFileType, i, uuid.NewGenerator().NewString(), redo.LogEXT)
+		err := extStorage.WriteFile(ctx, fileName, make([]byte, 1024))
+		require.NoError(t, err)
+	}
+
+	cfg := &config.ConsistentConfig{
+		Level:                 string(redo.ConsistentLevelEventual),
+		MaxLogSize:            redo.DefaultMaxLogSize,
+		Storage:               uri.String(),
+		FlushIntervalInMs:     redo.MinFlushIntervalInMs,
+		MetaFlushIntervalInMs: redo.MinFlushIntervalInMs,
+		RetentionSize:         1,
+	}
+	m := NewMetaManager(changefeedID, cfg, 5)
+	m.extStorage = extStorage
+
+	// All files are retained since they are within the retention size.
+	require.NoError(t, m.gcLogs(ctx, 5))
+	require.Equal(t, int64(10*1024), m.GetLogSize())
+
+	// Files needed by the checkpoint are never removed.
+	m.cfg.RetentionSize = 0
+	require.NoError(t, m.gcLogs(ctx, 5))
+	require.Equal(t, int64(6*1024), m.GetLogSize())
+	cnt := 0
+	err = extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
+		commitTs, _, err := redo.ParseLogFileName(path)
+		require.NoError(t, err)
+		require.LessOrEqual(t, uint64(5), commitTs)
+		cnt++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 6, cnt)
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo/writer"
 	"github.com/pingcap/tiflow/cdc/redo/writer/file"
 	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"go.uber.org/zap"
@@ -71,6 +72,7 @@ type readerConfig struct {
 	uri                url.URL
 	useExternalStorage bool
 	workerNums         int
+	masterKey          encryption.MasterKey
 }
 
 type reader struct {
@@ -214,6 +216,20 @@ func isLZ4Compressed(data []byte) bool {
 	return bytes.Equal(data[:4], lz4MagicNumber)
 }
 
+// getLogFileCompression returns the compression recorded in the name of the
+// log file, which is like `xxx_uuid.zst.log`. The files without it are written
+// by the old versions, which only compress the logs by lz4.
+func getLogFileCompression(fileName string, data []byte) string {
+	ext := filepath.Ext(strings.TrimSuffix(filepath.Base(fileName), redo.LogEXT))
+	if cc := compression.FromFileExtension(ext); cc != compression.None {
+		return cc
+	}
+	if isLZ4Compressed(data) {
+		return compression.LZ4
+	}
+	return compression.None
+}
+
 func readAllFromBuffer(buf []byte) (logHeap, error) {
 	r := &reader{
 		br: bytes.NewReader(buf),
@@ -262,9 +278,13 @@ func sortAndWriteFile(
 		log.Warn("download file is empty", zap.String("file", fileName))
 		return nil
 	}
-	// it's lz4 compressed, decompress it
-	if isLZ4Compressed(fileContent) {
-		if fileContent, err = compression.Decode(compression.LZ4, fileContent); err != nil {
+	// decrypt it if it's encrypted, the data is compressed before encrypted
+	if fileContent, err = encryption.Decrypt(cfg.masterKey, fileContent); err != nil {
+		return err
+	}
+	// decompress it by the compression recorded in the file name
+	if cc := getLogFileCompression(fileName, fileContent); cc != compression.None {
+		if fileContent, err = compression.Decode(cc, fileContent); err != nil {
 			return err
 		}
 	}
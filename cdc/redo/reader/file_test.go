@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"github.com/stretchr/testify/require"
 )
@@ -77,3 +78,30 @@ func TestFileReaderRead(t *testing.T) {
 		require.NoError(t, r.Close())
 	}
 }
+
+func TestGetLogFileCompression(t *testing.T) {
+	t.Parallel()
+
+	lz4Data, err := compression.Encode(compression.LZ4, []byte("redo log"))
+	require.NoError(t, err)
+	// the data isn't guessed to be compressed by its leading bytes.
+	zstdMagic := []byte{0x28, 0xB5, 0x2F, 0xFD, 0x00}
+	for _, tc := range []struct {
+		fileName string
+		data     []byte
+		expected string
+	}{
+		{"dir/cp_default_cf_row_10_uuid.zst.log", nil, compression.Zstd},
+		{"dir/cp_default_cf_row_10_uuid.gz.log", nil, compression.Gzip},
+		{"dir/cp_default_cf_row_10_uuid.lz4.log", nil, compression.LZ4},
+		{"dir/cp_default_cf_row_10_uuid.log", zstdMagic, compression.None},
+		// the files written by the old versions are compressed by lz4.
+		{"dir/cp_default_cf_row_10_uuid.log", lz4Data, compression.LZ4},
+	} {
+		require.Equal(t, tc.expected, getLogFileCompression(tc.fileName, tc.data), tc.fileName)
+		commitTs, fileType, err := redo.ParseLogFileName(tc.fileName)
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), commitTs)
+		require.Equal(t, redo.RedoRowLogFileType, fileType)
+	}
+}
@@ -17,15 +17,21 @@ import (
 	"container/heap"
 	"context"
 	"io"
+	"math"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/pingcap/log"
+	tfilter "github.com/pingcap/tidb/pkg/util/table-filter"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -81,14 +87,37 @@ type LogReaderConfig struct {
 	// will load the file to memory first then write the sorted file to disk
 	// the memory used is WorkerNums * defaultMaxLogSize (64 * megabyte) total
 	WorkerNums int
+
+	// EncryptionMasterKey is the URI of the master key used to decrypt the
+	// encrypted redo logs, it's not required if the redo logs are not encrypted.
+	EncryptionMasterKey string
+
+	// StartTs is the commit ts after which the redo logs are read, the
+	// checkpoint ts in the redo meta is used if it's zero. It can be less
+	// than the checkpoint ts if the logs are retained in the storage.
+	StartTs uint64
+	// StartTsFromMeta indicates the StartTs is a checkpoint ts read from the
+	// redo meta before, rather than a ts all the events at and before which
+	// are applied, so the DDL at the StartTs is read again.
+	StartTsFromMeta bool
+
+	// TargetTs is the max commit ts of the redo logs to read, the resolved ts
+	// in the redo meta is used if it's zero.
+	TargetTs uint64
+
+	// FilterRules selects the tables whose redo logs are read, it is in the same
+	// format as `FilterConfig.Rules`. All tables are read if it's empty.
+	FilterRules []string
 }
 
 // LogReader implement RedoLogReader interface
 type LogReader struct {
-	cfg   *LogReaderConfig
-	meta  *common.LogMeta
-	rowCh chan *model.RowChangedEventInRedoLog
-	ddlCh chan *model.DDLEvent
+	cfg       *LogReaderConfig
+	masterKey encryption.MasterKey
+	filter    tfilter.Filter
+	meta      *common.LogMeta
+	rowCh     chan *model.RowChangedEventInRedoLog
+	ddlCh     chan *model.DDLEvent
 }
 
 // newLogReader creates a LogReader instance.
@@ -103,11 +132,26 @@ func newLogReader(ctx context.Context, cfg *LogReaderConfig) (*LogReader, error)
 	if cfg.WorkerNums == 0 {
 		cfg.WorkerNums = defaultWorkerNum
 	}
+	masterKey, err := encryption.NewMasterKey(cfg.EncryptionMasterKey)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid, err)
+	}
+
+	var tableFilter tfilter.Filter
+	if len(cfg.FilterRules) != 0 {
+		tableFilter, err = filter.VerifyTableRules(&config.FilterConfig{Rules: cfg.FilterRules})
+		if err != nil {
+			return nil, err
+		}
+		tableFilter = tfilter.CaseInsensitive(tableFilter)
+	}
 
 	logReader := &LogReader{
-		cfg:   cfg,
-		rowCh: make(chan *model.RowChangedEventInRedoLog, defaultReaderChanSize),
-		ddlCh: make(chan *model.DDLEvent, defaultReaderChanSize),
+		cfg:       cfg,
+		masterKey: masterKey,
+		filter:    tableFilter,
+		rowCh:     make(chan *model.RowChangedEventInRedoLog, defaultReaderChanSize),
+		ddlCh:     make(chan *model.DDLEvent, defaultReaderChanSize),
 	}
 	// remove logs in local dir first, if have logs left belongs to previous changefeed with the same name may have error when apply logs
 	if err := os.RemoveAll(cfg.Dir); err != nil {
@@ -151,20 +195,29 @@ func (l *LogReader) runRowReader(egCtx context.Context) error {
 		uri:                l.cfg.URI,
 		useExternalStorage: l.cfg.UseExternalStorage,
 		workerNums:         l.cfg.WorkerNums,
+		masterKey:          l.masterKey,
 	}
 	return l.runReader(egCtx, rowCfg)
 }
 
 func (l *LogReader) runDDLReader(egCtx context.Context) error {
 	defer close(l.ddlCh)
+	// The DDL at the checkpoint ts may not be executed in the downstream,
+	// so it is read again unless the logs are read from a given start ts,
+	// which means all events before and at the start ts are applied.
+	ddlStartTs := l.meta.CheckpointTs - 1
+	if l.cfg.StartTs != 0 && !l.cfg.StartTsFromMeta {
+		ddlStartTs = l.meta.CheckpointTs
+	}
 	ddlCfg := &readerConfig{
-		startTs:            l.meta.CheckpointTs - 1,
+		startTs:            ddlStartTs,
 		endTs:              l.meta.ResolvedTs,
 		dir:                l.cfg.Dir,
 		fileType:           redo.RedoDDLLogFileType,
 		uri:                l.cfg.URI,
 		useExternalStorage: l.cfg.UseExternalStorage,
 		workerNums:         l.cfg.WorkerNums,
+		masterKey:          l.masterKey,
 	}
 	return l.runReader(egCtx, ddlCfg)
 }
@@ -198,7 +251,8 @@ func (l *LogReader) runReader(egCtx context.Context, cfg *readerConfig) error {
 			row := item.data.RedoRow.Row
 			// By design only data (startTs,endTs] is needed,
 			// so filter out data may beyond the boundary.
-			if row != nil && row.CommitTs > cfg.startTs && row.CommitTs <= cfg.endTs {
+			if row != nil && row.CommitTs > cfg.startTs && row.CommitTs <= cfg.endTs &&
+				l.matchRow(row) {
 				select {
 				case <-egCtx.Done():
 					return errors.Trace(egCtx.Err())
@@ -207,7 +261,8 @@ func (l *LogReader) runReader(egCtx context.Context, cfg *readerConfig) error {
 			}
 		case redo.RedoDDLLogFileType:
 			ddl := item.data.RedoDDL.DDL
-			if ddl != nil && ddl.CommitTs > cfg.startTs && ddl.CommitTs <= cfg.endTs {
+			if ddl != nil && ddl.CommitTs > cfg.startTs && ddl.CommitTs <= cfg.endTs &&
+				l.matchDDL(ddl) {
 				select {
 				case <-egCtx.Done():
 					return errors.Trace(egCtx.Err())
@@ -233,6 +288,35 @@ func (l *LogReader) runReader(egCtx context.Context, cfg *readerConfig) error {
 	return nil
 }
 
+// matchRow returns whether the row belongs to a table selected by the filter.
+func (l *LogReader) matchRow(row *model.RowChangedEventInRedoLog) bool {
+	if l.filter == nil {
+		return true
+	}
+	return row.Table != nil && l.filter.MatchTable(row.Table.Schema, row.Table.Table)
+}
+
+// matchDDL returns whether the DDL changes a schema or table selected by the filter.
+func (l *LogReader) matchDDL(ddl *model.DDLEvent) bool {
+	if l.filter == nil {
+		return true
+	}
+	for _, tableInfo := range []*model.TableInfo{ddl.TableInfo, ddl.PreTableInfo} {
+		if tableInfo == nil {
+			continue
+		}
+		tableName := tableInfo.TableName
+		if tableName.Table == "" {
+			if l.filter.MatchSchema(tableName.Schema) {
+				return true
+			}
+		} else if l.filter.MatchTable(tableName.Schema, tableName.Table) {
+			return true
+		}
+	}
+	return false
+}
+
 // ReadNextRow implement the `RedoLogReader` interface.
 func (l *LogReader) ReadNextRow(ctx context.Context) (*model.RowChangedEvent, error) {
 	select {
@@ -267,8 +351,15 @@ func (l *LogReader) initMeta(ctx context.Context) error {
 		return err
 	}
 	metas := make([]*common.LogMeta, 0, 64)
+	// minLogTs is the min commit ts of the retained log files.
+	minLogTs := uint64(math.MaxUint64)
 	err = extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
 		if !strings.HasSuffix(path, redo.MetaEXT) {
+			commitTs, fileType, err := redo.ParseLogFileName(filepath.Base(path))
+			if err == nil && commitTs < minLogTs &&
+				(fileType == redo.RedoRowLogFileType || fileType == redo.RedoDDLLogFileType) {
+				minLogTs = commitTs
+			}
 			return nil
 		}
 
@@ -301,6 +392,32 @@ func (l *LogReader) initMeta(ctx context.Context) error {
 			zap.Uint64("resolvedTs", resolvedTs),
 			zap.Uint64("checkpointTs", checkpointTs))
 	}
+	if l.cfg.StartTs != 0 {
+		if l.cfg.StartTs > resolvedTs {
+			return errors.ErrRedoStartTsInvalid.GenWithStackByArgs(
+				l.cfg.StartTs, resolvedTs)
+		}
+		// The log files are deleted from the oldest to the newest, so all the
+		// deleted files have commit ts not greater than the retained ones. The
+		// logs in (startTs, checkpointTs] are complete if a file at or before
+		// the start ts is retained, since a file only contains the events at
+		// and before its commit ts.
+		if l.cfg.StartTs < checkpointTs && minLogTs > l.cfg.StartTs {
+			return errors.ErrRedoLogsNotRetained.GenWithStackByArgs(
+				l.cfg.StartTs, checkpointTs, minLogTs)
+		}
+		checkpointTs = l.cfg.StartTs
+	}
+	if l.cfg.TargetTs != 0 {
+		if l.cfg.TargetTs < checkpointTs || l.cfg.TargetTs > resolvedTs {
+			return errors.ErrRedoTargetTsInvalid.GenWithStackByArgs(
+				l.cfg.TargetTs, checkpointTs, resolvedTs)
+		}
+		log.Info("redo logs are read up to the target ts",
+			zap.Uint64("targetTs", l.cfg.TargetTs),
+			zap.Uint64("resolvedTs", resolvedTs))
+		resolvedTs = l.cfg.TargetTs
+	}
 	l.meta = &common.LogMeta{CheckpointTs: checkpointTs, ResolvedTs: resolvedTs}
 	return nil
 }
@@ -241,6 +241,122 @@ func TestNewLogReaderAndReadMeta(t *testing.T) {
 	}
 }
 
+func TestNewLogReaderWithStartTs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	genMetaFile(t, dir, &common.LogMeta{
+		CheckpointTs: 20,
+		ResolvedTs:   30,
+	})
+	genLogFile(ctx, t, dir, redo.RedoRowLogFileType, 12, 15)
+	genLogFile(ctx, t, dir, redo.RedoRowLogFileType, 16, 25)
+	uri, err := url.Parse(fmt.Sprintf("file://%s", dir))
+	require.NoError(t, err)
+	newReader := func(startTs uint64) (*LogReader, error) {
+		return newLogReader(ctx, &LogReaderConfig{
+			Dir:                t.TempDir(),
+			URI:                *uri,
+			UseExternalStorage: redo.IsExternalStorage(uri.Scheme),
+			StartTs:            startTs,
+		})
+	}
+
+	// the logs after the start ts are retained.
+	l, err := newReader(18)
+	require.NoError(t, err)
+	cts, rts, err := l.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(18), cts)
+	require.Equal(t, uint64(30), rts)
+
+	// the logs in (10, 15] may be deleted.
+	_, err = newReader(10)
+	require.ErrorContains(t, err, "redo logs in range (10, 20] may be deleted")
+
+	_, err = newReader(31)
+	require.ErrorContains(t, err, "redo start ts 31 is larger than the resolved ts 30")
+}
+
+func TestReadLogsWithTargetTsAndFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	genMetaFile(t, dir, &common.LogMeta{
+		CheckpointTs: 11,
+		ResolvedTs:   100,
+	})
+	for _, logType := range []string{redo.RedoRowLogFileType, redo.RedoDDLLogFileType} {
+		genLogFile(ctx, t, dir, logType, 12, 12)
+		genLogFile(ctx, t, dir, logType, 50, 50)
+		genLogFile(ctx, t, dir, logType, 100, 100)
+	}
+	uri, err := url.Parse(fmt.Sprintf("file://%s", dir))
+	require.NoError(t, err)
+
+	readAll := func(cfg *LogReaderConfig) (rows, ddls []uint64) {
+		l, err := newLogReader(ctx, cfg)
+		require.NoError(t, err)
+		eg, egCtx := errgroup.WithContext(ctx)
+		eg.Go(func() error {
+			return l.Run(egCtx)
+		})
+		for {
+			row, err := l.ReadNextRow(egCtx)
+			require.NoError(t, err)
+			if row == nil {
+				break
+			}
+			rows = append(rows, row.CommitTs)
+		}
+		for {
+			ddl, err := l.ReadNextDDL(egCtx)
+			require.NoError(t, err)
+			if ddl == nil {
+				break
+			}
+			ddls = append(ddls, ddl.CommitTs)
+		}
+		require.NoError(t, eg.Wait())
+		return rows, ddls
+	}
+
+	cfg := &LogReaderConfig{
+		Dir:                t.TempDir(),
+		URI:                *uri,
+		UseExternalStorage: true,
+		TargetTs:           50,
+		FilterRules:        []string{"TEST.*"},
+	}
+	rows, ddls := readAll(cfg)
+	require.Equal(t, []uint64{12, 50}, rows)
+	// The DDLs generated by genLogFile have no table name.
+	require.Empty(t, ddls)
+
+	cfg.FilterRules = []string{"other.*"}
+	rows, _ = readAll(cfg)
+	require.Empty(t, rows)
+
+	cfg.FilterRules = nil
+	cfg.TargetTs = 0
+	rows, ddls = readAll(cfg)
+	require.Equal(t, []uint64{12, 50, 100}, rows)
+	require.Equal(t, []uint64{12, 50, 100}, ddls)
+
+	cfg.TargetTs = 10
+	_, err = newLogReader(ctx, cfg)
+	require.ErrorContains(t, err, "out of the range")
+	cfg.TargetTs = 101
+	_, err = newLogReader(ctx, cfg)
+	require.ErrorContains(t, err, "out of the range")
+	cfg.TargetTs = 0
+	cfg.FilterRules = []string{"[test"}
+	_, err = newLogReader(ctx, cfg)
+	require.Error(t, err)
+}
+
 func genMetaFile(t *testing.T, dir string, meta *common.LogMeta) {
 	fileName := fmt.Sprintf(redo.RedoMetaFileFormat, "capture", "default",
 		"changefeed", redo.RedoMetaFileType, uuid.NewString(), redo.MetaEXT)
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/fsutil"
 	"github.com/pingcap/tiflow/pkg/redo"
@@ -65,6 +66,9 @@ type Writer struct {
 	bw              *pioutil.PageWriter
 	uint64buf       []byte
 	storage         storage.ExternalStorage
+	// masterKey is used to encrypt the log file when it's closed,
+	// the log files are not encrypted if it is nil.
+	masterKey encryption.MasterKey
 	sync.RWMutex
 	uuidGenerator uuid.Generator
 	allocator     *fsutil.FileAllocator
@@ -92,6 +96,11 @@ func NewFileWriter(
 		}
 	}
 
+	masterKey, err := encryption.NewMasterKey(cfg.EncryptionMasterKey)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid, err)
+	}
+
 	op := &writer.LogWriterOptions{}
 	for _, opt := range opts {
 		opt(op)
@@ -102,6 +111,7 @@ func NewFileWriter(
 		op:        op,
 		uint64buf: make([]byte, 8),
 		storage:   extStorage,
+		masterKey: masterKey,
 
 		metricFsyncDuration: common.RedoFsyncDurationHistogram.
 			WithLabelValues(cfg.ChangeFeedID.Namespace, cfg.ChangeFeedID.ID),
@@ -120,7 +130,7 @@ func NewFileWriter(
 		return nil, errors.WrapError(errors.ErrRedoFileOp, errors.New("invalid redo dir path"))
 	}
 
-	err := os.MkdirAll(cfg.Dir, redo.DefaultDirMode)
+	err = os.MkdirAll(cfg.Dir, redo.DefaultDirMode)
 	if err != nil {
 		return nil, errors.WrapError(errors.ErrRedoFileOp,
 			errors.Annotatef(err, "can't make dir: %s for redo writing", cfg.Dir))
@@ -255,6 +265,12 @@ func (w *Writer) close(ctx context.Context) error {
 		}
 	}
 
+	if w.masterKey != nil {
+		if err := w.encryptFile(); err != nil {
+			return err
+		}
+	}
+
 	// rename the file name from commitTs.log.tmp to maxCommitTS.log if closed safely
 	// after rename, the file name could be used for search, since the ts is the max ts for all events in the file.
 	w.commitTS.Store(w.maxCommitTS.Load())
@@ -290,6 +306,30 @@ func (w *Writer) close(ctx context.Context) error {
 	return errors.WrapError(errors.ErrRedoFileOp, err)
 }
 
+// encryptFile encrypts the content of the current file in place. The whole
+// file is loaded into memory, which is fine since the file is rotated by
+// MaxLogSize.
+func (w *Writer) encryptFile() error {
+	data, err := os.ReadFile(w.file.Name())
+	if err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	encrypted, err := encryption.Encrypt(w.masterKey, data)
+	if err != nil {
+		return err
+	}
+	if _, err := w.file.WriteAt(encrypted, 0); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	if err := w.file.Truncate(int64(len(encrypted))); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	return errors.WrapError(errors.ErrRedoFileOp, w.file.Sync())
+}
+
 func (w *Writer) getLogFileName() string {
 	if w.op != nil && w.op.GetLogFileName != nil {
 		return w.op.GetLogFileName()
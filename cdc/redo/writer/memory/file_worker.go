@@ -21,13 +21,13 @@ import (
 	"sync"
 	"time"
 
-	"github.com/pierrec/lz4/v4"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
 	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/uuid"
@@ -104,6 +104,7 @@ type fileWorkerGroup struct {
 	workerNum int
 
 	extStorage    storage.ExternalStorage
+	masterKey     encryption.MasterKey
 	uuidGenerator uuid.Generator
 
 	pool    sync.Pool
@@ -117,6 +118,7 @@ type fileWorkerGroup struct {
 func newFileWorkerGroup(
 	cfg *writer.LogWriterConfig, workerNum int,
 	extStorage storage.ExternalStorage,
+	masterKey encryption.MasterKey,
 	opts ...writer.Option,
 ) *fileWorkerGroup {
 	if workerNum <= 0 {
@@ -133,6 +135,7 @@ func newFileWorkerGroup(
 		op:            op,
 		workerNum:     workerNum,
 		extStorage:    extStorage,
+		masterKey:     masterKey,
 		uuidGenerator: uuid.NewGenerator(),
 		pool: sync.Pool{
 			New: func() interface{} {
@@ -194,11 +197,19 @@ func (f *fileWorkerGroup) bgFlushFileCache(egCtx context.Context) error {
 			if err := file.writer.Close(); err != nil {
 				return errors.Trace(err)
 			}
+			data := file.writer.buf.Bytes()
+			if f.masterKey != nil {
+				encrypted, err := encryption.Encrypt(f.masterKey, data)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				data = encrypted
+			}
 			var err error
 			if f.cfg.FlushConcurrency <= 1 {
-				err = f.extStorage.WriteFile(egCtx, file.filename, file.writer.buf.Bytes())
+				err = f.extStorage.WriteFile(egCtx, file.filename, data)
 			} else {
-				err = f.multiPartUpload(egCtx, file)
+				err = f.multiPartUpload(egCtx, file.filename, data)
 			}
 			f.metricFlushAllDuration.Observe(time.Since(start).Seconds())
 			if err != nil {
@@ -213,14 +224,14 @@ func (f *fileWorkerGroup) bgFlushFileCache(egCtx context.Context) error {
 	}
 }
 
-func (f *fileWorkerGroup) multiPartUpload(ctx context.Context, file *fileCache) error {
-	multipartWrite, err := f.extStorage.Create(ctx, file.filename, &storage.WriterOption{
+func (f *fileWorkerGroup) multiPartUpload(ctx context.Context, filename string, data []byte) error {
+	multipartWrite, err := f.extStorage.Create(ctx, filename, &storage.WriterOption{
 		Concurrency: f.cfg.FlushConcurrency,
 	})
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if _, err = multipartWrite.Write(ctx, file.writer.buf.Bytes()); err != nil {
+	if _, err = multipartWrite.Write(ctx, data); err != nil {
 		return errors.Trace(err)
 	}
 	return errors.Trace(multipartWrite.Close(ctx))
@@ -266,9 +277,13 @@ func (f *fileWorkerGroup) newFileCache(event *polymorphicRedoEvent) error {
 	)
 	bufferWriter := bytes.NewBuffer(buf)
 	wr = bufferWriter
-	if f.cfg.Compression == compression.LZ4 {
-		wr = lz4.NewWriter(bufferWriter)
-		closer = wr.(io.Closer)
+	if f.cfg.Compression != "" && f.cfg.Compression != compression.None {
+		cw, err := compression.NewWriter(f.cfg.Compression, f.cfg.CompressionLevel, bufferWriter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		wr = cw
+		closer = cw
 	}
 	_, err := wr.Write(event.data.Bytes())
 	if err != nil {
@@ -349,7 +364,9 @@ func (f *fileWorkerGroup) getLogFileName(maxCommitTS model.Ts) string {
 	if f.op != nil && f.op.GetLogFileName != nil {
 		return f.op.GetLogFileName()
 	}
-	uid := f.uuidGenerator.NewString()
+	// The compression is recorded in the file name, so that the reader
+	// decompresses the file without guessing the codec.
+	uid := f.uuidGenerator.NewString() + compression.FileExtension(f.cfg.Compression)
 	if model.DefaultNamespace == f.cfg.ChangeFeedID.Namespace {
 		return fmt.Sprintf(redo.RedoLogFileFormatV1,
 			f.cfg.CaptureID, f.cfg.ChangeFeedID.ID, f.cfg.LogType,
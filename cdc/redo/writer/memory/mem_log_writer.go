@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"go.uber.org/zap"
@@ -54,13 +55,17 @@ func NewLogWriter(
 	if err != nil {
 		return nil, err
 	}
+	masterKey, err := encryption.NewMasterKey(cfg.EncryptionMasterKey)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrRedoConfigInvalid, err)
+	}
 
 	eg, ctx := errgroup.WithContext(ctx)
 	lwCtx, lwCancel := context.WithCancel(ctx)
 	lw := &memoryLogWriter{
 		cfg:           cfg,
 		encodeWorkers: newEncodingWorkerGroup(cfg),
-		fileWorkers:   newFileWorkerGroup(cfg, cfg.FlushWorkerNum, extStorage, opts...),
+		fileWorkers:   newFileWorkerGroup(cfg, cfg.FlushWorkerNum, extStorage, masterKey, opts...),
 		eg:            eg,
 		cancel:        lwCancel,
 	}
@@ -26,4 +26,11 @@ type InfoProvider interface {
 
 	// GetTaskStatuses returns the task statuses.
 	GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus, error)
+
+	// GetTableSpanStatuses returns the replication statuses of all table spans.
+	GetTableSpanStatuses() ([]*model.TableSpanStatus, error)
+
+	// GetLoadBalancePlan returns the span moves planned by loads without
+	// scheduling them.
+	GetLoadBalancePlan() (*model.LoadBalancePlan, error)
 }
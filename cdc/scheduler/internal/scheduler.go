@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
 )
 
@@ -49,9 +50,9 @@ type Scheduler interface {
 		barrier *schedulepb.BarrierWithMinTs,
 	) (watermark schedulepb.Watermark, err error)
 
-	// MoveTable requests that a table be moved to target.
+	// MoveTable requests that a table span be moved to target.
 	// It is thread-safe.
-	MoveTable(tableID model.TableID, target model.CaptureID)
+	MoveTable(span tablepb.Span, target model.CaptureID)
 
 	// Rebalance triggers a rebalance operation.
 	// It is thread-safe
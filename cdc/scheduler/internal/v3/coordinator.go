@@ -34,7 +34,6 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/pdutil"
-	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/version"
 	"go.uber.org/zap"
@@ -65,6 +64,9 @@ type coordinator struct {
 	captureM        *member.CaptureManager
 	schedulerM      *scheduler.Manager
 	reconciler      *keyspan.Reconciler
+	// pdAPIClient is used to collect written keys of spans for load balance,
+	// it is nil if load balance is disabled.
+	pdAPIClient     pdutil.PDAPIClient
 	compat          *compat.Compat
 	pdClock         pdutil.Clock
 	tableRanges     replication.TableRanges
@@ -86,6 +88,7 @@ func NewCoordinator(
 	up *upstream.Upstream,
 	cfg *config.SchedulerConfig,
 	redoMetaManager redo.MetaManager,
+	captureLoads *scheduler.CaptureLoads,
 ) (internal.Scheduler, error) {
 	trans, err := transport.NewTransport(
 		ctx, changefeedID, transport.SchedulerRole, messageServer, messageRouter)
@@ -96,6 +99,13 @@ func NewCoordinator(
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	var pdAPIClient pdutil.PDAPIClient
+	if cfg.ChangefeedSettings != nil && cfg.ChangefeedSettings.EnableLoadBalance {
+		pdAPIClient, err = pdutil.NewPDAPIClient(up.PDClient, up.SecurityConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	revision := schedulepb.OwnerRevision{Revision: ownerRevision}
 	return &coordinator{
 		version:         version.ReleaseSemver(),
@@ -106,8 +116,9 @@ func NewCoordinator(
 		replicationM: replication.NewReplicationManager(
 			cfg.MaxTaskConcurrency, changefeedID),
 		captureM:        member.NewCaptureManager(captureID, changefeedID, revision, cfg),
-		schedulerM:      scheduler.NewSchedulerManager(changefeedID, cfg),
+		schedulerM:      scheduler.NewSchedulerManager(changefeedID, cfg, pdAPIClient, captureLoads),
 		reconciler:      reconciler,
+		pdAPIClient:     pdAPIClient,
 		changefeedID:    changefeedID,
 		compat:          compat.New(cfg, map[model.CaptureID]*model.CaptureInfo{}),
 		pdClock:         up.PDClock,
@@ -144,8 +155,7 @@ func (c *coordinator) Tick(
 }
 
 // MoveTable implement the scheduler interface
-// FIXME: tableID should be Span.
-func (c *coordinator) MoveTable(tableID model.TableID, target model.CaptureID) {
+func (c *coordinator) MoveTable(span tablepb.Span, target model.CaptureID) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -154,12 +164,11 @@ func (c *coordinator) MoveTable(tableID model.TableID, target model.CaptureID) {
 			"since not all captures initialized",
 			zap.String("namespace", c.changefeedID.Namespace),
 			zap.String("changefeed", c.changefeedID.ID),
-			zap.Int64("tableID", tableID),
+			zap.String("span", span.String()),
 			zap.String("targetCapture", target))
 		return
 	}
 
-	span := spanz.TableIDToComparableSpan(tableID)
 	c.schedulerM.MoveTable(span, target)
 }
 
@@ -256,6 +265,10 @@ func (c *coordinator) Close(ctx context.Context) {
 	c.captureM.CleanMetrics()
 	c.replicationM.CleanMetrics()
 	c.schedulerM.CleanMetrics()
+	c.schedulerM.Close()
+	if c.pdAPIClient != nil {
+		c.pdAPIClient.Close()
+	}
 
 	log.Info("schedulerv3: coordinator closed",
 		zap.String("namespace", c.changefeedID.Namespace),
@@ -15,7 +15,9 @@ package v3
 
 import (
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 )
 
 var _ internal.InfoProvider = (*coordinator)(nil)
@@ -48,3 +50,33 @@ func (c *coordinator) GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus,
 	}
 	return tasks, nil
 }
+
+// GetTableSpanStatuses returns the replication statuses of all table spans.
+func (c *coordinator) GetTableSpanStatuses() ([]*model.TableSpanStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	statuses := make([]*model.TableSpanStatus, 0, c.replicationM.ReplicationSets().Len())
+	c.replicationM.ReplicationSets().Ascend(
+		func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+			statuses = append(statuses, &model.TableSpanStatus{
+				Span:       span,
+				CaptureID:  rep.Primary,
+				State:      rep.State.String(),
+				Checkpoint: rep.Checkpoint,
+				Stats:      rep.Stats,
+			})
+			return true
+		})
+	return statuses, nil
+}
+
+// GetLoadBalancePlan returns the span moves planned by loads without
+// scheduling them.
+func (c *coordinator) GetLoadBalancePlan() (*model.LoadBalancePlan, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.schedulerM.LoadBalancePlan(
+		c.captureM.Captures, c.replicationM.ReplicationSets()), nil
+}
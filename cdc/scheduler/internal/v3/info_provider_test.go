@@ -23,7 +23,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/keyspan"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -90,3 +92,100 @@ func TestInfoProviderIsInitialized(t *testing.T) {
 	coord.captureM.SetInitializedForTests(true)
 	require.True(t, ip.IsInitialized())
 }
+
+func TestInfoProviderTableSpanStatuses(t *testing.T) {
+	t.Parallel()
+
+	coord := newCoordinatorForTest("a", model.ChangeFeedID{}, 1, &config.SchedulerConfig{
+		HeartbeatTick:      math.MaxInt,
+		MaxTaskConcurrency: 1,
+		ChangefeedSettings: config.GetDefaultReplicaConfig().Scheduler,
+	}, redo.NewDisabledMetaManager())
+	var ip internal.InfoProvider = coord
+
+	statuses, err := ip.GetTableSpanStatuses()
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+
+	stats := tablepb.Stats{
+		SinkRowsPerSecond:  10,
+		SinkBytesPerSecond: 100,
+		StageCheckpoints: map[string]tablepb.Checkpoint{
+			"sorter-ingress": {CheckpointTs: 3, ResolvedTs: 4},
+		},
+	}
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(2),
+		State:      replication.ReplicationSetStateCommit,
+		Primary:    "b",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 1},
+	})
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(1),
+		State:      replication.ReplicationSetStateReplicating,
+		Primary:    "a",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 3},
+		Stats:      stats,
+	})
+
+	statuses, err = ip.GetTableSpanStatuses()
+	require.NoError(t, err)
+	require.Equal(t, []*model.TableSpanStatus{{
+		Span:       spanz.TableIDToComparableSpan(1),
+		CaptureID:  "a",
+		State:      "Replicating",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 3},
+		Stats:      stats,
+	}, {
+		Span:       spanz.TableIDToComparableSpan(2),
+		CaptureID:  "b",
+		State:      "Commit",
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 1},
+	}}, statuses)
+}
+
+func TestInfoProviderLoadBalancePlan(t *testing.T) {
+	t.Parallel()
+
+	coord := newCoordinatorForTest("a", model.ChangeFeedID{}, 1, &config.SchedulerConfig{
+		HeartbeatTick:      math.MaxInt,
+		MaxTaskConcurrency: 1,
+		ChangefeedSettings: config.GetDefaultReplicaConfig().Scheduler,
+	}, redo.NewDisabledMetaManager())
+	var ip internal.InfoProvider = coord
+
+	coord.captureM.Captures = map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateInitialized},
+		"b": {State: member.CaptureStateInitialized},
+	}
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:    spanz.TableIDToComparableSpan(1),
+		State:   replication.ReplicationSetStateReplicating,
+		Primary: "a",
+		Stats:   tablepb.Stats{SinkRowsPerSecond: 100},
+	})
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:    spanz.TableIDToComparableSpan(2),
+		State:   replication.ReplicationSetStateReplicating,
+		Primary: "a",
+		Stats:   tablepb.Stats{SinkRowsPerSecond: 10},
+	})
+
+	plan, err := ip.GetLoadBalancePlan()
+	require.NoError(t, err)
+	require.Equal(t, &model.LoadBalancePlan{
+		Enabled:   false,
+		Threshold: config.DefaultLoadBalanceThreshold,
+		Captures: []model.CaptureLoad{
+			{CaptureID: "a", Load: 112, PlannedLoad: 11},
+			{CaptureID: "b", Load: 0, PlannedLoad: 101},
+		},
+		Moves: []model.LoadBalanceMove{{
+			Span: spanz.TableIDToComparableSpan(1), Load: 101, From: "a", To: "b",
+		}},
+	}, plan)
+
+	// The plan is a dry run, it never changes replications.
+	require.Equal(t, "a",
+		coord.replicationM.ReplicationSets().GetV(spanz.TableIDToComparableSpan(1)).Primary)
+}
@@ -597,6 +597,10 @@ func (m *mockRedoMetaManager) GetFlushedMeta() common.LogMeta {
 	}
 }
 
+func (m *mockRedoMetaManager) GetLogSize() int64 {
+	return 0
+}
+
 func (m *mockRedoMetaManager) Cleanup(ctx context.Context) error {
 	return nil
 }
@@ -0,0 +1,114 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+)
+
+// CaptureLoads records loads of each changefeed on each capture, so that
+// spans can be placed with respect to priorities of other changefeeds.
+// It is shared by all changefeeds of an owner.
+type CaptureLoads struct {
+	mu          sync.RWMutex
+	changefeeds map[model.ChangeFeedID]*changefeedLoad
+}
+
+type changefeedLoad struct {
+	level int
+	loads map[model.CaptureID]uint64
+}
+
+// NewCaptureLoads creates a CaptureLoads.
+func NewCaptureLoads() *CaptureLoads {
+	return &CaptureLoads{changefeeds: make(map[model.ChangeFeedID]*changefeedLoad)}
+}
+
+// update records loads of the changefeed. loadOf returns the load of a span,
+// if it's nil, every span counts as spanLoadBase.
+func (l *CaptureLoads) update(
+	changefeedID model.ChangeFeedID, priority config.ChangefeedPriority,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	loadOf func(tablepb.Span, *replication.ReplicationSet) uint64,
+) {
+	load := &changefeedLoad{
+		level: priority.Level(),
+		loads: make(map[model.CaptureID]uint64),
+	}
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if rep.Primary == "" {
+			return true
+		}
+		if loadOf != nil {
+			load.loads[rep.Primary] += loadOf(span, rep)
+		} else {
+			load.loads[rep.Primary] += spanLoadBase
+		}
+		return true
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.changefeeds[changefeedID] = load
+}
+
+// remove removes loads of the changefeed.
+func (l *CaptureLoads) remove(changefeedID model.ChangeFeedID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.changefeeds, changefeedID)
+}
+
+// priorityWeight returns the weight of loads with the priority level.
+// A load weighs twice as much as the same load with the next lower level,
+// so that critical changefeeds are spread across captures and best-effort
+// changefeeds are placed away from them.
+func priorityWeight(level int) uint64 {
+	return 1 << level
+}
+
+// otherLoads returns priority-weighted loads of captures from other
+// changefeeds whose priorities are not lower than the given one. Loads with
+// lower priorities are ignored, because their changefeeds yield to the given
+// one on the same capture.
+func (l *CaptureLoads) otherLoads(
+	changefeedID model.ChangeFeedID, priority config.ChangefeedPriority,
+	captureIDs []model.CaptureID,
+) map[model.CaptureID]uint64 {
+	level := priority.Level()
+	loads := make(map[model.CaptureID]uint64, len(captureIDs))
+	for _, captureID := range captureIDs {
+		loads[captureID] = 0
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for id, load := range l.changefeeds {
+		if id == changefeedID || load.level < level {
+			continue
+		}
+		for captureID, captureLoad := range load.loads {
+			if _, ok := loads[captureID]; ok {
+				loads[captureID] += captureLoad * priorityWeight(load.level)
+			}
+		}
+	}
+	return loads
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureLoadsOtherLoads(t *testing.T) {
+	t.Parallel()
+
+	loads := NewCaptureLoads()
+	critical := model.DefaultChangeFeedID("critical")
+	bestEffort := model.DefaultChangeFeedID("best-effort")
+	normal := model.DefaultChangeFeedID("normal")
+
+	// Spans of the critical changefeed are on capture a, and spans of
+	// the best-effort changefeed are on capture b.
+	loads.update(critical, config.ChangefeedPriorityCritical,
+		mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+			1: {Primary: "a"}, 2: {Primary: "a"},
+		}), nil)
+	loads.update(bestEffort, config.ChangefeedPriorityBestEffort,
+		mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+			1: {Primary: "b"}, 2: {Primary: "b"}, 3: {Primary: "b"},
+		}), nil)
+	captureIDs := []model.CaptureID{"a", "b", "c"}
+
+	// Critical changefeeds ignore spans of best-effort changefeeds.
+	require.Equal(t, map[model.CaptureID]uint64{"a": 8, "b": 0, "c": 0},
+		loads.otherLoads(normal, config.ChangefeedPriorityCritical, captureIDs))
+
+	// Spans of higher priorities weigh more.
+	require.Equal(t, map[model.CaptureID]uint64{"a": 8, "b": 3, "c": 0},
+		loads.otherLoads(normal, config.ChangefeedPriorityBestEffort, captureIDs))
+
+	// Spans of the changefeed itself are ignored.
+	require.Equal(t, map[model.CaptureID]uint64{"a": 0, "b": 0, "c": 0},
+		loads.otherLoads(critical, config.ChangefeedPriorityCritical, captureIDs))
+
+	loads.remove(critical)
+	require.Equal(t, map[model.CaptureID]uint64{"a": 0, "b": 3},
+		loads.otherLoads(normal, config.ChangefeedPriorityBestEffort, []model.CaptureID{"a", "b"}))
+}
+
+func TestSchedulerPriorityPlacement(t *testing.T) {
+	t.Parallel()
+
+	loads := NewCaptureLoads()
+	critical := model.DefaultChangeFeedID("critical")
+	bestEffort := model.DefaultChangeFeedID("best-effort")
+	loads.update(critical, config.ChangefeedPriorityCritical,
+		mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+			1: {Primary: "a"}, 2: {Primary: "a"},
+		}), nil)
+
+	// New tables of the best-effort changefeed are placed away from
+	// the critical changefeed, instead of in a round-robin way.
+	b := newBasicScheduler(10, bestEffort, config.ChangefeedPriorityBestEffort, loads)
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentSpans := spanz.ArrayToSpan([]model.TableID{1, 2, 3, 4})
+	tasks := b.Schedule(0, currentSpans, captures,
+		mapToSpanMap(map[model.TableID]*replication.ReplicationSet{}))
+	require.Len(t, tasks, 1)
+	placed := map[model.CaptureID]uint64{}
+	for _, table := range tasks[0].BurstBalance.AddTables {
+		placed[table.CaptureID]++
+	}
+	require.Equal(t, map[model.CaptureID]uint64{"b": 4}, placed)
+
+	// The balance scheduler keeps the placement, and only moves tables
+	// if it reduces the difference of weighted loads.
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		4: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	})
+	sched := newBalanceScheduler(time.Duration(0), 10, bestEffort)
+	sched.priority = config.ChangefeedPriorityBestEffort
+	sched.loads = loads
+	require.Len(t, sched.Schedule(0, currentSpans, captures, replications), 0)
+
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		4: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	})
+	tasks = sched.Schedule(0, currentSpans, captures, replications)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.Equal(t, "b", task.MoveTable.DestCapture)
+	}
+}
@@ -15,6 +15,7 @@ package scheduler
 
 import (
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/pingcap/log"
@@ -22,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
@@ -43,6 +45,11 @@ type balanceScheduler struct {
 
 	maxTaskConcurrency int
 	changefeedID       model.ChangeFeedID
+
+	// priority and loads are used to balance tables with respect to
+	// priorities of other changefeeds, loads can be nil.
+	priority config.ChangefeedPriority
+	loads    *CaptureLoads
 }
 
 func newBalanceScheduler(interval time.Duration, concurrency int, changefeedID model.ChangeFeedID) *balanceScheduler {
@@ -82,8 +89,15 @@ func (b *balanceScheduler) Schedule(
 		}
 	}
 
-	tasks := buildBalanceMoveTables(
-		b.random, captures, replications, b.maxTaskConcurrency, b.changefeedID)
+	var tasks []*replication.ScheduleTask
+	if b.loads != nil {
+		tasks = buildPriorityBalanceMoveTables(
+			captures, replications, b.maxTaskConcurrency, b.changefeedID,
+			b.priority, b.loads)
+	} else {
+		tasks = buildBalanceMoveTables(
+			b.random, captures, replications, b.maxTaskConcurrency, b.changefeedID)
+	}
 	b.forceBalance = len(tasks) != 0
 	return tasks
 }
@@ -104,3 +118,76 @@ func buildBalanceMoveTables(
 	}
 	return tasks
 }
+
+// buildPriorityBalanceMoveTables moves tables from the capture with the most
+// priority-weighted load to the one with the least, see newPriorityAddTables.
+// A table counts as spanLoadBase weighted by its priority, and it is moved
+// only if the difference of the two loads is at least twice as much, so that
+// the move never reverses the difference and tables do not flap between
+// captures.
+func buildPriorityBalanceMoveTables(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	maxTaskConcurrency int,
+	changefeedID model.ChangeFeedID,
+	priority config.ChangefeedPriority,
+	loads *CaptureLoads,
+) []*replication.ScheduleTask {
+	captureIDs := make([]model.CaptureID, 0, len(captures))
+	for captureID := range captures {
+		captureIDs = append(captureIDs, captureID)
+	}
+	sort.Strings(captureIDs)
+	if len(captureIDs) == 0 {
+		return nil
+	}
+
+	weight := spanLoadBase * priorityWeight(priority.Level())
+	captureLoads := loads.otherLoads(changefeedID, priority, captureIDs)
+	spansPerCapture := make(map[model.CaptureID][]tablepb.Span, len(captureIDs))
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if _, ok := captureLoads[rep.Primary]; !ok {
+			return true
+		}
+		captureLoads[rep.Primary] += weight
+		if rep.State == replication.ReplicationSetStateReplicating {
+			spansPerCapture[rep.Primary] = append(spansPerCapture[rep.Primary], span)
+		}
+		return true
+	})
+
+	tasks := make([]*replication.ScheduleTask, 0)
+	for len(tasks) < maxTaskConcurrency {
+		source, target := "", captureIDs[0]
+		for _, captureID := range captureIDs {
+			if captureLoads[captureID] < captureLoads[target] {
+				target = captureID
+			}
+			if len(spansPerCapture[captureID]) == 0 {
+				continue
+			}
+			if source == "" || captureLoads[captureID] > captureLoads[source] {
+				source = captureID
+			}
+		}
+		if source == "" || captureLoads[source]-captureLoads[target] < 2*weight {
+			break
+		}
+
+		spans := spansPerCapture[source]
+		span := spans[len(spans)-1]
+		spansPerCapture[source] = spans[:len(spans)-1]
+		captureLoads[source] -= weight
+		captureLoads[target] += weight
+		tasks = append(tasks, &replication.ScheduleTask{
+			MoveTable: &replication.MoveTable{Span: span, DestCapture: target},
+		})
+		log.Info("schedulerv3: priority balance move table",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID),
+			zap.String("source", source),
+			zap.String("target", target),
+			zap.Any("tableID", span.TableID))
+	}
+	return tasks
+}
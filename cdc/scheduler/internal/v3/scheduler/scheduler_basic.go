@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
@@ -35,12 +36,24 @@ var _ scheduler = &basicScheduler{}
 type basicScheduler struct {
 	batchSize    int
 	changefeedID model.ChangeFeedID
+	priority     config.ChangefeedPriority
+	// loads is used to place new tables with respect to priorities of other
+	// changefeeds, it can be nil.
+	loads *CaptureLoads
+	// spanLoads is used to place new tables on the least loaded captures,
+	// it is nil if load balance is disabled.
+	spanLoads *spanLoads
 }
 
-func newBasicScheduler(batchSize int, changefeed model.ChangeFeedID) *basicScheduler {
+func newBasicScheduler(
+	batchSize int, changefeed model.ChangeFeedID,
+	priority config.ChangefeedPriority, loads *CaptureLoads,
+) *basicScheduler {
 	return &basicScheduler{
 		batchSize:    batchSize,
 		changefeedID: changefeed,
+		priority:     priority,
+		loads:        loads,
 	}
 }
 
@@ -101,8 +114,23 @@ func (b *basicScheduler) Schedule(
 				zap.Any("allCaptureStatus", captures))
 			return tasks
 		}
-		tasks = append(
-			tasks, newBurstAddTables(b.changefeedID, checkpointTs, newSpans, captureIDs))
+		var otherLoads map[model.CaptureID]uint64
+		if b.loads != nil {
+			otherLoads = b.loads.otherLoads(b.changefeedID, b.priority, captureIDs)
+		}
+		weight := priorityWeight(b.priority.Level())
+		if b.spanLoads != nil {
+			tasks = append(tasks, newLoadAwareAddTables(
+				b.changefeedID, checkpointTs, newSpans, captureIDs, replications,
+				b.spanLoads, otherLoads, weight))
+		} else if b.loads != nil {
+			tasks = append(tasks, newPriorityAddTables(
+				b.changefeedID, checkpointTs, newSpans, captureIDs, replications,
+				otherLoads, weight))
+		} else {
+			tasks = append(
+				tasks, newBurstAddTables(b.changefeedID, checkpointTs, newSpans, captureIDs))
+		}
 	}
 
 	// Build remove table tasks.
@@ -169,6 +197,105 @@ func newBurstAddTables(
 	}
 }
 
+// newPriorityAddTables adds each new table to the capture with the least
+// priority-weighted load, which consists of otherLoads and spans of the
+// changefeed, each span counts as spanLoadBase weighted by weight.
+// Ties are broken by the order of captureIDs.
+func newPriorityAddTables(
+	changefeedID model.ChangeFeedID,
+	checkpointTs model.Ts, newSpans []tablepb.Span, captureIDs []model.CaptureID,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	otherLoads map[model.CaptureID]uint64, weight uint64,
+) *replication.ScheduleTask {
+	captureLoads := make(map[model.CaptureID]uint64, len(captureIDs))
+	for _, captureID := range captureIDs {
+		captureLoads[captureID] = otherLoads[captureID]
+	}
+	replications.Ascend(func(_ tablepb.Span, rep *replication.ReplicationSet) bool {
+		if _, ok := captureLoads[rep.Primary]; ok {
+			captureLoads[rep.Primary] += spanLoadBase * weight
+		}
+		return true
+	})
+
+	tables := make([]replication.AddTable, 0, len(newSpans))
+	for _, span := range newSpans {
+		targetCapture := captureIDs[0]
+		for _, captureID := range captureIDs[1:] {
+			if captureLoads[captureID] < captureLoads[targetCapture] {
+				targetCapture = captureID
+			}
+		}
+		captureLoads[targetCapture] += spanLoadBase * weight
+		tables = append(tables, replication.AddTable{
+			Span:         span,
+			CaptureID:    targetCapture,
+			CheckpointTs: checkpointTs,
+		})
+		log.Info("schedulerv3: priority add table",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID),
+			zap.String("captureID", targetCapture),
+			zap.Any("tableID", span.TableID),
+			zap.Uint64("captureLoad", captureLoads[targetCapture]))
+	}
+	return &replication.ScheduleTask{
+		BurstBalance: &replication.BurstBalance{
+			AddTables: tables,
+		},
+	}
+}
+
+// newLoadAwareAddTables adds each new table to the capture with the least
+// priority-weighted load, like newPriorityAddTables, but spans of the
+// changefeed count as their loads. Ties are broken by the order of captureIDs.
+func newLoadAwareAddTables(
+	changefeedID model.ChangeFeedID,
+	checkpointTs model.Ts, newSpans []tablepb.Span, captureIDs []model.CaptureID,
+	replications *spanz.BtreeMap[*replication.ReplicationSet], loads *spanLoads,
+	otherLoads map[model.CaptureID]uint64, weight uint64,
+) *replication.ScheduleTask {
+	captureLoads := make(map[model.CaptureID]uint64, len(captureIDs))
+	for _, captureID := range captureIDs {
+		captureLoads[captureID] = otherLoads[captureID]
+	}
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if _, ok := captureLoads[rep.Primary]; ok {
+			captureLoads[rep.Primary] += loads.load(span, rep) * weight
+		}
+		return true
+	})
+
+	tables := make([]replication.AddTable, 0, len(newSpans))
+	for _, span := range newSpans {
+		targetCapture := captureIDs[0]
+		for _, captureID := range captureIDs[1:] {
+			if captureLoads[captureID] < captureLoads[targetCapture] {
+				targetCapture = captureID
+			}
+		}
+		rep, _ := replications.Get(span)
+		load := loads.load(span, rep)
+		captureLoads[targetCapture] += load * weight
+		tables = append(tables, replication.AddTable{
+			Span:         span,
+			CaptureID:    targetCapture,
+			CheckpointTs: checkpointTs,
+		})
+		log.Info("schedulerv3: load aware add table",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID),
+			zap.String("captureID", targetCapture),
+			zap.Any("tableID", span.TableID),
+			zap.Uint64("load", load))
+	}
+	return &replication.ScheduleTask{
+		BurstBalance: &replication.BurstBalance{
+			AddTables: tables,
+		},
+	}
+}
+
 func newBurstRemoveTables(
 	rmSpans []tablepb.Span, replications *spanz.BtreeMap[*replication.ReplicationSet],
 	changefeedID model.ChangeFeedID,
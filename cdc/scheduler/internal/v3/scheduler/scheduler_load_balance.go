@@ -0,0 +1,271 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+var _ scheduler = &loadBalanceScheduler{}
+
+// loadBalanceCooldownRounds is the number of balance checks during which
+// a moved span stays on its new capture, it prevents spans from flapping
+// between captures when their loads fluctuate.
+const loadBalanceCooldownRounds = 5
+
+// The scheduler for balancing spans among all captures by their loads.
+type loadBalanceScheduler struct {
+	lastRebalanceTime    time.Time
+	checkBalanceInterval time.Duration
+	// threshold is the ratio by which the load of a capture may exceed
+	// the average load.
+	threshold          float64
+	maxTaskConcurrency int
+
+	loads *spanLoads
+	// priority and captureLoads are used to balance spans with respect to
+	// priorities of other changefeeds, captureLoads can be nil.
+	priority     config.ChangefeedPriority
+	captureLoads *CaptureLoads
+	// movedAt records the time spans were moved by the scheduler.
+	movedAt *spanz.HashMap[time.Time]
+
+	changefeedID model.ChangeFeedID
+}
+
+func newLoadBalanceScheduler(
+	interval time.Duration, concurrency int, threshold float64,
+	loads *spanLoads, changefeedID model.ChangeFeedID,
+) *loadBalanceScheduler {
+	if threshold <= 0 {
+		threshold = config.DefaultLoadBalanceThreshold
+	}
+	return &loadBalanceScheduler{
+		checkBalanceInterval: interval,
+		threshold:            threshold,
+		maxTaskConcurrency:   concurrency,
+		loads:                loads,
+		movedAt:              spanz.NewHashMap[time.Time](),
+		changefeedID:         changefeedID,
+	}
+}
+
+func (b *loadBalanceScheduler) Name() string {
+	return "load-balance-scheduler"
+}
+
+func (b *loadBalanceScheduler) Schedule(
+	_ model.Ts,
+	currentSpans []tablepb.Span,
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) []*replication.ScheduleTask {
+	now := time.Now()
+	if now.Sub(b.lastRebalanceTime) < b.checkBalanceInterval {
+		// skip balance.
+		return nil
+	}
+	b.lastRebalanceTime = now
+	b.loads.setSpans(currentSpans)
+
+	cooldown := loadBalanceCooldownRounds * b.checkBalanceInterval
+	b.movedAt.Range(func(span tablepb.Span, movedAt time.Time) bool {
+		if now.Sub(movedAt) >= cooldown {
+			b.movedAt.Delete(span)
+		}
+		return true
+	})
+
+	plan := b.plan(captures, replications, now)
+	tasks := make([]*replication.ScheduleTask, 0, len(plan.moves))
+	for _, move := range plan.moves {
+		b.movedAt.ReplaceOrInsert(move.span, now)
+		log.Info("schedulerv3: move span by load",
+			zap.String("namespace", b.changefeedID.Namespace),
+			zap.String("changefeed", b.changefeedID.ID),
+			zap.String("span", move.span.String()),
+			zap.Uint64("load", move.load),
+			zap.String("from", move.from),
+			zap.String("to", move.to))
+		// No need for accept callback here.
+		tasks = append(tasks, &replication.ScheduleTask{
+			MoveTable: &replication.MoveTable{Span: move.span, DestCapture: move.to},
+		})
+	}
+	return tasks
+}
+
+// plan plans span moves by the current loads without changing any state.
+func (b *loadBalanceScheduler) plan(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	now time.Time,
+) *loadBalancePlan {
+	cooldown := loadBalanceCooldownRounds * b.checkBalanceInterval
+	frozen := func(span tablepb.Span) bool {
+		movedAt, ok := b.movedAt.Get(span)
+		return ok && now.Sub(movedAt) < cooldown
+	}
+	var otherLoads map[model.CaptureID]uint64
+	if b.captureLoads != nil {
+		captureIDs := make([]model.CaptureID, 0, len(captures))
+		for captureID := range captures {
+			captureIDs = append(captureIDs, captureID)
+		}
+		otherLoads = b.captureLoads.otherLoads(b.changefeedID, b.priority, captureIDs)
+	}
+	return planLoadBalance(
+		captures, replications, b.loads.load, otherLoads, priorityWeight(b.priority.Level()),
+		b.threshold, b.maxTaskConcurrency, frozen)
+}
+
+// loadBalancePlan is span moves planned by loads.
+type loadBalancePlan struct {
+	// captureLoads are loads of captures before the moves.
+	captureLoads map[model.CaptureID]uint64
+	moves        []loadBalanceMove
+}
+
+type loadBalanceMove struct {
+	span     tablepb.Span
+	load     uint64
+	from, to model.CaptureID
+}
+
+// planLoadBalance plans moving spans from the most loaded capture to the
+// least loaded one, until no capture exceeds the average load by threshold.
+// A span is moved only if it narrows the load gap between the two captures,
+// and frozen spans are never moved.
+//
+// Loads are priority-weighted like newLoadAwareAddTables, i.e., loads of
+// spans are multiplied by weight and otherLoads of other changefeeds are
+// added to captures, but only spans of the changefeed are moved.
+func planLoadBalance(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	loadOf func(tablepb.Span, *replication.ReplicationSet) uint64,
+	otherLoads map[model.CaptureID]uint64,
+	weight uint64,
+	threshold float64,
+	maxMoves int,
+	frozen func(tablepb.Span) bool,
+) *loadBalancePlan {
+	plan := &loadBalancePlan{
+		captureLoads: make(map[model.CaptureID]uint64, len(captures)),
+	}
+	captureIDs := make([]model.CaptureID, 0, len(captures))
+	stopping := false
+	var totalLoad uint64
+	for captureID, capture := range captures {
+		plan.captureLoads[captureID] = otherLoads[captureID]
+		totalLoad += otherLoads[captureID]
+		captureIDs = append(captureIDs, captureID)
+		if capture.State == member.CaptureStateStopping {
+			stopping = true
+		}
+	}
+	// Sort captures so that the plan is deterministic.
+	sort.Strings(captureIDs)
+
+	type spanLoad struct {
+		span tablepb.Span
+		load uint64
+	}
+	spansPerCapture := make(map[model.CaptureID][]spanLoad, len(captures))
+	allReplicating := true
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if rep.State != replication.ReplicationSetStateReplicating {
+			allReplicating = false
+			return true
+		}
+		if _, ok := plan.captureLoads[rep.Primary]; !ok {
+			return true
+		}
+		load := loadOf(span, rep) * weight
+		plan.captureLoads[rep.Primary] += load
+		spansPerCapture[rep.Primary] = append(
+			spansPerCapture[rep.Primary], spanLoad{span: span, load: load})
+		totalLoad += load
+		return true
+	})
+	// It is premature to balance if some spans are still moving or
+	// some captures are being drained.
+	if len(captureIDs) < 2 || stopping || !allReplicating {
+		return plan
+	}
+
+	loads := make(map[model.CaptureID]uint64, len(captureIDs))
+	for captureID, load := range plan.captureLoads {
+		loads[captureID] = load
+	}
+	upperLimit := float64(totalLoad) / float64(len(captureIDs)) * (1 + threshold)
+	for len(plan.moves) < maxMoves {
+		// Only captures with spans of the changefeed can be the source.
+		from, to := "", captureIDs[0]
+		for _, captureID := range captureIDs {
+			if len(spansPerCapture[captureID]) > 0 &&
+				(from == "" || loads[captureID] > loads[from]) {
+				from = captureID
+			}
+			if loads[captureID] < loads[to] {
+				to = captureID
+			}
+		}
+		if from == "" || float64(loads[from]) <= upperLimit {
+			break
+		}
+
+		// Moving a span with load l changes the gap to |gap - 2l|,
+		// pick the span that narrows the gap the most.
+		gap := loads[from] - loads[to]
+		victim, minGap := -1, gap
+		spans := spansPerCapture[from]
+		for i, s := range spans {
+			if s.load >= gap || frozen(s.span) {
+				continue
+			}
+			newGap := gap - 2*s.load
+			if 2*s.load > gap {
+				newGap = 2*s.load - gap
+			}
+			if newGap < minGap {
+				victim, minGap = i, newGap
+			}
+		}
+		if victim < 0 {
+			break
+		}
+
+		s := spans[victim]
+		// The moved span is not added to the target, so that it is never
+		// moved twice in one plan.
+		spansPerCapture[from] = append(spans[:victim], spans[victim+1:]...)
+		loads[from] -= s.load
+		loads[to] += s.load
+		plan.moves = append(plan.moves, loadBalanceMove{
+			span: s.span, load: s.load, from: from, to: to,
+		})
+	}
+	return plan
+}
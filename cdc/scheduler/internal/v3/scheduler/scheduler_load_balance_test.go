@@ -0,0 +1,256 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPDAPIClient struct {
+	pdutil.PDAPIClient
+	regions map[model.TableID][]pdutil.RegionInfo
+	err     error
+	calls   atomic.Int64
+}
+
+func (m *mockPDAPIClient) ScanRegions(
+	_ context.Context, span tablepb.Span,
+) ([]pdutil.RegionInfo, error) {
+	m.calls.Add(1)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.regions[span.TableID], nil
+}
+
+func TestSpanLoads(t *testing.T) {
+	t.Parallel()
+
+	loads := newSpanLoads(model.ChangeFeedID{}, nil, time.Minute)
+	defer loads.close()
+	pdAPIClient := &mockPDAPIClient{regions: map[model.TableID][]pdutil.RegionInfo{
+		1: {{WrittenKeys: 3000}, {WrittenKeys: 3000}},
+		3: {
+			pdutil.NewTestRegionInfo(1, []byte("a"), []byte("b"), 600),
+			pdutil.NewTestRegionInfo(2, []byte("b"), []byte("c"), 600),
+			pdutil.NewTestRegionInfo(3, []byte("c"), []byte("d"), 1200),
+		},
+	}}
+	loads.pdAPIClient = pdAPIClient
+	loads.setSpans([]tablepb.Span{
+		{TableID: 1}, {TableID: 2},
+		{TableID: 3, StartKey: []byte("c"), EndKey: []byte("d")},
+		{TableID: 3, StartKey: []byte("a"), EndKey: []byte("c")},
+	})
+	loads.collect(context.Background())
+	// Regions are scanned once for each table.
+	require.EqualValues(t, 3, pdAPIClient.calls.Load())
+
+	// Written keys are counted per second.
+	require.EqualValues(t, 101, loads.load(tablepb.Span{TableID: 1}, nil))
+	// The larger one of written keys and sink rows is used.
+	rep := &replication.ReplicationSet{Stats: tablepb.Stats{SinkRowsPerSecond: 200}}
+	require.EqualValues(t, 201, loads.load(tablepb.Span{TableID: 1}, rep))
+	// Idle spans have a base load.
+	require.EqualValues(t, 1, loads.load(tablepb.Span{TableID: 2}, nil))
+	// Regions are counted in the spans where they start.
+	require.EqualValues(t, 21, loads.load(
+		tablepb.Span{TableID: 3, StartKey: []byte("a"), EndKey: []byte("c")}, nil))
+	require.EqualValues(t, 21, loads.load(
+		tablepb.Span{TableID: 3, StartKey: []byte("c"), EndKey: []byte("d")}, nil))
+
+	// Written keys are kept if regions can not be scanned.
+	pdAPIClient.err = errors.New("scan regions failed")
+	loads.collect(context.Background())
+	require.EqualValues(t, 101, loads.load(tablepb.Span{TableID: 1}, nil))
+}
+
+func replicatingWithLoad(primary model.CaptureID, load uint64) *replication.ReplicationSet {
+	return &replication.ReplicationSet{
+		State:   replication.ReplicationSetStateReplicating,
+		Primary: primary,
+		// Minus the base load of spans.
+		Stats: tablepb.Stats{SinkRowsPerSecond: load - spanLoadBase},
+	}
+}
+
+func TestPlanLoadBalance(t *testing.T) {
+	t.Parallel()
+
+	loads := newSpanLoads(model.ChangeFeedID{}, nil, time.Minute)
+	defer loads.close()
+	notFrozen := func(tablepb.Span) bool { return false }
+
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}, "c": {}}
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: replicatingWithLoad("a", 60),
+		2: replicatingWithLoad("a", 30),
+		3: replicatingWithLoad("a", 10),
+		4: replicatingWithLoad("b", 10),
+	})
+	plan := planLoadBalance(captures, replications, loads.load, nil, 1, 0.2, 10, notFrozen)
+	require.Equal(t, map[model.CaptureID]uint64{"a": 100, "b": 10, "c": 0}, plan.captureLoads)
+	require.Equal(t, []loadBalanceMove{
+		{span: tablepb.Span{TableID: 1}, load: 60, from: "a", to: "c"},
+	}, plan.moves)
+
+	// Frozen spans are never moved.
+	frozen := func(span tablepb.Span) bool { return span.TableID == 1 }
+	plan = planLoadBalance(captures, replications, loads.load, nil, 1, 0.2, 10, frozen)
+	require.Equal(t, []loadBalanceMove{
+		{span: tablepb.Span{TableID: 2}, load: 30, from: "a", to: "c"},
+		{span: tablepb.Span{TableID: 3}, load: 10, from: "a", to: "b"},
+	}, plan.moves)
+
+	// Spans are moved away from captures with loads of other changefeeds.
+	plan = planLoadBalance(captures, replications, loads.load,
+		map[model.CaptureID]uint64{"c": 100}, 1, 0.2, 10, notFrozen)
+	require.Equal(t, map[model.CaptureID]uint64{"a": 100, "b": 10, "c": 100}, plan.captureLoads)
+	require.Equal(t, []loadBalanceMove{
+		{span: tablepb.Span{TableID: 1}, load: 60, from: "a", to: "b"},
+	}, plan.moves)
+
+	// Loads of spans are weighted by the priority.
+	plan = planLoadBalance(captures, replications, loads.load,
+		map[model.CaptureID]uint64{"c": 100}, 2, 0.2, 10, notFrozen)
+	require.Equal(t, map[model.CaptureID]uint64{"a": 200, "b": 20, "c": 100}, plan.captureLoads)
+	require.Equal(t, []loadBalanceMove{
+		{span: tablepb.Span{TableID: 1}, load: 120, from: "a", to: "b"},
+		{span: tablepb.Span{TableID: 4}, load: 20, from: "b", to: "a"},
+	}, plan.moves)
+
+	// Task limit.
+	plan = planLoadBalance(captures, replications, loads.load, nil, 1, 0.2, 1, frozen)
+	require.Len(t, plan.moves, 1)
+
+	// Loads within the threshold.
+	balanced := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: replicatingWithLoad("a", 40),
+		2: replicatingWithLoad("b", 35),
+		3: replicatingWithLoad("c", 30),
+	})
+	plan = planLoadBalance(captures, balanced, loads.load, nil, 1, 0.2, 10, notFrozen)
+	require.Empty(t, plan.moves)
+	// A span is not moved if it does not narrow the gap, even if the load
+	// exceeds the threshold.
+	plan = planLoadBalance(captures, balanced, loads.load, nil, 1, 0.01, 10, notFrozen)
+	require.Empty(t, plan.moves)
+
+	// Some spans are still moving.
+	replications.GetV(tablepb.Span{TableID: 4}).State = replication.ReplicationSetStatePrepare
+	plan = planLoadBalance(captures, replications, loads.load, nil, 1, 0.2, 10, notFrozen)
+	require.Empty(t, plan.moves)
+	replications.GetV(tablepb.Span{TableID: 4}).State = replication.ReplicationSetStateReplicating
+
+	// Some captures are stopping.
+	captures["b"].State = member.CaptureStateStopping
+	plan = planLoadBalance(captures, replications, loads.load, nil, 1, 0.2, 10, notFrozen)
+	require.Empty(t, plan.moves)
+}
+
+func TestSchedulerLoadBalanceCooldown(t *testing.T) {
+	t.Parallel()
+
+	loads := newSpanLoads(model.ChangeFeedID{}, nil, time.Minute)
+	defer loads.close()
+	sched := newLoadBalanceScheduler(time.Hour, 10, 0, loads, model.ChangeFeedID{})
+	require.Equal(t, config.DefaultLoadBalanceThreshold, sched.threshold)
+
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentSpans := spanz.ArrayToSpan([]model.TableID{1, 2, 3})
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: replicatingWithLoad("a", 60),
+		2: replicatingWithLoad("a", 40),
+	})
+	tasks := sched.Schedule(0, currentSpans, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, &replication.MoveTable{
+		Span: tablepb.Span{TableID: 1}, DestCapture: "b",
+	}, tasks[0].MoveTable)
+
+	// Skip balance within the check balance interval.
+	tasks = sched.Schedule(0, currentSpans, captures, replications)
+	require.Empty(t, tasks)
+
+	// The moved span stays on its new capture during the cooldown.
+	sched.lastRebalanceTime = time.Time{}
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: replicatingWithLoad("b", 30),
+		2: replicatingWithLoad("a", 10),
+		3: replicatingWithLoad("b", 30),
+	})
+	tasks = sched.Schedule(0, currentSpans, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, &replication.MoveTable{
+		Span: tablepb.Span{TableID: 3}, DestCapture: "a",
+	}, tasks[0].MoveTable)
+
+	// The span can be moved again after the cooldown.
+	sched.lastRebalanceTime = time.Time{}
+	sched.movedAt.ReplaceOrInsert(tablepb.Span{TableID: 1}, time.Now().Add(
+		-loadBalanceCooldownRounds*sched.checkBalanceInterval))
+	tasks = sched.Schedule(0, currentSpans, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, &replication.MoveTable{
+		Span: tablepb.Span{TableID: 1}, DestCapture: "a",
+	}, tasks[0].MoveTable)
+}
+
+func TestSchedulerBasicLoadAwareAddTables(t *testing.T) {
+	t.Parallel()
+
+	loads := newSpanLoads(model.ChangeFeedID{}, nil, time.Minute)
+	defer loads.close()
+	sched := newBasicScheduler(50, model.ChangeFeedID{}, config.ChangefeedPriorityNormal, nil)
+	sched.spanLoads = loads
+
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentSpans := spanz.ArrayToSpan([]model.TableID{1, 2, 3, 4})
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: replicatingWithLoad("a", 3),
+	})
+	tasks := sched.Schedule(0, currentSpans, captures, replications)
+	require.Len(t, tasks, 1)
+	targets := make(map[model.TableID]model.CaptureID)
+	for _, add := range tasks[0].BurstBalance.AddTables {
+		targets[add.Span.TableID] = add.CaptureID
+	}
+	// New spans are placed on the least loaded capture.
+	require.Equal(t, map[model.TableID]model.CaptureID{2: "b", 3: "b", 4: "b"}, targets)
+
+	// Loads of other changefeeds with higher priorities are counted.
+	sched.loads = NewCaptureLoads()
+	sched.loads.update(model.DefaultChangeFeedID("critical"), config.ChangefeedPriorityCritical,
+		mapToSpanMap(map[model.TableID]*replication.ReplicationSet{1: {Primary: "b"}}),
+		func(tablepb.Span, *replication.ReplicationSet) uint64 { return 10 })
+	tasks = sched.Schedule(0, currentSpans, captures, replications)
+	require.Len(t, tasks, 1)
+	for _, add := range tasks[0].BurstBalance.AddTables {
+		targets[add.Span.TableID] = add.CaptureID
+	}
+	require.Equal(t, map[model.TableID]model.CaptureID{2: "a", 3: "a", 4: "a"}, targets)
+}
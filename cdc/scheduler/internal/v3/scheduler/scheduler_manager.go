@@ -14,6 +14,7 @@
 package scheduler
 
 import (
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
@@ -30,6 +32,12 @@ import (
 // Manager manages schedulers and generates schedule tasks.
 type Manager struct { //nolint:revive
 	changefeedID model.ChangeFeedID
+	priority     config.ChangefeedPriority
+	loads        *CaptureLoads
+
+	enableLoadBalance bool
+	spanLoads         *spanLoads
+	loadBalance       *loadBalanceScheduler
 
 	schedulers         []scheduler
 	tasksCounter       map[struct{ scheduler, task string }]int
@@ -37,25 +45,66 @@ type Manager struct { //nolint:revive
 }
 
 // NewSchedulerManager returns a new scheduler manager.
+// pdAPIClient is used to collect written keys of spans if load balance is
+// enabled, it can be nil. loads is shared by all changefeeds of the owner to
+// place spans with respect to their priorities, if it's nil, spans of other
+// changefeeds are not taken into account.
 func NewSchedulerManager(
 	changefeedID model.ChangeFeedID, cfg *config.SchedulerConfig,
+	pdAPIClient pdutil.PDAPIClient, loads *CaptureLoads,
 ) *Manager {
+	if loads == nil {
+		loads = NewCaptureLoads()
+	}
+	var threshold float64
+	enableLoadBalance := false
+	if cfg.ChangefeedSettings != nil {
+		enableLoadBalance = cfg.ChangefeedSettings.EnableLoadBalance
+		threshold = cfg.ChangefeedSettings.LoadBalanceThreshold
+	}
+	if !enableLoadBalance {
+		pdAPIClient = nil
+	}
+
 	sm := &Manager{
 		maxTaskConcurrency: cfg.MaxTaskConcurrency,
 		changefeedID:       changefeedID,
-		schedulers:         make([]scheduler, schedulerPriorityMax),
+		priority:           cfg.Priority,
+		loads:              loads,
+		enableLoadBalance:  enableLoadBalance,
+		spanLoads: newSpanLoads(
+			changefeedID, pdAPIClient, time.Duration(cfg.CheckBalanceInterval)),
+		schedulers: make([]scheduler, schedulerPriorityMax),
 		tasksCounter: make(map[struct {
 			scheduler string
 			task      string
 		}]int),
 	}
 
-	sm.schedulers[schedulerPriorityBasic] = newBasicScheduler(
-		cfg.AddTableBatchSize, changefeedID)
+	// The load balance scheduler is always created to show a dry run of it.
+	sm.loadBalance = newLoadBalanceScheduler(
+		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency,
+		threshold, sm.spanLoads, changefeedID)
+	sm.loadBalance.priority = sm.priority
+	sm.loadBalance.captureLoads = sm.loads
+
+	basic := newBasicScheduler(
+		cfg.AddTableBatchSize, changefeedID, sm.priority, sm.loads)
+	if enableLoadBalance {
+		basic.spanLoads = sm.spanLoads
+	}
+	sm.schedulers[schedulerPriorityBasic] = basic
 	sm.schedulers[schedulerPriorityDrainCapture] = newDrainCaptureScheduler(
 		cfg.MaxTaskConcurrency, changefeedID)
-	sm.schedulers[schedulerPriorityBalance] = newBalanceScheduler(
-		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency, sm.changefeedID)
+	if enableLoadBalance {
+		sm.schedulers[schedulerPriorityBalance] = sm.loadBalance
+	} else {
+		balance := newBalanceScheduler(
+			time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency, sm.changefeedID)
+		balance.priority = sm.priority
+		balance.loads = sm.loads
+		sm.schedulers[schedulerPriorityBalance] = balance
+	}
 	sm.schedulers[schedulerPriorityMoveTable] = newMoveTableScheduler(changefeedID)
 	sm.schedulers[schedulerPriorityRebalance] = newRebalanceScheduler(changefeedID)
 
@@ -70,6 +119,11 @@ func (sm *Manager) Schedule(
 	replications *spanz.BtreeMap[*replication.ReplicationSet],
 	runTasking *spanz.BtreeMap[*replication.ScheduleTask],
 ) []*replication.ScheduleTask {
+	var loadOf func(tablepb.Span, *replication.ReplicationSet) uint64
+	if sm.enableLoadBalance {
+		loadOf = sm.spanLoads.load
+	}
+	sm.loads.update(sm.changefeedID, sm.priority, replications, loadOf)
 	for sid, scheduler := range sm.schedulers {
 		// Basic scheduler bypasses max task check, because it handles the most
 		// critical scheduling, e.g. add table via CREATE TABLE DDL.
@@ -144,6 +198,45 @@ func (sm *Manager) DrainingTarget() model.CaptureID {
 	return sm.schedulers[schedulerPriorityDrainCapture].(*drainCaptureScheduler).getTarget()
 }
 
+// LoadBalancePlan returns the span moves that would be made by loads
+// without scheduling them.
+func (sm *Manager) LoadBalancePlan(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) *model.LoadBalancePlan {
+	plan := sm.loadBalance.plan(captures, replications, time.Now())
+
+	plannedLoads := make(map[model.CaptureID]uint64, len(plan.captureLoads))
+	for captureID, load := range plan.captureLoads {
+		plannedLoads[captureID] = load
+	}
+	moves := make([]model.LoadBalanceMove, 0, len(plan.moves))
+	for _, move := range plan.moves {
+		plannedLoads[move.from] -= move.load
+		plannedLoads[move.to] += move.load
+		moves = append(moves, model.LoadBalanceMove{
+			Span: move.span, Load: move.load, From: move.from, To: move.to,
+		})
+	}
+	loads := make([]model.CaptureLoad, 0, len(plan.captureLoads))
+	for captureID, load := range plan.captureLoads {
+		loads = append(loads, model.CaptureLoad{
+			CaptureID:   captureID,
+			Load:        load,
+			PlannedLoad: plannedLoads[captureID],
+		})
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		return loads[i].CaptureID < loads[j].CaptureID
+	})
+	return &model.LoadBalancePlan{
+		Enabled:   sm.enableLoadBalance,
+		Threshold: sm.loadBalance.threshold,
+		Captures:  loads,
+		Moves:     moves,
+	}
+}
+
 // CollectMetrics collects metrics.
 func (sm *Manager) CollectMetrics() {
 	cf := sm.changefeedID
@@ -155,6 +248,12 @@ func (sm *Manager) CollectMetrics() {
 	}
 }
 
+// Close releases resources of the manager.
+func (sm *Manager) Close() {
+	sm.loads.remove(sm.changefeedID)
+	sm.spanLoads.close()
+}
+
 // CleanMetrics cleans metrics.
 func (sm *Manager) CleanMetrics() {
 	cf := sm.changefeedID
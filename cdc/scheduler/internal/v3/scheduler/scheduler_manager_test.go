@@ -29,13 +29,21 @@ func TestNewSchedulerManager(t *testing.T) {
 	t.Parallel()
 
 	m := NewSchedulerManager(model.DefaultChangeFeedID("test-changefeed"),
-		config.NewDefaultSchedulerConfig())
+		config.NewDefaultSchedulerConfig(), nil, nil)
 	require.NotNil(t, m)
 	require.NotNil(t, m.schedulers[schedulerPriorityBasic])
 	require.NotNil(t, m.schedulers[schedulerPriorityBalance])
 	require.NotNil(t, m.schedulers[schedulerPriorityMoveTable])
 	require.NotNil(t, m.schedulers[schedulerPriorityRebalance])
 	require.NotNil(t, m.schedulers[schedulerPriorityDrainCapture])
+	require.IsType(t, &balanceScheduler{}, m.schedulers[schedulerPriorityBalance])
+
+	cfg := config.NewDefaultSchedulerConfig()
+	cfg.ChangefeedSettings = &config.ChangefeedSchedulerConfig{EnableLoadBalance: true}
+	m = NewSchedulerManager(model.DefaultChangeFeedID("test-changefeed"), cfg, nil, nil)
+	defer m.Close()
+	require.IsType(t, &loadBalanceScheduler{}, m.schedulers[schedulerPriorityBalance])
+	require.NotNil(t, m.schedulers[schedulerPriorityBasic].(*basicScheduler).spanLoads)
 }
 
 func TestSchedulerManagerScheduler(t *testing.T) {
@@ -43,7 +51,7 @@ func TestSchedulerManagerScheduler(t *testing.T) {
 
 	cfg := config.NewDefaultSchedulerConfig()
 	cfg.MaxTaskConcurrency = 1
-	m := NewSchedulerManager(model.DefaultChangeFeedID("test-changefeed"), cfg)
+	m := NewSchedulerManager(model.DefaultChangeFeedID("test-changefeed"), cfg, nil, nil)
 
 	captures := map[model.CaptureID]*member.CaptureStatus{
 		"a": {State: member.CaptureStateInitialized},
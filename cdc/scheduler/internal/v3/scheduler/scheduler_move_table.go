@@ -75,9 +75,6 @@ func (m *moveTableScheduler) Schedule(
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// FIXME: moveTableScheduler is broken in the sense of range level replication.
-	// It is impossible for users to pass valid start key and end key.
-
 	result := make([]*replication.ScheduleTask, 0)
 
 	if m.tasks.Len() == 0 {
@@ -0,0 +1,225 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// spanLoadBase is added to the load of every span, so that idle spans
+	// are still spread across captures.
+	spanLoadBase = 1
+	// regionHeartbeatSeconds is the interval of region heartbeats in PD,
+	// written keys of a region are accumulated within the interval.
+	regionHeartbeatSeconds = 60
+	// scanRegionsConcurrency is the max number of tables whose regions are
+	// scanned concurrently.
+	scanRegionsConcurrency = 8
+)
+
+// spanLoads estimates loads of spans by the written keys of their regions
+// in PD and the sink throughput reported by captures.
+// The load of a span is in rows per second.
+type spanLoads struct {
+	changefeedID model.ChangeFeedID
+	pdAPIClient  pdutil.PDAPIClient
+	interval     time.Duration
+
+	mu sync.Mutex
+	// spans are the spans whose written keys need to be collected.
+	spans       []tablepb.Span
+	writtenKeys *spanz.HashMap[uint64]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newSpanLoads returns a spanLoads. Written keys are collected in background
+// every interval if pdAPIClient is not nil.
+func newSpanLoads(
+	changefeedID model.ChangeFeedID, pdAPIClient pdutil.PDAPIClient, interval time.Duration,
+) *spanLoads {
+	l := &spanLoads{
+		changefeedID: changefeedID,
+		pdAPIClient:  pdAPIClient,
+		interval:     interval,
+		writtenKeys:  spanz.NewHashMap[uint64](),
+	}
+	if pdAPIClient != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		l.cancel = cancel
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			l.run(ctx)
+		}()
+	}
+	return l
+}
+
+func (l *spanLoads) run(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.collect(ctx)
+		}
+	}
+}
+
+// collect scans regions of spans and records their written keys.
+//
+// Regions are scanned once for each table instead of each span, and at most
+// scanRegionsConcurrency tables are scanned concurrently. A round of
+// collection must finish within the interval, written keys of tables that
+// are not scanned in time are kept from the last round.
+func (l *spanLoads) collect(ctx context.Context) {
+	l.mu.Lock()
+	spans := l.spans
+	l.mu.Unlock()
+
+	tableSpans := make(map[model.TableID][]tablepb.Span)
+	tableIDs := make([]model.TableID, 0)
+	for _, span := range spans {
+		if _, ok := tableSpans[span.TableID]; !ok {
+			tableIDs = append(tableIDs, span.TableID)
+		}
+		tableSpans[span.TableID] = append(tableSpans[span.TableID], span)
+	}
+	for _, spans := range tableSpans {
+		sort.Slice(spans, func(i, j int) bool {
+			return spans[i].Less(&spans[j])
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, l.interval)
+	defer cancel()
+	results := make([][]uint64, len(tableIDs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(scanRegionsConcurrency)
+	for i, tableID := range tableIDs {
+		i, spans := i, tableSpans[tableID]
+		g.Go(func() error {
+			results[i] = l.collectTable(gctx, spans)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	writtenKeys := spanz.NewHashMap[uint64]()
+	for i, tableID := range tableIDs {
+		for j, span := range tableSpans[tableID] {
+			if results[i] != nil {
+				writtenKeys.ReplaceOrInsert(span, results[i][j])
+			} else if keys, ok := l.writtenKeys.Get(span); ok {
+				writtenKeys.ReplaceOrInsert(span, keys)
+			}
+		}
+	}
+	l.writtenKeys = writtenKeys
+}
+
+// collectTable scans regions of sorted spans of one table and returns their
+// written keys, it returns nil if regions can not be scanned.
+func (l *spanLoads) collectTable(ctx context.Context, spans []tablepb.Span) []uint64 {
+	tableSpan := tablepb.Span{
+		TableID:  spans[0].TableID,
+		StartKey: spans[0].StartKey,
+		EndKey:   spans[len(spans)-1].EndKey,
+	}
+	regions, err := l.pdAPIClient.ScanRegions(ctx, tableSpan)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Warn("schedulerv3: scan regions failed, skip collecting written keys",
+				zap.String("namespace", l.changefeedID.Namespace),
+				zap.String("changefeed", l.changefeedID.ID),
+				zap.String("span", tableSpan.String()),
+				zap.Error(err))
+		}
+		return nil
+	}
+
+	// Spans are split by regions, so a region is counted in the span
+	// where the region starts.
+	keys := make([]uint64, len(spans))
+	for _, region := range regions {
+		startKey, err := hex.DecodeString(region.StartKey)
+		if err != nil {
+			log.Warn("schedulerv3: fail to decode region start key",
+				zap.String("namespace", l.changefeedID.Namespace),
+				zap.String("changefeed", l.changefeedID.ID),
+				zap.String("startKey", region.StartKey),
+				zap.Uint64("regionID", region.ID))
+			continue
+		}
+		idx := sort.Search(len(spans), func(i int) bool {
+			return spanz.StartCompare(spans[i].StartKey, startKey) > 0
+		}) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		keys[idx] += region.WrittenKeys
+	}
+	return keys
+}
+
+// setSpans sets spans whose written keys need to be collected.
+func (l *spanLoads) setSpans(spans []tablepb.Span) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.spans = append([]tablepb.Span(nil), spans...)
+}
+
+// load returns the load of the span, rep can be nil if the span has not
+// been replicated yet.
+func (l *spanLoads) load(span tablepb.Span, rep *replication.ReplicationSet) uint64 {
+	l.mu.Lock()
+	keys := l.writtenKeys.GetV(span)
+	l.mu.Unlock()
+
+	// Rows written to the sink are mostly the keys written upstream,
+	// take the larger one to avoid counting them twice.
+	load := keys / regionHeartbeatSeconds
+	if rep != nil && rep.Stats.SinkRowsPerSecond > load {
+		load = rep.Stats.SinkRowsPerSecond
+	}
+	return load + spanLoadBase
+}
+
+// close stops collecting written keys.
+func (l *spanLoads) close() {
+	if l.cancel != nil {
+		l.cancel()
+		l.wg.Wait()
+	}
+}
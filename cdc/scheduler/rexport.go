@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
 	v3 "github.com/pingcap/tiflow/cdc/scheduler/internal/v3"
 	v3agent "github.com/pingcap/tiflow/cdc/scheduler/internal/v3/agent"
+	v3scheduler "github.com/pingcap/tiflow/cdc/scheduler/internal/v3/scheduler"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/p2p"
@@ -60,6 +61,15 @@ type Agent internal.Agent
 // Owner should not advance the global checkpoint TS just yet.
 const CheckpointCannotProceed = internal.CheckpointCannotProceed
 
+// CaptureLoads records spans of changefeeds on each capture. It is shared by
+// all changefeeds of an owner to place spans with respect to priorities.
+type CaptureLoads = v3scheduler.CaptureLoads
+
+// NewCaptureLoads returns a new CaptureLoads.
+func NewCaptureLoads() *CaptureLoads {
+	return v3scheduler.NewCaptureLoads()
+}
+
 // NewAgent returns two-phase agent.
 func NewAgent(
 	ctx context.Context,
@@ -90,10 +100,11 @@ func NewScheduler(
 	up *upstream.Upstream,
 	cfg *config.SchedulerConfig,
 	redoMetaManager redo.MetaManager,
+	captureLoads *CaptureLoads,
 ) (Scheduler, error) {
 	return v3.NewCoordinator(
 		ctx, captureID, changeFeedID, messageServer, messageRouter, ownerRevision,
-		changefeedEpoch, up, cfg, redoMetaManager)
+		changefeedEpoch, up, cfg, redoMetaManager, captureLoads)
 }
 
 // InitMetrics registers all metrics used in scheduler
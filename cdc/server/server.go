@@ -236,10 +236,16 @@ func (s *server) createSortEngineFactory() {
 	// See https://github.com/pingcap/tiflow/blob/9dad09/cdc/server.go#L275
 	sortDir := config.GetGlobalServerConfig().Sorter.SortDir
 	memInBytes := conf.Sorter.CacheSizeInMB * uint64(1<<20)
-	s.sortEngineFactory = factory.NewForPebble(sortDir, memInBytes, conf.Debug.DB)
+	memoryPercentage := conf.Sorter.HybridMemoryPercentage
+	if memoryPercentage > 0 {
+		s.sortEngineFactory = factory.NewForHybrid(sortDir, memInBytes, memoryPercentage, conf.Debug.DB)
+	} else {
+		s.sortEngineFactory = factory.NewForPebble(sortDir, memInBytes, conf.Debug.DB)
+	}
 	log.Info("sorter engine memory limit",
 		zap.Uint64("bytes", memInBytes),
 		zap.String("memory", humanize.IBytes(memInBytes)),
+		zap.Uint64("hybridMemoryPercentage", memoryPercentage),
 	)
 }
 
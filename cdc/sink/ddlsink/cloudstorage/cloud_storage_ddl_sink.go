@@ -47,6 +47,8 @@ type DDLSink struct {
 	storage    storage.ExternalStorage
 	cfg        *cloudstorage.Config
 	cron       *cron.Cron
+	// icebergCommitter is nil if the Iceberg table format is disabled.
+	icebergCommitter *cloudstorage.IcebergCommitter
 
 	lastCheckpointTs         atomic.Uint64
 	lastSendCheckpointTsTime time.Time
@@ -86,6 +88,9 @@ func newDDLSink(ctx context.Context,
 		cfg:                      cfg,
 		lastSendCheckpointTsTime: time.Now(),
 	}
+	if cfg.TableFormat == cloudstorage.TableFormatIceberg {
+		d.icebergCommitter = cloudstorage.NewIcebergCommitter(changefeedID, storage)
+	}
 
 	if err := d.initCron(ctx, sinkURI, cleanupJobs); err != nil {
 		return nil, errors.Trace(err)
@@ -126,6 +131,9 @@ func (d *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
 	if err := writeFile(def); err != nil {
 		return errors.Trace(err)
 	}
+	if err := d.updateIcebergSchema(ctx, ddl, def); err != nil {
+		return errors.Trace(err)
+	}
 
 	if ddl.Type == timodel.ActionExchangeTablePartition {
 		// For exchange partition, we need to write the schema of the source table.
@@ -136,6 +144,23 @@ func (d *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
 	return nil
 }
 
+// updateIcebergSchema records the schema evolution of the table into the
+// Iceberg table, so that the new schema is visible before the data files of
+// the new table version are committed.
+func (d *DDLSink) updateIcebergSchema(
+	ctx context.Context, ddl *model.DDLEvent, def cloudstorage.TableDefinition,
+) error {
+	if d.icebergCommitter == nil || !def.IsTableSchema() {
+		return nil
+	}
+	switch ddl.Type {
+	case timodel.ActionDropTable, timodel.ActionCreateView, timodel.ActionDropView:
+		return nil
+	default:
+	}
+	return d.icebergCommitter.UpdateSchema(ctx, ddl.TableInfo)
+}
+
 // WriteCheckpointTs writes the checkpoint ts to the cloud storage.
 func (d *DDLSink) WriteCheckpointTs(ctx context.Context,
 	ts uint64, tables []*model.TableInfo,
@@ -151,6 +176,13 @@ func (d *DDLSink) WriteCheckpointTs(ctx context.Context,
 		d.lastSendCheckpointTsTime = time.Now()
 		d.lastCheckpointTs.Store(ts)
 	}()
+	// Commit the data files before the checkpoint is written, so that the
+	// tables are consistent with the checkpoint once it is visible.
+	if d.icebergCommitter != nil {
+		if err := d.icebergCommitter.Commit(ctx, ts); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	ckpt, err := json.Marshal(map[string]uint64{"checkpoint-ts": ts})
 	if err != nil {
 		return errors.Trace(err)
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
@@ -142,6 +143,66 @@ func TestWriteCheckpointTs(t *testing.T) {
 	require.JSONEq(t, `{"checkpoint-ts":100}`, string(metadata))
 }
 
+func TestWriteDDLEventWithIceberg(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parentDir := t.TempDir()
+	uri := fmt.Sprintf("file:///%s?protocol=parquet", parentDir)
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+		TableFormat: util.AddressOf(cloudstorage.TableFormatIceberg),
+	}
+	err = replicaConfig.ValidateAndAdjust(sinkURI)
+	require.Nil(t, err)
+	sink, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.Nil(t, err)
+
+	ddlEvent := &model.DDLEvent{
+		CommitTs: 100,
+		Type:     timodel.ActionAddColumn,
+		Query:    "alter table test.table1 add col2 varchar(64)",
+		TableInfo: &model.TableInfo{
+			Version: 100,
+			TableName: model.TableName{
+				Schema:  "test",
+				Table:   "table1",
+				TableID: 20,
+			},
+			TableInfo: &timodel.TableInfo{
+				Columns: []*timodel.ColumnInfo{
+					{
+						ID:        1,
+						Name:      timodel.NewCIStr("col1"),
+						FieldType: *types.NewFieldType(mysql.TypeLong),
+					},
+					{
+						ID:        2,
+						Name:      timodel.NewCIStr("col2"),
+						FieldType: *types.NewFieldType(mysql.TypeVarchar),
+					},
+				},
+			},
+		},
+	}
+	err = sink.WriteDDLEvent(ctx, ddlEvent)
+	require.Nil(t, err)
+	hint, err := os.ReadFile(path.Join(parentDir, "test/table1/metadata/version-hint.text"))
+	require.Nil(t, err)
+	require.Equal(t, "1", string(hint))
+	_, err = os.Stat(path.Join(parentDir, "test/table1/metadata/v1.metadata.json"))
+	require.Nil(t, err)
+
+	// Skip the throttling of the checkpoint.
+	sink.lastSendCheckpointTsTime = time.Time{}
+	err = sink.WriteCheckpointTs(ctx, 100, []*model.TableInfo{ddlEvent.TableInfo})
+	require.Nil(t, err)
+	metadata, err := os.ReadFile(path.Join(parentDir, "metadata"))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"checkpoint-ts":100}`, string(metadata))
+}
+
 func TestCleanupExpiredFiles(t *testing.T) {
 	t.Parallel()
 
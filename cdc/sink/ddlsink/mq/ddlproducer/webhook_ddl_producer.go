@@ -0,0 +1,75 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddlproducer
+
+import (
+	"context"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/webhook"
+	"go.uber.org/zap"
+)
+
+// Assert DDLProducer implementation
+var _ DDLProducer = (*webhookDDLProducer)(nil)
+
+// webhookDDLProducer sends DDL and checkpoint messages to a webhook endpoint,
+// each message is sent in its own request.
+type webhookDDLProducer struct {
+	id     model.ChangeFeedID
+	client *webhook.Client
+}
+
+// NewWebhookDDLProducer creates a new webhook DDL producer.
+func NewWebhookDDLProducer(
+	_ context.Context,
+	changefeedID model.ChangeFeedID,
+	cfg *webhook.Config,
+) DDLProducer {
+	log.Info("Starting webhook DDL producer ...",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID))
+	return &webhookDDLProducer{
+		id:     changefeedID,
+		client: webhook.NewClient(changefeedID, cfg),
+	}
+}
+
+// SyncBroadcastMessage sends the message once, a path has no partitions.
+func (p *webhookDDLProducer) SyncBroadcastMessage(ctx context.Context, topic string,
+	_ int32, message *common.Message,
+) error {
+	return p.SyncSendMessage(ctx, topic, 0, message)
+}
+
+// SyncSendMessage sends the message and waits for the endpoint to accept it.
+func (p *webhookDDLProducer) SyncSendMessage(ctx context.Context, topic string,
+	_ int32, message *common.Message,
+) error {
+	if err := p.client.Send(ctx, topic, []*common.Message{message}); err != nil {
+		log.Error("Webhook DDL producer send message failed",
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID),
+			zap.String("path", topic), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Close closes the idle connections.
+func (p *webhookDDLProducer) Close() {
+	p.client.Close()
+}
@@ -15,6 +15,7 @@ package mq
 
 import (
 	"context"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
@@ -22,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher/topic"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/manager"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -31,6 +33,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// internalTopicPrefix is the prefix of the kafka internal topics,
+// e.g. __consumer_offsets and __transaction_state.
+const internalTopicPrefix = "__"
+
 // DDLDispatchRule is the dispatch rule for DDL event.
 type DDLDispatchRule int
 
@@ -113,32 +119,44 @@ func (k *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
 		return nil
 	}
 
-	topic := k.eventRouter.GetTopicForDDL(ddl)
+	topics := []string{k.eventRouter.GetTopicForDDL(ddl)}
+	if pattern := k.eventRouter.GetTopicPatternForDDL(ddl); pattern != nil {
+		topics, err = k.matchTopics(ctx, topics, pattern)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 	partitionRule := getDDLDispatchRule(k.protocol)
 	log.Debug("Emit ddl event",
 		zap.Uint64("commitTs", ddl.CommitTs),
 		zap.String("query", ddl.Query),
+		zap.Strings("topics", topics),
 		zap.String("namespace", k.id.Namespace),
 		zap.String("changefeed", k.id.ID))
-	// Notice: We must call GetPartitionNum here,
-	// which will be responsible for automatically creating topics when they don't exist.
-	// If it is not called here and kafka has `auto.create.topics.enable` turned on,
-	// then the auto-created topic will not be created as configured by ticdc.
-	partitionNum, err := k.topicManager.GetPartitionNum(ctx, topic)
-	if err != nil {
-		return errors.Trace(err)
-	}
+	for _, topic := range topics {
+		// Notice: We must call GetPartitionNum here,
+		// which will be responsible for automatically creating topics when they don't exist.
+		// If it is not called here and kafka has `auto.create.topics.enable` turned on,
+		// then the auto-created topic will not be created as configured by ticdc.
+		partitionNum, err := k.topicManager.GetPartitionNum(ctx, topic)
+		if err != nil {
+			return errors.Trace(err)
+		}
 
-	if partitionRule == PartitionAll {
-		err = k.statistics.RecordDDLExecution(func() error {
-			return k.producer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
-		})
-		return errors.Trace(err)
+		if partitionRule == PartitionAll {
+			err = k.statistics.RecordDDLExecution(func() error {
+				return k.producer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
+			})
+		} else {
+			err = k.statistics.RecordDDLExecution(func() error {
+				return k.producer.SyncSendMessage(ctx, topic, 0, msg)
+			})
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
-	err = k.statistics.RecordDDLExecution(func() error {
-		return k.producer.SyncSendMessage(ctx, topic, 0, msg)
-	})
-	return errors.Trace(err)
+	return nil
 }
 
 // WriteCheckpointTs sends the checkpoint ts to the MQ system.
@@ -172,6 +190,13 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 		tableNames = append(tableNames, table.TableName)
 	}
 	topics := k.eventRouter.GetActiveTopics(tableNames)
+	// The topics of the tables routed by column values are only known
+	// after the rows are sent, so the checkpoint is broadcast to
+	// all the existing topics matching the topic expressions.
+	topics, err = k.matchTopics(ctx, topics, k.eventRouter.GetActiveTopicPatterns(tableNames)...)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	for _, topic := range topics {
 		partitionNum, err := k.topicManager.GetPartitionNum(ctx, topic)
 		if err != nil {
@@ -185,6 +210,37 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 	return nil
 }
 
+// matchTopics appends the existing topics which match any of the patterns to topics,
+// the internal topics such as __consumer_offsets are never matched.
+func (k *DDLSink) matchTopics(
+	ctx context.Context, topics []string, patterns ...*topic.Pattern,
+) ([]string, error) {
+	if len(patterns) == 0 || k.admin == nil {
+		return topics, nil
+	}
+	allTopics, err := k.admin.GetAllTopics(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	seen := make(map[string]struct{}, len(topics))
+	for _, name := range topics {
+		seen[name] = struct{}{}
+	}
+	for _, name := range allTopics {
+		if _, ok := seen[name]; ok || strings.HasPrefix(name, internalTopicPrefix) {
+			continue
+		}
+		for _, pattern := range patterns {
+			if pattern.MatchString(name) {
+				seen[name] = struct{}{}
+				topics = append(topics, name)
+				break
+			}
+		}
+	}
+	return topics, nil
+}
+
 // Close closes the sink.
 func (k *DDLSink) Close() {
 	if k.producer != nil {
@@ -22,6 +22,7 @@ import (
 	mm "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink/mq/ddlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher/topic"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	"github.com/stretchr/testify/require"
@@ -240,6 +241,88 @@ func TestWriteCheckpointTsToTableTopics(t *testing.T) {
 	require.Len(t, s.producer.(*ddlproducer.MockDDLProducer).GetEvents("cdc_person2", 0), 1)
 }
 
+func TestWriteCheckpointTsToColumnTopics(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=true&compression=gzip" +
+		"&protocol=canal-json&enable-tidb-extension=true"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	replicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{
+			Matcher:   []string{"*.*"},
+			TopicRule: "{schema}_{table}_{column:tenant_id}",
+		},
+	}
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	s, err := NewKafkaDDLSink(ctx, model.DefaultChangeFeedID("test"),
+		sinkURI, replicaConfig,
+		kafka.NewMockFactory,
+		ddlproducer.NewMockDDLProducer)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	// The topics are created by the DML sink when the rows are routed.
+	for _, topic := range []string{
+		"cdc_person_acme", "cdc_person_globex", "cdc_other_acme", "__cdc_person_acme",
+	} {
+		err = s.admin.CreateTopic(ctx, &kafka.TopicDetail{
+			Name:              topic,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		}, false)
+		require.NoError(t, err)
+	}
+
+	tables := []*model.TableInfo{
+		{
+			TableName: model.TableName{
+				Schema: "cdc",
+				Table:  "person",
+			},
+		},
+	}
+	err = s.WriteCheckpointTs(ctx, uint64(417318403368288260), tables)
+	require.NoError(t, err)
+
+	producer := s.producer.(*ddlproducer.MockDDLProducer)
+	require.Len(t, producer.GetAllEvents(), 3)
+	require.Len(t, producer.GetEvents("mock_topic", 0), 1)
+	require.Len(t, producer.GetEvents("cdc_person_acme", 0), 1)
+	require.Len(t, producer.GetEvents("cdc_person_globex", 0), 1)
+
+	ddl := &model.DDLEvent{
+		CommitTs: 417318403368288260,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{
+				Schema: "cdc", Table: "person",
+			},
+		},
+		Query: "create table person(id int, tenant_id varchar(64), primary key(id))",
+		Type:  mm.ActionCreateTable,
+	}
+	err = s.WriteDDLEvent(ctx, ddl)
+	require.NoError(t, err)
+	require.Len(t, producer.GetAllEvents(), 6)
+	require.Len(t, producer.GetEvents("cdc_person_acme", 0), 2)
+	require.Empty(t, producer.GetEvents("cdc_other_acme", 0))
+
+	// The internal topics are never matched.
+	matched, err := s.matchTopics(ctx, nil, topic.Expression("{column:tenant_id}_acme").Pattern("cdc", "person"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"cdc_person_acme", "cdc_other_acme"}, matched)
+}
+
 func TestWriteCheckpointTsWhenCanalJsonTiDBExtensionIsDisable(t *testing.T) {
 	t.Parallel()
 
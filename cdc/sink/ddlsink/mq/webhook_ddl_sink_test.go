@@ -0,0 +1,99 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	mm "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDDLSink(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		paths    []string
+		commitTs []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, r.URL.Path)
+		commitTs = append(commitTs, r.Header.Get(webhook.HeaderCommitTs))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sinkURI, err := url.Parse(server.URL + "/cdc?protocol=canal-json&enable-tidb-extension=true")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.*"}, TopicRule: "cdc/{schema}/{table}"},
+	}
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+
+	s, err := NewWebhookDDLSink(ctx, model.DefaultChangeFeedID("test"), sinkURI, replicaConfig)
+	require.NoError(t, err)
+	defer s.Close()
+
+	ddl := &model.DDLEvent{
+		CommitTs: 417318403368288260,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "person"},
+		},
+		Query: "create table person(id int, name varchar(32), primary key(id))",
+		Type:  mm.ActionCreateTable,
+	}
+	require.NoError(t, s.WriteDDLEvent(ctx, ddl))
+
+	// The checkpoint is sent to the default path when there are no tables.
+	require.NoError(t, s.WriteCheckpointTs(ctx, 417318403368288261, nil))
+	require.NoError(t, s.WriteCheckpointTs(ctx, 417318403368288262, []*model.TableInfo{
+		{TableName: model.TableName{Schema: "test", Table: "person"}},
+		{TableName: model.TableName{Schema: "other", Table: "t"}},
+	}))
+
+	require.Equal(t, []string{
+		"/cdc/test/person", "/cdc", "/cdc/test/person", "/cdc",
+	}, paths)
+	require.Equal(t, []string{
+		"417318403368288260", "417318403368288261", "417318403368288262", "417318403368288262",
+	}, commitTs)
+}
+
+func TestWebhookDDLSinkUnsupportedProtocol(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("http://127.0.0.1:8080/cdc?protocol=avro")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	protocol := "avro"
+	replicaConfig.Sink.Protocol = &protocol
+
+	_, err = NewWebhookDDLSink(context.Background(), model.DefaultChangeFeedID("test"),
+		sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "ErrSinkURIInvalid")
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink"
+	ppostgres "github.com/pingcap/tiflow/pkg/sink/postgres"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDDLMaxRetry uint64 = 20
+
+	// networkDriftDuration is used to construct a context timeout for database operations.
+	networkDriftDuration = 5 * time.Second
+)
+
+// GetDBConnImpl is the implementation of ppostgres.Factory.
+// Exported for testing.
+var GetDBConnImpl ppostgres.Factory = ppostgres.CreatePostgresDBConn
+
+// Assert Sink implementation
+var _ ddlsink.Sink = (*DDLSink)(nil)
+
+// DDLSink is a sink that writes DDL events to PostgreSQL.
+// TiDB DDLs are translated by ppostgres.TranslateDDL, the unsupported ones
+// fail the changefeed unless skip-unsupported-ddl is set.
+type DDLSink struct {
+	// id indicates which processor (changefeed) this sink belongs to.
+	id model.ChangeFeedID
+	// db is the database connection.
+	db  *sql.DB
+	cfg *ppostgres.Config
+	// statistics is the statistics of this sink.
+	// We use it to record the DDL count.
+	statistics *metrics.Statistics
+}
+
+// NewDDLSink creates a new DDLSink.
+func NewDDLSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*DDLSink, error) {
+	cfg := ppostgres.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+
+	db, err := GetDBConnImpl(ctx, ppostgres.GenerateDSN(sinkURI))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &DDLSink{
+		id:         changefeedID,
+		db:         db,
+		cfg:        cfg,
+		statistics: metrics.NewStatistics(ctx, changefeedID, sink.TxnSink),
+	}
+
+	log.Info("PostgreSQL DDL sink is created",
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return m, nil
+}
+
+// WriteDDLEvent writes a DDL event to the PostgreSQL database.
+func (m *DDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	stmts, ok := ppostgres.TranslateDDL(ddl)
+	if !ok {
+		if !m.cfg.SkipUnsupportedDDL {
+			return cerror.ErrPostgresUnsupportedDDL.GenWithStackByArgs(ddl.Query, ddl.Type)
+		}
+		log.Warn("DDL is not supported by PostgreSQL sink, skip it",
+			zap.Uint64("commitTs", ddl.CommitTs), zap.String("ddl", ddl.Query),
+			zap.Stringer("type", ddl.Type),
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID))
+		return nil
+	}
+	if len(stmts) == 0 {
+		return nil
+	}
+	return m.execDDLWithMaxRetries(ctx, ddl, stmts)
+}
+
+func (m *DDLSink) execDDLWithMaxRetries(ctx context.Context, ddl *model.DDLEvent, stmts []string) error {
+	return retry.Do(ctx, func() error {
+		err := m.statistics.RecordDDLExecution(func() error { return m.execDDL(ctx, ddl, stmts) })
+		if err != nil {
+			log.Warn("Execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.Strings("statements", stmts),
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	}, retry.WithBackoffBaseDelay(ppostgres.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(ppostgres.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(isRetryableDDLError))
+}
+
+// execDDL executes the translated statements in one transaction, DDLs are
+// transactional in PostgreSQL.
+func (m *DDLSink) execDDL(pctx context.Context, ddl *model.DDLEvent, stmts []string) error {
+	writeTimeout, _ := time.ParseDuration(m.cfg.WriteTimeout)
+	writeTimeout += networkDriftDuration
+	ctx, cancelFunc := context.WithTimeout(pctx, writeTimeout)
+	defer cancelFunc()
+
+	start := time.Now()
+	log.Info("Start exec DDL", zap.String("DDL", ddl.Query), zap.Uint64("commitTs", ddl.CommitTs),
+		zap.Strings("statements", stmts),
+		zap.String("namespace", m.id.Namespace), zap.String("changefeed", m.id.ID))
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPostgresTxnError, err)
+	}
+
+	for _, stmt := range stmts {
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Error("Failed to rollback", zap.String("sql", stmt),
+					zap.String("namespace", m.id.Namespace),
+					zap.String("changefeed", m.id.ID), zap.Error(rbErr))
+			}
+			return cerror.WrapError(cerror.ErrPostgresTxnError, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error("Failed to exec DDL", zap.String("sql", ddl.Query),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("namespace", m.id.Namespace),
+			zap.String("changefeed", m.id.ID), zap.Error(err))
+		return cerror.WrapError(cerror.ErrPostgresTxnError,
+			errors.WithMessage(err, fmt.Sprintf("Query info: %s; ", strings.Join(stmts, "; "))))
+	}
+
+	log.Info("Exec DDL succeeded", zap.String("sql", ddl.Query),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return nil
+}
+
+func isRetryableDDLError(err error) bool {
+	if !cerror.IsRetryableError(err) {
+		return false
+	}
+	pqErr, ok := errors.Cause(err).(*pq.Error)
+	if !ok {
+		return true
+	}
+	switch pqErr.Code.Class() {
+	// syntax error or access rule violation and feature not supported can't be
+	// fixed by retrying.
+	case "42", "0A":
+		return false
+	}
+	return true
+}
+
+// WriteCheckpointTs does nothing.
+func (m *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	// Only for RowSink for now.
+	return nil
+}
+
+// Close closes the database connection.
+func (m *DDLSink) Close() {
+	if m.statistics != nil {
+		m.statistics.Close()
+	}
+	if m.db != nil {
+		if err := m.db.Close(); err != nil {
+			log.Warn("PostgreSQL ddl sink close db wit error",
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+		}
+	}
+}
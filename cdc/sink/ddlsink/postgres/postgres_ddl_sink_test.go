@@ -0,0 +1,87 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/pingcap/errors"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDDLEvent(t *testing.T) {
+	errSyntax := &pq.Error{Code: "42601"}
+	GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`CREATE SCHEMA IF NOT EXISTS "test"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`TRUNCATE TABLE "test"."t1"`).WillReturnError(errSyntax)
+		mock.ExpectRollback()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("postgres://127.0.0.1:5432/postgres")
+	require.Nil(t, err)
+	sink, err := NewDDLSink(ctx, model.DefaultChangeFeedID("test-changefeed"),
+		sinkURI, config.GetDefaultReplicaConfig())
+	require.Nil(t, err)
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t1"},
+	}
+	err = sink.WriteDDLEvent(ctx, &model.DDLEvent{
+		StartTs: 1000, CommitTs: 1010, TableInfo: tableInfo,
+		Type: timodel.ActionCreateSchema, Query: "CREATE DATABASE test",
+	})
+	require.Nil(t, err)
+
+	// Unsupported DDLs fail the changefeed without touching the downstream,
+	// unless skip-unsupported-ddl is set.
+	createView := &model.DDLEvent{
+		StartTs: 1020, CommitTs: 1030, TableInfo: tableInfo,
+		Type: timodel.ActionCreateView, Query: "CREATE VIEW test.v1 AS SELECT 1",
+	}
+	err = sink.WriteDDLEvent(ctx, createView)
+	require.True(t, cerror.ErrPostgresUnsupportedDDL.Equal(err))
+	require.True(t, cerror.ShouldFailChangefeed(err))
+	sink.cfg.SkipUnsupportedDDL = true
+	err = sink.WriteDDLEvent(ctx, createView)
+	require.Nil(t, err)
+
+	// Syntax errors are not retried.
+	err = sink.WriteDDLEvent(ctx, &model.DDLEvent{
+		StartTs: 1040, CommitTs: 1050, TableInfo: tableInfo,
+		Type: timodel.ActionTruncateTable, Query: "TRUNCATE TABLE test.t1",
+	})
+	require.Equal(t, errSyntax, errors.Cause(err))
+
+	sink.Close()
+}
@@ -25,11 +25,14 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/transformer/columnmask"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/sink"
@@ -84,6 +87,8 @@ type DMLSink struct {
 	defragmenter *defragmenter
 	// workers defines a group of workers for writing events to external storage.
 	workers []*dmlWorker
+	// columnMask masks the sensitive columns before the events are encoded.
+	columnMask *columnmask.ColumnMask
 
 	alive struct {
 		sync.RWMutex
@@ -122,6 +127,17 @@ func NewDMLSink(ctx context.Context,
 		return nil, err
 	}
 
+	masterKey, err := encryption.NewMasterKey(cfg.EncryptionMasterKey)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+	}
+	if masterKey != nil {
+		log.Info("data files of the storage sink are encrypted",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeed", changefeedID.ID),
+			zap.String("masterKeyID", masterKey.ID()))
+	}
+
 	// fetch protocol from replicaConfig defined by changefeed config file.
 	protocol, err := util.GetProtocol(
 		putil.GetOrZero(replicaConfig.Sink.Protocol),
@@ -130,8 +146,9 @@ func NewDMLSink(ctx context.Context,
 		return nil, errors.Trace(err)
 	}
 
-	// get cloud storage file extension according to the specific protocol.
-	ext := util.GetFileExtension(protocol)
+	// get cloud storage file extension according to the specific protocol,
+	// the compressed data files carry an extra extension, such as `.json.gz`.
+	ext := util.GetFileExtension(protocol) + compression.FileExtension(cfg.Compression)
 	// the last param maxMsgBytes is mainly to limit the size of a single message for
 	// batch protocols in mq scenario. In cloud storage sink, we just set it to max int.
 	encoderConfig, err := util.GetEncoderConfig(changefeedID, sinkURI, protocol, replicaConfig, math.MaxInt)
@@ -142,6 +159,10 @@ func NewDMLSink(ctx context.Context,
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
 	}
+	columnMask, err := columnmask.New(replicaConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	wgCtx, wgCancel := context.WithCancel(ctx)
 	s := &DMLSink{
@@ -150,6 +171,7 @@ func NewDMLSink(ctx context.Context,
 		outputRawChangeEvent: replicaConfig.Sink.CloudStorageConfig.GetOutputRawChangeEvent(),
 		encodingWorkers:      make([]*encodingWorker, defaultEncodingConcurrency),
 		workers:              make([]*dmlWorker, cfg.WorkerCount),
+		columnMask:           columnMask,
 		statistics:           metrics.NewStatistics(wgCtx, changefeedID, sink.TxnSink),
 		cancel:               wgCancel,
 		dead:                 make(chan struct{}),
@@ -168,8 +190,8 @@ func NewDMLSink(ctx context.Context,
 	// create a group of dml workers.
 	for i := 0; i < cfg.WorkerCount; i++ {
 		inputCh := chann.NewAutoDrainChann[eventFragment]()
-		s.workers[i] = newDMLWorker(i, s.changefeedID, storage, cfg, ext,
-			inputCh, pdClock, s.statistics)
+		s.workers[i] = newDMLWorker(i, s.changefeedID, storage, cfg, protocol, ext,
+			masterKey, inputCh, pdClock, s.statistics)
 		workerChannels[i] = inputCh
 	}
 
@@ -258,6 +280,11 @@ func (s *DMLSink) WriteEvents(txns ...*dmlsink.CallbackableEvent[*model.SingleTa
 			},
 			TableInfoVersion: txn.Event.TableInfoVersion,
 		}
+		for _, row := range txn.Event.Rows {
+			if err := s.columnMask.Apply(row); err != nil {
+				return errors.Trace(err)
+			}
+		}
 		seq := atomic.AddUint64(&s.lastSeqNum, 1)
 
 		s.statistics.ObserveRows(txn.Event.Rows...)
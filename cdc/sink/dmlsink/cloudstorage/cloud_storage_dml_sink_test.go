@@ -32,6 +32,9 @@ import (
 	"github.com/pingcap/tiflow/engine/pkg/clock"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/parquet"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
@@ -310,3 +313,63 @@ func TestCloudStorageWriteEventsWithDateSeparator(t *testing.T) {
 	cancel()
 	s.Close()
 }
+
+func TestCloudStorageWriteParquetEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	parentDir := t.TempDir()
+	uri := fmt.Sprintf("file:///%s?flush-interval=2s", parentDir)
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DateSeparator = util.AddressOf(config.DateSeparatorNone.String())
+	replicaConfig.Sink.Protocol = util.AddressOf(config.ProtocolParquet.String())
+	replicaConfig.Sink.FileIndexWidth = util.AddressOf(6)
+	errCh := make(chan error, 5)
+	s, err := NewDMLSink(ctx,
+		model.DefaultChangeFeedID("test"),
+		pdutil.NewMonotonicClock(clock.New()),
+		sinkURI, replicaConfig, errCh)
+	require.Nil(t, err)
+	var cnt uint64 = 0
+	batch := 100
+	tableStatus := state.TableSinkSinking
+
+	txns := generateTxnEvents(&cnt, batch, &tableStatus)
+	err = s.WriteEvents(txns...)
+	require.Nil(t, err)
+	time.Sleep(3 * time.Second)
+
+	tableDir := path.Join(parentDir, "test/table1/33")
+	fileNames := getTableFiles(t, tableDir)
+	require.ElementsMatch(t, []string{"CDC000001.parquet", "CDC.index"}, fileNames)
+	content, err := os.ReadFile(path.Join(tableDir, "CDC000001.parquet"))
+	require.Nil(t, err)
+	require.Equal(t, uint64(1000), atomic.LoadUint64(&cnt))
+
+	// the file can be decoded with the table definition of the schema file.
+	var tableDef cloudstorage.TableDefinition
+	tableDef.FromTableInfo(txns[0].Event.TableInfo, 33, false)
+	decoder, err := parquet.NewBatchDecoder(
+		common.NewConfig(config.ProtocolParquet), &tableDef, content)
+	require.Nil(t, err)
+	for i := 0; i < 10*batch; i++ {
+		tp, hasNext, err := decoder.HasNext()
+		require.Nil(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+		row, err := decoder.NextRowChangedEvent()
+		require.Nil(t, err)
+		require.Equal(t, uint64(100), row.CommitTs)
+		require.Equal(t, int64(i), row.Columns[0].Value)
+		require.Equal(t, []byte("hello world"), row.Columns[1].Value)
+	}
+	_, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+
+	cancel()
+	s.Close()
+}
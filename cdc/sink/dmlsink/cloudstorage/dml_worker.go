@@ -27,10 +27,14 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	mcloudstorage "github.com/pingcap/tiflow/cdc/sink/metrics/cloudstorage"
 	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/parquet"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -43,6 +47,10 @@ type dmlWorker struct {
 	changeFeedID model.ChangeFeedID
 	storage      storage.ExternalStorage
 	config       *cloudstorage.Config
+	protocol     config.Protocol
+	// masterKey wraps the data keys of the data files, the data files are
+	// not encrypted if it is nil.
+	masterKey encryption.MasterKey
 	// toBeFlushedCh contains a set of batchedTask waiting to be flushed to cloud storage.
 	toBeFlushedCh          chan batchedTask
 	inputCh                *chann.DrainableChann[eventFragment]
@@ -108,7 +116,9 @@ func newDMLWorker(
 	changefeedID model.ChangeFeedID,
 	storage storage.ExternalStorage,
 	config *cloudstorage.Config,
+	protocol config.Protocol,
 	extension string,
+	masterKey encryption.MasterKey,
 	inputCh *chann.DrainableChann[eventFragment],
 	pdClock pdutil.Clock,
 	statistics *metrics.Statistics,
@@ -118,6 +128,8 @@ func newDMLWorker(
 		changeFeedID:      changefeedID,
 		storage:           storage,
 		config:            config,
+		protocol:          protocol,
+		masterKey:         masterKey,
 		inputCh:           inputCh,
 		toBeFlushedCh:     make(chan batchedTask, 64),
 		statistics:        statistics,
@@ -255,20 +267,21 @@ func (d *dmlWorker) writeIndexFile(ctx context.Context, path, content string) er
 
 func (d *dmlWorker) writeDataFile(ctx context.Context, path string, task *singleTableTask) error {
 	var callbacks []func()
-	buf := bytes.NewBuffer(make([]byte, 0, task.size))
 	rowsCnt := 0
-	bytesCnt := int64(0)
 	for _, msg := range task.msgs {
-		bytesCnt += int64(len(msg.Value))
 		rowsCnt += msg.GetRowsCount()
-		buf.Write(msg.Value)
 		callbacks = append(callbacks, msg.Callback)
 	}
+	data, err := d.encodeDataFile(task)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	bytesCnt := int64(len(data))
 
 	if err := d.statistics.RecordBatchExecution(func() (int, int64, error) {
 		start := time.Now()
 		if d.config.FlushConcurrency <= 1 {
-			return rowsCnt, bytesCnt, d.storage.WriteFile(ctx, path, buf.Bytes())
+			return rowsCnt, bytesCnt, d.storage.WriteFile(ctx, path, data)
 		}
 
 		writer, inErr := d.storage.Create(ctx, path, &storage.WriterOption{
@@ -291,7 +304,7 @@ func (d *dmlWorker) writeDataFile(ctx context.Context, path string, task *single
 				}
 			}
 		}()
-		if _, inErr = writer.Write(ctx, buf.Bytes()); inErr != nil {
+		if _, inErr = writer.Write(ctx, data); inErr != nil {
 			return 0, 0, inErr
 		}
 
@@ -301,6 +314,27 @@ func (d *dmlWorker) writeDataFile(ctx context.Context, path string, task *single
 		return err
 	}
 
+	// The data file must be recorded before the callbacks are called,
+	// otherwise it may be missed by the snapshot of the next checkpoint.
+	if d.config.TableFormat == cloudstorage.TableFormatIceberg {
+		dataFile := &cloudstorage.IcebergDataFile{
+			RecordCount:     int64(rowsCnt),
+			FileSizeInBytes: bytesCnt,
+		}
+		for i, msg := range task.msgs {
+			if i == 0 || msg.Ts < dataFile.MinCommitTs {
+				dataFile.MinCommitTs = msg.Ts
+			}
+			if msg.Ts > dataFile.MaxCommitTs {
+				dataFile.MaxCommitTs = msg.Ts
+			}
+		}
+		err := cloudstorage.WriteIcebergPendingFile(ctx, d.storage, task.tableInfo, path, dataFile)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	d.metricWriteBytes.Add(float64(bytesCnt))
 	d.metricFileCount.Add(1)
 	for _, cb := range callbacks {
@@ -312,6 +346,39 @@ func (d *dmlWorker) writeDataFile(ctx context.Context, path string, task *single
 	return nil
 }
 
+// encodeDataFile returns the content of the data file of the task.
+func (d *dmlWorker) encodeDataFile(task *singleTableTask) ([]byte, error) {
+	data, err := d.marshalDataFile(task)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if d.masterKey == nil {
+		return data, nil
+	}
+	// The data is encrypted after it is compressed, since the ciphertext
+	// can't be compressed.
+	return encryption.Encrypt(d.masterKey, data)
+}
+
+func (d *dmlWorker) marshalDataFile(task *singleTableTask) ([]byte, error) {
+	if d.protocol == config.ProtocolParquet {
+		// A parquet file can't be concatenated, so the messages carry the
+		// encoded rows, which are written into one row group of a new file.
+		var tableDef cloudstorage.TableDefinition
+		tableDef.FromTableInfo(task.tableInfo, task.tableInfo.Version, false)
+		return parquet.WriteFile(&tableDef, task.msgs)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, task.size))
+	for _, msg := range task.msgs {
+		buf.Write(msg.Value)
+	}
+	if d.config.Compression == compression.None {
+		return buf.Bytes(), nil
+	}
+	return compression.EncodeWithLevel(d.config.Compression, d.config.CompressionLevel, buf.Bytes())
+}
+
 // genAndDispatchTask dispatches flush tasks in two conditions:
 // 1. the flush interval exceeds the upper limit.
 // 2. the file size exceeds the upper limit.
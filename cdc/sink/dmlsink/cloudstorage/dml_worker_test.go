@@ -55,7 +55,7 @@ func testDMLWorker(ctx context.Context, t *testing.T, dir string) *dmlWorker {
 		sink.TxnSink)
 	pdlock := pdutil.NewMonotonicClock(clock.New())
 	d := newDMLWorker(1, model.DefaultChangeFeedID("dml-worker-test"), storage,
-		cfg, ".json", chann.NewAutoDrainChann[eventFragment](), pdlock, statistics)
+		cfg, config.ProtocolCanalJSON, ".json", nil, chann.NewAutoDrainChann[eventFragment](), pdlock, statistics)
 	return d
 }
 
@@ -0,0 +1,180 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+	sinkutil "github.com/pingcap/tiflow/cdc/sink/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/builder"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const defaultStorageTimeout = 5 * time.Minute
+
+// writer writes the encoded rows of a failed transaction.
+type writer interface {
+	write(ctx context.Context, txn *model.SingleTableTxn, messages []*common.Message) error
+	close()
+}
+
+// Queue is the dead letter queue of a sink. Transactions which can not be
+// applied to the downstream are encoded and written to it, so that the
+// replication can continue.
+type Queue struct {
+	changefeedID model.ChangeFeedID
+	topic        string
+	writer       writer
+
+	encoderBuilder codec.RowEventEncoderBuilder
+	maxErrorCount  int64
+	// errorCount is the number of failed transactions since the sink is created.
+	// It's not persisted, so it's reset once the sink is recreated, for example,
+	// after the changefeed is restarted or moved to another capture.
+	errorCount atomic.Int64
+	// warnings is used to report the written transactions as warnings.
+	warnings chan<- error
+
+	metricWriteCount prometheus.Counter
+}
+
+// New creates a dead letter queue with the sink config of the replica config.
+func New(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	replicaConfig *config.ReplicaConfig,
+	warnings chan<- error,
+) (*Queue, error) {
+	cfg := replicaConfig.Sink.DeadLetterQueue
+	uri, err := url.Parse(cfg.URI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	protocol, err := config.ParseSinkProtocolFromString(cfg.Protocol)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var (
+		w               writer
+		topic           string
+		maxMessageBytes = config.DefaultMaxMessageBytes
+	)
+	if uri.Scheme == sink.KafkaScheme || uri.Scheme == sink.KafkaSSLScheme {
+		var kw *kafkaWriter
+		kw, err = newKafkaWriter(ctx, changefeedID, uri, replicaConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		w, topic, maxMessageBytes = kw, kw.topic, kw.maxMessageBytes
+	} else {
+		w, err = newStorageWriter(ctx, cfg.URI, protocol)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	encoderConfig, err := sinkutil.GetEncoderConfig(
+		changefeedID, uri, protocol, replicaConfig, maxMessageBytes)
+	if err != nil {
+		w.close()
+		return nil, errors.Trace(err)
+	}
+	encoderBuilder, err := builder.NewRowEventEncoderBuilder(ctx, encoderConfig)
+	if err != nil {
+		w.close()
+		return nil, errors.Trace(err)
+	}
+
+	log.Info("dead letter queue is created",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("uri", util.MaskSensitiveDataInURI(cfg.URI)),
+		zap.String("protocol", protocol.String()),
+		zap.Int64("maxErrorCount", cfg.MaxErrorCount))
+	return &Queue{
+		changefeedID:     changefeedID,
+		topic:            topic,
+		writer:           w,
+		encoderBuilder:   encoderBuilder,
+		maxErrorCount:    cfg.MaxErrorCount,
+		warnings:         warnings,
+		metricWriteCount: txn.DeadLetterQueueWriteCount.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}, nil
+}
+
+// Write writes a transaction which fails to apply with cause to the queue,
+// and reports it as a warning. An error is returned if the transaction can
+// not be written, or too many transactions have been written.
+func (q *Queue) Write(ctx context.Context, txn *model.SingleTableTxn, cause error) error {
+	count := q.errorCount.Inc()
+	if count > q.maxErrorCount {
+		log.Error("too many transactions fail to apply",
+			zap.String("namespace", q.changefeedID.Namespace),
+			zap.String("changefeed", q.changefeedID.ID),
+			zap.Int64("maxErrorCount", q.maxErrorCount),
+			zap.Error(cause))
+		return cerror.ErrDeadLetterQueueTooManyErrors.GenWithStackByArgs(count, q.maxErrorCount)
+	}
+
+	encoder := q.encoderBuilder.Build()
+	for _, row := range txn.Rows {
+		if err := encoder.AppendRowChangedEvent(ctx, q.topic, row, nil); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := q.writer.write(ctx, txn, encoder.Build()); err != nil {
+		return errors.Trace(err)
+	}
+	q.metricWriteCount.Inc()
+
+	tableName := txn.TableInfo.TableName.String()
+	log.Warn("transaction is written to the dead letter queue",
+		zap.String("namespace", q.changefeedID.Namespace),
+		zap.String("changefeed", q.changefeedID.ID),
+		zap.String("table", tableName),
+		zap.Uint64("startTs", txn.StartTs),
+		zap.Uint64("commitTs", txn.CommitTs),
+		zap.Int("rows", len(txn.Rows)),
+		zap.Int64("errorCount", count),
+		zap.Error(cause))
+	warning := cerror.ErrDeadLetterQueueWritten.GenWithStackByArgs(
+		tableName, txn.CommitTs, errors.Cause(cause).Error())
+	select {
+	case q.warnings <- warning:
+	default:
+	}
+	return nil
+}
+
+// Close closes the queue.
+func (q *Queue) Close() {
+	q.writer.close()
+	q.encoderBuilder.CleanMetrics()
+	txn.DeadLetterQueueWriteCount.DeleteLabelValues(q.changefeedID.Namespace, q.changefeedID.ID)
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueWriteToStorage(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(10))")
+	row := helper.DML2Event("insert into test.t values (1, 'a')", "test", "t")
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DeadLetterQueue = &config.DeadLetterQueueConfig{
+		URI:           "file://" + dir,
+		Protocol:      config.ProtocolCanalJSON.String(),
+		MaxErrorCount: 1,
+	}
+	warnings := make(chan error, 1)
+	q, err := New(ctx, model.DefaultChangeFeedID("test"), replicaConfig, warnings)
+	require.NoError(t, err)
+	defer q.Close()
+
+	txn := &model.SingleTableTxn{
+		TableInfo: row.TableInfo,
+		StartTs:   row.StartTs,
+		CommitTs:  row.CommitTs,
+		Rows:      []*model.RowChangedEvent{row},
+	}
+	require.NoError(t, q.Write(ctx, txn, errors.New("data too long")))
+	warning := <-warnings
+	require.True(t, cerror.ErrDeadLetterQueueWritten.Equal(warning))
+	require.Contains(t, warning.Error(), "data too long")
+
+	files, err := filepath.Glob(filepath.Join(dir, "test", "t", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"type":"INSERT"`)
+
+	// The changefeed fails once the max error count is exceeded.
+	err = q.Write(ctx, txn, errors.New("data too long"))
+	require.True(t, cerror.ErrDeadLetterQueueTooManyErrors.Equal(err))
+	require.True(t, cerror.ShouldFailChangefeed(err))
+}
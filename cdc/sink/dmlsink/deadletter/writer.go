@@ -0,0 +1,144 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	sinkutil "github.com/pingcap/tiflow/cdc/sink/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// storageWriter writes each failed transaction into a file of the external
+// storage, one encoded row per line.
+type storageWriter struct {
+	storage   storage.ExternalStorage
+	extension string
+}
+
+func newStorageWriter(ctx context.Context, uri string, protocol config.Protocol) (*storageWriter, error) {
+	s, err := util.GetExternalStorageWithTimeout(ctx, uri, defaultStorageTimeout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storageWriter{
+		storage:   s,
+		extension: sinkutil.GetFileExtension(protocol),
+	}, nil
+}
+
+// fileName returns the path of the transaction, which is
+// {schema}/{table}/{commitTs}-{uuid}{extension}.
+func (w *storageWriter) fileName(txn *model.SingleTableTxn) string {
+	return fmt.Sprintf("%s/%s/%d-%s%s",
+		txn.TableInfo.GetSchemaName(), txn.TableInfo.GetTableName(),
+		txn.CommitTs, uuid.NewString(), w.extension)
+}
+
+func (w *storageWriter) write(
+	ctx context.Context, txn *model.SingleTableTxn, messages []*common.Message,
+) error {
+	var buf bytes.Buffer
+	for _, msg := range messages {
+		buf.Write(msg.Value)
+		buf.WriteString("\n")
+	}
+	return errors.Trace(w.storage.WriteFile(ctx, w.fileName(txn), buf.Bytes()))
+}
+
+func (w *storageWriter) close() {
+	w.storage.Close()
+}
+
+// kafkaWriter sends the rows of failed transactions to a kafka topic.
+type kafkaWriter struct {
+	topic           string
+	maxMessageBytes int
+	adminClient     kafka.ClusterAdminClient
+	producer        kafka.SyncProducer
+}
+
+func newKafkaWriter(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	uri *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (_ *kafkaWriter, err error) {
+	topic, err := sinkutil.GetTopic(uri)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	options := kafka.NewOptions()
+	if err := options.Apply(changefeedID, uri, replicaConfig); err != nil {
+		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+	}
+	factory, err := kafka.NewSaramaFactory(options, changefeedID)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrKafkaNewProducer, err)
+	}
+	adminClient, err := factory.AdminClient(ctx)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrKafkaNewProducer, err)
+	}
+	defer func() {
+		if err != nil {
+			adminClient.Close()
+		}
+	}()
+	if err := kafka.AdjustOptions(ctx, adminClient, options, topic); err != nil {
+		return nil, cerror.WrapError(cerror.ErrKafkaNewProducer, err)
+	}
+	if _, err := sinkutil.GetTopicManagerAndTryCreateTopic(
+		ctx, changefeedID, topic, options.DeriveTopicConfig(), adminClient,
+	); err != nil {
+		return nil, errors.Trace(err)
+	}
+	producer, err := factory.SyncProducer(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &kafkaWriter{
+		topic:           topic,
+		maxMessageBytes: options.MaxMessageBytes,
+		adminClient:     adminClient,
+		producer:        producer,
+	}, nil
+}
+
+func (w *kafkaWriter) write(
+	ctx context.Context, _ *model.SingleTableTxn, messages []*common.Message,
+) error {
+	for _, msg := range messages {
+		if err := w.producer.SendMessage(ctx, w.topic, 0, msg); err != nil {
+			return cerror.WrapError(cerror.ErrKafkaSendMessage, err)
+		}
+	}
+	return nil
+}
+
+func (w *kafkaWriter) close() {
+	w.producer.Close()
+	w.adminClient.Close()
+}
@@ -13,6 +13,8 @@
 
 package dmlsink
 
+import "github.com/pingcap/tiflow/cdc/processor/tablepb"
+
 // EventSink is the interface for event sink.
 type EventSink[E TableEvent] interface {
 	// WriteEvents writes events to the sink.
@@ -25,3 +27,13 @@ type EventSink[E TableEvent] interface {
 	// The EventSink meets internal errors and has been dead already.
 	Dead() <-chan struct{}
 }
+
+// TableListener is implemented by the event sinks which keep states of
+// tables. Table sinks notify their backend sinks when they are created
+// and closed, so that the states can be rebuilt for new table sinks.
+type TableListener interface {
+	// AddTable is called when a table sink of the span is created.
+	AddTable(span tablepb.Span)
+	// RemoveTable is called after the table sink of the span is closed.
+	RemoveTable(span tablepb.Span)
+}
@@ -89,6 +89,14 @@ func New(
 		}
 		s.txnSink = txnSink
 		s.category = CategoryTxn
+	case sink.PostgresScheme, sink.PostgreSQLScheme:
+		txnSink, err := txn.NewPostgresSink(ctx, changefeedID, sinkURI, cfg, errCh,
+			txn.DefaultConflictDetectorSlots)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = txnSink
+		s.category = CategoryTxn
 	case sink.KafkaScheme, sink.KafkaSSLScheme:
 		factoryCreator := kafka.NewSaramaFactory
 		if util.GetOrZero(cfg.Sink.EnableKafkaSinkV2) {
@@ -121,6 +129,13 @@ func New(
 		}
 		s.txnSink = mqs
 		s.category = CategoryMQ
+	case sink.HTTPScheme, sink.HTTPSScheme:
+		mqs, err := mq.NewWebhookDMLSink(ctx, changefeedID, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = mqs
+		s.category = CategoryMQ
 	default:
 		return nil,
 			cerror.ErrSinkURIInvalid.GenWithStack("the sink scheme (%s) is not supported", scheme)
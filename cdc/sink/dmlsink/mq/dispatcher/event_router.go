@@ -88,30 +88,47 @@ func NewEventRouter(
 }
 
 // GetTopicForRowChange returns the target topic for row changes.
-func (s *EventRouter) GetTopicForRowChange(row *model.RowChangedEvent) string {
+func (s *EventRouter) GetTopicForRowChange(row *model.RowChangedEvent) (string, error) {
 	topicDispatcher, _ := s.matchDispatcher(row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName())
-	return topicDispatcher.Substitute(row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName())
+	return topicDispatcher.SubstituteRow(row)
 }
 
 // GetTopicForDDL returns the target topic for DDL.
+// If the topic of the table depends on the column values, the default topic is returned,
+// and the topics matching GetTopicPatternForDDL should also receive the DDL.
 func (s *EventRouter) GetTopicForDDL(ddl *model.DDLEvent) string {
-	var schema, table string
-	if ddl.PreTableInfo != nil {
-		if ddl.PreTableInfo.TableName.Table == "" {
-			return s.defaultTopic
-		}
-		schema = ddl.PreTableInfo.TableName.Schema
-		table = ddl.PreTableInfo.TableName.Table
-	} else {
-		if ddl.TableInfo.TableName.Table == "" {
-			return s.defaultTopic
-		}
-		schema = ddl.TableInfo.TableName.Schema
-		table = ddl.TableInfo.TableName.Table
+	tableName, ok := getDDLTableName(ddl)
+	if !ok {
+		return s.defaultTopic
+	}
+
+	topicDispatcher, _ := s.matchDispatcher(tableName.Schema, tableName.Table)
+	if topicDispatcher.Pattern(tableName.Schema, tableName.Table) != nil {
+		return s.defaultTopic
+	}
+	return topicDispatcher.Substitute(tableName.Schema, tableName.Table)
+}
+
+// GetTopicPatternForDDL returns the pattern of the topics the DDL should be sent to
+// if the topic of the table depends on the column values, otherwise nil is returned.
+func (s *EventRouter) GetTopicPatternForDDL(ddl *model.DDLEvent) *topic.Pattern {
+	tableName, ok := getDDLTableName(ddl)
+	if !ok {
+		return nil
 	}
+	topicDispatcher, _ := s.matchDispatcher(tableName.Schema, tableName.Table)
+	return topicDispatcher.Pattern(tableName.Schema, tableName.Table)
+}
 
-	topicDispatcher, _ := s.matchDispatcher(schema, table)
-	return topicDispatcher.Substitute(schema, table)
+func getDDLTableName(ddl *model.DDLEvent) (model.TableName, bool) {
+	tableInfo := ddl.TableInfo
+	if ddl.PreTableInfo != nil {
+		tableInfo = ddl.PreTableInfo
+	}
+	if tableInfo.TableName.Table == "" {
+		return model.TableName{}, false
+	}
+	return tableInfo.TableName, true
 }
 
 // GetPartitionForRowChange returns the target partition for row changes.
@@ -157,17 +174,30 @@ func (s *EventRouter) VerifyTables(infos []*model.TableInfo) error {
 			}
 		default:
 		}
+		topicDispatcher, _ := s.matchDispatcher(table.TableName.Schema, table.TableName.Table)
+		if v, ok := topicDispatcher.(*topic.DynamicTopicDispatcher); ok {
+			columns := v.Columns()
+			if _, ok := table.OffsetsByNames(columns); !ok {
+				return cerror.ErrDispatcherFailed.GenWithStack(
+					"columns not found when verify the table, table: %v, topic columns: %v", table.TableName, columns)
+			}
+		}
 	}
 	return nil
 }
 
 // GetActiveTopics returns a list of the corresponding topics
 // for the tables that are actively synchronized.
+// The tables whose topic depends on the column values are skipped,
+// use GetActiveTopicPatterns to find their topics.
 func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
 	topics := make([]string, 0)
 	topicsMap := make(map[string]bool, len(activeTables))
 	for _, table := range activeTables {
 		topicDispatcher, _ := s.matchDispatcher(table.Schema, table.Table)
+		if topicDispatcher.Pattern(table.Schema, table.Table) != nil {
+			continue
+		}
 		topicName := topicDispatcher.Substitute(table.Schema, table.Table)
 		if topicName == s.defaultTopic {
 			log.Debug("topic name corresponding to the table is the same as the default topic name",
@@ -190,6 +220,19 @@ func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
 	return topics
 }
 
+// GetActiveTopicPatterns returns the patterns of the topics for the tables
+// that are actively synchronized and whose topic depends on the column values.
+func (s *EventRouter) GetActiveTopicPatterns(activeTables []model.TableName) []*topic.Pattern {
+	var patterns []*topic.Pattern
+	for _, table := range activeTables {
+		topicDispatcher, _ := s.matchDispatcher(table.Schema, table.Table)
+		if pattern := topicDispatcher.Pattern(table.Schema, table.Table); pattern != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
 // GetDefaultTopic returns the default topic name.
 func (s *EventRouter) GetDefaultTopic() string {
 	return s.defaultTopic
@@ -265,6 +308,9 @@ func validateTopicExpression(expr topic.Expression, scheme string, protocol conf
 	if sink.IsPulsarScheme(scheme) {
 		return expr.PulsarValidate()
 	}
+	if sink.IsHTTPScheme(scheme) {
+		return expr.WebhookValidate()
+	}
 
 	switch protocol {
 	case config.ProtocolAvro:
@@ -159,42 +159,137 @@ func TestGetTopicForRowChange(t *testing.T) {
 	d, err := NewEventRouter(replicaConfig, config.ProtocolCanalJSON, "test", "kafka")
 	require.NoError(t, err)
 
-	topicName := d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err := d.GetTopicForRowChange(&model.RowChangedEvent{
 		TableInfo: &model.TableInfo{
 			TableName: model.TableName{Schema: "test_default1", Table: "table"},
 		},
 	})
+	require.NoError(t, err)
 	require.Equal(t, "test", topicName)
 
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		TableInfo: &model.TableInfo{
 			TableName: model.TableName{Schema: "test_default2", Table: "table"},
 		},
 	})
+	require.NoError(t, err)
 	require.Equal(t, "test", topicName)
 
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		TableInfo: &model.TableInfo{
 			TableName: model.TableName{Schema: "test_table", Table: "table"},
 		},
 	})
+	require.NoError(t, err)
 	require.Equal(t, "hello_test_table_world", topicName)
 
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		TableInfo: &model.TableInfo{
 			TableName: model.TableName{Schema: "test_index_value", Table: "table"},
 		},
 	})
+	require.NoError(t, err)
 	require.Equal(t, "test_index_value_world", topicName)
 
-	topicName = d.GetTopicForRowChange(&model.RowChangedEvent{
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
 		TableInfo: &model.TableInfo{
 			TableName: model.TableName{Schema: "a", Table: "table"},
 		},
 	})
+	require.NoError(t, err)
 	require.Equal(t, "a_table", topicName)
 }
 
+func TestGetTopicForRowChangeByColumn(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := &config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"tenant.*"},
+					PartitionRule: "default",
+					TopicRule:     "tenant_{column:tenant_id}",
+				},
+				{
+					Matcher:       []string{"bucket.*"},
+					PartitionRule: "default",
+					TopicRule:     "{schema}_{table}_{column:tenant_id%4}",
+				},
+			},
+		},
+	}
+	d, err := NewEventRouter(replicaConfig, config.ProtocolCanalJSON, "test", sink.KafkaScheme)
+	require.NoError(t, err)
+
+	cols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "tenant_id", Value: "acme"},
+	}
+	tableInfo := model.BuildTableInfo("tenant", "orders", cols, [][]int{{0}})
+	topicName, err := d.GetTopicForRowChange(&model.RowChangedEvent{
+		TableInfo: tableInfo,
+		Columns:   model.Columns2ColumnDatas(cols, tableInfo),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "tenant_acme", topicName)
+
+	// the delete event is routed by the pre columns.
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
+		TableInfo:  tableInfo,
+		PreColumns: model.Columns2ColumnDatas(cols, tableInfo),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "tenant_acme", topicName)
+
+	// NULL value is rejected.
+	nullCols := []*model.Column{
+		{Name: "id", Value: 2, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "tenant_id", Value: nil},
+	}
+	_, err = d.GetTopicForRowChange(&model.RowChangedEvent{
+		TableInfo: tableInfo,
+		Columns:   model.Columns2ColumnDatas(nullCols, tableInfo),
+	})
+	require.ErrorContains(t, err, "is NULL")
+
+	// missing column is rejected.
+	missingCols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+	}
+	missingTableInfo := model.BuildTableInfo("tenant", "users", missingCols, [][]int{{0}})
+	_, err = d.GetTopicForRowChange(&model.RowChangedEvent{
+		TableInfo: missingTableInfo,
+		Columns:   model.Columns2ColumnDatas(missingCols, missingTableInfo),
+	})
+	require.ErrorContains(t, err, "not found")
+	require.Error(t, d.VerifyTables([]*model.TableInfo{missingTableInfo}))
+	require.NoError(t, d.VerifyTables([]*model.TableInfo{tableInfo}))
+
+	bucketTableInfo := model.BuildTableInfo("bucket", "orders", cols, [][]int{{0}})
+	topicName, err = d.GetTopicForRowChange(&model.RowChangedEvent{
+		TableInfo: bucketTableInfo,
+		Columns:   model.Columns2ColumnDatas(cols, bucketTableInfo),
+	})
+	require.NoError(t, err)
+	require.Regexp(t, "^bucket_orders_[0-3]$", topicName)
+
+	// DDL and checkpoint are sent to the default topic and the matched topics.
+	ddl := &model.DDLEvent{TableInfo: tableInfo}
+	require.Equal(t, "test", d.GetTopicForDDL(ddl))
+	pattern := d.GetTopicPatternForDDL(ddl)
+	require.NotNil(t, pattern)
+	require.True(t, pattern.MatchString("tenant_acme"))
+	require.False(t, pattern.MatchString("test"))
+
+	names := []model.TableName{tableInfo.TableName, bucketTableInfo.TableName, {Schema: "a", Table: "b"}}
+	require.Equal(t, []string{"test"}, d.GetActiveTopics(names))
+	patterns := d.GetActiveTopicPatterns(names)
+	require.Len(t, patterns, 2)
+	require.True(t, patterns[1].MatchString(topicName))
+	require.False(t, patterns[1].MatchString("bucket_orders_x"))
+}
+
 func TestGetPartitionForRowChange(t *testing.T) {
 	t.Parallel()
 
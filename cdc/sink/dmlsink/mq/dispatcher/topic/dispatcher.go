@@ -15,12 +15,19 @@ package topic
 
 import (
 	"fmt"
+
+	"github.com/pingcap/tiflow/cdc/model"
 )
 
 // Dispatcher is an abstraction for dispatching rows and ddls into different topics.
 type Dispatcher interface {
 	fmt.Stringer
 	Substitute(schema, table string) string
+	// SubstituteRow returns the topic of the row, it may depend on the column values.
+	SubstituteRow(row *model.RowChangedEvent) (string, error)
+	// Pattern returns the pattern of topics the rows of the table may be dispatched to,
+	// nil is returned if the topic does not depend on the column values.
+	Pattern(schema, table string) *Pattern
 }
 
 // StaticTopicDispatcher is a topic dispatcher which dispatches rows and DDL to the specific topic.
@@ -40,6 +47,16 @@ func (s *StaticTopicDispatcher) Substitute(schema, table string) string {
 	return s.topic
 }
 
+// SubstituteRow returns the static topic.
+func (s *StaticTopicDispatcher) SubstituteRow(_ *model.RowChangedEvent) (string, error) {
+	return s.topic, nil
+}
+
+// Pattern always returns nil since the topic is static.
+func (s *StaticTopicDispatcher) Pattern(_, _ string) *Pattern {
+	return nil
+}
+
 func (s *StaticTopicDispatcher) String() string {
 	return s.topic
 }
@@ -62,6 +79,22 @@ func (d *DynamicTopicDispatcher) Substitute(schema, table string) string {
 	return d.expression.Substitute(schema, table)
 }
 
+// SubstituteRow converts schema/table name and column values in a topic expression
+// to kafka topic name.
+func (d *DynamicTopicDispatcher) SubstituteRow(row *model.RowChangedEvent) (string, error) {
+	return d.expression.SubstituteRow(row)
+}
+
+// Pattern returns the pattern of topics the rows of the table may be dispatched to.
+func (d *DynamicTopicDispatcher) Pattern(schema, table string) *Pattern {
+	return d.expression.Pattern(schema, table)
+}
+
+// Columns returns the names of the columns referenced by the topic expression.
+func (d *DynamicTopicDispatcher) Columns() []string {
+	return d.expression.Columns()
+}
+
 func (d *DynamicTopicDispatcher) String() string {
 	return string(d.expression)
 }
@@ -14,9 +14,12 @@
 package topic
 
 import (
+	"hash/crc32"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -34,6 +37,10 @@ var (
 	schemaRE = regexp.MustCompile(`\{schema\}`)
 	// tableRE is used to match substring '{table}' in topic expression
 	tableRE = regexp.MustCompile(`\{table\}`)
+	// columnRE is used to match substring '{column:<name>}' or '{column:<name>%<buckets>}'
+	// in topic expression, the former is substituted with the column value and the
+	// latter with the hash bucket of the column value.
+	columnRE = regexp.MustCompile(`\{column:([A-Za-z0-9_]+)(?:%([1-9][0-9]*))?\}`)
 	// avro has different topic name pattern requirements, '{schema}' and '{table}' placeholders
 	// are necessary
 	avroTopicNameRE = regexp.MustCompile(
@@ -44,6 +51,11 @@ var (
 		`(^((persistent|non-persistent)://)[A-Za-z0-9{}._\-]*/[A-Za-z0-9{}._\-]*/[A-Za-z0-9{}._\-]*$)|` +
 			`(^[A-Za-z0-9._-]*\{schema}[A-Za-z0-9._-]*\{table}[A-Za-z0-9._-]*)$`,
 	)
+	// webhookPathRE is used to match a valid webhook path expression, '/' is
+	// allowed so that events can be routed to nested paths.
+	webhookPathRE = regexp.MustCompile(
+		`^[A-Za-z0-9\._\-/]*(\{schema\})?([A-Za-z0-9\._\-/]*\{table\})?[A-Za-z0-9\._\-/]*$`,
+	)
 	// pulsarTopicNameREFull is used to match pulsar full topic name
 	pulsarTopicNameREFull = regexp.MustCompile(
 		`(?:persistent|non-persistent)://.*`,
@@ -58,13 +70,28 @@ const kafkaTopicNameMaxLength = 249
 // The expression should be in form of: [prefix]{schema}[middle][{table}][suffix]
 // prefix/suffix/middle are optional and should match the regex of [A-Za-z0-9\._\-]*
 // {table} can also be optional.
+// Any part of prefix/suffix/middle can also reference a column value of the row
+// by '{column:<name>}', or the hash bucket of a column value by '{column:<name>%<buckets>}'.
+// The column placeholders must come with a literal part, {schema} or {table}.
+// An UPDATE which changes the topic of a row is sent as a DELETE to the old
+// topic and an INSERT to the new topic.
 type Expression string
 
 // Validate checks whether a kafka topic name is valid or not.
 // return true if the expression is hard coded.
 func (e Expression) Validate() error {
-	// validate the topic expression
-	if ok := topicNameRE.MatchString(string(e)); ok {
+	// the column placeholders can not be the whole expression, otherwise the
+	// topics it matches can not be told apart from the other topics.
+	if columnRE.MatchString(string(e)) && columnRE.ReplaceAllString(string(e), "") == "" {
+		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs(
+			"topic expression must contain a literal part, {schema} or {table} " +
+				"besides the column placeholders",
+		)
+	}
+	// validate the topic expression, the column placeholders are treated as
+	// plain characters since they are substituted by valid characters.
+	expr := columnRE.ReplaceAllString(string(e), "_")
+	if ok := topicNameRE.MatchString(expr); ok {
 		return nil
 	}
 
@@ -94,7 +121,162 @@ func (e Expression) Substitute(schema, table string) string {
 	// doing the real conversion things
 	topicName := schemaRE.ReplaceAllString(topicExpr, replacedSchema)
 	topicName = tableRE.ReplaceAllString(topicName, replacedTable)
+	return normalizeTopicName(topicName)
+}
+
+// Columns returns the names of the columns referenced by the expression.
+func (e Expression) Columns() []string {
+	matches := columnRE.FindAllStringSubmatch(string(e), -1)
+	columns := make([]string, 0, len(matches))
+	for _, match := range matches {
+		columns = append(columns, match[1])
+	}
+	return columns
+}
+
+// SubstituteRow converts schema/table name and the referenced column values
+// in a topic expression to kafka topic name. An error is returned if any
+// referenced column is missing in the table or its value is NULL.
+func (e Expression) SubstituteRow(row *model.RowChangedEvent) (string, error) {
+	schema := row.TableInfo.GetSchemaName()
+	table := row.TableInfo.GetTableName()
+	if !columnRE.MatchString(string(e)) {
+		return e.Substitute(schema, table), nil
+	}
+
+	columns := row.Columns
+	if len(columns) == 0 {
+		columns = row.PreColumns
+	}
+	topicExpr := string(e)
+	for _, match := range columnRE.FindAllStringSubmatch(topicExpr, -1) {
+		offsets, ok := row.TableInfo.OffsetsByNames([]string{match[1]})
+		if !ok {
+			return "", errors.ErrDispatcherFailed.GenWithStack(
+				"column %s referenced by topic expression %s not found, table: %s.%s",
+				match[1], e, schema, table)
+		}
+		var value interface{}
+		if offsets[0] < len(columns) && columns[offsets[0]] != nil {
+			value = columns[offsets[0]].Value
+		}
+		if value == nil {
+			return "", errors.ErrDispatcherFailed.GenWithStack(
+				"column %s referenced by topic expression %s is NULL, table: %s.%s",
+				match[1], e, schema, table)
+		}
+		replaced := model.ColumnValueString(value)
+		if match[2] != "" {
+			buckets, err := strconv.ParseUint(match[2], 10, 32)
+			if err != nil {
+				return "", errors.WrapError(errors.ErrDispatcherFailed, err)
+			}
+			sum := crc32.ChecksumIEEE([]byte(replaced))
+			replaced = strconv.FormatUint(uint64(sum)%buckets, 10)
+		} else {
+			replaced = kafkaForbidRE.ReplaceAllString(replaced, "_")
+		}
+		topicExpr = strings.Replace(topicExpr, match[0], replaced, 1)
+	}
+	return Expression(topicExpr).Substitute(schema, table), nil
+}
+
+// Pattern matches all the topic names an expression with column placeholders
+// can be substituted to for a given table.
+type Pattern struct {
+	// full matches the topic names which are not truncated.
+	full *regexp.Regexp
+	// truncated matches the prefixes of the topic names, it is used for the
+	// topic names which are truncated to kafkaTopicNameMaxLength.
+	truncated *regexp.Regexp
+}
+
+// MatchString reports whether the topic name may be substituted from the expression.
+func (p *Pattern) MatchString(topic string) bool {
+	switch {
+	case len(topic) > kafkaTopicNameMaxLength:
+		return false
+	case topic == "_":
+		return p.full.MatchString(topic) || p.full.MatchString(".")
+	case topic == "__":
+		return p.full.MatchString(topic) || p.full.MatchString("..")
+	case len(topic) == kafkaTopicNameMaxLength:
+		return p.truncated.MatchString(topic)
+	default:
+		return p.full.MatchString(topic)
+	}
+}
 
+// String implements fmt.Stringer.
+func (p *Pattern) String() string {
+	return p.full.String()
+}
+
+// Pattern returns the pattern which matches all the topic names the expression
+// can be substituted to for the given table, or nil if the expression does not
+// reference any column.
+func (e Expression) Pattern(schema, table string) *Pattern {
+	if !columnRE.MatchString(string(e)) {
+		return nil
+	}
+	replacedSchema := kafkaForbidRE.ReplaceAllString(schema, "_")
+	replacedTable := kafkaForbidRE.ReplaceAllString(table, "_")
+	replace := func(part string) string {
+		part = schemaRE.ReplaceAllLiteralString(part, replacedSchema)
+		return tableRE.ReplaceAllLiteralString(part, replacedTable)
+	}
+
+	// literals holds the parts around the column placeholders, and placeholders
+	// holds the character class of the value each placeholder is substituted to.
+	var literals, placeholders []string
+	last := 0
+	topicExpr := string(e)
+	for _, loc := range columnRE.FindAllStringSubmatchIndex(topicExpr, -1) {
+		literals = append(literals, replace(topicExpr[last:loc[0]]))
+		if loc[4] >= 0 {
+			placeholders = append(placeholders, `[0-9]`)
+		} else {
+			// the column value may be empty.
+			placeholders = append(placeholders, `[A-Za-z0-9\._\-]`)
+		}
+		last = loc[1]
+	}
+	literals = append(literals, replace(topicExpr[last:]))
+
+	var full strings.Builder
+	full.WriteString("^")
+	for i, literal := range literals {
+		full.WriteString(regexp.QuoteMeta(literal))
+		if i < len(placeholders) {
+			if placeholders[i] == `[0-9]` {
+				// the hash bucket always has at least one digit.
+				full.WriteString(placeholders[i] + "+")
+			} else {
+				full.WriteString(placeholders[i] + "*")
+			}
+		}
+	}
+	full.WriteString("$")
+
+	// A truncated topic name is a prefix of the substituted name, so every
+	// remaining character of the expression becomes optional.
+	truncated := ""
+	for i := len(literals) - 1; i >= 0; i-- {
+		if i < len(placeholders) {
+			truncated = placeholders[i] + "*" + truncated
+		}
+		for j := len(literals[i]) - 1; j >= 0; j-- {
+			truncated = "(?:" + regexp.QuoteMeta(literals[i][j:j+1]) + truncated + ")?"
+		}
+	}
+	return &Pattern{
+		full:      regexp.MustCompile(full.String()),
+		truncated: regexp.MustCompile("^" + truncated + "$"),
+	}
+}
+
+// normalizeTopicName makes sure the topic name is accepted by kafka.
+func normalizeTopicName(topicName string) string {
 	// topicName will be truncated if it exceed the limit.
 	// And topicName '.' and '..' are also invalid, replace them with '_'.
 	//    See https://github.com/apache/kafka/blob/trunk/clients/src/main/java/org/apache/kafka/common/internals/Topic.java#L46
@@ -135,6 +317,15 @@ func (e Expression) PulsarValidate() error {
 	return nil
 }
 
+// WebhookValidate checks whether a webhook path expression is valid or not.
+func (e Expression) WebhookValidate() error {
+	if ok := webhookPathRE.MatchString(string(e)); ok {
+		return nil
+	}
+	return errors.ErrWebhookInvalidConfig.GenWithStack(
+		"invalid path expression %s, it should be in form of [prefix]{schema}[middle][{table}][suffix]", e)
+}
+
 // IsHardCode checks whether a topic name is hard code or not.
 func IsHardCode(topicName string) bool {
 	return hardCodeTopicNameRe.MatchString(topicName)
@@ -15,8 +15,10 @@ package topic
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -251,6 +253,95 @@ func TestTableOptional(t *testing.T) {
 	require.Equal(t, topicName, "prefix_test")
 }
 
+func TestColumnExpression(t *testing.T) {
+	for _, expression := range []string{
+		"tenant_{column:tenant_id}", "{schema}_{column:tenant_id}_{table}",
+		"{schema}_{table}_{column:tenant_id%16}", "{column:a}_{column:b}",
+	} {
+		require.NoError(t, Expression(expression).Validate(), expression)
+	}
+	for _, expression := range []string{
+		"{column:}", "{column:tenant_id%0}", "{column:tenant-id}", "{column:a%b}",
+		"{column:a}", "{column:a}{column:b%4}",
+	} {
+		require.ErrorContains(t, Expression(expression).Validate(),
+			"invalid topic expression", expression)
+	}
+	require.Equal(t, []string{"a", "b"}, Expression("{column:a}_{column:b%2}").Columns())
+	require.Empty(t, Expression("{schema}_{table}").Columns())
+
+	cols := []*model.Column{
+		{Name: "id", Value: 1, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "tenant_id", Value: []byte("a/b")},
+	}
+	tableInfo := model.BuildTableInfo("test", "t1", cols, [][]int{{0}})
+	row := &model.RowChangedEvent{
+		TableInfo: tableInfo,
+		Columns:   model.Columns2ColumnDatas(cols, tableInfo),
+	}
+	topicName, err := Expression("{schema}_{column:tenant_id}").SubstituteRow(row)
+	require.NoError(t, err)
+	require.Equal(t, "test_a_b", topicName)
+	topicName, err = Expression("{table}-{column:id}").SubstituteRow(row)
+	require.NoError(t, err)
+	require.Equal(t, "t1-1", topicName)
+
+	// the bucket is stable for the same value.
+	topicName, err = Expression("{column:tenant_id%8}").SubstituteRow(row)
+	require.NoError(t, err)
+	other, err := Expression("{column:tenant_id%8}").SubstituteRow(row)
+	require.NoError(t, err)
+	require.Equal(t, topicName, other)
+	require.Regexp(t, "^[0-7]$", topicName)
+
+	_, err = Expression("{column:unknown}").SubstituteRow(row)
+	require.ErrorContains(t, err, "not found")
+
+	require.Nil(t, Expression("{schema}_{table}").Pattern("test", "t1"))
+	pattern := Expression("{schema}.{table}_{column:tenant_id}").Pattern("te.st", "t1")
+	require.True(t, pattern.MatchString("te.st.t1_a_b"))
+	// the column value may be empty.
+	require.True(t, pattern.MatchString("te.st.t1_"))
+	require.False(t, pattern.MatchString("teXst.t1_a"))
+	pattern = Expression("{schema}_{column:tenant_id%8}").Pattern("test", "t1")
+	require.True(t, pattern.MatchString("test_7"))
+	require.False(t, pattern.MatchString("test_a"))
+	require.False(t, pattern.MatchString("test_"))
+
+	// the truncated topic names are matched.
+	longTable := strings.Repeat("t", kafkaTopicNameMaxLength)
+	pattern = Expression("{schema}_{table}_{column:tenant_id}").Pattern("test", longTable)
+	longTableInfo := model.BuildTableInfo("test", longTable, cols, [][]int{{0}})
+	topicName, err = Expression("{schema}_{table}_{column:tenant_id}").SubstituteRow(&model.RowChangedEvent{
+		TableInfo: longTableInfo,
+		Columns:   model.Columns2ColumnDatas(cols, longTableInfo),
+	})
+	require.NoError(t, err)
+	require.Len(t, topicName, kafkaTopicNameMaxLength)
+	require.True(t, pattern.MatchString(topicName))
+	require.False(t, pattern.MatchString("x"+topicName[1:]))
+	pattern = Expression("ab_{column:tenant_id}").Pattern("test", "t1")
+	require.True(t, pattern.MatchString("ab_"+strings.Repeat("x", kafkaTopicNameMaxLength-3)))
+	require.False(t, pattern.MatchString("ab_"+strings.Repeat("x", kafkaTopicNameMaxLength-2)))
+}
+
+func TestWebhookValidate(t *testing.T) {
+	for _, expression := range []string{
+		"events", "cdc/{schema}", "cdc/{schema}/{table}/rows", "{schema}_{table}",
+	} {
+		require.NoError(t, Expression(expression).WebhookValidate(), expression)
+	}
+	for _, expression := range []string{
+		"cdc/{table}/{schema}", "cdc?a=b", "cdc/{db}",
+	} {
+		require.ErrorContains(t, Expression(expression).WebhookValidate(),
+			"ErrWebhookInvalidConfig", expression)
+	}
+
+	topicName := Expression("cdc/{schema}/{table}").Substitute("test", "t/1")
+	require.Equal(t, "cdc/test/t_1", topicName)
+}
+
 // cmd: go test -run='^$' -bench '^(BenchmarkSubstitute)$' github.com/pingcap/tiflow/cdc/sink/dispatcher/topic
 // goos: linux
 // goarch: amd64
@@ -0,0 +1,184 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlproducer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/webhook"
+	"go.uber.org/zap"
+)
+
+var _ DMLProducer = (*webhookDMLProducer)(nil)
+
+type webhookMessage struct {
+	path    string
+	message *common.Message
+}
+
+// webhookDMLProducer is used to send messages to a webhook endpoint.
+// Messages are buffered per path and sent in batches, a batch is sent when
+// it is full or when the flush interval elapses.
+type webhookDMLProducer struct {
+	// id indicates which processor (changefeed) this sink belongs to.
+	id     model.ChangeFeedID
+	cfg    *webhook.Config
+	client *webhook.Client
+	msgCh  chan webhookMessage
+
+	// closedMu is used to protect `closed`.
+	// We need to ensure that closed producers are never written to.
+	closedMu sync.RWMutex
+	// closed is used to indicate whether the producer is closed.
+	// We also use it to guard against double closes.
+	closed bool
+	// done is closed when the run loop exits.
+	done   chan struct{}
+	cancel context.CancelFunc
+}
+
+// NewWebhookDMLProducer creates a new webhook producer.
+func NewWebhookDMLProducer(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	cfg *webhook.Config,
+	errCh chan error,
+) DMLProducer {
+	log.Info("Starting webhook DML producer ...",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID))
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &webhookDMLProducer{
+		id:     changefeedID,
+		cfg:    cfg,
+		client: webhook.NewClient(changefeedID, cfg),
+		msgCh:  make(chan webhookMessage, cfg.MaxBatchSize),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer close(p.done)
+		if err := p.run(ctx); err != nil && errors.Cause(err) != context.Canceled {
+			select {
+			case <-ctx.Done():
+				return
+			case errCh <- err:
+				log.Error("Webhook DML producer run error",
+					zap.String("namespace", p.id.Namespace),
+					zap.String("changefeed", p.id.ID),
+					zap.Error(err))
+			default:
+				log.Error("Error channel is full in webhook DML producer",
+					zap.String("namespace", p.id.Namespace),
+					zap.String("changefeed", p.id.ID),
+					zap.Error(err))
+			}
+		}
+	}()
+
+	return p
+}
+
+// AsyncSendMessage buffers the message, the callback of the message is called
+// after the batch containing it is accepted by the endpoint.
+func (p *webhookDMLProducer) AsyncSendMessage(
+	ctx context.Context, topic string,
+	_ int32, message *common.Message,
+) error {
+	// We have to hold the lock to avoid writing to a closed producer.
+	p.closedMu.RLock()
+	defer p.closedMu.RUnlock()
+
+	// If the producer is closed, we should skip the message and return an error.
+	if p.closed {
+		return cerror.ErrWebhookProducerClosed.GenWithStackByArgs()
+	}
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case <-p.done:
+		return cerror.ErrWebhookProducerClosed.GenWithStackByArgs()
+	case p.msgCh <- webhookMessage{path: topic, message: message}:
+	}
+	return nil
+}
+
+func (p *webhookDMLProducer) Close() {
+	// Cancel the run loop first, AsyncSendMessage may be blocked on a full channel.
+	p.cancel()
+	// We have to hold the lock to synchronize closing with writing.
+	p.closedMu.Lock()
+	defer p.closedMu.Unlock()
+	// If the producer has already been closed, we should skip this close operation.
+	if p.closed {
+		log.Warn("Webhook DML producer already closed",
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID))
+		return
+	}
+	<-p.done
+	p.client.Close()
+	p.closed = true
+}
+
+func (p *webhookDMLProducer) run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	buffer := make(map[string][]*common.Message)
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case m := <-p.msgCh:
+			batch := append(buffer[m.path], m.message)
+			if len(batch) < p.cfg.MaxBatchSize {
+				buffer[m.path] = batch
+				continue
+			}
+			delete(buffer, m.path)
+			if err := p.send(ctx, m.path, batch); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			for path, batch := range buffer {
+				delete(buffer, path)
+				if err := p.send(ctx, path, batch); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (p *webhookDMLProducer) send(ctx context.Context, path string, batch []*common.Message) error {
+	if err := p.client.Send(ctx, path, batch); err != nil {
+		return err
+	}
+	for _, message := range batch {
+		if message.Callback != nil {
+			message.Callback()
+		}
+	}
+	return nil
+}
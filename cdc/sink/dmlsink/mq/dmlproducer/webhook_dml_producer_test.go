@@ -0,0 +1,117 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlproducer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookConfig(t *testing.T, serverURL string, params string) *webhook.Config {
+	sinkURI, err := url.Parse(serverURL + "/?" + params)
+	require.NoError(t, err)
+	cfg := webhook.NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI))
+	return cfg
+}
+
+func TestWebhookDMLProducerBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		batches = make(map[string][]string)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		batches[r.URL.Path] = append(batches[r.URL.Path], string(body))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	cfg := newWebhookConfig(t, server.URL, "max-batch-size=2&flush-interval=1s")
+	p := NewWebhookDMLProducer(ctx, model.DefaultChangeFeedID("test"), cfg, errCh)
+	defer p.Close()
+
+	var acked atomic.Int32
+	for _, m := range []struct{ path, value string }{
+		{"a", "1"}, {"b", "2"}, {"a", "3"}, {"b", "4"}, {"a", "5"},
+	} {
+		err := p.AsyncSendMessage(ctx, m.path, 0, &common.Message{
+			Value:    []byte(m.value),
+			Callback: func() { acked.Add(1) },
+		})
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return acked.Load() == 5
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"1\n3\n", "5\n"}, batches["/a"])
+	require.Equal(t, []string{"2\n4\n"}, batches["/b"])
+	require.Len(t, errCh, 0)
+}
+
+func TestWebhookDMLProducerError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	cfg := newWebhookConfig(t, server.URL, "max-batch-size=1")
+	p := NewWebhookDMLProducer(ctx, model.DefaultChangeFeedID("test"), cfg, errCh)
+
+	err := p.AsyncSendMessage(ctx, "a", 0, &common.Message{
+		Value:    []byte("1"),
+		Callback: func() { require.FailNow(t, "callback should not be called") },
+	})
+	require.NoError(t, err)
+
+	select {
+	case err := <-errCh:
+		require.True(t, strings.Contains(err.Error(), "ErrWebhookSendMessage"), err.Error())
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no error is reported")
+	}
+
+	p.Close()
+	err = p.AsyncSendMessage(ctx, "a", 0, &common.Message{Value: []byte("2")})
+	require.ErrorContains(t, err, "ErrWebhookProducerClosed")
+}
@@ -22,7 +22,6 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
-	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/transformer/columnselector"
 	"github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -98,7 +97,7 @@ func NewKafkaDMLSink(
 		return nil, errors.Trace(err)
 	}
 
-	trans, err := columnselector.New(replicaConfig)
+	trans, err := newTransformers(replicaConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -113,6 +112,24 @@ func NewKafkaDMLSink(
 		return nil, cerror.WrapError(cerror.ErrKafkaNewProducer, err)
 	}
 
+	outputRawChangeEvent := replicaConfig.Sink.KafkaConfig.GetOutputRawChangeEvent()
+	if options.EnableTransaction {
+		// Make sure the marker topic exists before any transaction is committed.
+		if _, err = topicManager.GetPartitionNum(ctx, options.TransactionMarkerTopic); err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The markers of each table are recovered when the table sink is created,
+		// and the producer of each table is created by its first flush.
+		s := newTransactionalDMLSink(ctx, changefeedID, factory, options.TransactionMarkerTopic,
+			adminClient, topicManager, eventRouter, trans, encoderBuilder,
+			protocol, scheme, outputRawChangeEvent, errCh)
+		log.Info("DML sink transactional producer created",
+			zap.String("namespace", changefeedID.Namespace),
+			zap.String("changefeedID", changefeedID.ID),
+			zap.String("markerTopic", options.TransactionMarkerTopic))
+		return s, nil
+	}
+
 	failpointCh := make(chan error, 1)
 	asyncProducer, err := factory.AsyncProducer(ctx, failpointCh)
 	if err != nil {
@@ -123,7 +140,7 @@ func NewKafkaDMLSink(
 	dmlProducer := producerCreator(ctx, changefeedID, asyncProducer, metricsCollector, errCh, failpointCh)
 	encoderGroup := codec.NewEncoderGroup(replicaConfig.Sink, encoderBuilder, changefeedID)
 	s := newDMLSink(ctx, changefeedID, dmlProducer, adminClient, topicManager, eventRouter, trans, encoderGroup,
-		protocol, scheme, replicaConfig.Sink.KafkaConfig.GetOutputRawChangeEvent(), errCh)
+		protocol, scheme, outputRawChangeEvent, errCh)
 	log.Info("DML sink producer created",
 		zap.String("namespace", changefeedID.Namespace),
 		zap.String("changefeedID", changefeedID.ID))
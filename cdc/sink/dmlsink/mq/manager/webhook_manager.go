@@ -0,0 +1,40 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import "context"
+
+// webhookTopicManager is a manager for webhook paths.
+// Paths don't need to be created and have no partitions,
+// so it is only used to meet the requirement of mq sink's interface.
+type webhookTopicManager struct{}
+
+// NewWebhookTopicManager creates a new topic manager for webhook sink.
+func NewWebhookTopicManager() TopicManager {
+	return &webhookTopicManager{}
+}
+
+// GetPartitionNum always return 1, every path is treated as a single partition
+// so that the events of one path are sent in order.
+func (m *webhookTopicManager) GetPartitionNum(_ context.Context, _ string) (int32, error) {
+	return 1, nil
+}
+
+// CreateTopicAndWaitUntilVisible no need to create first
+func (m *webhookTopicManager) CreateTopicAndWaitUntilVisible(_ context.Context, _ string) (int32, error) {
+	return 1, nil
+}
+
+// Close does nothing.
+func (m *webhookTopicManager) Close() {}
@@ -21,11 +21,14 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/manager"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/transformer"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/transformer/columnmask"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/transformer/columnselector"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -36,8 +39,11 @@ import (
 	"go.uber.org/zap"
 )
 
-// Assert EventSink[E event.TableEvent] implementation
-var _ dmlsink.EventSink[*model.SingleTableTxn] = (*dmlSink)(nil)
+// Assert EventSink[E event.TableEvent] and TableListener implementation
+var (
+	_ dmlsink.EventSink[*model.SingleTableTxn] = (*dmlSink)(nil)
+	_ dmlsink.TableListener                    = (*dmlSink)(nil)
+)
 
 // dmlSink is the mq sink.
 // It will send the events to the MQ system.
@@ -50,14 +56,17 @@ type dmlSink struct {
 	alive struct {
 		sync.RWMutex
 
-		transformer transformer.Transformer
+		transformers *transformers
 		// eventRouter used to route events to the right topic and partition.
 		eventRouter *dispatcher.EventRouter
 		// topicManager used to manage topics.
 		// It is also responsible for creating topics.
 		topicManager manager.TopicManager
 		worker       *worker
-		isDead       bool
+		// txnWorker is only used when the transactional mode is enabled,
+		// it replaces the worker.
+		txnWorker *txnWorker
+		isDead    bool
 	}
 
 	// adminClient is used to query kafka cluster information, it's shared among
@@ -74,6 +83,28 @@ type dmlSink struct {
 	outputRawChangeEvent bool
 }
 
+// transformers are applied to each row before encoding.
+type transformers struct {
+	// mask is applied before the row is routed, so that the raw values
+	// of the masked columns never decide its topic and partition key.
+	mask transformer.Transformer
+	// selector is applied after the topic of the row is calculated.
+	selector transformer.Transformer
+}
+
+// newTransformers returns the transformers applied to each row before encoding.
+func newTransformers(replicaConfig *config.ReplicaConfig) (*transformers, error) {
+	selector, err := columnselector.New(replicaConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	mask, err := columnmask.New(replicaConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &transformers{mask: mask, selector: selector}, nil
+}
+
 func newDMLSink(
 	ctx context.Context,
 	changefeedID model.ChangeFeedID,
@@ -81,7 +112,7 @@ func newDMLSink(
 	adminClient kafka.ClusterAdminClient,
 	topicManager manager.TopicManager,
 	eventRouter *dispatcher.EventRouter,
-	transformer transformer.Transformer,
+	transformers *transformers,
 	encoderGroup codec.EncoderGroup,
 	protocol config.Protocol,
 	scheme string,
@@ -102,20 +133,74 @@ func newDMLSink(
 		scheme:               scheme,
 		outputRawChangeEvent: outputRawChangeEvent,
 	}
-	s.alive.transformer = transformer
+	s.alive.transformers = transformers
 	s.alive.eventRouter = eventRouter
 	s.alive.topicManager = topicManager
 	s.alive.worker = worker
 
 	// Spawn a goroutine to send messages by the worker.
+	s.spawn(ctx, worker.run, worker.close, errCh)
+	return s
+}
+
+// newTransactionalDMLSink creates a sink which sends every flush of
+// a table in a Kafka transaction.
+func newTransactionalDMLSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	factory kafka.Factory,
+	markerTopic string,
+	adminClient kafka.ClusterAdminClient,
+	topicManager manager.TopicManager,
+	eventRouter *dispatcher.EventRouter,
+	transformers *transformers,
+	encoderBuilder codec.RowEventEncoderBuilder,
+	protocol config.Protocol,
+	scheme string,
+	outputRawChangeEvent bool,
+	errCh chan error,
+) *dmlSink {
+	ctx, cancel := context.WithCancelCause(ctx)
+	statistics := metrics.NewStatistics(ctx, changefeedID, sink.RowSink)
+	worker := newTxnWorker(changefeedID, protocol, factory, markerTopic,
+		encoderBuilder, statistics)
+
+	s := &dmlSink{
+		id:                   changefeedID,
+		protocol:             protocol,
+		adminClient:          adminClient,
+		ctx:                  ctx,
+		cancel:               cancel,
+		dead:                 make(chan struct{}),
+		scheme:               scheme,
+		outputRawChangeEvent: outputRawChangeEvent,
+	}
+	s.alive.transformers = transformers
+	s.alive.eventRouter = eventRouter
+	s.alive.topicManager = topicManager
+	s.alive.txnWorker = worker
+
+	// Spawn a goroutine to send transactions by the worker.
+	s.spawn(ctx, worker.run, worker.close, errCh)
+	return s
+}
+
+// spawn runs the worker in a background goroutine,
+// and marks the sink as dead once the worker exits.
+func (s *dmlSink) spawn(
+	ctx context.Context,
+	run func(context.Context) error,
+	closeWorker func(),
+	errCh chan error,
+) {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		err := s.alive.worker.run(ctx)
+		err := run(ctx)
 
 		s.alive.Lock()
 		s.alive.isDead = true
-		s.alive.worker.close()
+		closeWorker()
 		s.alive.Unlock()
 		close(s.dead)
 
@@ -137,8 +222,6 @@ func newDMLSink(
 			}
 		}
 	}()
-
-	return s
 }
 
 // WriteEvents writes events to the sink.
@@ -161,6 +244,10 @@ func (s *dmlSink) WriteEvents(txns ...*dmlsink.CallbackableEvent[*model.SingleTa
 		}
 	}
 
+	if s.alive.txnWorker != nil {
+		return s.writeTransactionalEvents(txns)
+	}
+
 	for _, txn := range txns {
 		if txn.GetTableSinkState() != state.TableSinkSinking {
 			// The table where the event comes from is in stopping, so it's safe
@@ -168,53 +255,167 @@ func (s *dmlSink) WriteEvents(txns ...*dmlsink.CallbackableEvent[*model.SingleTa
 			txn.Callback()
 			continue
 		}
-		rowCallback := toRowCallback(txn.Callback, uint64(len(txn.Event.Rows)))
+		events := make([]mqEvent, 0, len(txn.Event.Rows))
 		for _, row := range txn.Event.Rows {
-			topic := s.alive.eventRouter.GetTopicForRowChange(row)
-			partitionNum, err := s.alive.topicManager.GetPartitionNum(s.ctx, topic)
-			failpoint.Inject("MQSinkGetPartitionError", func() {
-				log.Info("failpoint MQSinkGetPartitionError injected", zap.String("changefeedID", s.id.ID))
-				err = errors.New("MQSinkGetPartitionError")
-			})
+			routed, err := s.route(row, txn.SinkState)
 			if err != nil {
 				s.cancel(err)
 				return errors.Trace(err)
 			}
+			events = append(events, routed...)
+		}
+		rowCallback := toRowCallback(txn.Callback, uint64(len(events)))
+		for _, event := range events {
+			event.rowEvent.Callback = rowCallback
+			// This never be blocked because this is an unbounded channel.
+			// We already limit the memory usage by MemoryQuota at SinkManager level.
+			// So it is safe to send the event to a unbounded channel here.
+			s.alive.worker.msgChan.In() <- event
+		}
+	}
+	return nil
+}
 
-			err = s.alive.transformer.Apply(row)
-			if err != nil {
-				s.cancel(err)
-				return errors.Trace(err)
-			}
-			// Note: Calculate the partition index after the transformer is applied.
-			// Because the transformer may change the row of the event.
-			index, key, err := s.alive.eventRouter.GetPartitionForRowChange(row, partitionNum)
+// writeTransactionalEvents groups the events by table, and sends the events
+// of each table to the transactional worker as one flush. The transactions
+// already committed by a previous run of the changefeed are skipped by the
+// worker.
+func (s *dmlSink) writeTransactionalEvents(txns []*dmlsink.TxnCallbackableEvent) error {
+	var (
+		tableIDs []model.TableID
+		flushes  = make(map[model.TableID]*txnFlush)
+	)
+	for _, txn := range txns {
+		if txn.GetTableSinkState() != state.TableSinkSinking {
+			// The table where the event comes from is in stopping, so it's safe
+			// to drop the event directly.
+			txn.Callback()
+			continue
+		}
+		tableID := txn.Event.GetPhysicalTableID()
+		table, pos := s.alive.txnWorker.advance(tableID, txn.Event.GetCommitTs())
+
+		flush, ok := flushes[tableID]
+		if !ok {
+			flush = &txnFlush{tableID: tableID, table: table}
+			flushes[tableID] = flush
+			tableIDs = append(tableIDs, tableID)
+		}
+		ftxn := &flushTxn{TxnCallbackableEvent: txn, pos: pos}
+		for _, row := range txn.Event.Rows {
+			routed, err := s.route(row, txn.SinkState)
 			if err != nil {
 				s.cancel(err)
 				return errors.Trace(err)
 			}
-
-			// This never be blocked because this is an unbounded channel.
-			// We already limit the memory usage by MemoryQuota at SinkManager level.
-			// So it is safe to send the event to a unbounded channel here.
-			s.alive.worker.msgChan.In() <- mqEvent{
-				key: model.TopicPartitionKey{
-					Topic:          topic,
-					Partition:      index,
-					PartitionKey:   key,
-					TotalPartition: partitionNum,
-				},
-				rowEvent: &dmlsink.RowChangeCallbackableEvent{
-					Event:     row,
-					Callback:  rowCallback,
-					SinkState: txn.SinkState,
-				},
-			}
+			ftxn.events = append(ftxn.events, routed...)
 		}
+		flush.txns = append(flush.txns, ftxn)
+		flush.marker = pos
+	}
+
+	for _, tableID := range tableIDs {
+		s.alive.txnWorker.flushChan.In() <- flushes[tableID]
 	}
 	return nil
 }
 
+// AddTable implements dmlsink.TableListener.
+func (s *dmlSink) AddTable(span tablepb.Span) {
+	if s.alive.txnWorker != nil {
+		s.alive.txnWorker.addTable(span.TableID)
+	}
+}
+
+// RemoveTable implements dmlsink.TableListener.
+func (s *dmlSink) RemoveTable(span tablepb.Span) {
+	if s.alive.txnWorker != nil {
+		s.alive.txnWorker.removeTable(span.TableID)
+	}
+}
+
+// route calculates the topic and partition of the row, and returns the events
+// to send. An UPDATE which changes the topic of the row is split into a DELETE
+// sent to the old topic and an INSERT sent to the new topic, just like the
+// UPDATE which changes the handle key, so the old topic doesn't keep a stale row.
+func (s *dmlSink) route(
+	row *model.RowChangedEvent, sinkState *state.TableSinkState,
+) ([]mqEvent, error) {
+	// Mask the columns before routing, so that the raw values
+	// of the masked columns never decide the topic.
+	if err := s.alive.transformers.mask.Apply(row); err != nil {
+		return nil, errors.Trace(err)
+	}
+	topic, err := s.alive.eventRouter.GetTopicForRowChange(row)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows := []*model.RowChangedEvent{row}
+	topics := []string{topic}
+	if row.IsUpdate() {
+		deleteEvent, insertEvent, err := model.SplitUpdateEvent(row)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The topic of the DELETE is calculated from the old values.
+		oldTopic, err := s.alive.eventRouter.GetTopicForRowChange(deleteEvent)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if oldTopic != topic {
+			rows = []*model.RowChangedEvent{deleteEvent, insertEvent}
+			topics = []string{oldTopic, topic}
+		}
+	}
+
+	events := make([]mqEvent, 0, len(rows))
+	for i, row := range rows {
+		key, err := s.partition(row, topics[i])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		events = append(events, mqEvent{
+			key: key,
+			rowEvent: &dmlsink.RowChangeCallbackableEvent{
+				Event:     row,
+				SinkState: sinkState,
+			},
+		})
+	}
+	return events, nil
+}
+
+// partition calculates the partition of the row in the given topic.
+func (s *dmlSink) partition(
+	row *model.RowChangedEvent, topic string,
+) (model.TopicPartitionKey, error) {
+	partitionNum, err := s.alive.topicManager.GetPartitionNum(s.ctx, topic)
+	failpoint.Inject("MQSinkGetPartitionError", func() {
+		log.Info("failpoint MQSinkGetPartitionError injected", zap.String("changefeedID", s.id.ID))
+		err = errors.New("MQSinkGetPartitionError")
+	})
+	if err != nil {
+		return model.TopicPartitionKey{}, errors.Trace(err)
+	}
+
+	err = s.alive.transformers.selector.Apply(row)
+	if err != nil {
+		return model.TopicPartitionKey{}, errors.Trace(err)
+	}
+	// Note: Calculate the partition index after the transformer is applied.
+	// Because the transformer may change the row of the event.
+	index, key, err := s.alive.eventRouter.GetPartitionForRowChange(row, partitionNum)
+	if err != nil {
+		return model.TopicPartitionKey{}, errors.Trace(err)
+	}
+	return model.TopicPartitionKey{
+		Topic:          topic,
+		Partition:      index,
+		PartitionKey:   key,
+		TotalPartition: partitionNum,
+	}, nil
+}
+
 // Close closes the sink.
 func (s *dmlSink) Close() {
 	if s.cancel != nil {
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
@@ -27,7 +28,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 )
 
 func TestNewKafkaDMLSinkFailed(t *testing.T) {
@@ -112,3 +115,223 @@ func TestWriteEvents(t *testing.T) {
 	require.Len(t, errCh, 0)
 	require.Len(t, s.alive.worker.producer.(*dmlproducer.MockDMLProducer).GetAllEvents(), 3000)
 }
+
+func TestWriteEventsTransactional(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=2.4.0&max-batch-size=1&partition-num=1" +
+		"&kafka-client-id=unit-test&protocol=open-protocol&enable-transaction=true"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	changefeedID := model.DefaultChangeFeedID("test")
+	markerTopic := kafka.DefaultTransactionMarkerTopic(changefeedID)
+	store := kafka.NewMockTransactionStore()
+	factoryCreator := kafka.NewMockFactoryCreatorWithTransactionStore(store)
+
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	sql := `create table test.t(a varchar(255) primary key)`
+	job := helper.DDL2Job(sql)
+	tableInfo := model.WrapTableInfo(0, "test", 1, job.BinlogInfo.TableInfo)
+
+	tableStatus := state.TableSinkSinking
+	newEvents := func(commitTs ...uint64) ([]*dmlsink.TxnCallbackableEvent, *atomic.Int64) {
+		var called atomic.Int64
+		events := make([]*dmlsink.TxnCallbackableEvent, 0, len(commitTs))
+		for _, ts := range commitTs {
+			row := &model.RowChangedEvent{
+				CommitTs:  ts,
+				TableInfo: tableInfo,
+				Columns: model.Columns2ColumnDatas(
+					[]*model.Column{{Name: "a", Value: fmt.Sprintf("a%d", ts)}}, tableInfo),
+			}
+			events = append(events, &dmlsink.TxnCallbackableEvent{
+				Event: &model.SingleTableTxn{
+					PhysicalTableID: 1,
+					CommitTs:        ts,
+					Rows:            []*model.RowChangedEvent{row},
+				},
+				Callback:  func() { called.Inc() },
+				SinkState: &tableStatus,
+			})
+		}
+		return events, &called
+	}
+
+	s, err := NewKafkaDMLSink(ctx, changefeedID, sinkURI, replicaConfig, errCh,
+		factoryCreator, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	require.NotNil(t, s.alive.txnWorker)
+
+	// The first flush is committed, the second one fails and is aborted.
+	events, called := newEvents(1, 2, 2)
+	require.NoError(t, s.WriteEvents(events...))
+	require.Eventually(t, func() bool { return called.Load() == 3 }, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, store.CommittedMessages(kafka.DefaultMockTopicName), 3)
+
+	store.SetCommitError(errors.New("injected commit error"))
+	events, called = newEvents(3)
+	require.NoError(t, s.WriteEvents(events...))
+	select {
+	case err := <-errCh:
+		require.ErrorContains(t, err, "kafka transaction failed")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the sink should fail")
+	}
+	require.Equal(t, int64(0), called.Load())
+	require.Len(t, store.CommittedMessages(kafka.DefaultMockTopicName), 3)
+	s.Close()
+
+	markers := store.CommittedMessages(markerTopic)
+	require.Len(t, markers, 1)
+	marker, err := kafka.DecodeTransactionMarker(markers[0].Message.Value)
+	require.NoError(t, err)
+	require.Equal(t, kafka.TransactionMarker{CommitTs: 2, Txns: 2}, marker)
+
+	// After restart, the committed transactions are skipped.
+	s, err = NewKafkaDMLSink(ctx, changefeedID, sinkURI, replicaConfig, errCh,
+		factoryCreator, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	defer s.Close()
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span)
+	events, called = newEvents(1, 2, 2, 3)
+	require.NoError(t, s.WriteEvents(events...))
+	require.Eventually(t, func() bool { return called.Load() == 4 }, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, store.CommittedMessages(kafka.DefaultMockTopicName), 4)
+	require.Len(t, store.CommittedMessages(markerTopic), 2)
+
+	// The markers are recovered again once the table sink is recreated,
+	// for example, after the table is moved back to this capture.
+	s.RemoveTable(span)
+	s.AddTable(span)
+	events, called = newEvents(3, 4)
+	require.NoError(t, s.WriteEvents(events...))
+	require.Eventually(t, func() bool { return called.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, store.CommittedMessages(kafka.DefaultMockTopicName), 5)
+	require.Len(t, store.CommittedMessages(markerTopic), 3)
+	require.Len(t, errCh, 0)
+
+	// Once the table is moved to another capture, the producer of the table
+	// on this capture is fenced.
+	moved, err := NewKafkaDMLSink(ctx, changefeedID, sinkURI, replicaConfig, errCh,
+		factoryCreator, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	defer moved.Close()
+	moved.AddTable(span)
+	events, called = newEvents(5)
+	require.NoError(t, moved.WriteEvents(events...))
+	require.Eventually(t, func() bool { return called.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	events, called = newEvents(6)
+	require.NoError(t, s.WriteEvents(events...))
+	select {
+	case err := <-errCh:
+		require.ErrorContains(t, err, "is fenced")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the zombie producer should be fenced")
+	}
+	require.Equal(t, int64(0), called.Load())
+	require.Len(t, store.CommittedMessages(kafka.DefaultMockTopicName), 6)
+}
+
+func TestRouteMaskedColumn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=true&protocol=canal-json"
+	uri := fmt.Sprintf(uriTemplate, "127.0.0.1:9092", kafka.DefaultMockTopicName)
+
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	require.NoError(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	replicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{
+			Matcher:       []string{"test.*"},
+			TopicRule:     "{schema}_{column:tenant}",
+			PartitionRule: "columns",
+			Columns:       []string{"tenant"},
+		},
+	}
+	replicaConfig.Sink.ColumnMasks = []*config.ColumnMask{
+		{
+			Matcher: []string{"test.*"},
+			Columns: []string{"tenant"},
+			Action:  config.ColumnMaskActionTruncate,
+			Length:  2,
+		},
+	}
+	errCh := make(chan error, 1)
+
+	ctx = context.WithValue(ctx, "testing.T", t)
+	changefeedID := model.DefaultChangeFeedID("test")
+	s, err := NewKafkaDMLSink(ctx, changefeedID, sinkURI, replicaConfig, errCh,
+		kafka.NewMockFactory, dmlproducer.NewDMLMockProducer)
+	require.NoError(t, err)
+	defer s.Close()
+
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	sql := `create table test.t(id int primary key, tenant varchar(64))`
+	job := helper.DDL2Job(sql)
+	tableInfo := model.WrapTableInfo(0, "test", 1, job.BinlogInfo.TableInfo)
+	row := &model.RowChangedEvent{
+		CommitTs:  1,
+		TableInfo: tableInfo,
+		Columns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "id", Value: 1},
+			{Name: "tenant", Value: "acme"},
+		}, tableInfo),
+	}
+
+	tableStatus := state.TableSinkSinking
+	// The raw value of the masked column is never used to route the row.
+	events, err := s.route(row, &tableStatus)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "test_ac", events[0].key.Topic)
+
+	newUpdate := func(before, after string) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			CommitTs:  2,
+			TableInfo: tableInfo,
+			PreColumns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "id", Value: 1},
+				{Name: "tenant", Value: before},
+			}, tableInfo),
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "id", Value: 1},
+				{Name: "tenant", Value: after},
+			}, tableInfo),
+		}
+	}
+	// The topic is not changed after the column is masked.
+	events, err = s.route(newUpdate("acme", "acne"), &tableStatus)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.True(t, events[0].rowEvent.Event.IsUpdate())
+	require.Equal(t, "test_ac", events[0].key.Topic)
+
+	// An UPDATE which changes the topic is split into a DELETE sent to
+	// the old topic and an INSERT sent to the new topic.
+	events, err = s.route(newUpdate("acme", "globex"), &tableStatus)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.True(t, events[0].rowEvent.Event.IsDelete())
+	require.Equal(t, "test_ac", events[0].key.Topic)
+	require.True(t, events[1].rowEvent.Event.IsInsert())
+	require.Equal(t, "test_gl", events[1].key.Topic)
+}
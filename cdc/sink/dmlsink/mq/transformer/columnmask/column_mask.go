@@ -0,0 +1,196 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package columnmask
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"unicode"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	filter "github.com/pingcap/tidb/pkg/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+type mask struct {
+	tableF  filter.Filter
+	columnM filter.ColumnFilter
+	action  string
+	salt    string
+	length  int
+}
+
+func newMask(rule *config.ColumnMask, caseSensitive bool) (*mask, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	tableM, err := filter.Parse(rule.Matcher)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrFilterRuleInvalid, err, rule.Matcher)
+	}
+	if !caseSensitive {
+		tableM = filter.CaseInsensitive(tableM)
+	}
+	columnM, err := filter.ParseColumnFilter(rule.Columns)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrFilterRuleInvalid, err, rule.Columns)
+	}
+	return &mask{
+		tableF:  tableM,
+		columnM: columnM,
+		action:  rule.Action,
+		salt:    rule.Salt,
+		length:  rule.Length,
+	}, nil
+}
+
+// apply masks the value of the column.
+func (m *mask) apply(
+	table *model.TableInfo, column *model.ColumnData,
+) (*model.ColumnData, error) {
+	if m.action == config.ColumnMaskActionNullify {
+		if table.ForceGetColumnFlagType(column.ColumnID).IsHandleKey() {
+			return nil, errors.ErrColumnMaskFailed.GenWithStack(
+				"cannot nullify the handle key column, table: %v, column: %s",
+				table.TableName, table.ForceGetColumnName(column.ColumnID))
+		}
+		return &model.ColumnData{ColumnID: column.ColumnID}, nil
+	}
+	if column.Value == nil {
+		return column, nil
+	}
+
+	if !isStringType(table.ForceGetColumnInfo(column.ColumnID).GetType()) {
+		return nil, errors.ErrColumnMaskFailed.GenWithStack(
+			"the %s action only supports string columns, table: %v, column: %s",
+			m.action, table.TableName, table.ForceGetColumnName(column.ColumnID))
+	}
+	var value string
+	switch v := column.Value.(type) {
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return nil, errors.ErrColumnMaskFailed.GenWithStack(
+			"unexpected value type %T, table: %v, column: %s",
+			column.Value, table.TableName, table.ForceGetColumnName(column.ColumnID))
+	}
+
+	switch m.action {
+	case config.ColumnMaskActionHash:
+		digest := sha256.Sum256([]byte(m.salt + value))
+		value = hex.EncodeToString(digest[:])
+	case config.ColumnMaskActionTruncate:
+		if runes := []rune(value); len(runes) > m.length {
+			value = string(runes[:m.length])
+		}
+	case config.ColumnMaskActionRedact:
+		value = redact(value, m.length)
+	}
+
+	masked := &model.ColumnData{ColumnID: column.ColumnID, ApproximateBytes: column.ApproximateBytes}
+	if _, ok := column.Value.([]byte); ok {
+		masked.Value = []byte(value)
+	} else {
+		masked.Value = value
+	}
+	return masked, nil
+}
+
+// redact replaces the letters and digits with `*`, except the last keep characters.
+func redact(value string, keep int) string {
+	runes := []rune(value)
+	for i := 0; i < len(runes)-keep; i++ {
+		if unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) {
+			runes[i] = '*'
+		}
+	}
+	return string(runes)
+}
+
+func isStringType(tp byte) bool {
+	switch tp {
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		return true
+	default:
+		return false
+	}
+}
+
+// ColumnMask manages an array of masks, each column is masked by
+// the first mask which matches both the table and the column.
+type ColumnMask struct {
+	masks []*mask
+}
+
+// New return a column mask
+func New(cfg *config.ReplicaConfig) (*ColumnMask, error) {
+	masks := make([]*mask, 0, len(cfg.Sink.ColumnMasks))
+	for _, r := range cfg.Sink.ColumnMasks {
+		m, err := newMask(r, cfg.CaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		masks = append(masks, m)
+	}
+	return &ColumnMask{masks: masks}, nil
+}
+
+// Apply the column mask to the given event.
+func (c *ColumnMask) Apply(event *model.RowChangedEvent) error {
+	if len(c.masks) == 0 {
+		return nil
+	}
+	matched := make([]*mask, 0, len(c.masks))
+	for _, m := range c.masks {
+		if m.tableF.MatchTable(event.TableInfo.GetSchemaName(), event.TableInfo.GetTableName()) {
+			matched = append(matched, m)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	if err := c.applyColumns(event.TableInfo, matched, event.Columns); err != nil {
+		return err
+	}
+	return c.applyColumns(event.TableInfo, matched, event.PreColumns)
+}
+
+func (c *ColumnMask) applyColumns(
+	table *model.TableInfo, masks []*mask, columns []*model.ColumnData,
+) error {
+	for idx, column := range columns {
+		// the column may be filtered out by the column selector.
+		if column == nil {
+			continue
+		}
+		colName := table.ForceGetColumnName(column.ColumnID)
+		for _, m := range masks {
+			if !m.columnM.MatchColumn(colName) {
+				continue
+			}
+			masked, err := m.apply(table, column)
+			if err != nil {
+				return err
+			}
+			columns[idx] = masked
+			break
+		}
+	}
+	return nil
+}
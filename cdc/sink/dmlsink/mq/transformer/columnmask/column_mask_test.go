@@ -0,0 +1,146 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package columnmask
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvent() *model.RowChangedEvent {
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLong},
+		{Name: "email", Type: mysql.TypeVarchar},
+		{Name: "phone", Type: mysql.TypeVarchar},
+		{Name: "name", Type: mysql.TypeVarchar},
+		{Name: "age", Type: mysql.TypeLong},
+	}
+	tableInfo := model.BuildTableInfoWithPKNames4Test("test", "users", columns,
+		map[string]struct{}{"id": {}})
+	values := []*model.Column{
+		{Name: "id", Value: int64(1)},
+		{Name: "email", Value: []byte("alice@example.com")},
+		{Name: "phone", Value: "+1 415-555-0100"},
+		{Name: "name", Value: []byte("Alice Liddell")},
+		{Name: "age", Value: int64(30)},
+	}
+	return &model.RowChangedEvent{
+		TableInfo:  tableInfo,
+		Columns:    model.Columns2ColumnDatas(values, tableInfo),
+		PreColumns: model.Columns2ColumnDatas(values, tableInfo),
+	}
+}
+
+func getValue(event *model.RowChangedEvent, columns []*model.ColumnData, name string) interface{} {
+	for _, col := range columns {
+		if event.TableInfo.ForceGetColumnName(col.ColumnID) == name {
+			return col.Value
+		}
+	}
+	return nil
+}
+
+func TestColumnMask(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	mask, err := New(replicaConfig)
+	require.NoError(t, err)
+	event := newEvent()
+	require.NoError(t, mask.Apply(event))
+	require.Equal(t, []byte("alice@example.com"), getValue(event, event.Columns, "email"))
+
+	replicaConfig.Sink.ColumnMasks = []*config.ColumnMask{
+		{
+			Matcher: []string{"test.users"},
+			Columns: []string{"email"},
+			Action:  config.ColumnMaskActionHash,
+			Salt:    "pepper",
+		},
+		{
+			Matcher: []string{"test.*"},
+			Columns: []string{"phone", "email"},
+			Action:  config.ColumnMaskActionRedact,
+			Length:  4,
+		},
+		{
+			Matcher: []string{"test.users"},
+			Columns: []string{"name"},
+			Action:  config.ColumnMaskActionTruncate,
+			Length:  5,
+		},
+		{
+			Matcher: []string{"test.users"},
+			Columns: []string{"age"},
+			Action:  config.ColumnMaskActionNullify,
+		},
+		{
+			Matcher: []string{"other.*"},
+			Columns: []string{"*"},
+			Action:  config.ColumnMaskActionNullify,
+		},
+	}
+	mask, err = New(replicaConfig)
+	require.NoError(t, err)
+
+	event = newEvent()
+	require.NoError(t, mask.Apply(event))
+	digest := sha256.Sum256([]byte("pepperalice@example.com"))
+	for _, columns := range [][]*model.ColumnData{event.Columns, event.PreColumns} {
+		require.Equal(t, int64(1), getValue(event, columns, "id"))
+		// the first matched mask is applied.
+		require.Equal(t, []byte(hex.EncodeToString(digest[:])), getValue(event, columns, "email"))
+		require.Equal(t, "+* ***-***-0100", getValue(event, columns, "phone"))
+		require.Equal(t, []byte("Alice"), getValue(event, columns, "name"))
+		require.Nil(t, getValue(event, columns, "age"))
+	}
+}
+
+func TestColumnMaskFailed(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.ColumnMasks = []*config.ColumnMask{
+		{
+			Matcher: []string{"test.users"},
+			Columns: []string{"age"},
+			Action:  "encrypt",
+		},
+	}
+	_, err := New(replicaConfig)
+	require.ErrorContains(t, err, "unsupported column mask action")
+
+	replicaConfig.Sink.ColumnMasks[0].Action = config.ColumnMaskActionTruncate
+	_, err = New(replicaConfig)
+	require.ErrorContains(t, err, "should be greater than 0")
+
+	// only string columns can be hashed.
+	replicaConfig.Sink.ColumnMasks[0].Action = config.ColumnMaskActionHash
+	mask, err := New(replicaConfig)
+	require.NoError(t, err)
+	require.ErrorContains(t, mask.Apply(newEvent()), "only supports string columns")
+
+	// the handle key column cannot be nullified.
+	replicaConfig.Sink.ColumnMasks[0].Columns = []string{"id"}
+	replicaConfig.Sink.ColumnMasks[0].Action = config.ColumnMaskActionNullify
+	mask, err = New(replicaConfig)
+	require.NoError(t, err)
+	require.ErrorContains(t, mask.Apply(newEvent()), "cannot nullify the handle key column")
+}
@@ -0,0 +1,403 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/kafka"
+	"go.uber.org/zap"
+)
+
+// txnFlush contains the transactions of a table written by one WriteEvents
+// call, they are sent to Kafka in a single transaction.
+type txnFlush struct {
+	tableID model.TableID
+	table   *txnTable
+	txns    []*flushTxn
+	// marker is the position of the last transaction in the flush.
+	marker kafka.TransactionMarker
+}
+
+// flushTxn is a transaction in a flush along with its encoded events.
+type flushTxn struct {
+	*dmlsink.TxnCallbackableEvent
+	// pos is the position of the transaction among the transactions
+	// written of the table, see kafka.TransactionMarker.
+	pos    kafka.TransactionMarker
+	events []mqEvent
+}
+
+// txnTable is the state of a table replicated by the worker.
+type txnTable struct {
+	// spans is the number of table sinks of the table.
+	spans int
+	// written is the position of the last transaction written.
+	written kafka.TransactionMarker
+	// recovered is the marker committed before the table sink is created,
+	// it's nil until the marker topic is read.
+	recovered *kafka.TransactionMarker
+
+	// mu protects the fields below, it's held during a flush of the table.
+	mu       sync.Mutex
+	producer kafka.TransactionalProducer
+	closed   bool
+}
+
+// close closes the producer of the table, flushes of the table are
+// dropped after that.
+func (t *txnTable) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.producer != nil {
+		t.producer.Close()
+		t.producer = nil
+	}
+	t.closed = true
+}
+
+// txnWorker sends each flush, along with its marker, to Kafka in a transaction.
+// Each table has its own transactional producer, whose transactional id is
+// bound to the table, so that the producer left on the previous capture of
+// the table is fenced once the table is moved or the capture restarts.
+// The transactional mode requires that a table is never split into spans.
+type txnWorker struct {
+	changeFeedID model.ChangeFeedID
+	protocol     config.Protocol
+	factory      kafka.Factory
+	markerTopic  string
+
+	encoder   codec.RowEventEncoder
+	flushChan *chann.DrainableChann[*txnFlush]
+
+	mu     sync.Mutex
+	tables map[model.TableID]*txnTable
+
+	statistics *metrics.Statistics
+}
+
+func newTxnWorker(
+	id model.ChangeFeedID,
+	protocol config.Protocol,
+	factory kafka.Factory,
+	markerTopic string,
+	encoderBuilder codec.RowEventEncoderBuilder,
+	statistics *metrics.Statistics,
+) *txnWorker {
+	return &txnWorker{
+		changeFeedID: id,
+		protocol:     protocol,
+		factory:      factory,
+		markerTopic:  markerTopic,
+		encoder:      encoderBuilder.Build(),
+		flushChan:    chann.NewAutoDrainChann[*txnFlush](),
+		tables:       make(map[model.TableID]*txnTable),
+		statistics:   statistics,
+	}
+}
+
+// addTable is called when a table sink of the table is created.
+func (w *txnWorker) addTable(tableID model.TableID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	table, ok := w.tables[tableID]
+	if !ok {
+		table = &txnTable{}
+		w.tables[tableID] = table
+	}
+	table.spans++
+}
+
+// removeTable is called when a table sink of the table is closed. The state
+// and the producer of the table are dropped once all table sinks of it are
+// closed, so that the markers are recovered again if the table is added
+// back later.
+func (w *txnWorker) removeTable(tableID model.TableID) {
+	w.mu.Lock()
+	table, ok := w.tables[tableID]
+	if !ok {
+		w.mu.Unlock()
+		return
+	}
+	table.spans--
+	if table.spans > 0 {
+		w.mu.Unlock()
+		return
+	}
+	delete(w.tables, tableID)
+	w.mu.Unlock()
+	table.close()
+}
+
+// advance moves the written position of the table forward by the transaction,
+// and returns the table and the position of the transaction.
+func (w *txnWorker) advance(
+	tableID model.TableID, commitTs uint64,
+) (*txnTable, kafka.TransactionMarker) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	table, ok := w.tables[tableID]
+	if !ok {
+		table = &txnTable{}
+		w.tables[tableID] = table
+	}
+	pos := table.written
+	if pos.CommitTs == commitTs {
+		pos.Txns++
+	} else {
+		pos = kafka.TransactionMarker{CommitTs: commitTs, Txns: 1}
+	}
+	table.written = pos
+	return table, pos
+}
+
+// recover returns the marker of the table committed before the table sink is
+// created. The marker topic is read once for all tables waiting for recovery.
+func (w *txnWorker) recover(
+	ctx context.Context, tableID model.TableID, table *txnTable,
+) (kafka.TransactionMarker, error) {
+	w.mu.Lock()
+	if table.recovered != nil {
+		defer w.mu.Unlock()
+		return *table.recovered, nil
+	}
+	waiting := map[model.TableID]*txnTable{tableID: table}
+	for id, t := range w.tables {
+		if t.recovered == nil {
+			waiting[id] = t
+		}
+	}
+	w.mu.Unlock()
+
+	start := time.Now()
+	markers, err := w.factory.TransactionMarkers(ctx, w.markerTopic)
+	if err != nil {
+		return kafka.TransactionMarker{}, errors.Trace(err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, t := range waiting {
+		marker := markers[kafka.TransactionMarkerKey(w.changeFeedID, id)]
+		t.recovered = &marker
+	}
+	log.Info("MQ sink transaction markers recovered",
+		zap.String("namespace", w.changeFeedID.Namespace),
+		zap.String("changefeed", w.changeFeedID.ID),
+		zap.String("markerTopic", w.markerTopic),
+		zap.Int("tables", len(waiting)),
+		zap.Duration("duration", time.Since(start)))
+	return *table.recovered, nil
+}
+
+func (w *txnWorker) run(ctx context.Context) (retErr error) {
+	defer func() {
+		log.Info("MQ sink transactional worker exited", zap.Error(retErr),
+			zap.String("namespace", w.changeFeedID.Namespace),
+			zap.String("changefeed", w.changeFeedID.ID),
+			zap.String("protocol", w.protocol.String()))
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case flush, ok := <-w.flushChan.Out():
+			if !ok {
+				log.Warn("MQ sink transactional worker channel closed",
+					zap.String("namespace", w.changeFeedID.Namespace),
+					zap.String("changefeed", w.changeFeedID.ID))
+				return nil
+			}
+			if err := w.flush(ctx, flush); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (w *txnWorker) flush(ctx context.Context, flush *txnFlush) error {
+	if flush.txns[0].GetTableSinkState() != state.TableSinkSinking {
+		for _, txn := range flush.txns {
+			txn.Callback()
+		}
+		return nil
+	}
+
+	// Transactions already committed by a previous run are skipped.
+	recovered, err := w.recover(ctx, flush.tableID, flush.table)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var (
+		txns   = make([]*flushTxn, 0, len(flush.txns))
+		events []mqEvent
+	)
+	for _, txn := range flush.txns {
+		if recovered.Covers(txn.pos.CommitTs, txn.pos.Txns) {
+			txn.Callback()
+			continue
+		}
+		txns = append(txns, txn)
+		events = append(events, txn.events...)
+	}
+	if len(txns) == 0 {
+		return nil
+	}
+
+	messages, rows, err := w.encode(ctx, events)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	messages = append(messages, &kafka.TransactionalMessage{
+		Topic:     w.markerTopic,
+		Partition: 0,
+		Message: common.NewResolvedMsg(w.protocol,
+			[]byte(kafka.TransactionMarkerKey(w.changeFeedID, flush.tableID)),
+			flush.marker.Encode(), flush.marker.CommitTs),
+	})
+
+	flush.table.mu.Lock()
+	defer flush.table.mu.Unlock()
+	if flush.table.closed {
+		// All table sinks of the table are closed, it's safe to drop the flush.
+		for _, txn := range txns {
+			txn.Callback()
+		}
+		return nil
+	}
+	producer, err := w.getProducer(ctx, flush.tableID, flush.table)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = w.statistics.RecordBatchExecution(func() (int, int64, error) {
+		if err := producer.BeginTxn(); err != nil {
+			return 0, 0, err
+		}
+		if err := producer.SendMessages(ctx, messages); err != nil {
+			w.abort(producer, flush.tableID)
+			return 0, 0, err
+		}
+		if err := producer.CommitTxn(); err != nil {
+			w.abort(producer, flush.tableID)
+			return 0, 0, err
+		}
+		var size int64
+		for _, m := range messages {
+			size += int64(m.Message.Length())
+		}
+		return rows, size, nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, txn := range txns {
+		txn.Callback()
+	}
+	return nil
+}
+
+// encode encodes the events, messages of the same topic and partition
+// are built together to keep the order of the events.
+func (w *txnWorker) encode(
+	ctx context.Context, events []mqEvent,
+) ([]*kafka.TransactionalMessage, int, error) {
+	var (
+		keys    []model.TopicPartitionKey
+		grouped = make(map[model.TopicPartitionKey][]*model.RowChangedEvent)
+	)
+	for _, event := range events {
+		if _, ok := grouped[event.key]; !ok {
+			keys = append(keys, event.key)
+		}
+		grouped[event.key] = append(grouped[event.key], event.rowEvent.Event)
+	}
+
+	var messages []*kafka.TransactionalMessage
+	for _, key := range keys {
+		rows := grouped[key]
+		w.statistics.ObserveRows(rows...)
+		for _, row := range rows {
+			if err := w.encoder.AppendRowChangedEvent(ctx, key.Topic, row, nil); err != nil {
+				return nil, 0, errors.Trace(err)
+			}
+		}
+		for _, message := range w.encoder.Build() {
+			message.SetPartitionKey(key.PartitionKey)
+			messages = append(messages, &kafka.TransactionalMessage{
+				Topic:     key.Topic,
+				Partition: key.Partition,
+				Message:   message,
+			})
+		}
+	}
+	return messages, len(events), nil
+}
+
+// getProducer returns the producer of the table, it must be called with
+// the lock of the table held.
+func (w *txnWorker) getProducer(
+	ctx context.Context, tableID model.TableID, table *txnTable,
+) (kafka.TransactionalProducer, error) {
+	if table.producer != nil {
+		return table.producer, nil
+	}
+	transactionalID := kafka.TransactionalID(w.changeFeedID, tableID)
+	producer, err := w.factory.TransactionalProducer(ctx, transactionalID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	log.Info("MQ sink transactional producer created",
+		zap.String("namespace", w.changeFeedID.Namespace),
+		zap.String("changefeed", w.changeFeedID.ID),
+		zap.Int64("tableID", tableID),
+		zap.String("transactionalID", transactionalID))
+	table.producer = producer
+	return producer, nil
+}
+
+func (w *txnWorker) abort(producer kafka.TransactionalProducer, tableID model.TableID) {
+	if err := producer.AbortTxn(); err != nil {
+		log.Warn("MQ sink abort transaction failed",
+			zap.String("namespace", w.changeFeedID.Namespace),
+			zap.String("changefeed", w.changeFeedID.ID),
+			zap.Int64("tableID", tableID),
+			zap.Error(err))
+	}
+}
+
+func (w *txnWorker) close() {
+	w.flushChan.CloseAndDrain()
+	w.mu.Lock()
+	tables := make([]*txnTable, 0, len(w.tables))
+	for _, table := range w.tables {
+		tables = append(tables, table)
+	}
+	w.mu.Unlock()
+	for _, table := range tables {
+		table.close()
+	}
+}
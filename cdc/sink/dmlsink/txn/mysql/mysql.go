@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/deadletter"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -78,6 +79,9 @@ type mysqlBackend struct {
 	// Indicate if the CachePrepStmts should be enabled or not
 	cachePrepStmts   bool
 	maxAllowedPacket int64
+
+	// deadLetterQueue is nil if the dead letter queue is not configured.
+	deadLetterQueue *deadletter.Queue
 }
 
 // NewMySQLBackends creates a new MySQL sink using schema storage
@@ -88,6 +92,7 @@ func NewMySQLBackends(
 	replicaConfig *config.ReplicaConfig,
 	dbConnFactory pmysql.Factory,
 	statistics *metrics.Statistics,
+	deadLetterQueue *deadletter.Queue,
 ) ([]*mysqlBackend, error) {
 	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
 
@@ -191,6 +196,7 @@ func NewMySQLBackends(
 			stmtCache:                       stmtCache,
 			cachePrepStmts:                  cachePrepStmts,
 			maxAllowedPacket:                maxAllowedPacket,
+			deadLetterQueue:                 deadLetterQueue,
 		})
 	}
 
@@ -231,7 +237,13 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 		zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
 
 	start := time.Now()
-	if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
+	err = s.execDMLWithMaxRetries(ctx, dmls)
+	if err != nil && s.deadLetterQueue != nil && isDeadLetterError(err) {
+		log.Warn("execute DMLs failed, execute transactions one by one",
+			zap.String("changefeed", s.changefeed), zap.Error(err))
+		err = s.execEachTxn(ctx)
+	}
+	if err != nil {
 		if errors.Cause(err) != context.Canceled {
 			log.Error("execute DMLs failed", zap.String("changefeed", s.changefeed), zap.Error(err))
 		}
@@ -256,6 +268,31 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 	return
 }
 
+// execEachTxn executes the buffered transactions one by one, and writes
+// the ones which fail because of their data to the dead letter queue.
+func (s *mysqlBackend) execEachTxn(ctx context.Context) error {
+	events, rows := s.events, s.rows
+	defer func() {
+		s.events, s.rows = events, rows
+	}()
+
+	for _, event := range events {
+		s.events = []*dmlsink.TxnCallbackableEvent{event}
+		s.rows = len(event.Event.Rows)
+		err := s.execDMLWithMaxRetries(ctx, s.prepareDMLs())
+		if err == nil {
+			continue
+		}
+		if !isDeadLetterError(err) {
+			return errors.Trace(err)
+		}
+		if err := s.deadLetterQueue.Write(ctx, event.Event, err); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // Close implements interface backend.
 func (s *mysqlBackend) Close() (err error) {
 	if s.stmtCache != nil {
@@ -802,7 +839,7 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 	}, retry.WithBackoffBaseDelay(pmysql.BackoffBaseDelay.Milliseconds()),
 		retry.WithBackoffMaxDelay(pmysql.BackoffMaxDelay.Milliseconds()),
 		retry.WithMaxTries(s.dmlMaxRetry),
-		retry.WithIsRetryableErr(isRetryableDMLError))
+		retry.WithIsRetryableErr(s.isRetryableDMLError))
 }
 
 func wrapMysqlTxnError(err error) error {
@@ -857,6 +894,34 @@ func isRetryableDMLError(err error) bool {
 	return true
 }
 
+// isRetryableDMLError is like the function isRetryableDMLError, except that
+// errors caused by the data of transactions are not retried if the dead letter
+// queue is configured, they are written to the queue instead.
+func (s *mysqlBackend) isRetryableDMLError(err error) bool {
+	if s.deadLetterQueue != nil && isDeadLetterError(err) {
+		return false
+	}
+	return isRetryableDMLError(err)
+}
+
+// isDeadLetterError returns true if the error is caused by the data of the
+// transaction, so it can not be applied no matter how many times it is retried.
+func isDeadLetterError(err error) bool {
+	errCode, ok := getSQLErrCode(err)
+	if !ok {
+		return false
+	}
+
+	switch errCode {
+	case mysql.ErrDataTooLong, mysql.ErrTruncatedWrongValue,
+		mysql.ErrTruncatedWrongValueForField, mysql.ErrWarnDataOutOfRange, mysql.ErrBadNull,
+		mysql.ErrNoReferencedRow, mysql.ErrRowIsReferenced,
+		mysql.ErrNoReferencedRow2, mysql.ErrRowIsReferenced2:
+		return true
+	}
+	return false
+}
+
 func getSQLErrCode(err error) (errors.ErrCode, bool) {
 	mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError)
 	if !ok {
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -36,8 +37,10 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/deadletter"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/sqlmodel"
@@ -78,7 +81,7 @@ func newMySQLBackend(
 	sinkURI.RawQuery = raw.Encode()
 
 	backends, err := NewMySQLBackends(ctx, changefeedID,
-		sinkURI, replicaConfig, dbConnFactory, statistics)
+		sinkURI, replicaConfig, dbConnFactory, statistics, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -689,7 +692,6 @@ func TestMysqlSinkNotRetryErrDupEntry(t *testing.T) {
 	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID(changefeed), sinkURI,
 		config.GetDefaultReplicaConfig(), mockDBInsertDupEntry)
 	require.Nil(t, err)
-	sink.setDMLMaxRetry(1)
 	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
 		Event: &model.SingleTableTxn{Rows: rows},
 	})
@@ -699,6 +701,98 @@ func TestMysqlSinkNotRetryErrDupEntry(t *testing.T) {
 	require.Nil(t, sink.Close())
 }
 
+func TestMysqlSinkDeadLetterQueue(t *testing.T) {
+	errDataTooLong := &dmysql.MySQLError{
+		Number:  mysql.ErrDataTooLong,
+		Message: "Data too long for column 'a' at row 1",
+	}
+	tableInfo := model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+	}, [][]int{{0}})
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:         2,
+			CommitTs:        3,
+			ReplicatingTs:   1,
+			TableInfo:       tableInfo,
+			PhysicalTableID: 1,
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{
+					Name:  "a",
+					Value: 1,
+				},
+			}, tableInfo),
+		},
+	}
+
+	dbIndex := 0
+	mockDBInsertDataTooLong := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB()
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db, the transaction fails in the batch and when it is
+		// executed alone, it's never retried.
+		db, mock := newTestMockDB(t)
+		for i := 0; i < 2; i++ {
+			mock.ExpectBegin()
+			mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(1).
+				WillReturnError(errDataTooLong)
+			mock.ExpectRollback()
+		}
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeedID := model.DefaultChangeFeedID("test-changefeed")
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&safe-mode=false" +
+			"&cache-prep-stmts=false")
+	require.Nil(t, err)
+
+	dir := t.TempDir()
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DeadLetterQueue = &config.DeadLetterQueueConfig{
+		URI:           "file://" + dir,
+		Protocol:      config.ProtocolCanalJSON.String(),
+		MaxErrorCount: 1,
+	}
+	warnings := make(chan error, 1)
+	queue, err := deadletter.New(ctx, changefeedID, replicaConfig, warnings)
+	require.Nil(t, err)
+	defer queue.Close()
+
+	sink, err := newMySQLBackend(ctx, changefeedID, sinkURI,
+		replicaConfig, mockDBInsertDataTooLong)
+	require.Nil(t, err)
+	sink.deadLetterQueue = queue
+	var flushed bool
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{TableInfo: tableInfo, StartTs: 2, CommitTs: 3, Rows: rows},
+		Callback: func() { flushed = true },
+	})
+	require.Nil(t, sink.Flush(context.Background()))
+	require.True(t, flushed)
+	require.True(t, cerror.ErrDeadLetterQueueWritten.Equal(<-warnings))
+	files, err := filepath.Glob(filepath.Join(dir, "s1", "t1", "*.json"))
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+
+	require.Nil(t, sink.Close())
+}
+
 func TestNewMySQLBackendExecDDL(t *testing.T) {
 	// TODO: fill it.
 }
@@ -0,0 +1,480 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	ppostgres "github.com/pingcap/tiflow/pkg/sink/postgres"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// Max interval for flushing transactions to the downstream.
+	maxFlushInterval = 10 * time.Millisecond
+
+	// networkDriftDuration is used to construct a context timeout for database operations.
+	networkDriftDuration = 5 * time.Second
+
+	defaultDMLMaxRetry uint64 = 8
+)
+
+type postgresBackend struct {
+	workerID    int
+	changefeed  string
+	db          *sql.DB
+	cfg         *ppostgres.Config
+	dmlMaxRetry uint64
+
+	events []*dmlsink.TxnCallbackableEvent
+	rows   int
+
+	statistics                    *metrics.Statistics
+	metricTxnSinkDMLBatchCommit   prometheus.Observer
+	metricTxnSinkDMLBatchCallback prometheus.Observer
+}
+
+// NewPostgresBackends creates PostgreSQL backends for the txn sink.
+func NewPostgresBackends(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	dbConnFactory ppostgres.Factory,
+	statistics *metrics.Statistics,
+) ([]*postgresBackend, error) {
+	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
+
+	cfg := ppostgres.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, err
+	}
+
+	db, err := dbConnFactory(ctx, ppostgres.GenerateDSN(sinkURI))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(cfg.WorkerCount)
+	db.SetMaxOpenConns(cfg.WorkerCount)
+
+	backends := make([]*postgresBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backends = append(backends, &postgresBackend{
+			workerID:    i,
+			changefeed:  changefeed,
+			db:          db,
+			cfg:         cfg,
+			dmlMaxRetry: defaultDMLMaxRetry,
+			statistics:  statistics,
+
+			metricTxnSinkDMLBatchCommit:   txn.SinkDMLBatchCommit.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+			metricTxnSinkDMLBatchCallback: txn.SinkDMLBatchCallback.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		})
+	}
+
+	log.Info("PostgreSQL backends is created",
+		zap.String("changefeed", changefeed),
+		zap.Int("workerCount", cfg.WorkerCount),
+		zap.Bool("safeMode", cfg.SafeMode))
+	return backends, nil
+}
+
+// OnTxnEvent implements interface backend.
+// It adds the event to the buffer, and return true if it needs flush immediately.
+func (s *postgresBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
+	s.events = append(s.events, event)
+	s.rows += len(event.Event.Rows)
+	return s.rows >= s.cfg.MaxTxnRow
+}
+
+// Flush implements interface backend.
+func (s *postgresBackend) Flush(ctx context.Context) (err error) {
+	if s.rows == 0 {
+		return
+	}
+
+	for _, event := range s.events {
+		s.statistics.ObserveRows(event.Event.Rows...)
+	}
+
+	dmls := s.prepareDMLs()
+	log.Debug("prepare DMLs", zap.String("changefeed", s.changefeed), zap.Any("rows", s.rows),
+		zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
+
+	start := time.Now()
+	if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
+		if errors.Cause(err) != context.Canceled {
+			log.Error("execute DMLs failed", zap.String("changefeed", s.changefeed), zap.Error(err))
+		}
+		return errors.Trace(err)
+	}
+	startCallback := time.Now()
+	for _, callback := range dmls.callbacks {
+		callback()
+	}
+	s.metricTxnSinkDMLBatchCommit.Observe(startCallback.Sub(start).Seconds())
+	s.metricTxnSinkDMLBatchCallback.Observe(time.Since(startCallback).Seconds())
+
+	// Be friently to GC.
+	for i := 0; i < len(s.events); i++ {
+		s.events[i] = nil
+	}
+	if cap(s.events) > 1024 {
+		s.events = make([]*dmlsink.TxnCallbackableEvent, 0)
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return
+}
+
+// Close implements interface backend.
+func (s *postgresBackend) Close() (err error) {
+	if s.db != nil {
+		err = s.db.Close()
+		s.db = nil
+	}
+	return
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *postgresBackend) MaxFlushInterval() time.Duration {
+	return maxFlushInterval
+}
+
+type preparedDMLs struct {
+	startTs         []model.Ts
+	sqls            []string
+	values          [][]interface{}
+	callbacks       []dmlsink.CallbackFunc
+	rowCount        int
+	approximateSize int64
+}
+
+// convert2RowChange is a helper function that convert the row change representation
+// of CDC into a general one.
+func convert2RowChange(row *model.RowChangedEvent) *sqlmodel.RowChange {
+	tidbTableInfo := row.TableInfo.TableInfo
+	// RowChangedEvent doesn't contain data for virtual columns,
+	// so we need to create a new table info without virtual columns before pass it to NewRowChange.
+	if row.TableInfo.HasVirtualColumns() {
+		tidbTableInfo = model.BuildTiDBTableInfoWithoutVirtualColumns(tidbTableInfo)
+	}
+
+	var preValues, postValues []interface{}
+	if len(row.PreColumns) != 0 {
+		preValues = make([]interface{}, 0, len(row.PreColumns))
+		for _, col := range row.PreColumns {
+			preValues = append(preValues, col.Value)
+		}
+	}
+	if len(row.Columns) != 0 {
+		postValues = make([]interface{}, 0, len(row.Columns))
+		for _, col := range row.Columns {
+			postValues = append(postValues, col.Value)
+		}
+	}
+
+	res := sqlmodel.NewRowChange(&row.TableInfo.TableName, nil,
+		preValues, postValues, tidbTableInfo, nil, nil)
+	res.SetApproximateDataSize(row.ApproximateDataSize)
+	return res
+}
+
+// convertColumnValues converts the column values to the representation
+// expected by PostgreSQL. The []byte values of non-binary columns are
+// converted to string, otherwise the driver sends them as bytea. Enum and
+// set values are converted from their numeric index to their names. Other
+// uint64 values are converted to string, since the driver rejects the ones
+// which overflow int64.
+func convertColumnValues(cols []*model.ColumnData, tableInfo *model.TableInfo) {
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		colInfo := tableInfo.ForceGetColumnInfo(col.ColumnID)
+		switch colInfo.GetType() {
+		case mysql.TypeEnum:
+			if v, ok := col.Value.(uint64); ok {
+				// 0 is the index of the empty error value.
+				name := ""
+				if enum, err := types.ParseEnumValue(colInfo.GetElems(), v); err == nil {
+					name = enum.Name
+				}
+				cols[i].Value = name
+			}
+			continue
+		case mysql.TypeSet:
+			if v, ok := col.Value.(uint64); ok {
+				name := ""
+				if set, err := types.ParseSetValue(colInfo.GetElems(), v); err == nil {
+					name = set.Name
+				}
+				cols[i].Value = name
+			}
+			continue
+		}
+		if v, ok := col.Value.(uint64); ok {
+			cols[i].Value = strconv.FormatUint(v, 10)
+			continue
+		}
+		if colInfo.GetCharset() != "" && colInfo.GetCharset() != charset.CharsetBin {
+			colValBytes, ok := col.Value.([]byte)
+			if ok {
+				cols[i].Value = string(colValBytes)
+			}
+		}
+	}
+}
+
+// prepareDMLs converts model.RowChangedEvent list to query string list and args list.
+// Successive row changes of the same type in one transaction are merged into
+// one multi-value statement. An UPDATE is translated into a DELETE of the old
+// row and an upsert of the new row, so changes of the handle key are covered.
+func (s *postgresBackend) prepareDMLs() *preparedDMLs {
+	startTs := make([]uint64, 0, s.rows)
+	sqls := make([]string, 0, s.rows)
+	values := make([][]interface{}, 0, s.rows)
+	callbacks := make([]dmlsink.CallbackFunc, 0, len(s.events))
+
+	// translateToInsert control the update and insert behavior.
+	translateToInsert := !s.cfg.SafeMode
+
+	rowCount := 0
+	approximateSize := int64(0)
+
+	var (
+		pending     []*sqlmodel.RowChange
+		pendingType sqlmodel.RowChangeType
+	)
+	flushPending := func() {
+		if len(pending) == 0 {
+			return
+		}
+		var query string
+		var args []interface{}
+		switch pendingType {
+		case sqlmodel.RowChangeDelete:
+			query, args = sqlmodel.GenPostgresDeleteSQL(pending...)
+		case sqlmodel.RowChangeInsert:
+			if translateToInsert {
+				query, args = sqlmodel.GenPostgresInsertSQL(pending...)
+			} else {
+				query, args = sqlmodel.GenPostgresUpsertSQL(pending...)
+			}
+		}
+		sqls = append(sqls, query)
+		values = append(values, args)
+		approximateSize += int64(len(query))
+		pending = pending[:0]
+	}
+	appendPending := func(tp sqlmodel.RowChangeType, change *sqlmodel.RowChange) {
+		if len(pending) > 0 && (pendingType != tp || len(pending) >= s.cfg.MaxTxnRow ||
+			!sqlmodel.SameTypeTargetAndColumns(pending[0], change)) {
+			flushPending()
+		}
+		pendingType = tp
+		pending = append(pending, change)
+	}
+
+	for _, event := range s.events {
+		if len(event.Event.Rows) == 0 {
+			continue
+		}
+		rowCount += len(event.Event.Rows)
+
+		firstRow := event.Event.Rows[0]
+		if len(startTs) == 0 || startTs[len(startTs)-1] != firstRow.StartTs {
+			startTs = append(startTs, firstRow.StartTs)
+		}
+
+		// A row can be translated in to INSERT, when it was committed after
+		// the table it belongs to been replicating by TiCDC, which means it must not be
+		// replicated before, and there is no such row in downstream.
+		translateToInsert = translateToInsert && firstRow.CommitTs > firstRow.ReplicatingTs
+
+		if event.Callback != nil {
+			callbacks = append(callbacks, event.Callback)
+		}
+
+		for _, row := range event.Event.Rows {
+			convertColumnValues(row.Columns, row.TableInfo)
+			convertColumnValues(row.PreColumns, row.TableInfo)
+			change := convert2RowChange(row)
+			switch change.Type() {
+			case sqlmodel.RowChangeInsert:
+				appendPending(sqlmodel.RowChangeInsert, change)
+			case sqlmodel.RowChangeDelete:
+				appendPending(sqlmodel.RowChangeDelete, change)
+				if !change.HasUniqueWhere() {
+					// A delete without key removes one of the duplicated
+					// rows, it can not be merged with another one.
+					flushPending()
+				}
+			case sqlmodel.RowChangeUpdate:
+				flushPending()
+				query, args := sqlmodel.GenPostgresDeleteSQL(change)
+				sqls = append(sqls, query)
+				values = append(values, args)
+				query, args = sqlmodel.GenPostgresUpsertSQL(change)
+				sqls = append(sqls, query)
+				values = append(values, args)
+				approximateSize += int64(len(sqls[len(sqls)-2]) + len(query))
+			}
+			approximateSize += row.ApproximateDataSize
+		}
+		// Statements are never merged across transactions.
+		flushPending()
+	}
+
+	if len(callbacks) == 0 {
+		callbacks = nil
+	}
+
+	return &preparedDMLs{
+		startTs:         startTs,
+		sqls:            sqls,
+		values:          values,
+		callbacks:       callbacks,
+		rowCount:        rowCount,
+		approximateSize: approximateSize,
+	}
+}
+
+func (s *postgresBackend) execDMLWithMaxRetries(pctx context.Context, dmls *preparedDMLs) error {
+	if len(dmls.sqls) != len(dmls.values) {
+		log.Error("unexpected number of sqls and values",
+			zap.String("changefeed", s.changefeed),
+			zap.Strings("sqls", dmls.sqls),
+			zap.Any("values", dmls.values))
+		return cerror.ErrUnexpected.FastGenByArgs("unexpected number of sqls and values")
+	}
+
+	start := time.Now()
+	return retry.Do(pctx, func() error {
+		writeTimeout, _ := time.ParseDuration(s.cfg.WriteTimeout)
+		writeTimeout += networkDriftDuration
+
+		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
+			tx, err := s.db.BeginTx(pctx, nil)
+			if err != nil {
+				return 0, 0, logDMLTxnErr(
+					cerror.WrapError(cerror.ErrPostgresTxnError, err),
+					start, s.changefeed, "BEGIN", dmls.rowCount, dmls.startTs)
+			}
+
+			for i, query := range dmls.sqls {
+				args := dmls.values[i]
+				log.Debug("exec row", zap.String("changefeed", s.changefeed), zap.Int("workerID", s.workerID),
+					zap.String("sql", query), zap.Any("args", args))
+				ctx, cancelFunc := context.WithTimeout(pctx, writeTimeout)
+				_, execError := tx.ExecContext(ctx, query, args...)
+				cancelFunc()
+				if execError != nil {
+					err := logDMLTxnErr(
+						cerror.WrapError(cerror.ErrPostgresTxnError, execError),
+						start, s.changefeed, query, dmls.rowCount, dmls.startTs)
+					if rbErr := tx.Rollback(); rbErr != nil {
+						if errors.Cause(rbErr) != context.Canceled {
+							log.Warn("failed to rollback txn", zap.String("changefeed", s.changefeed), zap.Error(rbErr))
+						}
+					}
+					return 0, 0, err
+				}
+			}
+
+			if err = tx.Commit(); err != nil {
+				return 0, 0, logDMLTxnErr(
+					cerror.WrapError(cerror.ErrPostgresTxnError, err),
+					start, s.changefeed, "COMMIT", dmls.rowCount, dmls.startTs)
+			}
+			return dmls.rowCount, dmls.approximateSize, nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Debug("Exec Rows succeeded",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfRows", dmls.rowCount))
+		return nil
+	}, retry.WithBackoffBaseDelay(ppostgres.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(ppostgres.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(s.dmlMaxRetry),
+		retry.WithIsRetryableErr(isRetryableDMLError))
+}
+
+func logDMLTxnErr(
+	err error, start time.Time, changefeed string,
+	query string, count int, startTs []model.Ts,
+) error {
+	if len(query) > 1024 {
+		query = query[:1024]
+	}
+	if isRetryableDMLError(err) {
+		log.Warn("execute DMLs with error, retry later",
+			zap.Error(err), zap.Duration("duration", time.Since(start)),
+			zap.String("query", query), zap.Int("count", count),
+			zap.Uint64s("startTs", startTs),
+			zap.String("changefeed", changefeed))
+	} else {
+		log.Error("execute DMLs with error, can not retry",
+			zap.Error(err), zap.Duration("duration", time.Since(start)),
+			zap.String("query", query), zap.Int("count", count),
+			zap.String("changefeed", changefeed))
+	}
+	return errors.WithMessage(err, fmt.Sprintf("Failed query info: %s; ", query))
+}
+
+func isRetryableDMLError(err error) bool {
+	if !cerror.IsRetryableError(err) {
+		return false
+	}
+
+	pqErr, ok := errors.Cause(err).(*pq.Error)
+	if !ok {
+		return true
+	}
+	switch pqErr.Code.Class() {
+	// integrity constraint violation, data exception and syntax error or
+	// access rule violation (e.g. undefined table) can't be fixed by retrying.
+	case "22", "23", "42":
+		return false
+	}
+	return true
+}
+
+// Only for testing.
+func (s *postgresBackend) setDMLMaxRetry(maxRetry uint64) {
+	s.dmlMaxRetry = maxRetry
+}
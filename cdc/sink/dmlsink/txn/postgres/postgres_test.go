@@ -0,0 +1,288 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink"
+	ppostgres "github.com/pingcap/tiflow/pkg/sink/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func newPostgresBackend(
+	ctx context.Context,
+	t *testing.T,
+	dbConnFactory func(ctx context.Context, dsnStr string) (*sql.DB, error),
+) *postgresBackend {
+	changefeedID := model.DefaultChangeFeedID("test-changefeed")
+	ctx1, cancel := context.WithCancel(ctx)
+	statistics := metrics.NewStatistics(ctx1, changefeedID, sink.TxnSink)
+	cancel() // Cancel background goroutines in returned metrics.Statistics.
+
+	sinkURI, err := url.Parse("postgres://127.0.0.1:5432/postgres?worker-count=1&sslmode=disable")
+	require.NoError(t, err)
+	backends, err := NewPostgresBackends(ctx, changefeedID, sinkURI,
+		config.GetDefaultReplicaConfig(), dbConnFactory, statistics)
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	return backends[0]
+}
+
+func newTestTableInfo() *model.TableInfo {
+	return model.BuildTableInfo("s1", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		},
+		{
+			Name:    "b",
+			Type:    mysql.TypeVarchar,
+			Charset: "utf8mb4",
+		},
+	}, [][]int{{0}})
+}
+
+func newInsertRow(tableInfo *model.TableInfo, commitTs model.Ts, a int, b string) *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		StartTs:   commitTs - 1,
+		CommitTs:  commitTs,
+		TableInfo: tableInfo,
+		Columns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "a", Value: a},
+			{Name: "b", Value: []byte(b)},
+		}, tableInfo),
+	}
+}
+
+func TestPrepareDMLs(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := newTestTableInfo()
+	update := &model.RowChangedEvent{
+		StartTs:   9,
+		CommitTs:  10,
+		TableInfo: tableInfo,
+		PreColumns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "a", Value: 1},
+			{Name: "b", Value: "x"},
+		}, tableInfo),
+		Columns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "a", Value: 3},
+			{Name: "b", Value: "y"},
+		}, tableInfo),
+	}
+	remove := &model.RowChangedEvent{
+		StartTs:   9,
+		CommitTs:  10,
+		TableInfo: tableInfo,
+		PreColumns: model.Columns2ColumnDatas([]*model.Column{
+			{Name: "a", Value: 2},
+			{Name: "b", Value: "x"},
+		}, tableInfo),
+	}
+
+	s := &postgresBackend{cfg: ppostgres.NewConfig()}
+	s.events = []*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{
+			newInsertRow(tableInfo, 10, 1, "a"),
+			newInsertRow(tableInfo, 10, 2, "b"),
+			update,
+			remove,
+		}},
+	}}
+	s.rows = 4
+	dmls := s.prepareDMLs()
+
+	require.Equal(t, []model.Ts{9}, dmls.startTs)
+	require.Equal(t, []string{
+		`INSERT INTO "s1"."t1" ("a","b") VALUES ($1,$2),($3,$4)`,
+		`DELETE FROM "s1"."t1" WHERE ("a" = $1)`,
+		`INSERT INTO "s1"."t1" ("a","b") VALUES ($1,$2) ON CONFLICT ("a") DO UPDATE SET "b"=EXCLUDED."b"`,
+		`DELETE FROM "s1"."t1" WHERE ("a" = $1)`,
+	}, dmls.sqls)
+	require.Equal(t, [][]interface{}{{1, "a", 2, "b"}, {1}, {3, "y"}, {2}}, dmls.values)
+	require.Equal(t, 4, dmls.rowCount)
+
+	// In safe mode, inserts are translated into upserts.
+	s.cfg.SafeMode = true
+	s.events = []*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{newInsertRow(tableInfo, 12, 5, "c")}},
+	}}
+	s.rows = 1
+	dmls = s.prepareDMLs()
+	require.Equal(t, []string{
+		`INSERT INTO "s1"."t1" ("a","b") VALUES ($1,$2) ON CONFLICT ("a") DO UPDATE SET "b"=EXCLUDED."b"`,
+	}, dmls.sqls)
+}
+
+func TestPrepareDMLsWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := model.BuildTableInfo("s1", "t2", []*model.Column{
+		{Name: "a", Type: mysql.TypeLong},
+		{Name: "e", Type: mysql.TypeEnum},
+	}, nil)
+	tableInfo.Columns[1].SetElems([]string{"x", "y"})
+	newDeleteRow := func() *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:   9,
+			CommitTs:  10,
+			TableInfo: tableInfo,
+			PreColumns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "a", Value: 1},
+				{Name: "e", Value: uint64(2)},
+			}, tableInfo),
+		}
+	}
+
+	s := &postgresBackend{cfg: ppostgres.NewConfig()}
+	s.events = []*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{
+			newDeleteRow(), newDeleteRow(),
+		}},
+	}}
+	s.rows = 2
+	dmls := s.prepareDMLs()
+
+	// Duplicated rows of a keyless table are deleted one by one, and enum
+	// values are replicated by name.
+	deleteSQL := `DELETE FROM "s1"."t2" WHERE ` +
+		`(ctid = (SELECT ctid FROM "s1"."t2" WHERE "a" = $1 AND "e" = $2 LIMIT 1))`
+	require.Equal(t, []string{deleteSQL, deleteSQL}, dmls.sqls)
+	require.Equal(t, [][]interface{}{{1, "y"}, {1, "y"}}, dmls.values)
+}
+
+func TestPrepareDMLsUnsignedBigint(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := model.BuildTableInfo("s1", "t3", []*model.Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLonglong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag | model.UnsignedFlag,
+		},
+	}, [][]int{{0}})
+	s := &postgresBackend{cfg: ppostgres.NewConfig()}
+	s.events = []*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{{
+			StartTs:   9,
+			CommitTs:  10,
+			TableInfo: tableInfo,
+			Columns: model.Columns2ColumnDatas([]*model.Column{
+				{Name: "a", Value: uint64(math.MaxUint64)},
+			}, tableInfo),
+		}}},
+	}}
+	s.rows = 1
+	dmls := s.prepareDMLs()
+
+	// Values overflowing int64 are sent as strings, which NUMERIC(20,0) accepts.
+	require.Equal(t, []string{`INSERT INTO "s1"."t3" ("a") VALUES ($1)`}, dmls.sqls)
+	require.Equal(t, [][]interface{}{{"18446744073709551615"}}, dmls.values)
+}
+
+func TestPostgresBackendExecDML(t *testing.T) {
+	t.Parallel()
+
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		require.Equal(t, "postgres://127.0.0.1:5432/postgres?sslmode=disable", dsnStr)
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "s1"."t1" ("a","b") VALUES ($1,$2),($3,$4)`).
+			WithArgs(1, "test", 2, "test").
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	backend := newPostgresBackend(ctx, t, mockGetDBConn)
+
+	tableInfo := newTestTableInfo()
+	rows := []*model.RowChangedEvent{
+		newInsertRow(tableInfo, 2, 1, "test"),
+		newInsertRow(tableInfo, 2, 2, "test"),
+	}
+	flushed := false
+	_ = backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: rows},
+		Callback: func() { flushed = true },
+	})
+	require.NoError(t, backend.Flush(ctx))
+	require.True(t, flushed)
+	require.NoError(t, backend.Close())
+}
+
+func TestPostgresBackendExecDMLError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		err      *pq.Error
+		attempts int
+	}{
+		// serialization_failure is retryable.
+		{err: &pq.Error{Code: "40001"}, attempts: 2},
+		// unique_violation can't be fixed by retrying.
+		{err: &pq.Error{Code: "23505"}, attempts: 1},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			for i := 0; i < tc.attempts; i++ {
+				mock.ExpectBegin()
+				mock.ExpectExec(`INSERT INTO "s1"."t1" ("a","b") VALUES ($1,$2)`).
+					WithArgs(1, "test").
+					WillReturnError(tc.err)
+				mock.ExpectRollback()
+			}
+			mock.ExpectClose()
+			return db, nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		backend := newPostgresBackend(ctx, t, mockGetDBConn)
+		backend.setDMLMaxRetry(2)
+
+		tableInfo := newTestTableInfo()
+		_ = backend.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{
+				newInsertRow(tableInfo, 2, 1, "test"),
+			}},
+		})
+		err := backend.Flush(ctx)
+		require.Equal(t, tc.err, errors.Cause(err))
+		require.NoError(t, backend.Close())
+		cancel()
+	}
+}
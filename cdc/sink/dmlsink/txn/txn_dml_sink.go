@@ -21,13 +21,16 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/deadletter"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/mysql"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/txn/postgres"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/causality"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	ppostgres "github.com/pingcap/tiflow/pkg/sink/postgres"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -54,6 +57,8 @@ type dmlSink struct {
 	dead chan struct{}
 
 	statistics *metrics.Statistics
+	// deadLetterQueue is only set for the MySQL sink with a dead letter queue.
+	deadLetterQueue *deadletter.Queue
 
 	scheme string
 }
@@ -64,6 +69,10 @@ type dmlSink struct {
 // You can use `SetupSuite` and `TearDownSuite` to do this to get a better way.
 var GetDBConnImpl pmysql.Factory = pmysql.CreateMySQLDBConn
 
+// GetPostgresDBConnImpl is the implementation of ppostgres.Factory.
+// Exported for testing.
+var GetPostgresDBConnImpl ppostgres.Factory = ppostgres.CreatePostgresDBConn
+
 // NewMySQLSink creates a mysql dmlSink with given parameters.
 func NewMySQLSink(
 	ctx context.Context,
@@ -76,7 +85,54 @@ func NewMySQLSink(
 	ctx, cancel := context.WithCancel(ctx)
 	statistics := metrics.NewStatistics(ctx, changefeedID, sink.TxnSink)
 
-	backendImpls, err := mysql.NewMySQLBackends(ctx, changefeedID, sinkURI, replicaConfig, GetDBConnImpl, statistics)
+	var deadLetterQueue *deadletter.Queue
+	if replicaConfig.Sink != nil && replicaConfig.Sink.DeadLetterQueue != nil {
+		var err error
+		deadLetterQueue, err = deadletter.New(ctx, changefeedID, replicaConfig, errCh)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	backendImpls, err := mysql.NewMySQLBackends(ctx, changefeedID, sinkURI, replicaConfig,
+		GetDBConnImpl, statistics, deadLetterQueue)
+	if err != nil {
+		if deadLetterQueue != nil {
+			deadLetterQueue.Close()
+		}
+		cancel()
+		return nil, err
+	}
+
+	backends := make([]backend, 0, len(backendImpls))
+	for _, impl := range backendImpls {
+		backends = append(backends, impl)
+	}
+
+	s := newSink(ctx, changefeedID, backends, errCh, conflictDetectorSlots)
+	s.statistics = statistics
+	s.deadLetterQueue = deadLetterQueue
+	s.cancel = cancel
+	s.scheme = sink.GetScheme(sinkURI)
+
+	return s, nil
+}
+
+// NewPostgresSink creates a PostgreSQL dmlSink with given parameters.
+func NewPostgresSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan<- error,
+	conflictDetectorSlots uint64,
+) (*dmlSink, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	statistics := metrics.NewStatistics(ctx, changefeedID, sink.TxnSink)
+
+	backendImpls, err := postgres.NewPostgresBackends(ctx, changefeedID, sinkURI,
+		replicaConfig, GetPostgresDBConnImpl, statistics)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -173,6 +229,9 @@ func (s *dmlSink) Close() {
 	if s.statistics != nil {
 		s.statistics.Close()
 	}
+	if s.deadLetterQueue != nil {
+		s.deadLetterQueue.Close()
+	}
 }
 
 // Dead checks whether it's dead or not.
@@ -88,6 +88,14 @@ var (
 			Name:      "txn_prepare_statement_errors",
 			Help:      "Prepare statement errors",
 		}, []string{"namespace", "changefeed"})
+
+	DeadLetterQueueWriteCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_dead_letter_queue_write_count",
+			Help:      "The number of transactions written to the dead letter queue",
+		}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file.
@@ -100,4 +108,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(SinkDMLBatchCommit)
 	registry.MustRegister(SinkDMLBatchCallback)
 	registry.MustRegister(PrepareStatementErrors)
+	registry.MustRegister(DeadLetterQueueWriteCount)
 }
@@ -84,6 +84,9 @@ func New[E dmlsink.TableEvent, P dmlsink.Appender[E]](
 	totalRowsCounter prometheus.Counter,
 	flushLagDuration prometheus.Observer,
 ) *EventTableSink[E, P] {
+	if listener, ok := backendSink.(dmlsink.TableListener); ok {
+		listener.AddTable(span)
+	}
 	return &EventTableSink[E, P]{
 		changefeedID:                     changefeedID,
 		span:                             span,
@@ -259,6 +262,9 @@ func (e *EventTableSink[E, P]) markAsClosed() (modified bool) {
 				zap.String("changefeed", e.changefeedID.ID),
 				zap.Stringer("span", &e.span),
 				zap.Uint64("checkpointTs", stoppedCheckpointTs.Ts))
+			if listener, ok := e.backendSink.(dmlsink.TableListener); ok {
+				listener.RemoveTable(e.span)
+			}
 			return true
 		}
 	}
@@ -60,6 +60,10 @@ func GetFileExtension(protocol config.Protocol) string {
 		return ".canal"
 	case config.ProtocolCsv:
 		return ".csv"
+	case config.ProtocolProtobuf:
+		return ".pb"
+	case config.ProtocolParquet:
+		return ".parquet"
 	default:
 		return ".unknown"
 	}
@@ -120,3 +124,9 @@ func GetTopicManagerAndTryCreateTopic(
 func IsPulsarSupportedProtocols(p config.Protocol) bool {
 	return p == config.ProtocolCanalJSON
 }
+
+// IsWebhookSupportedProtocols returns whether the protocol is supported by webhook sink.
+// Only the protocols which encode one event into one self-described message are supported.
+func IsWebhookSupportedProtocols(p config.Protocol) bool {
+	return p == config.ProtocolCanalJSON || p == config.ProtocolSimple || p == config.ProtocolDebezium
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/sorter/factory"
+	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/p2p"
@@ -38,6 +39,9 @@ type GlobalVars struct {
 
 	// OwnerRevision is the Etcd revision when the owner got elected.
 	OwnerRevision int64
+	// CaptureLoads is shared by all changefeeds of the owner to place spans
+	// with respect to their priorities.
+	CaptureLoads *scheduler.CaptureLoads
 
 	// MessageServer and MessageRouter are for peer-messaging
 	MessageServer *p2p.MessageServer
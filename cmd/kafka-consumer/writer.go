@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec/avro"
 	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
 	"github.com/pingcap/tiflow/pkg/sink/codec/open"
+	"github.com/pingcap/tiflow/pkg/sink/codec/protobuf"
 	"github.com/pingcap/tiflow/pkg/sink/codec/simple"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
@@ -62,6 +63,8 @@ func NewDecoder(ctx context.Context, option *option, upstreamTiDB *sql.DB) (code
 		decoder = avro.NewDecoder(option.codecConfig, schemaM, option.topic)
 	case config.ProtocolSimple:
 		decoder, err = simple.NewDecoder(ctx, option.codecConfig, upstreamTiDB)
+	case config.ProtocolProtobuf:
+		decoder = protobuf.NewDecoder()
 	default:
 		log.Panic("Protocol not supported", zap.Any("Protocol", option.protocol))
 	}
@@ -325,20 +328,24 @@ func (w *writer) WriteMessage(ctx context.Context, message *kafka.Message) bool
 					zap.Error(err))
 			}
 
-			if simple, ok := decoder.(*simple.Decoder); ok {
-				cachedEvents := simple.GetCachedEvents()
-				for _, row := range cachedEvents {
-					row.TableInfo.TableName.TableID = row.PhysicalTableID
-					group, ok := eventGroup[row.PhysicalTableID]
-					if !ok {
-						group = NewEventsGroup()
-						eventGroup[row.PhysicalTableID] = group
-					}
-					group.Append(row)
+			var cachedEvents []*model.RowChangedEvent
+			switch d := decoder.(type) {
+			case *simple.Decoder:
+				cachedEvents = d.GetCachedEvents()
+			case *protobuf.Decoder:
+				cachedEvents = d.GetCachedEvents()
+			}
+			for _, row := range cachedEvents {
+				row.TableInfo.TableName.TableID = row.PhysicalTableID
+				group, ok := eventGroup[row.PhysicalTableID]
+				if !ok {
+					group = NewEventsGroup()
+					eventGroup[row.PhysicalTableID] = group
 				}
+				group.Append(row)
 			}
 
-			// the Query maybe empty if using simple protocol, it's comes from `bootstrap` event.
+			// the Query maybe empty if using simple or protobuf protocol, it's comes from `bootstrap` event.
 			if partition == 0 && ddl.Query != "" {
 				w.appendDDL(ddl)
 				needFlush = true
@@ -356,15 +363,16 @@ func (w *writer) WriteMessage(ctx context.Context, message *kafka.Message) bool
 					zap.ByteString("value", value),
 					zap.Error(err))
 			}
-			// when using simple protocol, the row may be nil, since it's table info not received yet,
+			// when using simple or protobuf protocol, the row may be nil, since it's table info not received yet,
 			// it's cached in the decoder, so just continue here.
-			if w.option.protocol == config.ProtocolSimple && row == nil {
+			if row == nil && (w.option.protocol == config.ProtocolSimple ||
+				w.option.protocol == config.ProtocolProtobuf) {
 				continue
 			}
 
 			tableID := row.PhysicalTableID
-			// simple protocol decoder should have set the table id already.
-			if w.option.protocol != config.ProtocolSimple {
+			// simple and protobuf protocol decoder should have set the table id already.
+			if w.option.protocol != config.ProtocolSimple && w.option.protocol != config.ProtocolProtobuf {
 				tableID = w.fakeTableIDGenerator.
 					generateFakeTableID(row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName(), row.PhysicalTableID)
 				row.TableInfo.TableName.TableID = tableID
@@ -38,7 +38,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/tablesink"
 	sinkutil "github.com/pingcap/tiflow/cdc/sink/util"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/quotes"
 	psink "github.com/pingcap/tiflow/pkg/sink"
@@ -47,6 +49,8 @@ import (
 	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/codec/csv"
+	"github.com/pingcap/tiflow/pkg/sink/codec/parquet"
+	"github.com/pingcap/tiflow/pkg/sink/codec/protobuf"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	putil "github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
@@ -121,6 +125,8 @@ type consumer struct {
 	codecCfg        *common.Config
 	externalStorage storage.ExternalStorage
 	fileExtension   string
+	fileCompression string
+	masterKey       encryption.MasterKey
 	// tableDMLIdxMap maintains a map of <dmlPathKey, max file index>
 	tableDMLIdxMap map[cloudstorage.DmlPathKey]uint64
 	// tableTsMap maintains a map of <TableID, max commit ts>
@@ -134,7 +140,7 @@ type consumer struct {
 }
 
 func newConsumer(ctx context.Context) (*consumer, error) {
-	_, err := putil.GetTimezone(timezone)
+	tz, err := putil.GetTimezone(timezone)
 	if err != nil {
 		return nil, errors.Annotate(err, "can not load timezone")
 	}
@@ -159,6 +165,8 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 	switch putil.GetOrZero(replicaConfig.Sink.Protocol) {
 	case config.ProtocolCsv.String():
 	case config.ProtocolCanalJSON.String():
+	case config.ProtocolProtobuf.String():
+	case config.ProtocolParquet.String():
 	default:
 		return nil, fmt.Errorf(
 			"data encoded in protocol %s is not supported yet",
@@ -176,8 +184,28 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the TIMESTAMP values of the parquet files are decoded in the time zone.
+	codecConfig.TimeZone = tz
 
-	extension := sinkutil.GetFileExtension(protocol)
+	var fileCompression, encryptionMasterKey string
+	if replicaConfig.Sink.CloudStorageConfig != nil {
+		fileCompression = strings.ToLower(putil.GetOrZero(replicaConfig.Sink.CloudStorageConfig.Compression))
+		encryptionMasterKey = putil.GetOrZero(replicaConfig.Sink.CloudStorageConfig.EncryptionMasterKey)
+	}
+	if fileCompression == "" {
+		fileCompression = compression.None
+	}
+	if !compression.Supported(fileCompression) {
+		return nil, fmt.Errorf("data file compression %s is not supported yet", fileCompression)
+	}
+	extension := sinkutil.GetFileExtension(protocol) + compression.FileExtension(fileCompression)
+	// if the master key is provided, all data files must be encrypted, the
+	// plaintext ones are rejected by encryption.Decrypt.
+	masterKey, err := encryption.NewMasterKey(encryptionMasterKey)
+	if err != nil {
+		log.Error("failed to load encryption master key", zap.Error(err))
+		return nil, err
+	}
 
 	storage, err := putil.GetExternalStorageFromURI(ctx, upstreamURIStr)
 	if err != nil {
@@ -214,6 +242,8 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 		codecCfg:        codecConfig,
 		externalStorage: storage,
 		fileExtension:   extension,
+		fileCompression: fileCompression,
+		masterKey:       masterKey,
 		errCh:           errCh,
 		tableDMLIdxMap:  make(map[cloudstorage.DmlPathKey]uint64),
 		tableTsMap:      make(map[model.TableID]model.ResolvedTs),
@@ -322,6 +352,17 @@ func (c *consumer) emitDMLEvents(
 		if err != nil {
 			return errors.Trace(err)
 		}
+	case config.ProtocolParquet:
+		decoder, err = parquet.NewBatchDecoder(c.codecCfg, &tableDetail, content)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	case config.ProtocolProtobuf:
+		decoder = protobuf.NewStorageDecoder()
+		err := decoder.AddKeyValue(nil, content)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	cnt := 0
@@ -337,6 +378,16 @@ func (c *consumer) emitDMLEvents(
 		}
 		cnt++
 
+		// The protobuf data files carry the table schema in bootstrap events,
+		// which must be consumed before the rows.
+		if tp == model.MessageTypeDDL {
+			if _, err := decoder.NextDDLEvent(); err != nil {
+				log.Error("failed to get next DDL event", zap.Error(err))
+				return errors.Trace(err)
+			}
+			continue
+		}
+
 		if tp == model.MessageTypeRow {
 			row, err := decoder.NextRowChangedEvent()
 			if err != nil {
@@ -418,6 +469,16 @@ func (c *consumer) syncExecDMLEvents(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	content, err = encryption.Decrypt(c.masterKey, content)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.fileCompression != compression.None {
+		content, err = compression.Decode(c.fileCompression, content)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 	tableID := c.tableIDGenerator.generateFakeTableID(
 		key.Schema, key.Table, key.PartitionNum)
 	err = c.emitDMLEvents(ctx, tableID, tableDef, key, content)
@@ -834,6 +834,53 @@ var doc = `{
                 }
             }
         },
+        "/api/v2/changefeeds/import": {
+            "post": {
+                "description": "Create a changefeed exported from another TiCDC cluster sharing the same upstream, it starts from the exported checkpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Import a changefeed",
+                "parameters": [
+                    {
+                        "description": "exported changefeed",
+                        "name": "changefeed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.ExportedChangefeed"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.ChangeFeedInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v2/changefeeds/{changefeed_id}": {
             "get": {
                 "description": "get detail information of a changefeed",
@@ -992,6 +1039,168 @@ var doc = `{
                 }
             }
         },
+        "/api/v2/changefeeds/{changefeed_id}/export": {
+            "post": {
+                "description": "Export a stopped changefeed with a GC service safepoint set at its checkpoint, so that it can be imported into another TiCDC cluster sharing the same upstream",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Export a changefeed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.ExportedChangefeed"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/load_balance_plan": {
+            "get": {
+                "description": "get a dry run of the span moves planned by loads of a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Get load balance plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.LoadBalancePlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/move_table": {
+            "post": {
+                "description": "move a table span of a changefeed to the target capture",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Move a table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "description": "move table config",
+                        "name": "moveTableConfig",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.MoveTableConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v2/changefeeds/{changefeed_id}/pause": {
             "post": {
                 "description": "Pause a changefeed",
@@ -1043,6 +1252,57 @@ var doc = `{
                 }
             }
         },
+        "/api/v2/changefeeds/{changefeed_id}/rebalance": {
+            "post": {
+                "description": "rebalance the tables of a changefeed across all captures",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Rebalance tables",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v2/changefeeds/{changefeed_id}/resume": {
             "post": {
                 "description": "Resume a changefeed",
@@ -1116,7 +1376,58 @@ var doc = `{
                     "changefeed",
                     "v2"
                 ],
-                "summary": "Get synced status",
+                "summary": "Get synced status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "changefeed_id",
+                        "name": "changefeed_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.SyncedStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/changefeeds/{changefeed_id}/tables": {
+            "get": {
+                "description": "get the replication statuses of all table spans of a changefeed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "changefeed",
+                    "v2"
+                ],
+                "summary": "Get table statuses",
                 "parameters": [
                     {
                         "type": "string",
@@ -1136,7 +1447,10 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v2.SyncedStatus"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v2.TableSpanStatus"
+                            }
                         }
                     },
                     "400": {
@@ -1479,6 +1793,35 @@ var doc = `{
                 }
             }
         },
+        "config.ColumnMask": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is one of ` + "`" + `hash` + "`" + `, ` + "`" + `truncate` + "`" + `, ` + "`" + `nullify` + "`" + ` and ` + "`" + `redact` + "`" + `.",
+                    "type": "string"
+                },
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "length": {
+                    "description": "Length is used by the ` + "`" + `truncate` + "`" + ` and ` + "`" + `redact` + "`" + ` actions.",
+                    "type": "integer"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "salt": {
+                    "description": "Salt is only used by the ` + "`" + `hash` + "`" + ` action.",
+                    "type": "string"
+                }
+            }
+        },
         "config.ColumnSelector": {
             "type": "object",
             "properties": {
@@ -1496,6 +1839,23 @@ var doc = `{
                 }
             }
         },
+        "config.DeadLetterQueueConfig": {
+            "type": "object",
+            "properties": {
+                "max-error-count": {
+                    "description": "MaxErrorCount is the max number of failed transactions to tolerate,\nthe changefeed fails once it is exceeded.",
+                    "type": "integer"
+                },
+                "protocol": {
+                    "description": "Protocol is used to encode the failed transactions, canal-json by default.",
+                    "type": "string"
+                },
+                "uri": {
+                    "description": "URI is where failed transactions are written to. It can be a local path,\nan external storage URI such as s3://bucket/prefix, or a kafka URI\nwhose path is the topic.",
+                    "type": "string"
+                }
+            }
+        },
         "config.DebeziumConfig": {
             "type": "object",
             "properties": {
@@ -1585,6 +1945,9 @@ var doc = `{
                 "enable-tls": {
                     "type": "boolean"
                 },
+                "enable-transaction": {
+                    "type": "boolean"
+                },
                 "glue-schema-registry-config": {
                     "$ref": "#/definitions/config.GlueSchemaRegistryConfig"
                 },
@@ -1676,6 +2039,9 @@ var doc = `{
                 "sasl-user": {
                     "type": "string"
                 },
+                "transaction-marker-topic": {
+                    "type": "string"
+                },
                 "write-timeout": {
                     "type": "string"
                 }
@@ -1866,6 +2232,13 @@ var doc = `{
                 "cloud-storage-config": {
                     "$ref": "#/definitions/config.CloudStorageConfig"
                 },
+                "column-masks": {
+                    "description": "ColumnMasks is only available when the downstream is MQ or Storage.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/config.ColumnMask"
+                    }
+                },
                 "column-selectors": {
                     "type": "array",
                     "items": {
@@ -1884,6 +2257,10 @@ var doc = `{
                     "description": "DateSeparator is only available when the downstream is Storage.",
                     "type": "string"
                 },
+                "dead-letter-queue": {
+                    "description": "DeadLetterQueue is only available when the downstream is MySQL compatible.\nTransactions which fail to apply with non-retryable errors are written\nto it instead of stopping the changefeed.",
+                    "$ref": "#/definitions/config.DeadLetterQueueConfig"
+                },
                 "debezium": {
                     "description": "DebeziumConfig related configurations",
                     "$ref": "#/definitions/config.DebeziumConfig"
@@ -2297,6 +2674,20 @@ var doc = `{
                 }
             }
         },
+        "v2.CaptureLoad": {
+            "type": "object",
+            "properties": {
+                "capture_id": {
+                    "type": "string"
+                },
+                "load": {
+                    "type": "integer"
+                },
+                "planned_load": {
+                    "type": "integer"
+                }
+            }
+        },
         "v2.ChangeFeedInfo": {
             "type": "object",
             "properties": {
@@ -2328,6 +2719,9 @@ var doc = `{
                 "namespace": {
                     "type": "string"
                 },
+                "next_scheduled_transition": {
+                    "$ref": "#/definitions/v2.ScheduledTransition"
+                },
                 "resolved_ts": {
                     "type": "integer"
                 },
@@ -2429,10 +2823,18 @@ var doc = `{
         "v2.ChangefeedSchedulerConfig": {
             "type": "object",
             "properties": {
+                "enable_load_balance": {
+                    "description": "EnableLoadBalance set true to place and rebalance spans by their\nwrite and sink load instead of the number of spans on each TiCDC node.",
+                    "type": "boolean"
+                },
                 "enable_table_across_nodes": {
                     "description": "EnableTableAcrossNodes set true to split one table to multiple spans and\ndistribute to multiple TiCDC nodes.",
                     "type": "boolean"
                 },
+                "load_balance_threshold": {
+                    "description": "LoadBalanceThreshold is the ratio by which the load of a TiCDC node may\nexceed the average load before its spans are moved away.",
+                    "type": "number"
+                },
                 "region_threshold": {
                     "description": "RegionThreshold is the region count threshold of splitting a table.",
                     "type": "integer"
@@ -2446,6 +2848,15 @@ var doc = `{
         "v2.CloudStorageConfig": {
             "type": "object",
             "properties": {
+                "compression": {
+                    "type": "string"
+                },
+                "compression_level": {
+                    "type": "integer"
+                },
+                "encryption_master_key": {
+                    "type": "string"
+                },
                 "file_cleanup_cron_spec": {
                     "type": "string"
                 },
@@ -2467,6 +2878,9 @@ var doc = `{
                 "output_raw_change_event": {
                     "type": "boolean"
                 },
+                "table_format": {
+                    "type": "string"
+                },
                 "worker_count": {
                     "type": "integer"
                 }
@@ -2495,6 +2909,32 @@ var doc = `{
                 }
             }
         },
+        "v2.ColumnMask": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "matcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "salt": {
+                    "type": "string"
+                }
+            }
+        },
         "v2.ColumnSelector": {
             "type": "object",
             "properties": {
@@ -2518,9 +2958,15 @@ var doc = `{
                 "compression": {
                     "type": "string"
                 },
+                "compression_level": {
+                    "type": "integer"
+                },
                 "encoding_worker_num": {
                     "type": "integer"
                 },
+                "encryption_master_key": {
+                    "type": "string"
+                },
                 "flush_concurrency": {
                     "type": "integer"
                 },
@@ -2542,6 +2988,12 @@ var doc = `{
                 "meta_flush_interval": {
                     "type": "integer"
                 },
+                "retention_size": {
+                    "type": "integer"
+                },
+                "retention_time": {
+                    "type": "integer"
+                },
                 "storage": {
                     "type": "string"
                 },
@@ -2558,6 +3010,20 @@ var doc = `{
                 }
             }
         },
+        "v2.DeadLetterQueueConfig": {
+            "type": "object",
+            "properties": {
+                "max_error_count": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
         "v2.DebeziumConfig": {
             "type": "object",
             "properties": {
@@ -2632,6 +3098,41 @@ var doc = `{
                 }
             }
         },
+        "v2.ExportedChangefeed": {
+            "type": "object",
+            "properties": {
+                "changefeed_id": {
+                    "type": "string"
+                },
+                "checkpoint_ts": {
+                    "description": "the imported changefeed starts from the checkpoint ts",
+                    "type": "integer"
+                },
+                "creator_version": {
+                    "type": "string"
+                },
+                "gc_service_id": {
+                    "description": "the GC service safepoint that keeps the data after the checkpoint ts\nuntil the changefeed is imported, it is removed after the import",
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "replica_config": {
+                    "$ref": "#/definitions/v2.ReplicaConfig"
+                },
+                "sink_uri": {
+                    "description": "the credentials in the sink uri are masked, they must be filled in\nbefore the changefeed is imported",
+                    "type": "string"
+                },
+                "target_ts": {
+                    "type": "integer"
+                },
+                "upstream_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "v2.FilterConfig": {
             "type": "object",
             "properties": {
@@ -2717,6 +3218,9 @@ var doc = `{
                 "enable_tls": {
                     "type": "boolean"
                 },
+                "enable_transaction": {
+                    "type": "boolean"
+                },
                 "glue_schema_registry_config": {
                     "$ref": "#/definitions/v2.GlueSchemaRegistryConfig"
                 },
@@ -2807,6 +3311,9 @@ var doc = `{
                 "sasl_user": {
                     "type": "string"
                 },
+                "transaction_marker_topic": {
+                    "type": "string"
+                },
                 "write_timeout": {
                     "type": "string"
                 }
@@ -2821,11 +3328,63 @@ var doc = `{
                 "large_message_handle_compression": {
                     "type": "string"
                 },
+                "large_message_handle_compression_level": {
+                    "type": "integer"
+                },
                 "large_message_handle_option": {
                     "type": "string"
                 }
             }
         },
+        "v2.LoadBalanceMove": {
+            "type": "object",
+            "properties": {
+                "end_key": {
+                    "type": "string"
+                },
+                "load": {
+                    "type": "integer"
+                },
+                "source_capture_id": {
+                    "type": "string"
+                },
+                "start_key": {
+                    "description": "StartKey and EndKey are the hex encoded keys of the span.",
+                    "type": "string"
+                },
+                "table_id": {
+                    "type": "integer"
+                },
+                "target_capture_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.LoadBalancePlan": {
+            "type": "object",
+            "properties": {
+                "captures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.CaptureLoad"
+                    }
+                },
+                "enabled": {
+                    "description": "Enabled is true if spans are balanced by loads for the changefeed.",
+                    "type": "boolean"
+                },
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.LoadBalanceMove"
+                    }
+                },
+                "threshold": {
+                    "description": "Threshold is the ratio by which the load of a capture may exceed\nthe average load.",
+                    "type": "number"
+                }
+            }
+        },
         "v2.LogLevelReq": {
             "type": "object",
             "properties": {
@@ -2842,6 +3401,24 @@ var doc = `{
                 }
             }
         },
+        "v2.MoveTableConfig": {
+            "type": "object",
+            "properties": {
+                "table_id": {
+                    "type": "integer"
+                },
+                "start_key": {
+                    "description": "StartKey and EndKey are the hex encoded keys of the span to move,\nthey are only required when the table is split into multiple spans.",
+                    "type": "string"
+                },
+                "end_key": {
+                    "type": "string"
+                },
+                "target_capture_id": {
+                    "type": "string"
+                }
+            }
+        },
         "v2.MySQLConfig": {
             "type": "object",
             "properties": {
@@ -2900,6 +3477,23 @@ var doc = `{
                 }
             }
         },
+        "v2.PauseScheduleConfig": {
+            "type": "object",
+            "properties": {
+                "pause_at": {
+                    "description": "standard cron expression of the pause time",
+                    "type": "string"
+                },
+                "resume_at": {
+                    "description": "standard cron expression of the resume time",
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "the time zone the cron expressions are evaluated in, the local time zone of the owner is used if it is empty",
+                    "type": "string"
+                }
+            }
+        },
         "v2.ProcessorCommonInfo": {
             "type": "object",
             "properties": {
@@ -3026,6 +3620,9 @@ var doc = `{
                 "consistent": {
                     "$ref": "#/definitions/v2.ConsistentConfig"
                 },
+                "enable_snapshot_backfill": {
+                    "type": "boolean"
+                },
                 "enable_sync_point": {
                     "type": "boolean"
                 },
@@ -3050,12 +3647,21 @@ var doc = `{
                 "mounter": {
                     "$ref": "#/definitions/v2.MounterConfig"
                 },
+                "pause_schedule": {
+                    "$ref": "#/definitions/v2.PauseScheduleConfig"
+                },
+                "priority": {
+                    "type": "string"
+                },
                 "scheduler": {
                     "$ref": "#/definitions/v2.ChangefeedSchedulerConfig"
                 },
                 "sink": {
                     "$ref": "#/definitions/v2.SinkConfig"
                 },
+                "sort_disk_quota": {
+                    "type": "integer"
+                },
                 "sql_mode": {
                     "description": "Deprecated: we don't use this field since v8.0.0.",
                     "type": "string"
@@ -3117,6 +3723,17 @@ var doc = `{
                 }
             }
         },
+        "v2.ScheduledTransition": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
         "v2.ServerStatus": {
             "type": "object",
             "properties": {
@@ -3152,6 +3769,12 @@ var doc = `{
                 "cloud_storage_config": {
                     "$ref": "#/definitions/v2.CloudStorageConfig"
                 },
+                "column_masks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.ColumnMask"
+                    }
+                },
                 "column_selectors": {
                     "type": "array",
                     "items": {
@@ -3167,6 +3790,9 @@ var doc = `{
                 "date_separator": {
                     "type": "string"
                 },
+                "dead_letter_queue": {
+                    "$ref": "#/definitions/v2.DeadLetterQueueConfig"
+                },
                 "debezium": {
                     "$ref": "#/definitions/v2.DebeziumConfig"
                 },
@@ -3270,6 +3896,48 @@ var doc = `{
                     "type": "integer"
                 }
             }
+        },
+        "v2.TableSpanStatus": {
+            "type": "object",
+            "properties": {
+                "table_id": {
+                    "type": "integer"
+                },
+                "start_key": {
+                    "description": "StartKey and EndKey are the hex encoded keys of the span.",
+                    "type": "string"
+                },
+                "end_key": {
+                    "type": "string"
+                },
+                "capture_id": {
+                    "description": "CaptureID is the capture that is currently replicating the span.",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "State is the replication state of the span in the scheduler,\nit is one of Absent, Prepare, Commit, Replicating and Removing.",
+                    "type": "string"
+                },
+                "checkpoint_ts": {
+                    "type": "integer"
+                },
+                "resolved_ts": {
+                    "type": "integer"
+                },
+                "sorter_max_commit_ts": {
+                    "description": "SorterMaxCommitTs and SorterMaxResolvedTs are the max commit ts and\nthe max resolved ts received by the sorter.",
+                    "type": "integer"
+                },
+                "sorter_max_resolved_ts": {
+                    "type": "integer"
+                },
+                "sink_rows_per_second": {
+                    "type": "integer"
+                },
+                "sink_bytes_per_second": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
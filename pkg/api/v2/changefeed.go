@@ -47,6 +47,16 @@ type ChangefeedInterface interface {
 	Get(ctx context.Context, namespace string, name string) (*v2.ChangeFeedInfo, error)
 	// List lists all changefeeds
 	List(ctx context.Context, namespace string, state string) ([]v2.ChangefeedCommonInfo, error)
+	// Tables lists the replication statuses of all table spans of a changefeed
+	Tables(ctx context.Context, namespace string, name string) ([]v2.TableSpanStatus, error)
+	// MoveTable moves a table span of a changefeed to the target capture
+	MoveTable(ctx context.Context, cfg *v2.MoveTableConfig, namespace string, name string) error
+	// Rebalance rebalances the tables of a changefeed across all captures
+	Rebalance(ctx context.Context, namespace string, name string) error
+	// Export exports a stopped changefeed
+	Export(ctx context.Context, namespace string, name string) (*v2.ExportedChangefeed, error)
+	// Import creates a changefeed exported from another cluster
+	Import(ctx context.Context, exported *v2.ExportedChangefeed) (*v2.ChangeFeedInfo, error)
 }
 
 // changefeeds implements ChangefeedInterface
@@ -157,3 +167,65 @@ func (c *changefeeds) List(ctx context.Context,
 		Into(result)
 	return result.Items, err
 }
+
+// Tables lists the replication statuses of all table spans of a changefeed
+func (c *changefeeds) Tables(ctx context.Context,
+	namespace string, name string,
+) ([]v2.TableSpanStatus, error) {
+	err := model.ValidateChangefeedID(name)
+	if err != nil {
+		return nil, err
+	}
+	result := &v2.ListResponse[v2.TableSpanStatus]{}
+	u := fmt.Sprintf("changefeeds/%s/tables?namespace=%s", name, namespace)
+	err = c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result.Items, err
+}
+
+// MoveTable moves a table span of a changefeed to the target capture
+func (c *changefeeds) MoveTable(ctx context.Context,
+	cfg *v2.MoveTableConfig, namespace string, name string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/move_table?namespace=%s", name, namespace)
+	return c.client.Post().
+		WithURI(u).
+		WithBody(cfg).
+		Do(ctx).Error()
+}
+
+// Rebalance rebalances the tables of a changefeed across all captures
+func (c *changefeeds) Rebalance(ctx context.Context,
+	namespace string, name string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/rebalance?namespace=%s", name, namespace)
+	return c.client.Post().
+		WithURI(u).
+		Do(ctx).Error()
+}
+
+func (c *changefeeds) Export(ctx context.Context,
+	namespace string, name string,
+) (*v2.ExportedChangefeed, error) {
+	result := &v2.ExportedChangefeed{}
+	u := fmt.Sprintf("changefeeds/%s/export?namespace=%s", name, namespace)
+	err := c.client.Post().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *changefeeds) Import(ctx context.Context,
+	exported *v2.ExportedChangefeed,
+) (*v2.ChangeFeedInfo, error) {
+	result := &v2.ChangeFeedInfo{}
+	err := c.client.Post().
+		WithURI("changefeeds/import").
+		WithBody(exported).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
@@ -102,6 +102,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) Delete(ctx, namespace, name inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockChangefeedInterface)(nil).Delete), ctx, namespace, name)
 }
 
+// Export mocks base method.
+func (m *MockChangefeedInterface) Export(ctx context.Context, namespace, name string) (*v2.ExportedChangefeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, namespace, name)
+	ret0, _ := ret[0].(*v2.ExportedChangefeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockChangefeedInterfaceMockRecorder) Export(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockChangefeedInterface)(nil).Export), ctx, namespace, name)
+}
+
 // Get mocks base method.
 func (m *MockChangefeedInterface) Get(ctx context.Context, namespace, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +132,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) Get(ctx, namespace, name interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChangefeedInterface)(nil).Get), ctx, namespace, name)
 }
 
+// Import mocks base method.
+func (m *MockChangefeedInterface) Import(ctx context.Context, exported *v2.ExportedChangefeed) (*v2.ChangeFeedInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, exported)
+	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockChangefeedInterfaceMockRecorder) Import(ctx, exported interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockChangefeedInterface)(nil).Import), ctx, exported)
+}
+
 // List mocks base method.
 func (m *MockChangefeedInterface) List(ctx context.Context, namespace, state string) ([]v2.ChangefeedCommonInfo, error) {
 	m.ctrl.T.Helper()
@@ -132,6 +162,20 @@ func (mr *MockChangefeedInterfaceMockRecorder) List(ctx, namespace, state interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockChangefeedInterface)(nil).List), ctx, namespace, state)
 }
 
+// MoveTable mocks base method.
+func (m *MockChangefeedInterface) MoveTable(ctx context.Context, cfg *v2.MoveTableConfig, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTable", ctx, cfg, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveTable indicates an expected call of MoveTable.
+func (mr *MockChangefeedInterfaceMockRecorder) MoveTable(ctx, cfg, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTable", reflect.TypeOf((*MockChangefeedInterface)(nil).MoveTable), ctx, cfg, namespace, name)
+}
+
 // Pause mocks base method.
 func (m *MockChangefeedInterface) Pause(ctx context.Context, namespace, name string) error {
 	m.ctrl.T.Helper()
@@ -146,6 +190,20 @@ func (mr *MockChangefeedInterfaceMockRecorder) Pause(ctx, namespace, name interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockChangefeedInterface)(nil).Pause), ctx, namespace, name)
 }
 
+// Rebalance mocks base method.
+func (m *MockChangefeedInterface) Rebalance(ctx context.Context, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebalance", ctx, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rebalance indicates an expected call of Rebalance.
+func (mr *MockChangefeedInterfaceMockRecorder) Rebalance(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebalance", reflect.TypeOf((*MockChangefeedInterface)(nil).Rebalance), ctx, namespace, name)
+}
+
 // Resume mocks base method.
 func (m *MockChangefeedInterface) Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, namespace, name string) error {
 	m.ctrl.T.Helper()
@@ -160,6 +218,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) Resume(ctx, cfg, namespace, name
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockChangefeedInterface)(nil).Resume), ctx, cfg, namespace, name)
 }
 
+// Tables mocks base method.
+func (m *MockChangefeedInterface) Tables(ctx context.Context, namespace, name string) ([]v2.TableSpanStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tables", ctx, namespace, name)
+	ret0, _ := ret[0].([]v2.TableSpanStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tables indicates an expected call of Tables.
+func (mr *MockChangefeedInterfaceMockRecorder) Tables(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tables", reflect.TypeOf((*MockChangefeedInterface)(nil).Tables), ctx, namespace, name)
+}
+
 // Update mocks base method.
 func (m *MockChangefeedInterface) Update(ctx context.Context, cfg *v2.ChangefeedConfig, namespace, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,67 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "ticdc"
+	subsystem = "redo_standby"
+)
+
+var (
+	// standbyAppliedTsGauge records the physical time(ms) of the applied ts.
+	standbyAppliedTsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "applied_ts",
+		Help:      "The physical time(ms) of the max commit ts applied by the standby applier",
+	})
+
+	// standbyLagGauge records the lag between the applied ts and the current time.
+	standbyLagGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "lag_seconds",
+		Help:      "The lag(s) between the applied ts and the current time",
+	})
+
+	// standbyPendingLagGauge records the lag between the applied ts and
+	// the resolved ts in the redo meta.
+	standbyPendingLagGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "pending_lag_seconds",
+		Help:      "The lag(s) between the applied ts and the resolved ts of the redo logs",
+	})
+
+	// standbyApplyDurationHistogram records the duration of applying a batch of redo logs.
+	standbyApplyDurationHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "apply_duration_seconds",
+		Help:      "The latency distributions of applying a batch of redo logs",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2.0, 16),
+	})
+)
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(standbyAppliedTsGauge)
+	registry.MustRegister(standbyLagGauge)
+	registry.MustRegister(standbyPendingLagGauge)
+	registry.MustRegister(standbyApplyDurationHistogram)
+}
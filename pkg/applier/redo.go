@@ -61,6 +61,24 @@ type RedoApplierConfig struct {
 	SinkURI string
 	Storage string
 	Dir     string
+	// EncryptionMasterKey is the URI of the master key used to decrypt the
+	// encrypted redo logs.
+	EncryptionMasterKey string
+	// StartTs is the commit ts after which the redo logs are applied, the
+	// checkpoint ts in the redo meta is used if it's zero.
+	StartTs uint64
+	// StartTsFromMeta indicates the StartTs is the checkpoint ts read from the
+	// redo meta, so the DDL at the StartTs is applied again.
+	StartTsFromMeta bool
+	// TargetTs is the max commit ts of the redo logs to apply, the resolved ts
+	// in the redo meta is used if it's zero.
+	TargetTs uint64
+	// FilterRules selects the tables to apply, it is in the same format as
+	// `FilterConfig.Rules`. All tables are applied if it's empty.
+	FilterRules []string
+	// SQLFile is the file the replayed statements are written to,
+	// they are executed in the sink if it's empty.
+	SQLFile string
 }
 
 // RedoApplier implements a redo log applier
@@ -72,6 +90,11 @@ type RedoApplier struct {
 	ddlSink         ddlsink.Sink
 	appliedDDLCount uint64
 
+	// sqlFile and sqlWriter are used instead of the sinks
+	// if the statements are written to a SQL file.
+	sqlFile   *os.File
+	sqlWriter *SQLWriter
+
 	memQuota     *memquota.MemQuota
 	pendingQuota uint64
 
@@ -109,9 +132,14 @@ func (rac *RedoApplierConfig) toLogReaderConfig() (string, *reader.LogReaderConf
 		uri.Scheme = "file"
 	}
 	cfg := &reader.LogReaderConfig{
-		URI:                *uri,
-		Dir:                rac.Dir,
-		UseExternalStorage: redo.IsExternalStorage(uri.Scheme),
+		URI:                 *uri,
+		Dir:                 rac.Dir,
+		UseExternalStorage:  redo.IsExternalStorage(uri.Scheme),
+		EncryptionMasterKey: rac.EncryptionMasterKey,
+		StartTs:             rac.StartTs,
+		StartTsFromMeta:     rac.StartTsFromMeta,
+		TargetTs:            rac.TargetTs,
+		FilterRules:         rac.FilterRules,
 	}
 	return uri.Scheme, cfg, nil
 }
@@ -167,10 +195,19 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	log.Info("apply redo log starts",
 		zap.Uint64("checkpointTs", checkpointTs),
 		zap.Uint64("resolvedTs", resolvedTs))
-	if err := ra.initSink(ctx); err != nil {
-		return err
+	if ra.cfg.SQLFile != "" {
+		ra.sqlFile, err = os.OpenFile(ra.cfg.SQLFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		defer ra.sqlFile.Close() //nolint:errcheck
+		ra.sqlWriter = NewSQLWriter(ra.sqlFile)
+	} else {
+		if err := ra.initSink(ctx); err != nil {
+			return err
+		}
+		defer ra.sinkFactory.Close()
 	}
-	defer ra.sinkFactory.Close()
 
 	shouldApplyDDL := func(row *model.RowChangedEvent, ddl *model.DDLEvent) bool {
 		if ddl == nil {
@@ -219,6 +256,14 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 		}
 		ra.tableSinks[tableID].Close()
 	}
+	if ra.sqlWriter != nil {
+		if err := ra.sqlWriter.Flush(); err != nil {
+			return err
+		}
+		if err := ra.sqlFile.Sync(); err != nil {
+			return errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+	}
 
 	log.Info("apply redo log finishes",
 		zap.Uint64("appliedLogCount", ra.appliedLogCount),
@@ -284,6 +329,13 @@ func (ra *RedoApplier) applyDDL(
 	if shouldSkip() {
 		return nil
 	}
+	if ra.sqlWriter != nil {
+		if err := ra.sqlWriter.WriteDDL(ddl); err != nil {
+			return err
+		}
+		ra.appliedDDLCount++
+		return nil
+	}
 	log.Warn("apply DDL", zap.Any("ddl", ddl))
 	// Wait all tables to flush data before applying DDL.
 	// TODO: only block tables that are affected by this DDL.
@@ -302,6 +354,14 @@ func (ra *RedoApplier) applyDDL(
 func (ra *RedoApplier) applyRow(
 	row *model.RowChangedEvent, checkpointTs model.Ts,
 ) error {
+	if ra.sqlWriter != nil {
+		if err := ra.sqlWriter.WriteRow(row); err != nil {
+			return err
+		}
+		ra.appliedLogCount++
+		return nil
+	}
+
 	rowSize := uint64(row.ApproximateBytes())
 	if rowSize > ra.pendingQuota {
 		if err := ra.resetQuota(uint64(row.ApproximateBytes())); err != nil {
@@ -423,6 +483,12 @@ func createRedoReaderImpl(ctx context.Context, cfg *RedoApplierConfig) (reader.R
 	return reader.NewRedoLogReader(ctx, storageType, readerCfg)
 }
 
+// NewRedoLogReader creates a redo log reader with the given config,
+// it's used to inspect the redo logs without applying them.
+func NewRedoLogReader(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+	return createRedoReader(ctx, cfg)
+}
+
 // tempTxnInsertEventStorage is used to store insert events in the same transaction
 // once you begin to read events from storage, you should read all events before you write new events
 type tempTxnInsertEventStorage struct {
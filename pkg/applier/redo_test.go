@@ -18,6 +18,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -509,6 +510,109 @@ func TestApplyMeetSinkError(t *testing.T) {
 	require.Regexp(t, "CDC:ErrMySQLConnectionError", err)
 }
 
+func TestApplyToSQLFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	redoLogCh := make(chan *model.RowChangedEvent, 1024)
+	ddlEventCh := make(chan *model.DDLEvent, 1024)
+	createMockReader := func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh), nil
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = createMockReader
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	tableInfo := model.BuildTableInfo("test", "t1", []*model.Column{
+		{
+			Name: "a",
+			Type: mysqlParser.TypeLong,
+			Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+		}, {
+			Name: "b",
+			Type: mysqlParser.TypeString,
+			Flag: 0,
+		},
+	}, [][]int{{0}})
+	genColumns := func(a int, b string) []*model.ColumnData {
+		return model.Columns2ColumnDatas([]*model.Column{
+			{Name: "a", Value: a},
+			{Name: "b", Value: b},
+		}, tableInfo)
+	}
+	dmls := []*model.RowChangedEvent{
+		{StartTs: 1100, CommitTs: 1200, TableInfo: tableInfo, Columns: genColumns(1, "2")},
+		{StartTs: 1100, CommitTs: 1200, TableInfo: tableInfo, Columns: genColumns(2, "it's")},
+		{StartTs: 1150, CommitTs: 1250, TableInfo: tableInfo, PreColumns: genColumns(2, "it's")},
+		// update event which doesn't modify handle key
+		{
+			StartTs: 1160, CommitTs: 1260, TableInfo: tableInfo,
+			PreColumns: genColumns(1, "2"), Columns: genColumns(1, "3"),
+		},
+	}
+	for _, dml := range dmls {
+		redoLogCh <- dml
+	}
+	ddls := []*model.DDLEvent{
+		{
+			CommitTs: checkpointTs,
+			TableInfo: &model.TableInfo{
+				TableName: model.TableName{Schema: "test", Table: "checkpoint"},
+			},
+			Query: "create table checkpoint(id int)",
+			Type:  timodel.ActionCreateTable,
+		},
+		{
+			CommitTs: resolvedTs,
+			TableInfo: &model.TableInfo{
+				TableName: model.TableName{Schema: "test", Table: "resolved"},
+			},
+			Query: "create table resolved(id int not null unique key);",
+			Type:  timodel.ActionCreateTable,
+		},
+	}
+	for _, ddl := range ddls {
+		ddlEventCh <- ddl
+	}
+	close(redoLogCh)
+	close(ddlEventCh)
+
+	dir := t.TempDir()
+	sqlFile := filepath.Join(dir, "redo.sql")
+	cfg := &RedoApplierConfig{
+		Dir:     dir,
+		SQLFile: sqlFile,
+	}
+	ap := NewRedoApplier(cfg)
+	require.NoError(t, ap.Apply(ctx))
+
+	data, err := os.ReadFile(sqlFile)
+	require.NoError(t, err)
+	require.Equal(t, "-- DDL commit-ts: 1000\n"+
+		"USE `test`;\n"+
+		"create table checkpoint(id int);\n"+
+		"-- DML start-ts: 1100, commit-ts: 1200\n"+
+		"BEGIN;\n"+
+		"REPLACE INTO `test`.`t1` (`a`,`b`) VALUES (1,'2');\n"+
+		"REPLACE INTO `test`.`t1` (`a`,`b`) VALUES (2,'it\\'s');\n"+
+		"COMMIT;\n"+
+		"-- DML start-ts: 1150, commit-ts: 1250\n"+
+		"BEGIN;\n"+
+		"DELETE FROM `test`.`t1` WHERE `a` = 2 LIMIT 1;\n"+
+		"COMMIT;\n"+
+		"-- DML start-ts: 1160, commit-ts: 1260\n"+
+		"BEGIN;\n"+
+		"REPLACE INTO `test`.`t1` (`a`,`b`) VALUES (1,'3');\n"+
+		"COMMIT;\n"+
+		"-- DDL commit-ts: 2000\n"+
+		"USE `test`;\n"+
+		"create table resolved(id int not null unique key);\n", string(data))
+}
+
 func getMockDB(t *testing.T) *sql.DB {
 	// normal db
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
@@ -0,0 +1,168 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+)
+
+// SQLWriter writes the redo logs as SQL statements, so that they can be
+// inspected or executed later instead of being applied in the sink.
+type SQLWriter struct {
+	writer *bufio.Writer
+	// inTxn indicates whether a transaction is opened by `BEGIN`.
+	inTxn       bool
+	txnStartTs  model.Ts
+	txnCommitTs model.Ts
+}
+
+// NewSQLWriter creates a SQLWriter which writes the statements to w.
+func NewSQLWriter(w io.Writer) *SQLWriter {
+	return &SQLWriter{writer: bufio.NewWriter(w)}
+}
+
+// WriteDDL writes the DDL statement, the transaction opened before is committed first.
+func (w *SQLWriter) WriteDDL(ddl *model.DDLEvent) error {
+	if err := w.commitTxn(); err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- DDL commit-ts: %d\n", ddl.CommitTs)
+	if ddl.TableInfo != nil && ddl.TableInfo.TableName.Schema != "" {
+		b.WriteString(sqlescape.MustEscapeSQL("USE %n;\n", ddl.TableInfo.TableName.Schema))
+	}
+	b.WriteString(strings.TrimRight(strings.TrimSpace(ddl.Query), ";"))
+	b.WriteString(";\n")
+	return w.write(b.String())
+}
+
+// WriteRow writes the row as a `REPLACE` or `DELETE` statement, an update is
+// written as a `DELETE` of the old row followed by a `REPLACE`, so that the old
+// row is removed even if its handle key is changed. Rows in the same upstream
+// transaction are wrapped by `BEGIN` and `COMMIT`.
+func (w *SQLWriter) WriteRow(row *model.RowChangedEvent) error {
+	if w.inTxn && (w.txnStartTs != row.StartTs || w.txnCommitTs != row.CommitTs) {
+		if err := w.commitTxn(); err != nil {
+			return err
+		}
+	}
+	if !w.inTxn {
+		header := fmt.Sprintf("-- DML start-ts: %d, commit-ts: %d\nBEGIN;\n", row.StartTs, row.CommitTs)
+		if err := w.write(header); err != nil {
+			return err
+		}
+		w.inTxn = true
+		w.txnStartTs = row.StartTs
+		w.txnCommitTs = row.CommitTs
+	}
+
+	if row.IsDelete() || row.IsUpdate() {
+		stmt, err := genDeleteSQL(row)
+		if err != nil {
+			return errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		if err := w.write(stmt + ";\n"); err != nil {
+			return err
+		}
+	}
+	if row.IsDelete() {
+		return nil
+	}
+	stmt, err := genReplaceSQL(row)
+	if err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	return w.write(stmt + ";\n")
+}
+
+// Flush commits the opened transaction and flushes all the buffered statements.
+func (w *SQLWriter) Flush() error {
+	if err := w.commitTxn(); err != nil {
+		return err
+	}
+	if err := w.writer.Flush(); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	return nil
+}
+
+func (w *SQLWriter) commitTxn() error {
+	if !w.inTxn {
+		return nil
+	}
+	w.inTxn = false
+	return w.write("COMMIT;\n")
+}
+
+func (w *SQLWriter) write(s string) error {
+	if _, err := w.writer.WriteString(s); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	return nil
+}
+
+func genReplaceSQL(row *model.RowChangedEvent) (string, error) {
+	var placeholders []string
+	names := []interface{}{row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName()}
+	var values []interface{}
+	for _, col := range row.GetColumns() {
+		if col == nil || col.Flag.IsGeneratedColumn() {
+			continue
+		}
+		placeholders = append(placeholders, "%?")
+		names = append(names, col.Name)
+		values = append(values, col.Value)
+	}
+	format := fmt.Sprintf("REPLACE INTO %%n.%%n (%s) VALUES (%s)",
+		strings.TrimSuffix(strings.Repeat("%n,", len(placeholders)), ","),
+		strings.Join(placeholders, ","))
+	return sqlescape.EscapeSQL(format, append(names, values...)...)
+}
+
+func genDeleteSQL(row *model.RowChangedEvent) (string, error) {
+	cols := row.GetPreColumns()
+	// Use the handle key columns to locate the row if there are any,
+	// otherwise all the columns are used.
+	hasHandleKey := false
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			hasHandleKey = true
+			break
+		}
+	}
+	var conds []string
+	args := []interface{}{row.TableInfo.GetSchemaName(), row.TableInfo.GetTableName()}
+	for _, col := range cols {
+		if col == nil || col.Flag.IsGeneratedColumn() ||
+			(hasHandleKey && !col.Flag.IsHandleKey()) {
+			continue
+		}
+		if col.Value == nil {
+			conds = append(conds, "%n IS NULL")
+			args = append(args, col.Name)
+		} else {
+			conds = append(conds, "%n = %?")
+			args = append(args, col.Name, col.Value)
+		}
+	}
+	format := fmt.Sprintf("DELETE FROM %%n.%%n WHERE %s LIMIT 1", strings.Join(conds, " AND "))
+	return sqlescape.EscapeSQL(format, args...)
+}
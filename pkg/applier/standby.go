@@ -0,0 +1,185 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// DefaultStandbyCheckInterval is the default interval to check new redo logs.
+const DefaultStandbyCheckInterval = 5 * time.Second
+
+// StandbyApplierConfig is the configuration used by a standby applier.
+type StandbyApplierConfig struct {
+	// RedoApplierConfig is used to apply each batch of the redo logs, its
+	// StartTs is only used if there is no applied ts in the state file.
+	// The standby cluster is expected to contain all the changes at and
+	// before the start ts, the checkpoint ts in the redo meta is used if
+	// the start ts is zero.
+	RedoApplierConfig
+	// StateFile is the file the applied ts is persisted to, so that the
+	// standby applier can resume from it after restarting.
+	StateFile string
+	// CheckInterval is the interval to check whether there are new redo logs.
+	CheckInterval time.Duration
+}
+
+// StandbyApplier tails the redo logs in the external storage and applies
+// them to a standby cluster continuously. Each round it applies the redo
+// logs in the range (appliedTs, resolvedTs] and then advances the applied
+// ts to the resolved ts in the redo meta.
+//
+// Logs before the checkpoint ts in the redo meta are deleted by the upstream
+// changefeed, so `consistent.retention-time` or `consistent.retention-size`
+// should be large enough to cover the lag of the standby applier, otherwise
+// the standby applier fails since the logs it needs are deleted.
+type StandbyApplier struct {
+	cfg       *StandbyApplierConfig
+	appliedTs atomic.Uint64
+}
+
+// NewStandbyApplier creates a new StandbyApplier instance.
+func NewStandbyApplier(cfg *StandbyApplierConfig) *StandbyApplier {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultStandbyCheckInterval
+	}
+	return &StandbyApplier{cfg: cfg}
+}
+
+// AppliedTs returns the max commit ts of the applied redo logs.
+func (s *StandbyApplier) AppliedTs() uint64 {
+	return s.appliedTs.Load()
+}
+
+// Run applies the redo logs continuously until the context is canceled.
+func (s *StandbyApplier) Run(ctx context.Context) error {
+	appliedTs, err := loadAppliedTs(s.cfg.StateFile)
+	if err != nil {
+		return err
+	}
+	if appliedTs == 0 {
+		appliedTs = s.cfg.StartTs
+	}
+	s.appliedTs.Store(appliedTs)
+	log.Info("redo standby applier starts",
+		zap.Uint64("appliedTs", appliedTs),
+		zap.String("stateFile", s.cfg.StateFile),
+		zap.Duration("checkInterval", s.cfg.CheckInterval))
+
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.applyOnce(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyOnce applies the redo logs after the applied ts up to the resolved ts
+// in the redo meta.
+func (s *StandbyApplier) applyOnce(ctx context.Context) error {
+	cfg := s.cfg.RedoApplierConfig
+	cfg.StartTs = s.appliedTs.Load()
+	cfg.TargetTs = 0
+	// The checkpoint ts in the redo meta is used if nothing is applied yet.
+	startTsFromMeta := cfg.StartTs == 0
+	startTs, resolvedTs, err := NewRedoApplier(&cfg).ReadMeta(ctx)
+	if err != nil {
+		return err
+	}
+	s.updateMetrics(startTs, resolvedTs)
+	if resolvedTs <= startTs {
+		return nil
+	}
+
+	// Pin the range, so that the checkpoint ts and resolved ts advanced
+	// during applying are handled in the next round.
+	cfg.StartTs = startTs
+	cfg.StartTsFromMeta = startTsFromMeta
+	cfg.TargetTs = resolvedTs
+	start := time.Now()
+	if err := NewRedoApplier(&cfg).Apply(ctx); err != nil {
+		return err
+	}
+	if err := saveAppliedTs(s.cfg.StateFile, resolvedTs); err != nil {
+		return err
+	}
+	s.appliedTs.Store(resolvedTs)
+	standbyApplyDurationHistogram.Observe(time.Since(start).Seconds())
+	s.updateMetrics(resolvedTs, resolvedTs)
+	log.Info("redo standby applier applies redo logs",
+		zap.Uint64("startTs", startTs),
+		zap.Uint64("appliedTs", resolvedTs),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+func (s *StandbyApplier) updateMetrics(appliedTs, resolvedTs uint64) {
+	appliedPhysical := oracle.ExtractPhysical(appliedTs)
+	standbyAppliedTsGauge.Set(float64(appliedPhysical))
+	standbyLagGauge.Set(time.Since(oracle.GetTimeFromTS(appliedTs)).Seconds())
+	pendingLag := oracle.ExtractPhysical(resolvedTs) - appliedPhysical
+	standbyPendingLagGauge.Set(float64(pendingLag) / 1000)
+}
+
+// loadAppliedTs reads the applied ts from the state file,
+// it returns 0 if the file does not exist.
+func loadAppliedTs(stateFile string) (uint64, error) {
+	if stateFile == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	appliedTs, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	return appliedTs, nil
+}
+
+// saveAppliedTs persists the applied ts to the state file atomically.
+func saveAppliedTs(stateFile string, appliedTs uint64) error {
+	if stateFile == "" {
+		return nil
+	}
+	tmpFile := stateFile + ".tmp"
+	data := []byte(strconv.FormatUint(appliedTs, 10) + "\n")
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	if err := os.Rename(tmpFile, stateFile); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandbyApplier(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	var startTsList, targetTsList []uint64
+	var startTsFromMetaList []bool
+	createMockReader := func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		startTs := cfg.StartTs
+		if startTs == 0 {
+			startTs = checkpointTs
+		}
+		if cfg.TargetTs != 0 {
+			startTsList = append(startTsList, cfg.StartTs)
+			targetTsList = append(targetTsList, cfg.TargetTs)
+			startTsFromMetaList = append(startTsFromMetaList, cfg.StartTsFromMeta)
+		}
+		redoLogCh := make(chan *model.RowChangedEvent)
+		ddlEventCh := make(chan *model.DDLEvent)
+		close(redoLogCh)
+		close(ddlEventCh)
+		return NewMockReader(startTs, resolvedTs, redoLogCh, ddlEventCh), nil
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = createMockReader
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	dir := t.TempDir()
+	cfg := &StandbyApplierConfig{
+		RedoApplierConfig: RedoApplierConfig{
+			Dir:     dir,
+			SQLFile: filepath.Join(dir, "standby.sql"),
+		},
+		StateFile: filepath.Join(dir, "applied_ts"),
+	}
+	s := NewStandbyApplier(cfg)
+	require.Equal(t, DefaultStandbyCheckInterval, cfg.CheckInterval)
+
+	// apply logs in (checkpointTs, resolvedTs]
+	require.NoError(t, s.applyOnce(ctx))
+	require.Equal(t, resolvedTs, s.AppliedTs())
+	appliedTs, err := loadAppliedTs(cfg.StateFile)
+	require.NoError(t, err)
+	require.Equal(t, resolvedTs, appliedTs)
+
+	// no new logs
+	require.NoError(t, s.applyOnce(ctx))
+	require.Len(t, startTsList, 1)
+
+	// apply logs after the applied ts
+	resolvedTs = 3000
+	require.NoError(t, s.applyOnce(ctx))
+	require.Equal(t, []uint64{1000, 2000}, startTsList)
+	require.Equal(t, []uint64{2000, 3000}, targetTsList)
+	// the DDL at the checkpoint ts is applied again only in the first round.
+	require.Equal(t, []bool{true, false}, startTsFromMetaList)
+
+	// resume from the state file after restarting
+	s = NewStandbyApplier(cfg)
+	runCtx, runCancel := context.WithCancel(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Run(runCtx)
+	}()
+	require.Eventually(t, func() bool {
+		return s.AppliedTs() == resolvedTs
+	}, time.Second, 10*time.Millisecond)
+	runCancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+	require.Len(t, startTsList, 2)
+}
+
+func TestLoadAndSaveAppliedTs(t *testing.T) {
+	t.Parallel()
+
+	stateFile := filepath.Join(t.TempDir(), "applied_ts")
+	appliedTs, err := loadAppliedTs(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), appliedTs)
+
+	require.NoError(t, saveAppliedTs(stateFile, 42))
+	appliedTs, err = loadAppliedTs(stateFile)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), appliedTs)
+
+	// an empty state file disables persisting the applied ts
+	require.NoError(t, saveAppliedTs("", 42))
+	appliedTs, err = loadAppliedTs("")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), appliedTs)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedExportCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cf := mock.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeeds: cf}
+	exported := &v2.ExportedChangefeed{
+		Namespace:    "default",
+		ID:           "abc",
+		SinkURI:      "blackhole://",
+		CheckpointTs: 100,
+		GCServiceID:  "ticdc-default-1-exporting-default_abc",
+	}
+
+	// export to stdout
+	cmd := newCmdExportChangefeed(f)
+	cf.EXPECT().Export(gomock.Any(), "default", "abc").Return(exported, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{"export", "--changefeed-id=abc"}
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), `"checkpoint_ts": 100`)
+
+	// export to a file
+	file := filepath.Join(t.TempDir(), "abc.json")
+	cf.EXPECT().Export(gomock.Any(), "default", "abc").Return(exported, nil)
+	o := newExportChangefeedOptions()
+	o.changefeedID = "abc"
+	o.namespace = "default"
+	o.output = file
+	require.Nil(t, o.complete(f))
+	require.Nil(t, o.run(cmd))
+	data, err := os.ReadFile(file)
+	require.Nil(t, err)
+	actual := &v2.ExportedChangefeed{}
+	require.Nil(t, json.Unmarshal(data, actual))
+	require.Equal(t, exported, actual)
+
+	cf.EXPECT().Export(gomock.Any(), "default", "abc").Return(nil, errors.New("test"))
+	require.NotNil(t, o.run(cmd))
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// moveTableChangefeedOptions defines flags for the `cli changefeed move-table` command.
+type moveTableChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID  string
+	namespace     string
+	tableID       int64
+	startKey      string
+	endKey        string
+	targetCapture string
+}
+
+// newMoveTableChangefeedOptions creates new options for the `cli changefeed move-table` command.
+func newMoveTableChangefeedOptions() *moveTableChangefeedOptions {
+	return &moveTableChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *moveTableChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().Int64Var(&o.tableID, "table-id", 0, "ID of the table to move")
+	cmd.PersistentFlags().StringVar(&o.startKey, "start-key", "",
+		"Hex encoded start key of the span to move, required if the table is split into multiple spans")
+	cmd.PersistentFlags().StringVar(&o.endKey, "end-key", "",
+		"Hex encoded end key of the span to move, required if the table is split into multiple spans")
+	cmd.PersistentFlags().StringVar(&o.targetCapture, "target-capture", "", "ID of the capture to move the table to")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("table-id")
+	_ = cmd.MarkPersistentFlagRequired("target-capture")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *moveTableChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed move-table` command.
+func (o *moveTableChangefeedOptions) run() error {
+	ctx := context.GetDefaultContext()
+	cfg := &v2.MoveTableConfig{
+		TableID:         o.tableID,
+		StartKey:        o.startKey,
+		EndKey:          o.endKey,
+		TargetCaptureID: o.targetCapture,
+	}
+	return o.apiClient.Changefeeds().MoveTable(ctx, cfg, o.namespace, o.changefeedID)
+}
+
+// newCmdMoveTableChangefeed creates the `cli changefeed move-table` command.
+func newCmdMoveTableChangefeed(f factory.Factory) *cobra.Command {
+	o := newMoveTableChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "move-table",
+		Short: "Move a table (span) of a replication task (changefeed) to the target capture",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run())
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedMoveTableCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cf := mock.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeeds: cf}
+	cmd := newCmdMoveTableChangefeed(f)
+	cf.EXPECT().MoveTable(gomock.Any(), &v2.MoveTableConfig{
+		TableID:         1,
+		StartKey:        "7480",
+		EndKey:          "7481",
+		TargetCaptureID: "b",
+	}, "default", "abc").Return(nil)
+	os.Args = []string{
+		"move-table", "--changefeed-id=abc", "--namespace=default", "--table-id=1",
+		"--start-key=7480", "--end-key=7481", "--target-capture=b",
+	}
+	require.Nil(t, cmd.Execute())
+
+	cf.EXPECT().MoveTable(gomock.Any(), &v2.MoveTableConfig{
+		TableID:         2,
+		TargetCaptureID: "c",
+	}, "test", "abc").Return(errors.New("test"))
+	o := newMoveTableChangefeedOptions()
+	o.changefeedID = "abc"
+	o.namespace = "test"
+	o.tableID = 2
+	o.targetCapture = "c"
+	require.Nil(t, o.complete(f))
+	require.NotNil(t, o.run())
+}
@@ -45,6 +45,9 @@ type cfMeta struct {
 	ErrorHis       []int64                   `json:"error_history,omitempty"`
 	CreatorVersion string                    `json:"creator_version"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+	// NextScheduledTransition is the next pause or resume of the changefeed
+	// triggered by its pause schedule.
+	NextScheduledTransition *v2.ScheduledTransition `json:"next_scheduled_transition,omitempty"`
 }
 
 // queryChangefeedOptions defines flags for the `cli changefeed query` command.
@@ -52,6 +55,7 @@ type queryChangefeedOptions struct {
 	apiClientV2  apiv2client.APIV2Interface
 	changefeedID string
 	simplified   bool
+	tables       bool
 	namespace    string
 }
 
@@ -65,6 +69,7 @@ func newQueryChangefeedOptions() *queryChangefeedOptions {
 func (o *queryChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().BoolVarP(&o.simplified, "simple", "s", false, "Output simplified replication status")
+	cmd.PersistentFlags().BoolVar(&o.tables, "tables", false, "Output replication status of each table span")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
@@ -82,6 +87,13 @@ func (o *queryChangefeedOptions) complete(f factory.Factory) error {
 // run the `cli changefeed query` command.
 func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := context.Background()
+	if o.tables {
+		tables, err := o.apiClientV2.Changefeeds().Tables(ctx, o.namespace, o.changefeedID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return util.JSONPrint(cmd, tables)
+	}
 	if o.simplified {
 		infos, err := o.apiClientV2.Changefeeds().List(ctx, o.namespace, "all")
 		if err != nil {
@@ -115,6 +127,8 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 		RunningError:   detail.Error,
 		CreatorVersion: detail.CreatorVersion,
 		TaskStatus:     detail.TaskStatus,
+
+		NextScheduledTransition: detail.NextScheduledTransition,
 	}
 	return util.JSONPrint(cmd, meta)
 }
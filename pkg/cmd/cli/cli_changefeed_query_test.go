@@ -18,12 +18,14 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/errors"
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -82,9 +84,41 @@ func TestChangefeedQueryCli(t *testing.T) {
 	require.Nil(t, err)
 	// make sure config is printed
 	require.Contains(t, string(out), "config")
+	require.NotContains(t, string(out), "next_scheduled_transition")
+
+	// query a changefeed with a pause schedule
+	cfV2.EXPECT().Get(gomock.Any(), gomock.Any(), "bcd").Return(&v2.ChangeFeedInfo{
+		NextScheduledTransition: &v2.ScheduledTransition{
+			Time:   time.Now(),
+			Action: config.PauseScheduleActionPause,
+		},
+	}, nil)
+	b = bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, o.run(cmd))
+	out, err = io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), "next_scheduled_transition")
+	require.Contains(t, string(out), `"action": "pause"`)
 
 	// query failed
 	cfV2.EXPECT().Get(gomock.Any(), gomock.Any(), "bcd").Return(nil, errors.New("test"))
 	os.Args = []string{"query", "--simple=false", "--changefeed-id=bcd"}
 	require.NotNil(t, o.run(cmd))
+
+	// query tables
+	cfV2.EXPECT().Tables(gomock.Any(), gomock.Any(), "bcd").Return([]v2.TableSpanStatus{
+		{TableID: 1, CaptureID: "capture-1", State: "Replicating", SinkRowsPerSecond: 10},
+	}, nil)
+	o.tables = true
+	b = bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, o.run(cmd))
+	out, err = io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), "capture-1")
+	require.Contains(t, string(out), "sink_rows_per_second")
+
+	cfV2.EXPECT().Tables(gomock.Any(), gomock.Any(), "bcd").Return(nil, errors.New("test"))
+	require.NotNil(t, o.run(cmd))
 }
@@ -0,0 +1,79 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// rebalanceChangefeedOptions defines flags for the `cli changefeed rebalance` command.
+type rebalanceChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	namespace    string
+}
+
+// newRebalanceChangefeedOptions creates new options for the `cli changefeed rebalance` command.
+func newRebalanceChangefeedOptions() *rebalanceChangefeedOptions {
+	return &rebalanceChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *rebalanceChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *rebalanceChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed rebalance` command.
+func (o *rebalanceChangefeedOptions) run() error {
+	ctx := context.GetDefaultContext()
+	return o.apiClient.Changefeeds().Rebalance(ctx, o.namespace, o.changefeedID)
+}
+
+// newCmdRebalanceChangefeed creates the `cli changefeed rebalance` command.
+func newCmdRebalanceChangefeed(f factory.Factory) *cobra.Command {
+	o := newRebalanceChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "rebalance",
+		Short: "Rebalance the tables of a replication task (changefeed) across all captures",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run())
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -35,6 +35,10 @@ type applyRedoOptions struct {
 	sinkURI              string
 	enableProfiling      bool
 	memoryLimitInGiBytes int64
+	encryptionMasterKey  string
+	targetTs             uint64
+	filterRules          []string
+	sqlFile              string
 }
 
 // newapplyRedoOptions creates new applyRedoOptions for the `redo apply` command.
@@ -46,39 +50,64 @@ func newapplyRedoOptions() *applyRedoOptions {
 // flags related to template printing to it.
 func (o *applyRedoOptions) addFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "target database sink-uri")
-	// the possible error returned from MarkFlagRequired is `no such flag`
-	cmd.MarkFlagRequired("sink-uri") //nolint:errcheck
 	cmd.Flags().BoolVar(&o.enableProfiling, "enable-profiling", true, "enable pprof profiling")
 	cmd.Flags().Int64Var(&o.memoryLimitInGiBytes, "memory-limit", 10, "memory limit in GiB")
+	cmd.Flags().StringVar(&o.encryptionMasterKey, "encryption-master-key", "",
+		"master key used to decrypt the encrypted redo logs, eg, \"file:///path/to/keyfile\"")
+	cmd.Flags().Uint64Var(&o.targetTs, "target-ts", 0,
+		"apply redo logs up to the target ts, the resolved ts in redo meta is used if not set")
+	cmd.Flags().StringSliceVar(&o.filterRules, "filter-rules", nil,
+		"table filter rules to select the tables to apply, eg, \"test.*,!test.t1\"")
+	cmd.Flags().StringVar(&o.sqlFile, "sql-file", "",
+		"write the replayed statements to the file instead of executing them in the sink")
 }
 
 //nolint:unparam
 func (o *applyRedoOptions) complete(cmd *cobra.Command) error {
+	if (o.sinkURI == "") == (o.sqlFile == "") {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"exactly one of --sink-uri and --sql-file should be specified")
+	}
+	if o.sinkURI != "" {
+		sinkURI, err := enableSafeMode(o.sinkURI)
+		if err != nil {
+			return err
+		}
+		o.sinkURI = sinkURI
+	}
+	setMemoryLimit(o.memoryLimitInGiBytes)
+	return nil
+}
+
+// enableSafeMode sets safe-mode to true in the sink uri if not set,
+// since the redo logs at the boundary may be applied more than once.
+func enableSafeMode(rawSinkURI string) (string, error) {
 	// parse sinkURI as a URI
-	sinkURI, err := url.Parse(o.sinkURI)
+	sinkURI, err := url.Parse(rawSinkURI)
 	if err != nil {
-		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+		return "", cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
 	rawQuery := sinkURI.Query()
 	// set safe-mode to true if not set
-	if rawQuery.Get("safe-mode") != "true" {
-		rawQuery.Set("safe-mode", "true")
-		sinkURI.RawQuery = rawQuery.Encode()
-		o.sinkURI = sinkURI.String()
+	if rawQuery.Get("safe-mode") == "true" {
+		return rawSinkURI, nil
 	}
+	rawQuery.Set("safe-mode", "true")
+	sinkURI.RawQuery = rawQuery.Encode()
+	return sinkURI.String(), nil
+}
 
+func setMemoryLimit(memoryLimitInGiBytes int64) {
 	totalMemory, err := util.GetMemoryLimit()
 	if err == nil {
 		totalMemoryInBytes := int64(float64(totalMemory) * 0.8)
-		memoryLimitInBytes := o.memoryLimitInGiBytes * 1024 * 1024 * 1024
+		memoryLimitInBytes := memoryLimitInGiBytes * 1024 * 1024 * 1024
 		if totalMemoryInBytes != 0 && memoryLimitInBytes > totalMemoryInBytes {
 			memoryLimitInBytes = totalMemoryInBytes
 		}
 		debug.SetMemoryLimit(memoryLimitInBytes)
 		log.Info("set memory limit", zap.Int64("memoryLimit", memoryLimitInBytes))
 	}
-
-	return nil
 }
 
 // run runs the `redo apply` command.
@@ -99,15 +128,23 @@ func (o *applyRedoOptions) run(cmd *cobra.Command) error {
 	}
 
 	cfg := &applier.RedoApplierConfig{
-		Storage: o.storage,
-		SinkURI: o.sinkURI,
-		Dir:     o.dir,
+		Storage:             o.storage,
+		SinkURI:             o.sinkURI,
+		Dir:                 o.dir,
+		EncryptionMasterKey: o.encryptionMasterKey,
+		TargetTs:            o.targetTs,
+		FilterRules:         o.filterRules,
+		SQLFile:             o.sqlFile,
 	}
 	ap := applier.NewRedoApplier(cfg)
 	err := ap.Apply(ctx)
 	if err != nil {
 		return err
 	}
+	if o.sqlFile != "" {
+		cmd.Printf("Write redo log to %s successfully\n", o.sqlFile)
+		return nil
+	}
 	cmd.Println("Apply redo log successfully")
 	return nil
 }
@@ -39,4 +39,11 @@ func TestComplete(t *testing.T) {
 	err = o.complete(cmd)
 	require.NoError(t, err)
 	require.Equal(t, "mysql://root@127.0.0.1:3306?time-zone=UTC&safe-mode=true", o.sinkURI)
+
+	o.sqlFile = "/tmp/redo.sql"
+	require.ErrorContains(t, o.complete(cmd), "exactly one of --sink-uri and --sql-file")
+	o.sinkURI = ""
+	require.NoError(t, o.complete(cmd))
+	o.sqlFile = ""
+	require.ErrorContains(t, o.complete(cmd), "exactly one of --sink-uri and --sql-file")
 }
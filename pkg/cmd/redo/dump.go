@@ -0,0 +1,393 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	"github.com/pingcap/tiflow/pkg/applier"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	dumpFormatJSON      = "json"
+	dumpFormatCanalJSON = "canal-json"
+	dumpFormatSQL       = "sql"
+)
+
+// dumpOptions defines flags for the `redo dump` command.
+type dumpOptions struct {
+	options
+	encryptionMasterKey string
+	filterRules         []string
+	startTs             uint64
+	endTs               uint64
+	primaryKey          map[string]string
+	format              string
+	output              string
+}
+
+// newDumpOptions creates new dumpOptions for the `redo dump` command.
+func newDumpOptions() *dumpOptions {
+	return &dumpOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *dumpOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.encryptionMasterKey, "encryption-master-key", "",
+		"master key used to decrypt the encrypted redo logs, eg, \"file:///path/to/keyfile\"")
+	cmd.Flags().StringSliceVar(&o.filterRules, "filter-rules", nil,
+		"table filter rules to select the tables to dump, eg, \"test.*,!test.t1\"")
+	cmd.Flags().Uint64Var(&o.startTs, "start-ts", 0, "dump the events whose commit ts is not less than start-ts")
+	cmd.Flags().Uint64Var(&o.endTs, "end-ts", 0, "dump the events whose commit ts is not greater than end-ts")
+	cmd.Flags().StringToStringVar(&o.primaryKey, "primary-key", nil,
+		"only dump the rows with the given handle key values, eg, \"id=1\"")
+	cmd.Flags().StringVar(&o.format, "format", dumpFormatJSON, "output format (json|canal-json|sql)")
+	cmd.Flags().StringVar(&o.output, "output", "", "the file to write to, stdout is used if not set")
+}
+
+func (o *dumpOptions) validate() error {
+	switch o.format {
+	case dumpFormatJSON, dumpFormatCanalJSON, dumpFormatSQL:
+	default:
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"unsupported format %s, it should be one of json, canal-json and sql", o.format)
+	}
+	if o.endTs != 0 && o.startTs > o.endTs {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"start-ts %d is greater than end-ts %d", o.startTs, o.endTs)
+	}
+	return nil
+}
+
+// run runs the `redo dump` command.
+func (o *dumpOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	out := cmd.OutOrStdout()
+	if o.output != "" {
+		file, err := os.Create(o.output)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+		defer file.Close() //nolint:errcheck
+		out = file
+	}
+
+	rd, err := applier.NewRedoLogReader(ctx, o.readerConfig())
+	if err != nil {
+		return err
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return rd.Run(egCtx)
+	})
+	eg.Go(func() error {
+		return o.dump(egCtx, rd, out)
+	})
+	return eg.Wait()
+}
+
+// readerConfig returns the config of the redo log reader. The logs are read
+// from start-ts instead of the checkpoint ts in the redo meta if it's given,
+// and ErrRedoLogsNotRetained is returned if the logs after it are deleted.
+func (o *dumpOptions) readerConfig() *applier.RedoApplierConfig {
+	cfg := &applier.RedoApplierConfig{
+		Storage:             o.storage,
+		Dir:                 o.dir,
+		EncryptionMasterKey: o.encryptionMasterKey,
+		FilterRules:         o.filterRules,
+	}
+	// The reader reads the events committed after StartTs,
+	// while the events at start-ts are dumped.
+	if o.startTs > 1 {
+		cfg.StartTs = o.startTs - 1
+	}
+	return cfg
+}
+
+// dump reads all the events from the reader and writes the matched ones to w
+// in commit ts order, the DDL is written before the rows with larger commit ts.
+func (o *dumpOptions) dump(ctx context.Context, rd reader.RedoLogReader, w io.Writer) error {
+	printer, err := newEventPrinter(ctx, o.format, w)
+	if err != nil {
+		return err
+	}
+	row, err := rd.ReadNextRow(ctx)
+	if err != nil {
+		return err
+	}
+	ddl, err := rd.ReadNextDDL(ctx)
+	if err != nil {
+		return err
+	}
+	for row != nil || ddl != nil {
+		if ddl != nil && (row == nil || row.CommitTs > ddl.CommitTs) {
+			if o.matchDDL(ddl) {
+				if err := printer.printDDL(ddl); err != nil {
+					return err
+				}
+			}
+			if ddl, err = rd.ReadNextDDL(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		if o.matchRow(row) {
+			if err := printer.printRow(row); err != nil {
+				return err
+			}
+		}
+		if row, err = rd.ReadNextRow(ctx); err != nil {
+			return err
+		}
+	}
+	return printer.flush()
+}
+
+func (o *dumpOptions) matchCommitTs(commitTs uint64) bool {
+	return commitTs >= o.startTs && (o.endTs == 0 || commitTs <= o.endTs)
+}
+
+// matchDDL returns whether the DDL should be dumped, DDLs are
+// skipped if the rows are selected by the primary key.
+func (o *dumpOptions) matchDDL(ddl *model.DDLEvent) bool {
+	return len(o.primaryKey) == 0 && o.matchCommitTs(ddl.CommitTs)
+}
+
+// matchRow returns whether the row should be dumped, the row is matched by the
+// primary key if the values of the handle key columns before or after the
+// change equal to the given ones.
+func (o *dumpOptions) matchRow(row *model.RowChangedEvent) bool {
+	if !o.matchCommitTs(row.CommitTs) {
+		return false
+	}
+	if len(o.primaryKey) == 0 {
+		return true
+	}
+	return matchHandleKey(row.GetColumns(), o.primaryKey) ||
+		matchHandleKey(row.GetPreColumns(), o.primaryKey)
+}
+
+func matchHandleKey(cols []*model.Column, key map[string]string) bool {
+	matched := 0
+	for _, col := range cols {
+		if col == nil || !col.Flag.IsHandleKey() {
+			continue
+		}
+		value, ok := key[col.Name]
+		if !ok {
+			continue
+		}
+		if col.Value == nil || model.ColumnValueString(col.Value) != value {
+			return false
+		}
+		matched++
+	}
+	return matched != 0 && matched == len(key)
+}
+
+// eventPrinter writes the redo log events in a specific format.
+type eventPrinter interface {
+	printRow(row *model.RowChangedEvent) error
+	printDDL(ddl *model.DDLEvent) error
+	flush() error
+}
+
+func newEventPrinter(ctx context.Context, format string, w io.Writer) (eventPrinter, error) {
+	switch format {
+	case dumpFormatCanalJSON:
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = true
+		codecConfig.MaxMessageBytes = math.MaxInt
+		builder, err := canal.NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &canalJSONPrinter{ctx: ctx, encoder: builder.Build(), w: w}, nil
+	case dumpFormatSQL:
+		return &sqlPrinter{writer: applier.NewSQLWriter(w)}, nil
+	default:
+		return &jsonPrinter{encoder: json.NewEncoder(w)}, nil
+	}
+}
+
+// dumpEvent is the JSON representation of a redo log event.
+type dumpEvent struct {
+	Type       string                 `json:"type"`
+	StartTs    uint64                 `json:"start-ts,omitempty"`
+	CommitTs   uint64                 `json:"commit-ts"`
+	Schema     string                 `json:"schema"`
+	Table      string                 `json:"table,omitempty"`
+	Operation  string                 `json:"operation,omitempty"`
+	Columns    map[string]interface{} `json:"columns,omitempty"`
+	PreColumns map[string]interface{} `json:"pre-columns,omitempty"`
+	Query      string                 `json:"query,omitempty"`
+}
+
+type jsonPrinter struct {
+	encoder *json.Encoder
+}
+
+func (p *jsonPrinter) printRow(row *model.RowChangedEvent) error {
+	event := &dumpEvent{
+		Type:       "row",
+		StartTs:    row.StartTs,
+		CommitTs:   row.CommitTs,
+		Schema:     row.TableInfo.GetSchemaName(),
+		Table:      row.TableInfo.GetTableName(),
+		Columns:    columnsToMap(row.GetColumns()),
+		PreColumns: columnsToMap(row.GetPreColumns()),
+	}
+	switch {
+	case row.IsInsert():
+		event.Operation = "insert"
+	case row.IsDelete():
+		event.Operation = "delete"
+	default:
+		event.Operation = "update"
+	}
+	return errors.Trace(p.encoder.Encode(event))
+}
+
+func (p *jsonPrinter) printDDL(ddl *model.DDLEvent) error {
+	event := &dumpEvent{
+		Type:     "ddl",
+		StartTs:  ddl.StartTs,
+		CommitTs: ddl.CommitTs,
+		Query:    ddl.Query,
+	}
+	if ddl.TableInfo != nil {
+		event.Schema = ddl.TableInfo.TableName.Schema
+		event.Table = ddl.TableInfo.TableName.Table
+	}
+	return errors.Trace(p.encoder.Encode(event))
+}
+
+func (p *jsonPrinter) flush() error {
+	return nil
+}
+
+func columnsToMap(cols []*model.Column) map[string]interface{} {
+	if len(cols) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		if value, ok := col.Value.([]byte); ok {
+			// binary values are hex-encoded, since they are not valid
+			// UTF-8 strings in general and will be mangled by JSON.
+			if col.Flag.IsBinary() || !utf8.Valid(value) {
+				result[col.Name] = hex.EncodeToString(value)
+			} else {
+				result[col.Name] = string(value)
+			}
+		} else {
+			result[col.Name] = col.Value
+		}
+	}
+	return result
+}
+
+type canalJSONPrinter struct {
+	ctx     context.Context
+	encoder codec.RowEventEncoder
+	w       io.Writer
+}
+
+func (p *canalJSONPrinter) printRow(row *model.RowChangedEvent) error {
+	if err := p.encoder.AppendRowChangedEvent(p.ctx, "", row, nil); err != nil {
+		return errors.Trace(err)
+	}
+	for _, msg := range p.encoder.Build() {
+		if err := p.writeLine(msg.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *canalJSONPrinter) printDDL(ddl *model.DDLEvent) error {
+	msg, err := p.encoder.EncodeDDLEvent(ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return p.writeLine(msg.Value)
+}
+
+func (p *canalJSONPrinter) writeLine(value []byte) error {
+	if _, err := p.w.Write(append(value, '\n')); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func (p *canalJSONPrinter) flush() error {
+	return nil
+}
+
+type sqlPrinter struct {
+	writer *applier.SQLWriter
+}
+
+func (p *sqlPrinter) printRow(row *model.RowChangedEvent) error {
+	return p.writer.WriteRow(row)
+}
+
+func (p *sqlPrinter) printDDL(ddl *model.DDLEvent) error {
+	return p.writer.WriteDDL(ddl)
+}
+
+func (p *sqlPrinter) flush() error {
+	return p.writer.Flush()
+}
+
+// newCmdDump creates the `redo dump` command.
+func newCmdDump(opt *options) *cobra.Command {
+	o := newDumpOptions()
+	command := &cobra.Command{
+		Use:   "dump",
+		Short: "Dump the events in redo logs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.options = *opt
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,247 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	mysqlParser "github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/applier"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReader struct {
+	rows []*model.RowChangedEvent
+	ddls []*model.DDLEvent
+}
+
+func (r *mockReader) Run(ctx context.Context) error {
+	return nil
+}
+
+func (r *mockReader) ReadNextRow(ctx context.Context) (*model.RowChangedEvent, error) {
+	if len(r.rows) == 0 {
+		return nil, nil
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	return row, nil
+}
+
+func (r *mockReader) ReadNextDDL(ctx context.Context) (*model.DDLEvent, error) {
+	if len(r.ddls) == 0 {
+		return nil, nil
+	}
+	ddl := r.ddls[0]
+	r.ddls = r.ddls[1:]
+	return ddl, nil
+}
+
+func (r *mockReader) ReadMeta(ctx context.Context) (checkpointTs, resolvedTs uint64, err error) {
+	return 0, 0, nil
+}
+
+func newMockReader() *mockReader {
+	tableInfo := model.BuildTableInfo("test", "t1", []*model.Column{
+		{Name: "id", Type: mysqlParser.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "name", Type: mysqlParser.TypeVarchar},
+	}, [][]int{{0}})
+	genColumns := func(id int, name string) []*model.ColumnData {
+		return model.Columns2ColumnDatas([]*model.Column{
+			{Name: "id", Value: id},
+			{Name: "name", Value: []byte(name)},
+		}, tableInfo)
+	}
+	return &mockReader{
+		rows: []*model.RowChangedEvent{
+			{StartTs: 90, CommitTs: 100, TableInfo: tableInfo, Columns: genColumns(1, "a")},
+			{StartTs: 90, CommitTs: 100, TableInfo: tableInfo, Columns: genColumns(2, "b")},
+			{
+				StartTs: 190, CommitTs: 200, TableInfo: tableInfo,
+				PreColumns: genColumns(1, "a"), Columns: genColumns(1, "c"),
+			},
+			{StartTs: 290, CommitTs: 300, TableInfo: tableInfo, PreColumns: genColumns(2, "b")},
+		},
+		ddls: []*model.DDLEvent{
+			{
+				CommitTs: 150,
+				TableInfo: &model.TableInfo{
+					TableName: model.TableName{Schema: "test", Table: "t1"},
+				},
+				Query: "alter table t1 add column age int",
+			},
+		},
+	}
+}
+
+func TestDumpJSON(t *testing.T) {
+	t.Parallel()
+
+	o := newDumpOptions()
+	o.format = dumpFormatJSON
+	require.NoError(t, o.validate())
+	var buf bytes.Buffer
+	require.NoError(t, o.dump(context.Background(), newMockReader(), &buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	var types, operations []string
+	var commitTs []uint64
+	for _, line := range lines {
+		event := &dumpEvent{}
+		require.NoError(t, json.Unmarshal([]byte(line), event))
+		types = append(types, event.Type)
+		operations = append(operations, event.Operation)
+		commitTs = append(commitTs, event.CommitTs)
+	}
+	require.Equal(t, []string{"row", "row", "ddl", "row", "row"}, types)
+	require.Equal(t, []string{"insert", "insert", "", "update", "delete"}, operations)
+	require.Equal(t, []uint64{100, 100, 150, 200, 300}, commitTs)
+	require.Contains(t, lines[3], `"columns":{"id":1,"name":"c"}`)
+	require.Contains(t, lines[3], `"pre-columns":{"id":1,"name":"a"}`)
+}
+
+func TestDumpByPrimaryKeyAndCommitTs(t *testing.T) {
+	t.Parallel()
+
+	o := newDumpOptions()
+	o.format = dumpFormatSQL
+	o.primaryKey = map[string]string{"id": "2"}
+	var buf bytes.Buffer
+	require.NoError(t, o.dump(context.Background(), newMockReader(), &buf))
+	require.Equal(t, "-- DML start-ts: 90, commit-ts: 100\n"+
+		"BEGIN;\n"+
+		"REPLACE INTO `test`.`t1` (`id`,`name`) VALUES (2,'b');\n"+
+		"COMMIT;\n"+
+		"-- DML start-ts: 290, commit-ts: 300\n"+
+		"BEGIN;\n"+
+		"DELETE FROM `test`.`t1` WHERE `id` = 2 LIMIT 1;\n"+
+		"COMMIT;\n", buf.String())
+
+	o = newDumpOptions()
+	o.format = dumpFormatCanalJSON
+	o.startTs = 150
+	o.endTs = 200
+	buf.Reset()
+	require.NoError(t, o.dump(context.Background(), newMockReader(), &buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"isDdl":true`)
+	require.Contains(t, lines[1], `"type":"UPDATE"`)
+
+	// the column which is not a handle key never matches.
+	o = newDumpOptions()
+	o.primaryKey = map[string]string{"name": "a"}
+	buf.Reset()
+	require.NoError(t, o.dump(context.Background(), newMockReader(), &buf))
+	require.Empty(t, buf.String())
+}
+
+func TestDumpUpdateAsSQL(t *testing.T) {
+	t.Parallel()
+
+	o := newDumpOptions()
+	o.format = dumpFormatSQL
+	o.startTs = 200
+	o.endTs = 200
+	var buf bytes.Buffer
+	require.NoError(t, o.dump(context.Background(), newMockReader(), &buf))
+	require.Equal(t, "-- DML start-ts: 190, commit-ts: 200\n"+
+		"BEGIN;\n"+
+		"DELETE FROM `test`.`t1` WHERE `id` = 1 LIMIT 1;\n"+
+		"REPLACE INTO `test`.`t1` (`id`,`name`) VALUES (1,'c');\n"+
+		"COMMIT;\n", buf.String())
+}
+
+func TestColumnsToMap(t *testing.T) {
+	t.Parallel()
+
+	result := columnsToMap([]*model.Column{
+		{Name: "name", Value: []byte("a")},
+		{Name: "blob", Value: []byte{0x00, 0xff}, Flag: model.BinaryFlag},
+		{Name: "invalid", Value: []byte{0xff}},
+		{Name: "id", Value: 1},
+	})
+	require.Equal(t, map[string]interface{}{
+		"name": "a", "blob": "00ff", "invalid": "ff", "id": 1,
+	}, result)
+}
+
+func TestDumpValidate(t *testing.T) {
+	t.Parallel()
+
+	o := newDumpOptions()
+	o.format = "avro"
+	require.ErrorContains(t, o.validate(), "unsupported format avro")
+	o.format = dumpFormatJSON
+	o.startTs = 10
+	o.endTs = 5
+	require.ErrorContains(t, o.validate(), "start-ts 10 is greater than end-ts 5")
+}
+
+func TestDumpReaderStartTs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	meta := &common.LogMeta{CheckpointTs: 20, ResolvedTs: 30}
+	data, err := meta.MarshalMsg(nil)
+	require.NoError(t, err)
+	metaFile := fmt.Sprintf(redo.RedoMetaFileFormat, "capture", "default",
+		"changefeed", redo.RedoMetaFileType, uuid.NewString(), redo.MetaEXT)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, metaFile), data, 0o644))
+	// the logs before ts 15 are deleted.
+	for _, commitTs := range []uint64{15, 25} {
+		logFile := fmt.Sprintf(redo.RedoLogFileFormatV2, "capture", "default",
+			"changefeed", redo.RedoRowLogFileType, commitTs, uuid.NewString(), redo.LogEXT)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, logFile), nil, 0o644))
+	}
+
+	o := newDumpOptions()
+	o.storage = fmt.Sprintf("file://%s", dir)
+	o.dir = t.TempDir()
+
+	// the checkpoint ts is used if start-ts is not given.
+	rd, err := applier.NewRedoLogReader(ctx, o.readerConfig())
+	require.NoError(t, err)
+	cts, rts, err := rd.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), cts)
+	require.Equal(t, uint64(30), rts)
+
+	// the logs at and after start-ts are read even if it's less than the checkpoint ts.
+	o.startTs = 16
+	rd, err = applier.NewRedoLogReader(ctx, o.readerConfig())
+	require.NoError(t, err)
+	cts, _, err = rd.ReadMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), cts)
+
+	o.startTs = 10
+	_, err = applier.NewRedoLogReader(ctx, o.readerConfig())
+	require.True(t, cerror.ErrRedoLogsNotRetained.Equal(err))
+	require.ErrorContains(t, err, "redo logs in range (9, 20] may be deleted")
+}
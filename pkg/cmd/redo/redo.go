@@ -70,6 +70,8 @@ func NewCmdRedo() *cobra.Command {
 	// Add subcommands.
 	cmds.AddCommand(newCmdApply(o))
 	cmds.AddCommand(newCmdMeta(o))
+	cmds.AddCommand(newCmdDump(o))
+	cmds.AddCommand(newCmdStandby(o))
 
 	return cmds
 }
@@ -0,0 +1,141 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"net/http"
+	_ "net/http/pprof" // init pprof
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/applier"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// standbyOptions defines flags for the `redo standby` command.
+type standbyOptions struct {
+	options
+	sinkURI              string
+	statusAddr           string
+	memoryLimitInGiBytes int64
+	encryptionMasterKey  string
+	startTs              uint64
+	filterRules          []string
+	stateFile            string
+	checkInterval        time.Duration
+}
+
+// newStandbyOptions creates new standbyOptions for the `redo standby` command.
+func newStandbyOptions() *standbyOptions {
+	return &standbyOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *standbyOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "standby database sink-uri")
+	cmd.Flags().StringVar(&o.statusAddr, "status-addr", ":6060",
+		"address of the http server to expose metrics and pprof, it's disabled if empty")
+	cmd.Flags().Int64Var(&o.memoryLimitInGiBytes, "memory-limit", 10, "memory limit in GiB")
+	cmd.Flags().StringVar(&o.encryptionMasterKey, "encryption-master-key", "",
+		"master key used to decrypt the encrypted redo logs, eg, \"file:///path/to/keyfile\"")
+	cmd.Flags().Uint64Var(&o.startTs, "start-ts", 0,
+		"apply redo logs after the start ts if there is no applied ts in the state file, "+
+			"the checkpoint ts in redo meta is used if not set")
+	cmd.Flags().StringSliceVar(&o.filterRules, "filter-rules", nil,
+		"table filter rules to select the tables to apply, eg, \"test.*,!test.t1\"")
+	cmd.Flags().StringVar(&o.stateFile, "state-file", "redo-standby-applied-ts",
+		"file to persist the applied ts, it must be outside of the tmp-dir")
+	cmd.Flags().DurationVar(&o.checkInterval, "check-interval", applier.DefaultStandbyCheckInterval,
+		"interval to check new redo logs")
+	// the possible error returned from MarkFlagRequired is `no such flag`
+	cmd.MarkFlagRequired("sink-uri") //nolint:errcheck
+}
+
+func (o *standbyOptions) complete(_ *cobra.Command) error {
+	if o.stateFile == "" {
+		return cerror.ErrRedoConfigInvalid.GenWithStack("--state-file should be specified")
+	}
+	if o.checkInterval <= 0 {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"--check-interval must be greater than 0")
+	}
+	sinkURI, err := enableSafeMode(o.sinkURI)
+	if err != nil {
+		return err
+	}
+	o.sinkURI = sinkURI
+	setMemoryLimit(o.memoryLimitInGiBytes)
+	return nil
+}
+
+// run runs the `redo standby` command.
+func (o *standbyOptions) run(_ *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	if o.statusAddr != "" {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(prometheus.NewGoCollector())
+		applier.InitMetrics(registry)
+		http.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		go func() {
+			server := &http.Server{
+				Addr:              o.statusAddr,
+				ReadHeaderTimeout: 5 * time.Second,
+			}
+			log.Info("Start http status server", zap.String("addr", server.Addr))
+			if err := server.ListenAndServe(); err != nil {
+				log.Fatal("http status server", zap.Error(err))
+			}
+		}()
+	}
+
+	cfg := &applier.StandbyApplierConfig{
+		RedoApplierConfig: applier.RedoApplierConfig{
+			Storage:             o.storage,
+			SinkURI:             o.sinkURI,
+			Dir:                 o.dir,
+			EncryptionMasterKey: o.encryptionMasterKey,
+			StartTs:             o.startTs,
+			FilterRules:         o.filterRules,
+		},
+		StateFile:     o.stateFile,
+		CheckInterval: o.checkInterval,
+	}
+	return applier.NewStandbyApplier(cfg).Run(ctx)
+}
+
+// newCmdStandby creates the `redo standby` command.
+func newCmdStandby(opt *options) *cobra.Command {
+	o := newStandbyOptions()
+	command := &cobra.Command{
+		Use:   "standby",
+		Short: "Apply redo logs to a standby cluster continuously",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.options = *opt
+			if err := o.complete(cmd); err != nil {
+				return err
+			}
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
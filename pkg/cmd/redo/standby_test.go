@@ -0,0 +1,40 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandbyComplete(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "test",
+	}
+	o := newStandbyOptions()
+	o.sinkURI = "mysql://root@127.0.0.1:3306"
+	o.stateFile = "applied_ts"
+	o.checkInterval = time.Second
+	require.NoError(t, o.complete(cmd))
+	require.Equal(t, "mysql://root@127.0.0.1:3306?safe-mode=true", o.sinkURI)
+
+	o.checkInterval = 0
+	require.ErrorContains(t, o.complete(cmd), "--check-interval must be greater than 0")
+	o.checkInterval = time.Second
+	o.stateFile = ""
+	require.ErrorContains(t, o.complete(cmd), "--state-file should be specified")
+}
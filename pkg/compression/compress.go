@@ -15,9 +15,12 @@ package compression
 
 import (
 	"bytes"
+	"io"
 	"sync"
 
+	"github.com/klauspost/compress/gzip"
 	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v4"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -31,6 +34,24 @@ const (
 
 	// LZ4 compression
 	LZ4 string = "lz4"
+
+	// Zstd compression
+	Zstd string = "zstd"
+
+	// Gzip compression
+	Gzip string = "gzip"
+)
+
+const (
+	// DefaultLevel means the default level of the compression codec.
+	DefaultLevel = 0
+
+	// The valid levels of gzip, they are the same as compress/flate.
+	minGzipLevel = 1
+	maxGzipLevel = 9
+	// The valid levels of zstd, they are the same as the zstd command line.
+	minZstdLevel = 1
+	maxZstdLevel = 22
 )
 
 var (
@@ -45,27 +66,74 @@ var (
 			return new(bytes.Buffer)
 		},
 	}
+
+	// zstdDecoder is shared by all goroutines, DecodeAll is safe for
+	// concurrent use.
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderOnce sync.Once
+
+	// zstdEncoders caches one encoder for each level, EncodeAll is safe for
+	// concurrent use.
+	zstdEncoders sync.Map // level -> *zstd.Encoder
 )
 
 // Supported return true if the given compression is supported.
 func Supported(cc string) bool {
 	switch cc {
-	case None, Snappy, LZ4:
+	case None, Snappy, LZ4, Zstd, Gzip:
 		return true
 	}
 	return false
 }
 
+// ValidateLevel returns an error if the level is invalid for the given
+// compression. Only zstd and gzip have levels, DefaultLevel is always valid.
+func ValidateLevel(cc string, level int) error {
+	if level == DefaultLevel {
+		return nil
+	}
+	switch cc {
+	case Zstd:
+		if level >= minZstdLevel && level <= maxZstdLevel {
+			return nil
+		}
+	case Gzip:
+		if level >= minGzipLevel && level <= maxGzipLevel {
+			return nil
+		}
+	default:
+	}
+	return cerror.ErrCompressionFailed.GenWithStack(
+		"invalid level %d of compression %s", level, cc)
+}
+
 // Encode the given data by the given compression codec.
 func Encode(cc string, data []byte) ([]byte, error) {
+	return EncodeWithLevel(cc, DefaultLevel, data)
+}
+
+// EncodeWithLevel encodes the given data by the given compression codec and level.
+func EncodeWithLevel(cc string, level int, data []byte) ([]byte, error) {
 	switch cc {
 	case None:
 		return data, nil
 	case Snappy:
 		return snappy.Encode(nil, data), nil
-	case LZ4:
+	case Zstd:
+		if err := ValidateLevel(cc, level); err != nil {
+			return nil, err
+		}
+		encoder, err := getZstdEncoder(level)
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(data, nil), nil
+	case LZ4, Gzip:
 		var buf bytes.Buffer
-		writer := lz4.NewWriter(&buf)
+		writer, err := NewWriter(cc, level, &buf)
+		if err != nil {
+			return nil, err
+		}
 		if _, err := writer.Write(data); err != nil {
 			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
 		}
@@ -79,6 +147,63 @@ func Encode(cc string, data []byte) ([]byte, error) {
 	return nil, cerror.ErrCompressionFailed.GenWithStack("Unsupported compression %s", cc)
 }
 
+func getZstdEncoder(level int) (*zstd.Encoder, error) {
+	if encoder, ok := zstdEncoders.Load(level); ok {
+		return encoder.(*zstd.Encoder), nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstdEncoderOptions(level)...)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+	}
+	actual, loaded := zstdEncoders.LoadOrStore(level, encoder)
+	if loaded {
+		// Another goroutine has stored an encoder of the same level.
+		_ = encoder.Close()
+	}
+	return actual.(*zstd.Encoder), nil
+}
+
+func zstdEncoderOptions(level int) []zstd.EOption {
+	if level == DefaultLevel {
+		return nil
+	}
+	return []zstd.EOption{zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level))}
+}
+
+// NewWriter returns a writer which compresses the data written to it by the
+// given compression codec and level, and writes the compressed data to w.
+// The writer must be closed to flush the data. Snappy is not supported, since
+// Encode uses the block format of snappy instead of the stream format.
+func NewWriter(cc string, level int, w io.Writer) (io.WriteCloser, error) {
+	if err := ValidateLevel(cc, level); err != nil {
+		return nil, err
+	}
+	switch cc {
+	case None:
+		return nopWriteCloser{Writer: w}, nil
+	case LZ4:
+		return lz4.NewWriter(w), nil
+	case Zstd:
+		writer, err := zstd.NewWriter(w, zstdEncoderOptions(level)...)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+		}
+		return writer, nil
+	case Gzip:
+		if level == DefaultLevel {
+			level = gzip.DefaultCompression
+		}
+		writer, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+		}
+		return writer, nil
+	default:
+	}
+
+	return nil, cerror.ErrCompressionFailed.GenWithStack("Unsupported compression %s", cc)
+}
+
 // Decode the given data by the given compression codec.
 func Decode(cc string, data []byte) ([]byte, error) {
 	switch cc {
@@ -104,8 +229,63 @@ func Decode(cc string, data []byte) ([]byte, error) {
 		bufferPool.Put(buffer)
 
 		return res, err
+	case Zstd:
+		zstdDecoderOnce.Do(func() {
+			// NewReader never fails without options.
+			zstdDecoder, _ = zstd.NewReader(nil)
+		})
+		res, err := zstdDecoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+		}
+		return res, nil
+	case Gzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+		}
+		defer reader.Close()
+		res, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrCompressionFailed, err)
+		}
+		return res, nil
 	default:
 	}
 
 	return nil, cerror.ErrCompressionFailed.GenWithStack("Unsupported compression %s", cc)
 }
+
+// FileExtension returns the file extension of the data compressed by the
+// given compression, it is empty for None.
+func FileExtension(cc string) string {
+	switch cc {
+	case Snappy:
+		return ".snappy"
+	case LZ4:
+		return ".lz4"
+	case Zstd:
+		return ".zst"
+	case Gzip:
+		return ".gz"
+	default:
+		return ""
+	}
+}
+
+// FromFileExtension returns the compression of the given file extension,
+// it returns None if the extension doesn't belong to any compression.
+func FromFileExtension(ext string) string {
+	for _, cc := range []string{Snappy, LZ4, Zstd, Gzip} {
+		if ext == FileExtension(cc) {
+			return cc
+		}
+	}
+	return None
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
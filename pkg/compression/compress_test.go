@@ -0,0 +1,107 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(strings.Repeat("ticdc compression test data ", 1024))
+	for _, cc := range []string{None, Snappy, LZ4, Zstd, Gzip} {
+		encoded, err := Encode(cc, data)
+		require.NoError(t, err)
+		if cc != None {
+			require.Less(t, len(encoded), len(data))
+		}
+		decoded, err := Decode(cc, encoded)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+		require.Equal(t, cc, FromFileExtension(FileExtension(cc)))
+	}
+
+	_, err := Encode("brotli", data)
+	require.ErrorContains(t, err, "Unsupported compression brotli")
+}
+
+func TestEncodeWithLevel(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(strings.Repeat("ticdc compression level test data ", 1024))
+	for _, tc := range []struct {
+		cc    string
+		level int
+	}{
+		{Zstd, 1}, {Zstd, 3}, {Zstd, 19}, {Zstd, 22},
+		{Gzip, 1}, {Gzip, 6}, {Gzip, 9},
+		{LZ4, DefaultLevel},
+	} {
+		encoded, err := EncodeWithLevel(tc.cc, tc.level, data)
+		require.NoError(t, err)
+		decoded, err := Decode(tc.cc, encoded)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+
+	for _, tc := range []struct {
+		cc    string
+		level int
+	}{
+		{Zstd, 23}, {Zstd, -1}, {Gzip, 10}, {LZ4, 1}, {None, 1},
+	} {
+		require.Error(t, ValidateLevel(tc.cc, tc.level))
+		_, err := EncodeWithLevel(tc.cc, tc.level, data)
+		if tc.cc != None {
+			require.Error(t, err)
+		}
+	}
+
+	// The zstd encoders are cached by level.
+	encoder1, err := getZstdEncoder(3)
+	require.NoError(t, err)
+	encoder2, err := getZstdEncoder(3)
+	require.NoError(t, err)
+	require.Same(t, encoder1, encoder2)
+}
+
+func TestNewWriter(t *testing.T) {
+	t.Parallel()
+
+	for _, cc := range []string{None, LZ4, Zstd, Gzip} {
+		var buf bytes.Buffer
+		w, err := NewWriter(cc, DefaultLevel, &buf)
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			_, err = w.Write([]byte("row\n"))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		decoded, err := Decode(cc, buf.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("row\n", 10), string(decoded))
+	}
+
+	_, err := NewWriter(Snappy, DefaultLevel, &bytes.Buffer{})
+	require.Error(t, err)
+	require.Equal(t, ".zst", FileExtension(Zstd))
+	require.Equal(t, ".gz", FileExtension(Gzip))
+	require.Equal(t, "", FileExtension(None))
+	require.Equal(t, None, FromFileExtension(".log"))
+}
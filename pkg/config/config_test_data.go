@@ -130,6 +130,7 @@ const (
   "sorter": {
     "sort-dir": "/tmp/sorter",
     "cache-size-in-mb": 128,
+    "hybrid-memory-percentage": 0,
     "max-memory-percentage": 0,
     "max-memory-consumption": 0,
     "num-workerpool-goroutine": 0,
@@ -153,7 +154,8 @@ const (
     "frontier-concurrent": 8,
     "worker-pool-size": 0,
     "region-scan-limit": 40,
-    "region-retry-duration": 60000000000
+    "region-retry-duration": 60000000000,
+    "enable-shared-puller": false
   },
   "debug": {
     "db": {
@@ -366,7 +368,9 @@ const (
     "region-per-span": 0,
     "region-threshold": 100001,
     "write-key-threshold": 100001,
-    "region-per-span": 0
+    "region-per-span": 0,
+    "enable-load-balance": false,
+    "load-balance-threshold": 0
   },
   "integrity": {
     "integrity-check-level": "none",
@@ -539,7 +543,9 @@ const (
   "scheduler": {
     "enable-table-across-nodes": true,
     "region-threshold": 100001,
-    "write-key-threshold": 100001
+    "write-key-threshold": 100001,
+    "enable-load-balance": false,
+    "load-balance-threshold": 0
   },
   "integrity": {
     "integrity-check-level": "none",
@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -54,12 +55,29 @@ type ConsistentConfig struct {
 	UseFileBackend bool `toml:"use-file-backend" json:"use-file-backend"`
 	// Compression is the compression algorithm used for redo log.
 	// Default is "", it means no compression, equals to `none`.
-	// Supported compression algorithms are `none` and `lz4`.
+	// Supported compression algorithms are `none`, `lz4`, `zstd` and `gzip`.
+	// It is recorded in the name of the log files, such as `xxx.zst.log`.
 	Compression string `toml:"compression" json:"compression"`
+	// CompressionLevel is the level of `zstd` and `gzip` compression.
+	// Default is 0, it means the default level of the compression.
+	CompressionLevel int `toml:"compression-level" json:"compression-level,omitempty"`
+	// EncryptionMasterKey is the URI of the master key used to encrypt redo log files,
+	// such as `file:///path/to/keyfile` or `local-kms:///path/to/keyring.json?key-id=k1`.
+	// Default is "", it means redo log files are not encrypted.
+	EncryptionMasterKey string `toml:"encryption-master-key" json:"encryption-master-key,omitempty"`
 	// FlushConcurrency is the concurrency of flushing a single log file.
 	// Default is 1. It means a single log file will be flushed by only one worker.
 	// The singe file concurrent flushing feature supports only `s3` storage.
 	FlushConcurrency int `toml:"flush-concurrency" json:"flush-concurrency,omitempty"`
+	// RetentionTimeInSec is the duration(s) to retain redo log files whose
+	// commit ts is below the flushed checkpoint.
+	// Default is 0, it means log files are removed once they are below the checkpoint,
+	// unless RetentionSize is set.
+	RetentionTimeInSec int64 `toml:"retention-time" json:"retention-time,omitempty"`
+	// RetentionSize is the max total size(MiB) of the retained redo log files
+	// whose commit ts is below the flushed checkpoint.
+	// Default is 0, it means there is no size limit.
+	RetentionSize int64 `toml:"retention-size" json:"retention-size,omitempty"`
 	// MemoryUsage represents the percentage of ReplicaConfig.MemoryQuota
 	// that can be utilized by the redo log module.
 	MemoryUsage *ConsistentMemoryUsage `toml:"memory-usage" json:"memory-usage"`
@@ -98,10 +116,32 @@ func (c *ConsistentConfig) ValidateAndAdjust() error {
 			fmt.Sprintf("The consistent.meta-flush-interval:%d must be equal or greater than %d",
 				c.MetaFlushIntervalInMs, redo.MinFlushIntervalInMs))
 	}
-	if len(c.Compression) > 0 &&
-		c.Compression != compression.None && c.Compression != compression.LZ4 {
+	switch c.Compression {
+	case "", compression.None, compression.LZ4, compression.Zstd, compression.Gzip:
+	default:
 		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
-			fmt.Sprintf("The consistent.compression:%s must be 'none' or 'lz4'", c.Compression))
+			fmt.Sprintf("The consistent.compression:%s must be 'none', 'lz4', 'zstd' or 'gzip'",
+				c.Compression))
+	}
+	if err := compression.ValidateLevel(c.Compression, c.CompressionLevel); err != nil {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.compression-level:%d is invalid for compression '%s'",
+				c.CompressionLevel, c.Compression))
+	}
+	if err := encryption.ValidateMasterKeyURI(c.EncryptionMasterKey); err != nil {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.encryption-master-key is invalid: %s", err.Error()))
+	}
+
+	if c.RetentionTimeInSec < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.retention-time:%d must be equal or greater than 0",
+				c.RetentionTimeInSec))
+	}
+	if c.RetentionSize < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.retention-size:%d must be equal or greater than 0",
+				c.RetentionSize))
 	}
 
 	if c.EncodingWorkerNum == 0 {
@@ -32,6 +32,9 @@ type KVClientConfig struct {
 	RegionScanLimit int `toml:"region-scan-limit" json:"region-scan-limit"`
 	// the total retry duration of connecting a region
 	RegionRetryDuration TomlDuration `toml:"region-retry-duration" json:"region-retry-duration"`
+	// EnableSharedPuller makes changefeeds on a capture share region feeds of
+	// the same spans, so that each span is only pulled once from an upstream.
+	EnableSharedPuller bool `toml:"enable-shared-puller" json:"enable-shared-puller"`
 }
 
 // ValidateAndAdjust validates and adjusts the kv client configuration
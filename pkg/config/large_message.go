@@ -31,7 +31,10 @@ const (
 type LargeMessageHandleConfig struct {
 	LargeMessageHandleOption      string `toml:"large-message-handle-option" json:"large-message-handle-option"`
 	LargeMessageHandleCompression string `toml:"large-message-handle-compression" json:"large-message-handle-compression"`
-	ClaimCheckStorageURI          string `toml:"claim-check-storage-uri" json:"claim-check-storage-uri"`
+	// LargeMessageHandleCompressionLevel is the level of zstd and gzip,
+	// 0 means the default level of the compression.
+	LargeMessageHandleCompressionLevel int    `toml:"large-message-handle-compression-level" json:"large-message-handle-compression-level,omitempty"`
+	ClaimCheckStorageURI               string `toml:"claim-check-storage-uri" json:"claim-check-storage-uri"`
 }
 
 // NewDefaultLargeMessageHandleConfig return the default Config.
@@ -57,6 +60,10 @@ func (c *LargeMessageHandleConfig) AdjustAndValidate(protocol Protocol, enableTi
 		return cerror.ErrInvalidReplicaConfig.GenWithStack(
 			"large message handle compression is not supported, got %s", c.LargeMessageHandleCompression)
 	}
+	if err := compression.ValidateLevel(
+		c.LargeMessageHandleCompression, c.LargeMessageHandleCompressionLevel); err != nil {
+		return cerror.WrapError(cerror.ErrInvalidReplicaConfig, err)
+	}
 	if c.LargeMessageHandleOption == LargeMessageHandleOptionNone {
 		return nil
 	}
@@ -27,11 +27,26 @@ func TestLargeMessageHandle4Compression(t *testing.T) {
 	largeMessageHandle := NewDefaultLargeMessageHandleConfig()
 
 	// unsupported compression, return error
-	largeMessageHandle.LargeMessageHandleCompression = "zstd"
+	largeMessageHandle.LargeMessageHandleCompression = "brotli"
 
 	err := largeMessageHandle.AdjustAndValidate(ProtocolCanalJSON, false)
 	require.ErrorIs(t, err, cerror.ErrInvalidReplicaConfig)
 
+	largeMessageHandle.LargeMessageHandleCompression = compression.Zstd
+	largeMessageHandle.LargeMessageHandleCompressionLevel = 19
+	err = largeMessageHandle.AdjustAndValidate(ProtocolCanalJSON, false)
+	require.NoError(t, err)
+
+	// the level is out of the range of gzip, return error
+	largeMessageHandle.LargeMessageHandleCompression = compression.Gzip
+	err = largeMessageHandle.AdjustAndValidate(ProtocolCanalJSON, false)
+	require.ErrorIs(t, err, cerror.ErrInvalidReplicaConfig)
+
+	largeMessageHandle.LargeMessageHandleCompressionLevel = 9
+	err = largeMessageHandle.AdjustAndValidate(ProtocolCanalJSON, false)
+	require.NoError(t, err)
+	largeMessageHandle.LargeMessageHandleCompressionLevel = 0
+
 	largeMessageHandle.LargeMessageHandleCompression = compression.LZ4
 	err = largeMessageHandle.AdjustAndValidate(ProtocolCanalJSON, false)
 	require.NoError(t, err)
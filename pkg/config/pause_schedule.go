@@ -0,0 +1,91 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/robfig/cron"
+)
+
+// PauseScheduleAction is the action taken when a transition of a
+// pause schedule is reached.
+type PauseScheduleAction string
+
+const (
+	// PauseScheduleActionPause pauses the changefeed.
+	PauseScheduleActionPause PauseScheduleAction = "pause"
+	// PauseScheduleActionResume resumes the changefeed.
+	PauseScheduleActionResume PauseScheduleAction = "resume"
+)
+
+// PauseScheduleConfig represents a recurring window during which a changefeed
+// is paused by the owner, e.g. to keep a downstream maintenance window quiet.
+type PauseScheduleConfig struct {
+	// PauseAt is a standard cron expression, the changefeed is paused
+	// every time it is reached.
+	PauseAt string `toml:"pause-at" json:"pause-at"`
+	// ResumeAt is a standard cron expression, the changefeed is resumed
+	// every time it is reached if it is stopped.
+	ResumeAt string `toml:"resume-at" json:"resume-at"`
+	// TimeZone is the IANA time zone the cron expressions are evaluated in.
+	// The local time zone of the owner is used if it is empty.
+	TimeZone string `toml:"time-zone" json:"time-zone"`
+}
+
+// ValidateAndAdjust validates the pause schedule.
+func (c *PauseScheduleConfig) ValidateAndAdjust() error {
+	_, _, _, err := c.parse()
+	return err
+}
+
+// NextTransition returns the first transition of the schedule strictly
+// after t. The pause wins if both transitions happen at the same time.
+func (c *PauseScheduleConfig) NextTransition(t time.Time) (time.Time, PauseScheduleAction, error) {
+	loc, pause, resume, err := c.parse()
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	t = t.In(loc)
+	nextPause, nextResume := pause.Next(t), resume.Next(t)
+	if !nextPause.After(nextResume) {
+		return nextPause, PauseScheduleActionPause, nil
+	}
+	return nextResume, PauseScheduleActionResume, nil
+}
+
+func (c *PauseScheduleConfig) parse() (*time.Location, cron.Schedule, cron.Schedule, error) {
+	loc := time.Local
+	if c.TimeZone != "" {
+		var err error
+		loc, err = time.LoadLocation(c.TimeZone)
+		if err != nil {
+			return nil, nil, nil, cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				fmt.Sprintf("invalid pause-schedule time-zone %s: %s", c.TimeZone, err))
+		}
+	}
+	pause, err := cron.ParseStandard(c.PauseAt)
+	if err != nil {
+		return nil, nil, nil, cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("invalid pause-schedule pause-at %q: %s", c.PauseAt, err))
+	}
+	resume, err := cron.ParseStandard(c.ResumeAt)
+	if err != nil {
+		return nil, nil, nil, cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("invalid pause-schedule resume-at %q: %s", c.ResumeAt, err))
+	}
+	return loc, pause, resume, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseScheduleValidate(t *testing.T) {
+	t.Parallel()
+
+	c := &PauseScheduleConfig{PauseAt: "0 1 * * *", ResumeAt: "0 3 * * *", TimeZone: "UTC"}
+	require.NoError(t, c.ValidateAndAdjust())
+
+	c.PauseAt = "0 1 * *"
+	require.ErrorIs(t, c.ValidateAndAdjust(), cerror.ErrInvalidReplicaConfig)
+
+	c.PauseAt = "0 1 * * *"
+	c.ResumeAt = ""
+	require.ErrorIs(t, c.ValidateAndAdjust(), cerror.ErrInvalidReplicaConfig)
+
+	c.ResumeAt = "0 3 * * *"
+	c.TimeZone = "Mars/Olympus_Mons"
+	require.ErrorIs(t, c.ValidateAndAdjust(), cerror.ErrInvalidReplicaConfig)
+}
+
+func TestPauseScheduleNextTransition(t *testing.T) {
+	t.Parallel()
+
+	c := &PauseScheduleConfig{PauseAt: "0 1 * * *", ResumeAt: "0 3 * * *", TimeZone: "UTC"}
+	now := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+	next, action, err := c.NextTransition(now)
+	require.NoError(t, err)
+	require.Equal(t, PauseScheduleActionPause, action)
+	require.True(t, next.Equal(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)))
+
+	next, action, err = c.NextTransition(next)
+	require.NoError(t, err)
+	require.Equal(t, PauseScheduleActionResume, action)
+	require.True(t, next.Equal(time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)))
+
+	next, action, err = c.NextTransition(next)
+	require.NoError(t, err)
+	require.Equal(t, PauseScheduleActionPause, action)
+	require.True(t, next.Equal(time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC)))
+
+	// The cron expressions are evaluated in the configured time zone.
+	c.TimeZone = "Asia/Shanghai"
+	next, action, err = c.NextTransition(now)
+	require.NoError(t, err)
+	require.Equal(t, PauseScheduleActionPause, action)
+	require.True(t, next.Equal(time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// ChangefeedPriority is the priority class of a changefeed. Changefeeds sharing
+// captures with higher priorities are served first, and changefeeds with lower
+// priorities are throttled first under memory or CPU pressure.
+type ChangefeedPriority string
+
+const (
+	// ChangefeedPriorityCritical is for changefeeds whose checkpoints should
+	// never be delayed by other changefeeds.
+	ChangefeedPriorityCritical ChangefeedPriority = "critical"
+	// ChangefeedPriorityNormal is the default priority.
+	ChangefeedPriorityNormal ChangefeedPriority = "normal"
+	// ChangefeedPriorityBestEffort is for changefeeds which can be throttled.
+	ChangefeedPriorityBestEffort ChangefeedPriority = "best-effort"
+)
+
+// ChangefeedPriorities are all priorities from the lowest to the highest.
+var ChangefeedPriorities = []ChangefeedPriority{
+	ChangefeedPriorityBestEffort,
+	ChangefeedPriorityNormal,
+	ChangefeedPriorityCritical,
+}
+
+// Level returns the index of the priority in ChangefeedPriorities,
+// a higher level means a higher priority.
+func (p ChangefeedPriority) Level() int {
+	for i, priority := range ChangefeedPriorities {
+		if p == priority {
+			return i
+		}
+	}
+	return ChangefeedPriorityNormal.Level()
+}
+
+// Validate checks whether the priority is valid.
+func (p ChangefeedPriority) Validate() error {
+	for _, priority := range ChangefeedPriorities {
+		if p == priority {
+			return nil
+		}
+	}
+	return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+		fmt.Sprintf("invalid priority %q, it must be one of %v", p, ChangefeedPriorities))
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedPriority(t *testing.T) {
+	t.Parallel()
+
+	require.Less(t, ChangefeedPriorityBestEffort.Level(), ChangefeedPriorityNormal.Level())
+	require.Less(t, ChangefeedPriorityNormal.Level(), ChangefeedPriorityCritical.Level())
+	for _, p := range ChangefeedPriorities {
+		require.NoError(t, p.Validate())
+	}
+	require.ErrorIs(t, ChangefeedPriority("urgent").Validate(), cerror.ErrInvalidReplicaConfig)
+
+	c := GetDefaultReplicaConfig()
+	require.Equal(t, ChangefeedPriorityNormal, c.GetPriority())
+	c.Priority = util.AddressOf(ChangefeedPriorityCritical)
+	require.Equal(t, ChangefeedPriorityCritical, c.GetPriority())
+}
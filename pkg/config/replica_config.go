@@ -100,6 +100,7 @@ var defaultReplicaConfig = &ReplicaConfig{
 		EnableTableAcrossNodes: false,
 		RegionThreshold:        100_000,
 		WriteKeyThreshold:      0,
+		EnableLoadBalance:      false,
 	},
 	Integrity: &integrity.Config{
 		IntegrityCheckLevel:   integrity.CheckLevelNone,
@@ -145,6 +146,17 @@ type replicaConfig struct {
 	// replicate data of same tables from TiDB-1 to TiDB-2 and vice versa.
 	// This feature is only available for TiDB.
 	BDRMode *bool `toml:"bdr-mode" json:"bdr-mode,omitempty"`
+	// EnableSnapshotBackfill indicates whether to scan the existing rows of
+	// the tables newly included by updating the filter, and send them to
+	// the sink along with the incremental changes. The rows are scanned in
+	// resumable batches, each of which is replicated as a transaction.
+	EnableSnapshotBackfill *bool `toml:"enable-snapshot-backfill" json:"enable-snapshot-backfill,omitempty"`
+	// SortDiskQuota limits the disk usage of the sorter in bytes. Tables are
+	// paused if the changefeed uses up its quota, and they are pulled again
+	// from where they are paused after the quota is released.
+	SortDiskQuota *uint64 `toml:"sort-disk-quota" json:"sort-disk-quota,omitempty"`
+	// Priority is the priority class of the changefeed, it's normal if not set.
+	Priority *ChangefeedPriority `toml:"priority" json:"priority,omitempty"`
 	// SyncPointInterval is only available when the downstream is DB.
 	SyncPointInterval *time.Duration `toml:"sync-point-interval" json:"sync-point-interval,omitempty"`
 	// SyncPointRetention is only available when the downstream is DB.
@@ -160,6 +172,8 @@ type replicaConfig struct {
 	Integrity                    *integrity.Config   `toml:"integrity" json:"integrity"`
 	ChangefeedErrorStuckDuration *time.Duration      `toml:"changefeed-error-stuck-duration" json:"changefeed-error-stuck-duration,omitempty"`
 	SyncedStatus                 *SyncedStatusConfig `toml:"synced-status" json:"synced-status,omitempty"`
+	// PauseSchedule pauses and resumes the changefeed at the configured times.
+	PauseSchedule *PauseScheduleConfig `toml:"pause-schedule" json:"pause-schedule,omitempty"`
 
 	// Deprecated: we don't use this field since v8.0.0.
 	SQLMode string `toml:"sql-mode" json:"sql-mode"`
@@ -312,6 +326,12 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 				"integrity check enabled and column selector set, not allowed")
 
 		}
+
+		if c.Integrity.Enabled() && len(c.Sink.ColumnMasks) != 0 {
+			log.Error("it's not allowed to enable the integrity check and column mask at the same time")
+			return cerror.ErrInvalidReplicaConfig.GenWithStack(
+				"integrity check enabled and column mask set, not allowed")
+		}
 	}
 
 	if c.ChangefeedErrorStuckDuration != nil &&
@@ -323,6 +343,18 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 					minChangeFeedErrorStuckDuration.Seconds()))
 	}
 
+	if c.PauseSchedule != nil {
+		if err := c.PauseSchedule.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+
+	if c.Priority != nil {
+		if err := c.Priority.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -344,6 +376,14 @@ func (c *ReplicaConfig) FixMemoryQuota() {
 	c.MemoryQuota = DefaultChangefeedMemoryQuota
 }
 
+// GetPriority returns the priority of the changefeed, it's normal if not set.
+func (c *ReplicaConfig) GetPriority() ChangefeedPriority {
+	if c.Priority == nil {
+		return ChangefeedPriorityNormal
+	}
+	return *c.Priority
+}
+
 // isSinkCompatibleWithSpanReplication returns true if the sink uri is
 // compatible with span replication.
 func isSinkCompatibleWithSpanReplication(u *url.URL) bool {
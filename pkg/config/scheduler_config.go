@@ -20,6 +20,10 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
+// DefaultLoadBalanceThreshold is the default ratio by which the load of a
+// TiCDC node may exceed the average load.
+const DefaultLoadBalanceThreshold = 0.2
+
 // ChangefeedSchedulerConfig is per changefeed scheduler settings.
 type ChangefeedSchedulerConfig struct {
 	// EnableTableAcrossNodes set true to split one table to multiple spans and
@@ -31,10 +35,20 @@ type ChangefeedSchedulerConfig struct {
 	WriteKeyThreshold int `toml:"write-key-threshold" json:"write-key-threshold"`
 	// Deprecated.
 	RegionPerSpan int `toml:"region-per-span" json:"region-per-span"`
+	// EnableLoadBalance set true to place and rebalance spans by their
+	// write and sink load instead of the number of spans on each TiCDC node.
+	EnableLoadBalance bool `toml:"enable-load-balance" json:"enable-load-balance"`
+	// LoadBalanceThreshold is the ratio by which the load of a TiCDC node may
+	// exceed the average load before its spans are moved away.
+	// 0 means DefaultLoadBalanceThreshold.
+	LoadBalanceThreshold float64 `toml:"load-balance-threshold" json:"load-balance-threshold"`
 }
 
 // Validate validates the config.
 func (c *ChangefeedSchedulerConfig) Validate() error {
+	if c.EnableLoadBalance && c.LoadBalanceThreshold < 0 {
+		return errors.New("load-balance-threshold must not be negative")
+	}
 	if !c.EnableTableAcrossNodes {
 		return nil
 	}
@@ -68,6 +82,8 @@ type SchedulerConfig struct {
 
 	// ChangefeedSettings is setting by changefeed.
 	ChangefeedSettings *ChangefeedSchedulerConfig `toml:"-" json:"-"`
+	// Priority is the priority of the changefeed.
+	Priority ChangefeedPriority `toml:"-" json:"-"`
 }
 
 // NewDefaultSchedulerConfig return the default scheduler configuration.
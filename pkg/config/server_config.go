@@ -123,6 +123,7 @@ var defaultServerConfig = &ServerConfig{
 		// The default TiKV region election timeout is [10s, 20s],
 		// Use 1 minute to cover region leader missing.
 		RegionRetryDuration: TomlDuration(time.Minute),
+		EnableSharedPuller:  false,
 	},
 	Debug: &DebugConfig{
 		DB: &DBConfig{
@@ -139,7 +139,9 @@ type SinkConfig struct {
 	DispatchRules []*DispatchRule `toml:"dispatchers" json:"dispatchers,omitempty"`
 
 	ColumnSelectors []*ColumnSelector `toml:"column-selectors" json:"column-selectors,omitempty"`
-	// SchemaRegistry is only available when the downstream is MQ using avro protocol.
+	// ColumnMasks is only available when the downstream is MQ or Storage.
+	ColumnMasks []*ColumnMask `toml:"column-masks" json:"column-masks,omitempty"`
+	// SchemaRegistry is only available when the downstream is MQ using avro or protobuf protocol.
 	SchemaRegistry *string `toml:"schema-registry" json:"schema-registry,omitempty"`
 	// EncoderConcurrency is only available when the downstream is MQ.
 	EncoderConcurrency *int `toml:"encoder-concurrency" json:"encoder-concurrency,omitempty"`
@@ -202,6 +204,11 @@ type SinkConfig struct {
 	OpenProtocol *OpenProtocolConfig `toml:"open" json:"open,omitempty"`
 	// DebeziumConfig related configurations
 	Debezium *DebeziumConfig `toml:"debezium" json:"debezium,omitempty"`
+
+	// DeadLetterQueue is only available when the downstream is MySQL compatible.
+	// Transactions which fail to apply with non-retryable errors are written
+	// to it instead of stopping the changefeed.
+	DeadLetterQueue *DeadLetterQueueConfig `toml:"dead-letter-queue" json:"dead-letter-queue,omitempty"`
 }
 
 // MaskSensitiveData masks sensitive data in SinkConfig
@@ -215,10 +222,15 @@ func (s *SinkConfig) MaskSensitiveData() {
 	if s.PulsarConfig != nil {
 		s.PulsarConfig.MaskSensitiveData()
 	}
+	for _, mask := range s.ColumnMasks {
+		if mask.Salt != "" {
+			mask.Salt = "******"
+		}
+	}
 }
 
 // ShouldSendBootstrapMsg returns whether the sink should send bootstrap message.
-// Only enable bootstrap sending function for simple protocol
+// Only enable bootstrap sending function for simple and protobuf protocol
 // and when both send-bootstrap-interval-in-sec and send-bootstrap-in-msg-count are > 0
 func (s *SinkConfig) ShouldSendBootstrapMsg() bool {
 	if s == nil {
@@ -226,7 +238,7 @@ func (s *SinkConfig) ShouldSendBootstrapMsg() bool {
 	}
 	protocol := util.GetOrZero(s.Protocol)
 
-	return protocol == ProtocolSimple.String() &&
+	return (protocol == ProtocolSimple.String() || protocol == ProtocolProtobuf.String()) &&
 		util.GetOrZero(s.SendBootstrapIntervalInSec) > 0 &&
 		util.GetOrZero(s.SendBootstrapInMsgCount) > 0
 }
@@ -399,6 +411,59 @@ type ColumnSelector struct {
 	Columns []string `toml:"columns" json:"columns"`
 }
 
+const (
+	// ColumnMaskActionHash replaces the value with the hex encoded SHA-256
+	// digest of the salted value.
+	ColumnMaskActionHash = "hash"
+	// ColumnMaskActionTruncate keeps the first `length` characters of the value.
+	ColumnMaskActionTruncate = "truncate"
+	// ColumnMaskActionNullify replaces the value with NULL.
+	ColumnMaskActionNullify = "nullify"
+	// ColumnMaskActionRedact replaces the letters and digits of the value with `*`,
+	// except the last `length` characters, and keeps the other characters,
+	// so the format of the value is preserved.
+	ColumnMaskActionRedact = "redact"
+)
+
+// ColumnMask represents a column mask rule for tables.
+type ColumnMask struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	Columns []string `toml:"columns" json:"columns"`
+	// Action is one of `hash`, `truncate`, `nullify` and `redact`.
+	Action string `toml:"action" json:"action"`
+	// Salt is only used by the `hash` action.
+	Salt string `toml:"salt" json:"salt,omitempty"`
+	// Length is used by the `truncate` and `redact` actions.
+	Length int `toml:"length" json:"length,omitempty"`
+}
+
+// Validate checks the column mask rule.
+func (m *ColumnMask) Validate() error {
+	if len(m.Matcher) == 0 || len(m.Columns) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"matcher and columns of the column mask should not be empty")
+	}
+	switch m.Action {
+	case ColumnMaskActionHash, ColumnMaskActionNullify:
+	case ColumnMaskActionTruncate:
+		if m.Length <= 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"length of the truncate column mask should be greater than 0, but got %d", m.Length)
+		}
+	case ColumnMaskActionRedact:
+		if m.Length < 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"length of the redact column mask should not be negative, but got %d", m.Length)
+		}
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"unsupported column mask action %s, supported actions are %s, %s, %s and %s",
+			m.Action, ColumnMaskActionHash, ColumnMaskActionTruncate,
+			ColumnMaskActionNullify, ColumnMaskActionRedact)
+	}
+	return nil
+}
+
 // CodecConfig represents a MQ codec configuration
 type CodecConfig struct {
 	EnableTiDBExtension            *bool   `toml:"enable-tidb-extension" json:"enable-tidb-extension,omitempty"`
@@ -450,6 +515,13 @@ type KafkaConfig struct {
 
 	// OutputRawChangeEvent controls whether to split the update pk/uk events.
 	OutputRawChangeEvent *bool `toml:"output-raw-change-event" json:"output-raw-change-event,omitempty"`
+
+	// EnableTransaction wraps every table sink flush in a Kafka transaction,
+	// so that `read_committed` consumers see each row exactly once.
+	EnableTransaction *bool `toml:"enable-transaction" json:"enable-transaction,omitempty"`
+	// TransactionMarkerTopic is the topic used to record the last committed
+	// flush of each table. Defaults to a per-changefeed topic.
+	TransactionMarkerTopic *string `toml:"transaction-marker-topic" json:"transaction-marker-topic,omitempty"`
 }
 
 // GetOutputRawChangeEvent returns the value of OutputRawChangeEvent
@@ -691,6 +763,20 @@ type CloudStorageConfig struct {
 
 	// OutputRawChangeEvent controls whether to split the update pk/uk events.
 	OutputRawChangeEvent *bool `toml:"output-raw-change-event" json:"output-raw-change-event,omitempty"`
+
+	// Compression is the codec used to compress the data files, and
+	// CompressionLevel is only used by zstd and gzip.
+	Compression      *string `toml:"compression" json:"compression,omitempty"`
+	CompressionLevel *int    `toml:"compression-level" json:"compression-level,omitempty"`
+
+	// EncryptionMasterKey is the URI of the master key used to encrypt the data
+	// files, such as `file:///path/to/keyfile`. The data files are not encrypted
+	// if it is empty.
+	EncryptionMasterKey *string `toml:"encryption-master-key" json:"encryption-master-key,omitempty"`
+
+	// TableFormat is the table format maintained on top of the data files,
+	// only "iceberg" is supported now. It is disabled if it is empty.
+	TableFormat *string `toml:"table-format" json:"table-format,omitempty"`
 }
 
 // GetOutputRawChangeEvent returns the value of OutputRawChangeEvent
@@ -706,7 +792,17 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 		return err
 	}
 
-	if sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+	if s.DeadLetterQueue != nil {
+		if !sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"dead-letter-queue is only supported by MySQL compatible sinks")
+		}
+		if err := s.DeadLetterQueue.validateAndAdjust(); err != nil {
+			return err
+		}
+	}
+
+	if sink.IsMySQLCompatibleScheme(sinkURI.Scheme) || sink.IsPostgresScheme(sinkURI.Scheme) {
 		return nil
 	}
 
@@ -772,6 +868,12 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL) error {
 		}
 	}
 
+	for _, mask := range s.ColumnMasks {
+		if err := mask.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if util.GetOrZero(s.EncoderConcurrency) < 0 {
 		return cerror.ErrSinkInvalidConfig.GenWithStack(
 			"encoder-concurrency should greater than 0, but got %d", s.EncoderConcurrency)
@@ -847,7 +949,8 @@ func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 		zap.String("txnAtomicity", string(util.GetOrZero(s.TxnAtomicity))))
 
 	// Check that protocol config is compatible with the scheme.
-	if sink.IsMySQLCompatibleScheme(sinkURI.Scheme) && s.Protocol != nil {
+	if (sink.IsMySQLCompatibleScheme(sinkURI.Scheme) || sink.IsPostgresScheme(sinkURI.Scheme)) &&
+		s.Protocol != nil {
 		return cerror.ErrSinkURIInvalid.GenWithStackByArgs(fmt.Sprintf("protocol %s "+
 			"is incompatible with %s scheme", util.GetOrZero(s.Protocol), sinkURI.Scheme))
 	}
@@ -1024,6 +1127,66 @@ type OpenProtocolConfig struct {
 	OutputOldValue bool `toml:"output-old-value" json:"output-old-value"`
 }
 
+// DefaultDeadLetterQueueMaxErrorCount is the default max number of transactions
+// written to the dead letter queue before the changefeed fails.
+const DefaultDeadLetterQueueMaxErrorCount = 100
+
+// DeadLetterQueueConfig represents the dead letter queue of a sink.
+type DeadLetterQueueConfig struct {
+	// URI is where failed transactions are written to. It can be a local path,
+	// an external storage URI such as s3://bucket/prefix, or a kafka URI
+	// whose path is the topic.
+	URI string `toml:"uri" json:"uri"`
+	// Protocol is used to encode the failed transactions, canal-json by default.
+	Protocol string `toml:"protocol" json:"protocol,omitempty"`
+	// MaxErrorCount is the max number of failed transactions to tolerate,
+	// the changefeed fails once it is exceeded. The failed transactions are
+	// counted since the sink is created, the count is reset once the sink is
+	// recreated, e.g., after the changefeed restarts from an error.
+	MaxErrorCount int64 `toml:"max-error-count" json:"max-error-count,omitempty"`
+}
+
+func (c *DeadLetterQueueConfig) validateAndAdjust() error {
+	if c.URI == "" {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"dead-letter-queue.uri must be set")
+	}
+	uri, err := url.Parse(c.URI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrInvalidReplicaConfig, err)
+	}
+	if uri.Scheme != "" && !sink.IsStorageScheme(uri.Scheme) &&
+		uri.Scheme != sink.KafkaScheme && uri.Scheme != sink.KafkaSSLScheme {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("dead-letter-queue.uri with scheme %s is not supported", uri.Scheme))
+	}
+
+	if c.Protocol == "" {
+		c.Protocol = ProtocolCanalJSON.String()
+	}
+	protocol, err := ParseSinkProtocolFromString(c.Protocol)
+	if err != nil {
+		return err
+	}
+	// Only the protocols which encode one row into one self-described message
+	// are supported, so that each row can be read back separately.
+	switch protocol {
+	case ProtocolCanalJSON, ProtocolSimple, ProtocolDebezium:
+	default:
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("dead-letter-queue.protocol %s is not supported", c.Protocol))
+	}
+
+	if c.MaxErrorCount < 0 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"dead-letter-queue.max-error-count must not be negative")
+	}
+	if c.MaxErrorCount == 0 {
+		c.MaxErrorCount = DefaultDeadLetterQueueMaxErrorCount
+	}
+	return nil
+}
+
 // DebeziumConfig represents the configurations for debezium protocol encoding
 type DebeziumConfig struct {
 	OutputOldValue bool `toml:"output-old-value" json:"output-old-value"`
@@ -40,6 +40,8 @@ const (
 	ProtocolCsv
 	ProtocolDebezium
 	ProtocolSimple
+	ProtocolProtobuf
+	ProtocolParquet
 )
 
 // IsBatchEncode returns whether the protocol is a batch encoder.
@@ -72,6 +74,10 @@ func ParseSinkProtocolFromString(protocol string) (Protocol, error) {
 		return ProtocolDebezium, nil
 	case "simple":
 		return ProtocolSimple, nil
+	case "protobuf":
+		return ProtocolProtobuf, nil
+	case "parquet":
+		return ProtocolParquet, nil
 	default:
 		return ProtocolUnknown, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(protocol)
 	}
@@ -100,6 +106,10 @@ func (p Protocol) String() string {
 		return "debezium"
 	case ProtocolSimple:
 		return "simple"
+	case ProtocolProtobuf:
+		return "protobuf"
+	case ProtocolParquet:
+		return "parquet"
 	default:
 		panic("unreachable")
 	}
@@ -62,6 +62,14 @@ func TestParseSinkProtocolFromString(t *testing.T) {
 			protocol:             "open-protocol",
 			expectedProtocolEnum: ProtocolOpen,
 		},
+		{
+			protocol:             "protobuf",
+			expectedProtocolEnum: ProtocolProtobuf,
+		},
+		{
+			protocol:             "parquet",
+			expectedProtocolEnum: ProtocolParquet,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -109,6 +117,14 @@ func TestString(t *testing.T) {
 			protocolEnum:     ProtocolOpen,
 			expectedProtocol: "open-protocol",
 		},
+		{
+			protocolEnum:     ProtocolProtobuf,
+			expectedProtocol: "protobuf",
+		},
+		{
+			protocolEnum:     ProtocolParquet,
+			expectedProtocol: "parquet",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -418,6 +418,77 @@ func TestValidateAndAdjustCSVConfig(t *testing.T) {
 	}
 }
 
+func TestValidateAndAdjustDeadLetterQueueConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		sinkURI string
+		config  *DeadLetterQueueConfig
+		wantErr string
+	}{
+		{
+			name:    "local path",
+			sinkURI: "mysql://127.0.0.1:3306",
+			config:  &DeadLetterQueueConfig{URI: "/tmp/dlq"},
+		},
+		{
+			name:    "kafka topic",
+			sinkURI: "tidb://127.0.0.1:4000",
+			config: &DeadLetterQueueConfig{
+				URI: "kafka://127.0.0.1:9092/dlq", Protocol: "simple", MaxErrorCount: 10,
+			},
+		},
+		{
+			name:    "not a mysql sink",
+			sinkURI: "kafka://127.0.0.1:9092/topic?protocol=open-protocol",
+			config:  &DeadLetterQueueConfig{URI: "file:///tmp/dlq"},
+			wantErr: "dead-letter-queue is only supported by MySQL compatible sinks",
+		},
+		{
+			name:    "empty uri",
+			sinkURI: "mysql://127.0.0.1:3306",
+			config:  &DeadLetterQueueConfig{},
+			wantErr: "dead-letter-queue.uri must be set",
+		},
+		{
+			name:    "unsupported scheme",
+			sinkURI: "mysql://127.0.0.1:3306",
+			config:  &DeadLetterQueueConfig{URI: "mysql://127.0.0.1:3307"},
+			wantErr: "dead-letter-queue.uri with scheme mysql is not supported",
+		},
+		{
+			name:    "unsupported protocol",
+			sinkURI: "mysql://127.0.0.1:3306",
+			config:  &DeadLetterQueueConfig{URI: "file:///tmp/dlq", Protocol: "csv"},
+			wantErr: "dead-letter-queue.protocol csv is not supported",
+		},
+		{
+			name:    "negative max error count",
+			sinkURI: "mysql://127.0.0.1:3306",
+			config:  &DeadLetterQueueConfig{URI: "file:///tmp/dlq", MaxErrorCount: -1},
+			wantErr: "dead-letter-queue.max-error-count must not be negative",
+		},
+	}
+	for _, c := range tests {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sinkURI, err := url.Parse(tc.sinkURI)
+			require.NoError(t, err)
+			s := GetDefaultReplicaConfig().Sink
+			s.DeadLetterQueue = tc.config
+			err = s.validateAndAdjust(sinkURI)
+			if tc.wantErr != "" {
+				require.Regexp(t, tc.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, s.DeadLetterQueue.Protocol)
+			require.Positive(t, s.DeadLetterQueue.MaxErrorCount)
+		})
+	}
+}
+
 func TestValidateAndAdjustStorageConfig(t *testing.T) {
 	t.Parallel()
 
@@ -447,6 +518,9 @@ func TestShouldSendBootstrapMsg(t *testing.T) {
 	sinkConfig.Protocol = &protocol
 	require.True(t, sinkConfig.ShouldSendBootstrapMsg())
 
+	protocol = "protobuf"
+	require.True(t, sinkConfig.ShouldSendBootstrapMsg())
+
 	count := int32(0)
 	sinkConfig.SendBootstrapInMsgCount = &count
 	require.False(t, sinkConfig.ShouldSendBootstrapMsg())
@@ -27,6 +27,12 @@ type SorterConfig struct {
 	// Cache size of sorter in MB.
 	CacheSizeInMB uint64 `toml:"cache-size-in-mb" json:"cache-size-in-mb"`
 
+	// HybridMemoryPercentage is the percentage of the memory quota of every
+	// changefeed used to sort events in memory. If it's not 0, events are
+	// sorted in memory until the share is used up, and only the overflow is
+	// spilled to the sort-dir. The sinks of the changefeed use the rest.
+	HybridMemoryPercentage uint64 `toml:"hybrid-memory-percentage" json:"hybrid-memory-percentage"`
+
 	// Deprecated: we don't use this field anymore.
 	MaxMemoryPercentage int `toml:"max-memory-percentage" json:"max-memory-percentage"`
 	// Deprecated: we don't use this field anymore.
@@ -44,5 +50,8 @@ func (c *SorterConfig) ValidateAndAdjust() error {
 	if c.CacheSizeInMB < 8 || c.CacheSizeInMB*uint64(1<<20) > uint64(math.MaxInt64) {
 		return errors.ErrIllegalSorterParameter.GenWithStackByArgs("cache-size-in-mb should be greater than 8(MB)")
 	}
+	if c.HybridMemoryPercentage >= 100 {
+		return errors.ErrIllegalSorterParameter.GenWithStackByArgs("hybrid-memory-percentage should be less than 100")
+	}
 	return nil
 }
@@ -0,0 +1,153 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// The layout of an encrypted file is as follows, the integers are encoded
+// in big endian:
+//
+//	| magic (4) | version (1) | key ID length (2) | key ID |
+//	| wrapped data key length (2) | wrapped data key | nonce + ciphertext |
+//
+// Each file is encrypted by a random data key with AES-256-GCM, and the data
+// key is wrapped by the master key whose ID is recorded in the header, so the
+// file can be decrypted after the master key is rotated. The header is also
+// authenticated as the additional data of the ciphertext.
+const (
+	formatVersion = 1
+	dataKeyLen    = 32
+)
+
+var magic = []byte{'T', 'C', 'E', 'F'}
+
+// Encrypt encrypts the data with a new data key wrapped by the master key.
+func Encrypt(mk MasterKey, data []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeyLen)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionFailed, err)
+	}
+	wrapped, err := mk.Wrap(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	keyID := mk.ID()
+	if len(keyID) > 0xFFFF || len(wrapped) > 0xFFFF {
+		return nil, cerror.ErrEncryptionFailed.GenWithStack(
+			"the key ID or the wrapped data key is too long")
+	}
+
+	hdr := make([]byte, 0, len(magic)+1+2+len(keyID)+2+len(wrapped))
+	hdr = append(hdr, magic...)
+	hdr = append(hdr, formatVersion)
+	hdr = binary.BigEndian.AppendUint16(hdr, uint16(len(keyID)))
+	hdr = append(hdr, keyID...)
+	hdr = binary.BigEndian.AppendUint16(hdr, uint16(len(wrapped)))
+	hdr = append(hdr, wrapped...)
+
+	ciphertext, err := seal(dataKey, data, hdr)
+	if err != nil {
+		return nil, err
+	}
+	return append(hdr, ciphertext...), nil
+}
+
+// Decrypt decrypts the data returned by Encrypt. If no master key is
+// provided, the data which is not encrypted is returned as it is. Otherwise
+// the data must be encrypted, so that a file replaced by a plaintext one is
+// never accepted.
+func Decrypt(mk MasterKey, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		if mk != nil {
+			return nil, cerror.ErrDecryptionFailed.GenWithStack(
+				"the data is not encrypted, but master key %s is provided", mk.ID())
+		}
+		return data, nil
+	}
+	h, err := parseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if mk == nil {
+		return nil, cerror.ErrDecryptionFailed.GenWithStack(
+			"the data is encrypted by master key %s, but no master key is provided", h.keyID)
+	}
+	dataKey, err := mk.Unwrap(h.keyID, h.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, data[h.size:], data[:h.size])
+}
+
+// IsEncrypted returns true if the data is returned by Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// KeyID returns the ID of the master key which wraps the data key of the
+// encrypted data.
+func KeyID(data []byte) (string, error) {
+	h, err := parseHeader(data)
+	if err != nil {
+		return "", err
+	}
+	return h.keyID, nil
+}
+
+type header struct {
+	keyID      string
+	wrappedKey []byte
+	// size is the length of the header in bytes.
+	size int
+}
+
+func parseHeader(data []byte) (*header, error) {
+	if !IsEncrypted(data) {
+		return nil, cerror.ErrDecryptionFailed.GenWithStack("the data is not encrypted")
+	}
+	off := len(magic)
+	if len(data) < off+1 || data[off] != formatVersion {
+		return nil, cerror.ErrDecryptionFailed.GenWithStack("unsupported encryption format")
+	}
+	off++
+
+	readBytes := func() ([]byte, error) {
+		if len(data) < off+2 {
+			return nil, cerror.ErrDecryptionFailed.GenWithStack("the encryption header is corrupted")
+		}
+		n := int(binary.BigEndian.Uint16(data[off:]))
+		off += 2
+		if len(data) < off+n {
+			return nil, cerror.ErrDecryptionFailed.GenWithStack("the encryption header is corrupted")
+		}
+		b := data[off : off+n]
+		off += n
+		return b, nil
+	}
+	keyID, err := readBytes()
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := readBytes()
+	if err != nil {
+		return nil, err
+	}
+	return &header{keyID: string(keyID), wrappedKey: wrappedKey, size: off}, nil
+}
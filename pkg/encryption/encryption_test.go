@@ -0,0 +1,117 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeyFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	key := hex.EncodeToString([]byte(strings.Repeat("k", masterKeyLen)))
+	mk, err := NewMasterKey("file://" + writeKeyFile(t, dir, "key", key+"\n"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(mk.ID(), SchemeFile+":"))
+
+	data := []byte("ticdc envelope encryption test data")
+	encrypted, err := Encrypt(mk, data)
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(encrypted))
+	require.NotContains(t, string(encrypted), string(data))
+	keyID, err := KeyID(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, mk.ID(), keyID)
+
+	decrypted, err := Decrypt(mk, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+
+	// the plaintext is returned as it is
+	decrypted, err = Decrypt(nil, data)
+	require.NoError(t, err)
+	require.Equal(t, data, decrypted)
+
+	_, err = Decrypt(nil, encrypted)
+	require.ErrorContains(t, err, "no master key is provided")
+
+	// the plaintext is rejected if a master key is provided
+	_, err = Decrypt(mk, data)
+	require.ErrorContains(t, err, "the data is not encrypted")
+
+	// the header is authenticated
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xFF
+	_, err = Decrypt(mk, tampered)
+	require.Error(t, err)
+
+	otherKey := hex.EncodeToString([]byte(strings.Repeat("o", masterKeyLen)))
+	other, err := NewMasterKey(writeKeyFile(t, dir, "other", otherKey))
+	require.NoError(t, err)
+	_, err = Decrypt(other, encrypted)
+	require.ErrorContains(t, err, "is provided")
+}
+
+func TestLocalKMSKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	k1 := hex.EncodeToString([]byte(strings.Repeat("1", masterKeyLen)))
+	k2 := hex.EncodeToString([]byte(strings.Repeat("2", masterKeyLen)))
+	keyring := writeKeyFile(t, dir, "keyring.json", `{"k1": "`+k1+`", "k2": "`+k2+`"}`)
+
+	mk1, err := NewMasterKey("local-kms://" + keyring + "?key-id=k1")
+	require.NoError(t, err)
+	require.Equal(t, "k1", mk1.ID())
+	encrypted, err := Encrypt(mk1, []byte("data"))
+	require.NoError(t, err)
+
+	// the data encrypted by the old key can be decrypted after rotation.
+	mk2, err := NewMasterKey("local-kms://" + keyring + "?key-id=k2")
+	require.NoError(t, err)
+	decrypted, err := Decrypt(mk2, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), decrypted)
+
+	_, err = NewMasterKey("local-kms://" + keyring + "?key-id=k3")
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestValidateMasterKeyURI(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateMasterKeyURI(""))
+	require.NoError(t, ValidateMasterKeyURI("/path/to/key"))
+	require.NoError(t, ValidateMasterKeyURI("file:///path/to/key"))
+	require.NoError(t, ValidateMasterKeyURI("local-kms:///path/to/keyring.json?key-id=k1"))
+	require.Error(t, ValidateMasterKeyURI("local-kms:///path/to/keyring.json"))
+	require.Error(t, ValidateMasterKeyURI("kms://key"))
+	require.Error(t, ValidateMasterKeyURI("file://"))
+
+	_, err := NewMasterKey(writeKeyFile(t, t.TempDir(), "short", "abcd"))
+	require.ErrorContains(t, err, "must be 32 bytes")
+}
@@ -0,0 +1,248 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// SchemeFile is the scheme of a master key stored in a local keyfile,
+	// the keyfile contains a hex encoded 256-bit key.
+	SchemeFile = "file"
+	// SchemeLocalKMS is the scheme of a master key stored in a local keyring,
+	// which stands in for a KMS. The keyring is a JSON object which maps the
+	// key IDs to hex encoded 256-bit keys, the `key-id` parameter selects the
+	// key used to wrap new data keys, the others are only used to unwrap the
+	// data keys of the existing files, so that the master key can be rotated.
+	SchemeLocalKMS = "local-kms"
+
+	masterKeyLen = 32
+)
+
+// MasterKey wraps and unwraps the data keys of the encrypted files.
+type MasterKey interface {
+	// ID returns the ID of the key used to wrap new data keys,
+	// it is recorded in the header of the encrypted files.
+	ID() string
+	// Wrap encrypts the given data key.
+	Wrap(dataKey []byte) ([]byte, error)
+	// Unwrap decrypts the given data key which is wrapped by the key with keyID.
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// NewMasterKey loads the master key from the given URI, such as
+// `file:///path/to/keyfile` or `local-kms:///path/to/keyring.json?key-id=k1`.
+// A path without scheme is treated as a keyfile. It returns nil if the URI
+// is empty, which means the encryption is disabled.
+func NewMasterKey(uri string) (MasterKey, error) {
+	if uri == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionMasterKeyInvalid, err)
+	}
+	var mk MasterKey
+	switch strings.ToLower(u.Scheme) {
+	case "", SchemeFile:
+		mk, err = newFileMasterKey(u.Path)
+	case SchemeLocalKMS:
+		mk, err = newLocalKMSMasterKey(u.Path, u.Query().Get("key-id"))
+	default:
+		return nil, cerror.ErrEncryptionMasterKeyInvalid.GenWithStack(
+			"unsupported master key scheme %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mk, nil
+}
+
+// ValidateMasterKeyURI checks the format of the master key URI without
+// loading the key, the key may only be available on the capture nodes.
+func ValidateMasterKeyURI(uri string) error {
+	if uri == "" {
+		return nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrEncryptionMasterKeyInvalid, err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", SchemeFile:
+	case SchemeLocalKMS:
+		if u.Query().Get("key-id") == "" {
+			return cerror.ErrEncryptionMasterKeyInvalid.GenWithStack(
+				"key-id of the %s master key is required", SchemeLocalKMS)
+		}
+	default:
+		return cerror.ErrEncryptionMasterKeyInvalid.GenWithStack(
+			"unsupported master key scheme %s", u.Scheme)
+	}
+	if u.Path == "" {
+		return cerror.ErrEncryptionMasterKeyInvalid.GenWithStack(
+			"path of the master key is required")
+	}
+	return nil
+}
+
+// fileMasterKey is a single key loaded from a keyfile, its ID is the
+// fingerprint of the key.
+type fileMasterKey struct {
+	id  string
+	key []byte
+}
+
+func newFileMasterKey(path string) (*fileMasterKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionMasterKeyInvalid, err)
+	}
+	key, err := decodeKey(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(key)
+	return &fileMasterKey{
+		id:  SchemeFile + ":" + hex.EncodeToString(fingerprint[:8]),
+		key: key,
+	}, nil
+}
+
+func (k *fileMasterKey) ID() string {
+	return k.id
+}
+
+func (k *fileMasterKey) Wrap(dataKey []byte) ([]byte, error) {
+	return seal(k.key, dataKey, []byte(k.id))
+}
+
+func (k *fileMasterKey) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != k.id {
+		return nil, cerror.ErrDecryptionFailed.GenWithStack(
+			"the data key is wrapped by master key %s, but %s is provided", keyID, k.id)
+	}
+	return open(k.key, wrapped, []byte(keyID))
+}
+
+// localKMSMasterKey is a keyring loaded from a local file.
+type localKMSMasterKey struct {
+	id   string
+	keys map[string][]byte
+}
+
+func newLocalKMSMasterKey(path, keyID string) (*localKMSMasterKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionMasterKeyInvalid, err)
+	}
+	var keyring map[string]string
+	if err := json.Unmarshal(content, &keyring); err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionMasterKeyInvalid, err)
+	}
+	k := &localKMSMasterKey{
+		id:   keyID,
+		keys: make(map[string][]byte, len(keyring)),
+	}
+	for id, s := range keyring {
+		if k.keys[id], err = decodeKey(s); err != nil {
+			return nil, errors.Annotatef(err, "key %s", id)
+		}
+	}
+	if _, ok := k.keys[keyID]; !ok {
+		return nil, cerror.ErrEncryptionMasterKeyInvalid.GenWithStack(
+			"key %s is not found in the keyring %s", keyID, path)
+	}
+	return k, nil
+}
+
+func (k *localKMSMasterKey) ID() string {
+	return k.id
+}
+
+func (k *localKMSMasterKey) Wrap(dataKey []byte) ([]byte, error) {
+	return seal(k.keys[k.id], dataKey, []byte(k.id))
+}
+
+func (k *localKMSMasterKey) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, cerror.ErrDecryptionFailed.GenWithStack(
+			"master key %s is not found in the keyring", keyID)
+	}
+	return open(key, wrapped, []byte(keyID))
+}
+
+func decodeKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionMasterKeyInvalid, err)
+	}
+	if len(key) != masterKeyLen {
+		return nil, cerror.ErrEncryptionMasterKeyInvalid.GenWithStack(
+			"the master key must be %d bytes, got %d", masterKeyLen, len(key))
+	}
+	return key, nil
+}
+
+// seal encrypts the plaintext by AES-GCM, the random nonce is prepended to
+// the returned ciphertext.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionFailed, err)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncryptionFailed, err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the ciphertext returned by seal.
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDecryptionFailed, err)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, cerror.ErrDecryptionFailed.GenWithStack("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDecryptionFailed, err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
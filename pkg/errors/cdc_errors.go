@@ -134,6 +134,10 @@ var (
 		"column selector failed",
 		errors.RFCCodeText("CDC:ErrColumnSelectorFailed"),
 	)
+	ErrColumnMaskFailed = errors.Normalize(
+		"column mask failed",
+		errors.RFCCodeText("CDC:ErrColumnMaskFailed"),
+	)
 
 	// internal errors
 	ErrAdminStopProcessor = errors.Normalize(
@@ -204,6 +208,14 @@ var (
 		"invalid topic expression",
 		errors.RFCCodeText("CDC:ErrKafkaTopicExprInvalid"),
 	)
+	ErrKafkaTransaction = errors.Normalize(
+		"kafka transaction failed",
+		errors.RFCCodeText("CDC:ErrKafkaTransaction"),
+	)
+	ErrKafkaTransactionNotSupported = errors.Normalize(
+		"kafka transaction is not supported by %s",
+		errors.RFCCodeText("CDC:ErrKafkaTransactionNotSupported"),
+	)
 	ErrKafkaConfigNotFound = errors.Normalize(
 		"kafka config item not found",
 		errors.RFCCodeText("CDC:ErrKafkaConfigNotFound"),
@@ -269,6 +281,18 @@ var (
 		"redo log config invalid",
 		errors.RFCCodeText("CDC:ErrRedoConfigInvalid"),
 	)
+	ErrRedoTargetTsInvalid = errors.Normalize(
+		"redo target ts %d is out of the range [checkpoint ts %d, resolved ts %d]",
+		errors.RFCCodeText("CDC:ErrRedoTargetTsInvalid"),
+	)
+	ErrRedoStartTsInvalid = errors.Normalize(
+		"redo start ts %d is larger than the resolved ts %d",
+		errors.RFCCodeText("CDC:ErrRedoStartTsInvalid"),
+	)
+	ErrRedoLogsNotRetained = errors.Normalize(
+		"redo logs in range (%d, %d] may be deleted, the min commit ts of the retained logs is %d",
+		errors.RFCCodeText("CDC:ErrRedoLogsNotRetained"),
+	)
 	ErrRedoDownloadFailed = errors.Normalize(
 		"redo log down load to local failed",
 		errors.RFCCodeText("CDC:ErrRedoDownloadFailed"),
@@ -310,6 +334,18 @@ var (
 		"Compression failed",
 		errors.RFCCodeText("CDC:ErrCompressionFailed"),
 	)
+	ErrEncryptionFailed = errors.Normalize(
+		"encryption failed",
+		errors.RFCCodeText("CDC:ErrEncryptionFailed"),
+	)
+	ErrDecryptionFailed = errors.Normalize(
+		"decryption failed",
+		errors.RFCCodeText("CDC:ErrDecryptionFailed"),
+	)
+	ErrEncryptionMasterKeyInvalid = errors.Normalize(
+		"encryption master key is invalid",
+		errors.RFCCodeText("CDC:ErrEncryptionMasterKeyInvalid"),
+	)
 
 	ErrSinkURIInvalid = errors.Normalize(
 		"sink uri invalid '%s'",
@@ -332,6 +368,14 @@ var (
 		"MySQL duplicate entry error",
 		errors.RFCCodeText("CDC:ErrMySQLDuplicateEntry"),
 	)
+	ErrDeadLetterQueueWritten = errors.Normalize(
+		"transaction of table %s with commit ts %d is written to the dead letter queue, cause: %s",
+		errors.RFCCodeText("CDC:ErrDeadLetterQueueWritten"),
+	)
+	ErrDeadLetterQueueTooManyErrors = errors.Normalize(
+		"%d transactions are written to the dead letter queue, exceeds the max error count %d",
+		errors.RFCCodeText("CDC:ErrDeadLetterQueueTooManyErrors"),
+	)
 	ErrMySQLQueryError = errors.Normalize(
 		"MySQL query error",
 		errors.RFCCodeText("CDC:ErrMySQLQueryError"),
@@ -348,6 +392,38 @@ var (
 		"MySQL worker panic",
 		errors.RFCCodeText("CDC:ErrMySQLWorkerPanic"),
 	)
+	ErrPostgresTxnError = errors.Normalize(
+		"PostgreSQL txn error",
+		errors.RFCCodeText("CDC:ErrPostgresTxnError"),
+	)
+	ErrPostgresConnectionError = errors.Normalize(
+		"PostgreSQL connection error",
+		errors.RFCCodeText("CDC:ErrPostgresConnectionError"),
+	)
+	ErrPostgresInvalidConfig = errors.Normalize(
+		"PostgreSQL config invalid",
+		errors.RFCCodeText("CDC:ErrPostgresInvalidConfig"),
+	)
+	ErrPostgresUnsupportedDDL = errors.Normalize(
+		"DDL is not supported by PostgreSQL sink, ddl: %s, type: %s",
+		errors.RFCCodeText("CDC:ErrPostgresUnsupportedDDL"),
+	)
+	ErrWebhookInvalidConfig = errors.Normalize(
+		"webhook sink config invalid",
+		errors.RFCCodeText("CDC:ErrWebhookInvalidConfig"),
+	)
+	ErrWebhookSendMessage = errors.Normalize(
+		"webhook sink send message failed",
+		errors.RFCCodeText("CDC:ErrWebhookSendMessage"),
+	)
+	ErrWebhookProducerClosed = errors.Normalize(
+		"webhook producer closed",
+		errors.RFCCodeText("CDC:ErrWebhookProducerClosed"),
+	)
+	ErrProtobufSchemaAPIError = errors.Normalize(
+		"protobuf schema registry API error, %s",
+		errors.RFCCodeText("CDC:ErrProtobufSchemaAPIError"),
+	)
 	ErrAvroToEnvelopeError = errors.Normalize(
 		"to envelope failed",
 		errors.RFCCodeText("CDC:ErrAvroToEnvelopeError"),
@@ -424,6 +500,14 @@ var (
 		"csv decode failed",
 		errors.RFCCodeText("CDC:ErrCSVDecodeFailed"),
 	)
+	ErrParquetEncodeFailed = errors.Normalize(
+		"parquet encode failed",
+		errors.RFCCodeText("CDC:ErrParquetEncodeFailed"),
+	)
+	ErrParquetDecodeFailed = errors.Normalize(
+		"parquet decode failed",
+		errors.RFCCodeText("CDC:ErrParquetDecodeFailed"),
+	)
 	ErrDebeziumEncodeFailed = errors.Normalize(
 		"debezium encode failed",
 		errors.RFCCodeText("CDC:ErrDebeziumEncodeFailed"),
@@ -436,6 +520,10 @@ var (
 		"filename in storage sink is invalid",
 		errors.RFCCodeText("CDC:ErrStorageSinkInvalidFileName"),
 	)
+	ErrStorageSinkTableFormat = errors.Normalize(
+		"table format of storage sink is invalid",
+		errors.RFCCodeText("CDC:ErrStorageSinkTableFormat"),
+	)
 
 	// utilities related errors
 	ErrToTLSConfigFailed = errors.Normalize(
@@ -759,6 +847,10 @@ var (
 		"illegal parameter for sorter: %s",
 		errors.RFCCodeText("CDC:ErrIllegalSorterParameter"),
 	)
+	ErrSorterDiskQuotaExceeded = errors.Normalize(
+		"sorter disk usage %d bytes exceeds the quota %d bytes, pulling is paused",
+		errors.RFCCodeText("CDC:ErrSorterDiskQuotaExceeded"),
+	)
 	ErrConflictingFileLocks = errors.Normalize(
 		"file lock conflict: %s",
 		errors.RFCCodeText("ErrConflictingFileLocks"),
@@ -79,10 +79,13 @@ var changefeedUnRetryableErrors = []*errors.Error{
 	ErrCorruptedDataMutation,
 	ErrDispatcherFailed,
 	ErrColumnSelectorFailed,
+	ErrDeadLetterQueueTooManyErrors,
 
 	ErrSinkURIInvalid,
 	ErrKafkaInvalidConfig,
 	ErrMySQLInvalidConfig,
+	ErrPostgresInvalidConfig,
+	ErrPostgresUnsupportedDDL,
 	ErrStorageSinkInvalidConfig,
 }
 
@@ -14,6 +14,7 @@
 package orchestrator
 
 import (
+	"bytes"
 	"reflect"
 	"time"
 
@@ -242,6 +243,17 @@ func (s *ChangefeedReactorState) SetError(lastError *model.RunningError) {
 	})
 }
 
+// SetPausedBySchedule records whether the changefeed is paused by its pause schedule.
+func (s *ChangefeedReactorState) SetPausedBySchedule(paused bool) {
+	s.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.PausedBySchedule == paused {
+			return info, false, nil
+		}
+		info.PausedBySchedule = paused
+		return info, true, nil
+	})
+}
+
 // RemoveChangefeed removes the changefeed and clean the information and status.
 func (s *ChangefeedReactorState) RemoveChangefeed() {
 	// remove info
@@ -366,6 +378,64 @@ func (s *ChangefeedReactorState) TakeProcessorWarnings() []*model.RunningError {
 	return result
 }
 
+// MergeScannedSnapshotRanges merges the snapshot ranges scanned by processors
+// into the snapshot backfill of the changefeed, and cleans them in the task
+// positions.
+func (s *ChangefeedReactorState) MergeScannedSnapshotRanges() {
+	var ranges []model.SnapshotRange
+	for captureID, position := range s.TaskPositions {
+		if len(position.ScannedSnapshotRanges) == 0 {
+			continue
+		}
+		taken := position.ScannedSnapshotRanges
+		ranges = append(ranges, taken...)
+		s.PatchTaskPosition(captureID, func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			if position == nil {
+				return nil, false, nil
+			}
+			// Keep the ranges reported after they were taken.
+			remaining := make([]model.SnapshotRange, 0, len(position.ScannedSnapshotRanges))
+			for _, r := range position.ScannedSnapshotRanges {
+				if !containsSnapshotRange(taken, r) {
+					remaining = append(remaining, r)
+				}
+			}
+			if len(remaining) == len(position.ScannedSnapshotRanges) {
+				return position, false, nil
+			}
+			position.ScannedSnapshotRanges = remaining
+			return position, true, nil
+		})
+	}
+	if len(ranges) == 0 {
+		return
+	}
+	s.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.SnapshotBackfill == nil {
+			return info, false, nil
+		}
+		changed := info.SnapshotBackfill.MergeScannedRanges(ranges)
+		if changed {
+			log.Info("snapshot backfill progresses",
+				zap.String("namespace", s.ID.Namespace),
+				zap.String("changefeed", s.ID.ID),
+				zap.Int64s("finishedTableIDs", info.SnapshotBackfill.FinishedTableIDs),
+				zap.Int("scannedRanges", len(info.SnapshotBackfill.ScannedRanges)))
+		}
+		return info, changed, nil
+	})
+}
+
+func containsSnapshotRange(ranges []model.SnapshotRange, r model.SnapshotRange) bool {
+	for _, other := range ranges {
+		if other.TableID == r.TableID &&
+			bytes.Equal(other.StartKey, r.StartKey) && bytes.Equal(other.EndKey, r.EndKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // CleanUpTaskPositions removes the task positions of the changefeed.
 func (s *ChangefeedReactorState) CleanUpTaskPositions() {
 	for captureID := range s.TaskPositions {
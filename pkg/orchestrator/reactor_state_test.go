@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator/util"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	putil "github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
@@ -504,6 +505,49 @@ func TestPatchTaskPosition(t *testing.T) {
 	})
 }
 
+func TestMergeScannedSnapshotRanges(t *testing.T) {
+	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		model.DefaultChangeFeedID("test1"))
+	stateTester := NewReactorStateTester(t, state, nil)
+	startKey, endKey := spanz.GetTableRange(1)
+	midKey := append(append([]byte{}, startKey...), 'm')
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		return &model.ChangeFeedInfo{
+			SinkURI: "blackhole://",
+			Config:  config.GetDefaultReplicaConfig(),
+			SnapshotBackfill: &model.SnapshotBackfillInfo{
+				Ts:       1,
+				TableIDs: []model.TableID{1, 2},
+			},
+		}, true, nil
+	})
+	report := func(captureID model.CaptureID, r model.SnapshotRange) {
+		state.PatchTaskPosition(captureID, func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			return &model.TaskPosition{ScannedSnapshotRanges: []model.SnapshotRange{r}}, true, nil
+		})
+	}
+	report("capture1", model.SnapshotRange{TableID: 1, StartKey: startKey, EndKey: midKey})
+	stateTester.MustApplyPatches()
+
+	state.MergeScannedSnapshotRanges()
+	stateTester.MustApplyPatches()
+	require.Empty(t, state.TaskPositions["capture1"].ScannedSnapshotRanges)
+	require.Equal(t, []model.SnapshotRange{
+		{TableID: 1, StartKey: startKey, EndKey: midKey},
+	}, state.Info.SnapshotBackfill.ScannedRanges)
+	require.True(t, state.Info.SnapshotBackfill.ShouldBackfill(1))
+
+	// the table is finished once the rest of its range is reported
+	report("capture2", model.SnapshotRange{TableID: 1, StartKey: midKey, EndKey: endKey})
+	stateTester.MustApplyPatches()
+	state.MergeScannedSnapshotRanges()
+	stateTester.MustApplyPatches()
+	require.Empty(t, state.TaskPositions["capture2"].ScannedSnapshotRanges)
+	require.Empty(t, state.Info.SnapshotBackfill.ScannedRanges)
+	require.Equal(t, []model.TableID{1}, state.Info.SnapshotBackfill.FinishedTableIDs)
+	require.Equal(t, []model.TableID{2}, state.Info.SnapshotBackfill.UnfinishedTableIDs())
+}
+
 func TestGlobalStateUpdate(t *testing.T) {
 	t.Parallel()
 
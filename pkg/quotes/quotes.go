@@ -32,3 +32,13 @@ func QuoteName(name string) string {
 func EscapeName(name string) string {
 	return strings.Replace(name, "`", "``", -1)
 }
+
+// QuotePostgresSchema quotes a full table name with PostgreSQL identifier quotes.
+func QuotePostgresSchema(schema string, table string) string {
+	return QuotePostgresName(schema) + "." + QuotePostgresName(table)
+}
+
+// QuotePostgresName wraps a name with `"`, which is the identifier quote of PostgreSQL.
+func QuotePostgresName(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
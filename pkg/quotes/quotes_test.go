@@ -72,3 +72,22 @@ func TestEscapeName(t *testing.T) {
 		require.Equal(t, testCase.expected, escaped)
 	}
 }
+
+func TestQuotePostgresName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{"tbl", `"tbl"`},
+		{`t"bl`, `"t""bl"`},
+		{"t`bl", "\"t`bl\""},
+		{"", `""`},
+	}
+	for _, testCase := range cases {
+		escaped := QuotePostgresName(testCase.name)
+		require.Equal(t, testCase.expected, escaped)
+	}
+	require.Equal(t, `"test""db"."t1"`, QuotePostgresSchema(`test"db`, "t1"))
+}
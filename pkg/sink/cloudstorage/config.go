@@ -24,7 +24,9 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/imdario/mergo"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/encryption"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	psink "github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -62,6 +64,14 @@ const (
 	defaultFileCleanupCronSpec = "0 0 2 * * *"
 )
 
+const (
+	// TableFormatNone means only the data files are written.
+	TableFormatNone = ""
+	// TableFormatIceberg means the data files are committed to Iceberg
+	// style tables at each checkpoint.
+	TableFormatIceberg = "iceberg"
+)
+
 type urlConfig struct {
 	WorkerCount   *int    `form:"worker-count"`
 	FlushInterval *string `form:"flush-interval"`
@@ -80,6 +90,10 @@ type Config struct {
 	EnablePartitionSeparator bool
 	OutputColumnID           bool
 	FlushConcurrency         int
+	TableFormat              string
+	Compression              string
+	CompressionLevel         int
+	EncryptionMasterKey      string
 }
 
 // NewConfig returns the default cloud storage sink config.
@@ -90,6 +104,7 @@ func NewConfig() *Config {
 		FileSize:            defaultFileSize,
 		FileExpirationDays:  defaultFileExpirationDays,
 		FileCleanupCronSpec: defaultFileCleanupCronSpec,
+		Compression:         compression.None,
 	}
 }
 
@@ -141,6 +156,19 @@ func (c *Config) Apply(
 			c.FileCleanupCronSpec = *replicaConfig.Sink.CloudStorageConfig.FileCleanupCronSpec
 		}
 		c.FlushConcurrency = util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.FlushConcurrency)
+		c.TableFormat = strings.ToLower(util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.TableFormat))
+		c.Compression = strings.ToLower(util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.Compression))
+		c.CompressionLevel = util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.CompressionLevel)
+		c.EncryptionMasterKey = util.GetOrZero(replicaConfig.Sink.CloudStorageConfig.EncryptionMasterKey)
+	}
+	if err = c.validateTableFormat(util.GetOrZero(replicaConfig.Sink.Protocol)); err != nil {
+		return err
+	}
+	if err = c.validateCompression(util.GetOrZero(replicaConfig.Sink.Protocol)); err != nil {
+		return err
+	}
+	if err = c.validateEncryption(); err != nil {
+		return err
 	}
 
 	if c.FileIndexWidth < config.MinFileIndexWidth || c.FileIndexWidth > config.MaxFileIndexWidth {
@@ -214,6 +242,67 @@ func getFlushInterval(values *urlConfig, flushInterval *time.Duration) error {
 	return nil
 }
 
+func (c *Config) validateTableFormat(protocol string) error {
+	switch c.TableFormat {
+	case TableFormatNone:
+		return nil
+	case TableFormatIceberg:
+	default:
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig,
+			fmt.Errorf("unsupported table-format %s", c.TableFormat))
+	}
+
+	// Iceberg readers only understand columnar data files.
+	if p, err := config.ParseSinkProtocolFromString(protocol); err != nil || p != config.ProtocolParquet {
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig,
+			fmt.Errorf("table-format %s requires the parquet protocol, got %s", c.TableFormat, protocol))
+	}
+	// The committed snapshots reference the data files, they must not be
+	// removed behind the table format.
+	if c.FileExpirationDays > 0 {
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig,
+			fmt.Errorf("table-format %s can't be used with file-expiration-days", c.TableFormat))
+	}
+	return nil
+}
+
+func (c *Config) validateCompression(protocol string) error {
+	if c.Compression == "" || c.Compression == compression.None {
+		c.Compression = compression.None
+		return nil
+	}
+	if !compression.Supported(c.Compression) {
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig,
+			fmt.Errorf("unsupported compression %s", c.Compression))
+	}
+	if err := compression.ValidateLevel(c.Compression, c.CompressionLevel); err != nil {
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+	}
+	// Parquet compresses its column chunks itself, a compressed parquet
+	// file can't be read by any parquet reader.
+	if p, err := config.ParseSinkProtocolFromString(protocol); err == nil && p == config.ProtocolParquet {
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig,
+			fmt.Errorf("compression %s can't be used with the parquet protocol", c.Compression))
+	}
+	return nil
+}
+
+func (c *Config) validateEncryption() error {
+	if c.EncryptionMasterKey == "" {
+		return nil
+	}
+	if err := encryption.ValidateMasterKeyURI(c.EncryptionMasterKey); err != nil {
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig, err)
+	}
+	// The data files committed to a table format are read by the query
+	// engines directly, which can't decrypt them.
+	if c.TableFormat != TableFormatNone {
+		return cerror.WrapError(cerror.ErrStorageSinkInvalidConfig,
+			fmt.Errorf("encryption can't be used with table-format %s", c.TableFormat))
+	}
+	return nil
+}
+
 func getFileSize(values *urlConfig, fileSize *int) error {
 	if values.FileSize == nil {
 		return nil
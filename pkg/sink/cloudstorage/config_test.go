@@ -16,10 +16,12 @@ package cloudstorage
 import (
 	"context"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
@@ -165,3 +167,114 @@ func TestMergeConfig(t *testing.T) {
 	require.Equal(t, 33554432, c.FileSize)
 	require.Equal(t, "2m2s", c.FlushInterval.String())
 }
+
+func TestApplyTableFormat(t *testing.T) {
+	sinkURI, err := url.Parse("s3://bucket/prefix")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		tableFormat    string
+		protocol       string
+		expirationDays int
+		expectedErr    string
+	}{
+		{tableFormat: "", protocol: "csv"},
+		{tableFormat: "Iceberg", protocol: "parquet"},
+		{tableFormat: "iceberg", protocol: "csv", expectedErr: "requires the parquet protocol"},
+		{tableFormat: "iceberg", protocol: "parquet", expirationDays: 1, expectedErr: "file-expiration-days"},
+		{tableFormat: "delta", protocol: "parquet", expectedErr: "unsupported table-format delta"},
+	}
+	for _, tc := range testCases {
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Protocol = aws.String(tc.protocol)
+		replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+			TableFormat:        aws.String(tc.tableFormat),
+			FileExpirationDays: aws.Int(tc.expirationDays),
+		}
+		c := NewConfig()
+		err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+		if tc.expectedErr == "" {
+			require.NoError(t, err)
+			require.Equal(t, strings.ToLower(tc.tableFormat), c.TableFormat)
+		} else {
+			require.ErrorContains(t, err, tc.expectedErr)
+		}
+	}
+}
+
+func TestApplyCompression(t *testing.T) {
+	sinkURI, err := url.Parse("s3://bucket/prefix")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		compression string
+		level       int
+		protocol    string
+		expected    string
+		expectedErr string
+	}{
+		{compression: "", protocol: "csv", expected: compression.None},
+		{compression: "none", protocol: "parquet", expected: compression.None},
+		{compression: "Gzip", level: 9, protocol: "csv", expected: compression.Gzip},
+		{compression: "zstd", level: 19, protocol: "canal-json", expected: compression.Zstd},
+		{compression: "lz4", protocol: "csv", expected: compression.LZ4},
+		{compression: "gzip", level: 19, protocol: "csv", expectedErr: "invalid level 19"},
+		{compression: "brotli", protocol: "csv", expectedErr: "unsupported compression brotli"},
+		{compression: "zstd", protocol: "parquet", expectedErr: "parquet protocol"},
+	}
+	for _, tc := range testCases {
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Protocol = aws.String(tc.protocol)
+		replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+			Compression:      aws.String(tc.compression),
+			CompressionLevel: aws.Int(tc.level),
+		}
+		c := NewConfig()
+		err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+		if tc.expectedErr == "" {
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, c.Compression)
+			require.Equal(t, tc.level, c.CompressionLevel)
+		} else {
+			require.ErrorContains(t, err, tc.expectedErr)
+		}
+	}
+}
+
+func TestApplyEncryption(t *testing.T) {
+	sinkURI, err := url.Parse("s3://bucket/prefix")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		masterKey   string
+		protocol    string
+		tableFormat string
+		expectedErr string
+	}{
+		{masterKey: "", protocol: "csv"},
+		{masterKey: "file:///path/to/keyfile", protocol: "csv"},
+		{masterKey: "local-kms:///path/to/keyring.json?key-id=k1", protocol: "parquet"},
+		{masterKey: "local-kms:///path/to/keyring.json", protocol: "csv", expectedErr: "key-id"},
+		{masterKey: "kms://key", protocol: "csv", expectedErr: "unsupported master key scheme"},
+		{
+			masterKey: "file:///path/to/keyfile", protocol: "parquet", tableFormat: "iceberg",
+			expectedErr: "encryption can't be used with table-format iceberg",
+		},
+	}
+	for _, tc := range testCases {
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Protocol = aws.String(tc.protocol)
+		replicaConfig.Sink.CloudStorageConfig = &config.CloudStorageConfig{
+			EncryptionMasterKey: aws.String(tc.masterKey),
+			TableFormat:         aws.String(tc.tableFormat),
+		}
+		c := NewConfig()
+		err = c.Apply(context.TODO(), sinkURI, replicaConfig)
+		if tc.expectedErr == "" {
+			require.NoError(t, err)
+			require.Equal(t, tc.masterKey, c.EncryptionMasterKey)
+		} else {
+			require.ErrorContains(t, err, tc.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,785 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// The data files of a table are committed to an Iceberg style table at each
+// checkpoint. The layout of the table <schema>.<table> is:
+//
+//	<schema>/<table>/metadata/version-hint.text
+//	<schema>/<table>/metadata/v{version}.metadata.json
+//	<schema>/<table>/metadata/snap-{snapshotID}.json
+//	<schema>/<table>/metadata/{snapshotID}-m0.json
+//
+// The metadata, the manifest lists and the manifests are JSON documents
+// following the Iceberg v2 table spec. The data files are the parquet files
+// under the data directories generated by FilePathGenerator.
+//
+// A DML worker records each data file into the pending directory before the
+// rows in it are acknowledged. The owner commits the pending files of a table
+// as a new snapshot when the checkpoint is written, so the snapshot of a
+// checkpoint contains all rows committed before the checkpoint ts. A data file
+// may also contain rows committed after the checkpoint ts, readers that need
+// the exact state at the checkpoint should skip the rows whose _tidb_commit_ts
+// is greater than the ticdc.checkpoint-ts in the snapshot summary.
+const (
+	// The pending data files of a table are stored in the following path:
+	// .iceberg/pending/<schema>/<table>/{dataFilePath}.json
+	icebergPendingDir = ".iceberg/pending"
+	// The metadata of a table is stored in the following path:
+	// <schema>/<table>/metadata/
+	icebergMetadataDir      = "metadata"
+	icebergVersionHintFile  = "version-hint.text"
+	icebergMetadataFileName = "v%d.metadata.json"
+	icebergManifestListName = "snap-%d.json"
+	icebergManifestName     = "%d-m0.json"
+
+	icebergFormatVersion = 2
+	icebergNoSnapshot    = int64(-1)
+	// icebergStatusAdded is the status of a data file added by the snapshot
+	// of the manifest.
+	icebergStatusAdded = 1
+
+	// The meta columns written by the parquet protocol come first in the
+	// schema. The field IDs of the table columns are their column IDs plus
+	// icebergMetaFieldCount, so that a field keeps its ID across DDLs.
+	icebergOpFieldName       = "_tidb_op"
+	icebergCommitTsFieldName = "_tidb_commit_ts"
+	icebergMetaFieldCount    = 2
+
+	// icebergPropNameMapping maps the columns of the parquet files, which
+	// don't carry field IDs, to the fields by their names.
+	icebergPropNameMapping = "schema.name-mapping.default"
+	// icebergPropTableVersion is the table version of the current schema.
+	icebergPropTableVersion = "ticdc.table-version"
+	// icebergSummaryCheckpointTs is the checkpoint ts of a snapshot.
+	icebergSummaryCheckpointTs = "ticdc.checkpoint-ts"
+)
+
+// IcebergField is a field of an Iceberg schema.
+type IcebergField struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Type     string `json:"type"`
+}
+
+// IcebergSchema is a schema of an Iceberg table.
+type IcebergSchema struct {
+	Type     string          `json:"type"`
+	SchemaID int             `json:"schema-id"`
+	Fields   []*IcebergField `json:"fields"`
+}
+
+// IcebergPartitionSpec is a partition spec of an Iceberg table. The tables
+// written by the storage sink are not partitioned.
+type IcebergPartitionSpec struct {
+	SpecID int               `json:"spec-id"`
+	Fields []json.RawMessage `json:"fields"`
+}
+
+// IcebergSortOrder is a sort order of an Iceberg table. The tables written by
+// the storage sink are not sorted.
+type IcebergSortOrder struct {
+	OrderID int               `json:"order-id"`
+	Fields  []json.RawMessage `json:"fields"`
+}
+
+// IcebergDataFile is a data file of an Iceberg table. The commit ts range of
+// the rows in the file is recorded, so that readers can skip the files out of
+// the range they need.
+type IcebergDataFile struct {
+	Content         int    `json:"content"`
+	FilePath        string `json:"file-path"`
+	FileFormat      string `json:"file-format"`
+	RecordCount     int64  `json:"record-count"`
+	FileSizeInBytes int64  `json:"file-size-in-bytes"`
+	MinCommitTs     uint64 `json:"ticdc-min-commit-ts"`
+	MaxCommitTs     uint64 `json:"ticdc-max-commit-ts"`
+}
+
+// IcebergManifestEntry is an entry of a manifest.
+type IcebergManifestEntry struct {
+	Status         int              `json:"status"`
+	SnapshotID     int64            `json:"snapshot-id"`
+	SequenceNumber int64            `json:"sequence-number"`
+	DataFile       *IcebergDataFile `json:"data-file"`
+}
+
+// IcebergManifest lists the data files added by a snapshot.
+type IcebergManifest struct {
+	SchemaID int                     `json:"schema-id"`
+	Entries  []*IcebergManifestEntry `json:"entries"`
+}
+
+// IcebergManifestFile is a manifest referenced by a manifest list.
+type IcebergManifestFile struct {
+	ManifestPath    string `json:"manifest-path"`
+	ManifestLength  int64  `json:"manifest-length"`
+	AddedSnapshotID int64  `json:"added-snapshot-id"`
+	SequenceNumber  int64  `json:"sequence-number"`
+	AddedFilesCount int    `json:"added-files-count"`
+	AddedRowsCount  int64  `json:"added-rows-count"`
+}
+
+// IcebergManifestList lists all manifests of a snapshot.
+type IcebergManifestList struct {
+	Manifests []*IcebergManifestFile `json:"manifests"`
+}
+
+// IcebergSnapshot is a snapshot of an Iceberg table.
+type IcebergSnapshot struct {
+	SnapshotID       int64             `json:"snapshot-id"`
+	ParentSnapshotID *int64            `json:"parent-snapshot-id,omitempty"`
+	SequenceNumber   int64             `json:"sequence-number"`
+	TimestampMs      int64             `json:"timestamp-ms"`
+	ManifestList     string            `json:"manifest-list"`
+	Summary          map[string]string `json:"summary"`
+	SchemaID         int               `json:"schema-id"`
+}
+
+// IcebergSnapshotRef is a named reference to a snapshot.
+type IcebergSnapshotRef struct {
+	SnapshotID int64  `json:"snapshot-id"`
+	Type       string `json:"type"`
+}
+
+// IcebergSnapshotLogEntry is an entry of the snapshot log.
+type IcebergSnapshotLogEntry struct {
+	SnapshotID  int64 `json:"snapshot-id"`
+	TimestampMs int64 `json:"timestamp-ms"`
+}
+
+// IcebergMetadataLogEntry is an entry of the metadata log.
+type IcebergMetadataLogEntry struct {
+	MetadataFile string `json:"metadata-file"`
+	TimestampMs  int64  `json:"timestamp-ms"`
+}
+
+// IcebergTableMetadata is the metadata of an Iceberg table.
+type IcebergTableMetadata struct {
+	FormatVersion      int                            `json:"format-version"`
+	TableUUID          string                         `json:"table-uuid"`
+	Location           string                         `json:"location"`
+	LastSequenceNumber int64                          `json:"last-sequence-number"`
+	LastUpdatedMs      int64                          `json:"last-updated-ms"`
+	LastColumnID       int                            `json:"last-column-id"`
+	Schemas            []*IcebergSchema               `json:"schemas"`
+	CurrentSchemaID    int                            `json:"current-schema-id"`
+	PartitionSpecs     []*IcebergPartitionSpec        `json:"partition-specs"`
+	DefaultSpecID      int                            `json:"default-spec-id"`
+	LastPartitionID    int                            `json:"last-partition-id"`
+	SortOrders         []*IcebergSortOrder            `json:"sort-orders"`
+	DefaultSortOrderID int                            `json:"default-sort-order-id"`
+	Properties         map[string]string              `json:"properties"`
+	CurrentSnapshotID  int64                          `json:"current-snapshot-id"`
+	Snapshots          []*IcebergSnapshot             `json:"snapshots"`
+	Refs               map[string]*IcebergSnapshotRef `json:"refs"`
+	SnapshotLog        []*IcebergSnapshotLogEntry     `json:"snapshot-log"`
+	MetadataLog        []*IcebergMetadataLogEntry     `json:"metadata-log"`
+}
+
+// CurrentSnapshot returns the current snapshot of the table, it returns nil
+// if the table has no snapshot.
+func (m *IcebergTableMetadata) CurrentSnapshot() *IcebergSnapshot {
+	for _, s := range m.Snapshots {
+		if s.SnapshotID == m.CurrentSnapshotID {
+			return s
+		}
+	}
+	return nil
+}
+
+// icebergPendingFile is a data file waiting to be committed.
+type icebergPendingFile struct {
+	TableVersion uint64           `json:"table-version"`
+	Fields       []*IcebergField  `json:"fields"`
+	DataFile     *IcebergDataFile `json:"data-file"`
+}
+
+// NewIcebergFields returns the fields of the table, they are the same as the
+// columns of the parquet files written by the parquet protocol.
+func NewIcebergFields(info *model.TableInfo) ([]*IcebergField, error) {
+	fields := make([]*IcebergField, 0, len(info.Columns)+icebergMetaFieldCount)
+	fields = append(fields,
+		&IcebergField{ID: 1, Name: icebergOpFieldName, Type: "string"},
+		&IcebergField{ID: 2, Name: icebergCommitTsFieldName, Type: "long"},
+	)
+	for _, col := range info.Columns {
+		tp, err := icebergType(&col.FieldType)
+		if err != nil {
+			return nil, errors.ErrStorageSinkTableFormat.GenWithStack(
+				"%s of column %s in table %s", err.Error(), col.Name.O, info.TableName)
+		}
+		fields = append(fields, &IcebergField{
+			ID: int(col.ID) + icebergMetaFieldCount,
+			// The parquet protocol replaces ',' and '=' in the column names.
+			Name: strings.NewReplacer(",", "_", "=", "_").Replace(col.Name.O),
+			Type: tp,
+		})
+	}
+	return fields, nil
+}
+
+// icebergType returns the Iceberg type of the column, it matches the type of
+// the column in the parquet files. Unsigned BIGINT and BIT are mapped to long,
+// the values greater than math.MaxInt64 are read as negative numbers.
+func icebergType(ft *types.FieldType) (string, error) {
+	unsigned := mysql.HasUnsignedFlag(ft.GetFlag())
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeYear:
+		return "int", nil
+	case mysql.TypeLong:
+		if unsigned {
+			return "long", nil
+		}
+		return "int", nil
+	case mysql.TypeLonglong, mysql.TypeBit:
+		return "long", nil
+	case mysql.TypeFloat:
+		return "float", nil
+	case mysql.TypeDouble:
+		return "double", nil
+	case mysql.TypeNewDecimal:
+		return fmt.Sprintf("decimal(%d, %d)", ft.GetFlen(), ft.GetDecimal()), nil
+	case mysql.TypeDate:
+		return "date", nil
+	case mysql.TypeDatetime:
+		return "timestamp", nil
+	case mysql.TypeTimestamp:
+		return "timestamptz", nil
+	case mysql.TypeDuration, mysql.TypeSet, mysql.TypeEnum, mysql.TypeJSON:
+		return "string", nil
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if ft.GetCharset() == charset.CharsetBin {
+			return "binary", nil
+		}
+		return "string", nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", types.TypeToStr(ft.GetType(), ft.GetCharset()))
+	}
+}
+
+// WriteIcebergPendingFile records the data file of the table, the file is
+// committed to the Iceberg table by a later checkpoint.
+func WriteIcebergPendingFile(
+	ctx context.Context,
+	storage storage.ExternalStorage,
+	info *model.TableInfo,
+	dataFilePath string,
+	dataFile *IcebergDataFile,
+) error {
+	fields, err := NewIcebergFields(info)
+	if err != nil {
+		return err
+	}
+	dataFile.FilePath = absoluteStoragePath(storage, dataFilePath)
+	dataFile.FileFormat = "PARQUET"
+	data, err := json.Marshal(&icebergPendingFile{
+		TableVersion: info.Version,
+		Fields:       fields,
+		DataFile:     dataFile,
+	})
+	if err != nil {
+		return errors.WrapError(errors.ErrMarshalFailed, err)
+	}
+
+	schema, table := info.TableName.Schema, info.TableName.Table
+	name := strings.TrimPrefix(dataFilePath, path.Join(schema, table)+"/")
+	name = strings.ReplaceAll(name, "/", "_") + ".json"
+	return errors.Trace(storage.WriteFile(ctx, path.Join(icebergPendingDir, schema, table, name), data))
+}
+
+func absoluteStoragePath(storage storage.ExternalStorage, p string) string {
+	return strings.TrimSuffix(storage.URI(), "/") + "/" + p
+}
+
+type icebergTableKey struct {
+	schema string
+	table  string
+}
+
+// icebergTable is the state of a table cached by the committer.
+type icebergTable struct {
+	dir string
+	// version is the version of the metadata file, it is 0 if the table has
+	// not been created.
+	version   int
+	metadata  *IcebergTableMetadata
+	manifests []*IcebergManifestFile
+	// committed contains the paths of the data files in the current snapshot.
+	committed map[string]struct{}
+}
+
+// updateSchema adds a schema with the fields if there is no such schema, and
+// makes the schema of the newest table version the current schema. It returns
+// whether the metadata is changed.
+func (t *icebergTable) updateSchema(fields []*IcebergField, tableVersion uint64) bool {
+	meta := t.metadata
+	changed := false
+	var schema *IcebergSchema
+	for _, s := range meta.Schemas {
+		if reflect.DeepEqual(s.Fields, fields) {
+			schema = s
+			break
+		}
+	}
+	if schema == nil {
+		schema = &IcebergSchema{Type: "struct", SchemaID: len(meta.Schemas), Fields: fields}
+		meta.Schemas = append(meta.Schemas, schema)
+		for _, f := range fields {
+			if f.ID > meta.LastColumnID {
+				meta.LastColumnID = f.ID
+			}
+		}
+		meta.Properties[icebergPropNameMapping] = icebergNameMapping(meta.Schemas)
+		changed = true
+	}
+
+	// The data files of an old table version may be committed after the
+	// schema of a newer version is recorded.
+	current, _ := strconv.ParseUint(meta.Properties[icebergPropTableVersion], 10, 64)
+	if tableVersion > current || (tableVersion == current && meta.CurrentSchemaID != schema.SchemaID) {
+		meta.CurrentSchemaID = schema.SchemaID
+		meta.Properties[icebergPropTableVersion] = strconv.FormatUint(tableVersion, 10)
+		changed = true
+	}
+	return changed
+}
+
+// icebergNameMapping returns the name mapping of the schemas, a field may
+// have different names in different schemas.
+func icebergNameMapping(schemas []*IcebergSchema) string {
+	type mappedField struct {
+		FieldID int      `json:"field-id"`
+		Names   []string `json:"names"`
+	}
+	names := make(map[int][]string)
+	for _, s := range schemas {
+		for _, f := range s.Fields {
+			found := false
+			for _, name := range names[f.ID] {
+				if name == f.Name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				names[f.ID] = append(names[f.ID], f.Name)
+			}
+		}
+	}
+	mapping := make([]mappedField, 0, len(names))
+	for id, n := range names {
+		mapping = append(mapping, mappedField{FieldID: id, Names: n})
+	}
+	sort.Slice(mapping, func(i, j int) bool { return mapping[i].FieldID < mapping[j].FieldID })
+	data, _ := json.Marshal(mapping)
+	return string(data)
+}
+
+// IcebergCommitter commits the data files written by the storage sink to
+// Iceberg style tables. It is used by the owner, and it assumes that there is
+// only one committer of a changefeed at a time.
+type IcebergCommitter struct {
+	changefeedID model.ChangeFeedID
+	storage      storage.ExternalStorage
+	tables       map[icebergTableKey]*icebergTable
+}
+
+// NewIcebergCommitter creates an IcebergCommitter.
+func NewIcebergCommitter(
+	changefeedID model.ChangeFeedID, storage storage.ExternalStorage,
+) *IcebergCommitter {
+	return &IcebergCommitter{
+		changefeedID: changefeedID,
+		storage:      storage,
+		tables:       make(map[icebergTableKey]*icebergTable),
+	}
+}
+
+// UpdateSchema records the schema of the table, it is called for each DDL of
+// the table. The schema becomes the current schema of the table.
+func (c *IcebergCommitter) UpdateSchema(ctx context.Context, info *model.TableInfo) error {
+	fields, err := NewIcebergFields(info)
+	if err != nil {
+		return err
+	}
+	key := icebergTableKey{schema: info.TableName.Schema, table: info.TableName.Table}
+	t, err := c.getTable(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !t.updateSchema(fields, info.Version) {
+		return nil
+	}
+	if err := c.writeMetadata(ctx, t); err != nil {
+		// The cached table may be inconsistent with the storage.
+		delete(c.tables, key)
+		return err
+	}
+	return nil
+}
+
+// Commit commits the pending data files which may contain rows committed
+// before the checkpoint ts, a new snapshot is added to each table having
+// such files.
+func (c *IcebergCommitter) Commit(ctx context.Context, checkpointTs uint64) error {
+	pending, err := c.listPendingFiles(ctx)
+	if err != nil {
+		return err
+	}
+	keys := make([]icebergTableKey, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].schema != keys[j].schema {
+			return keys[i].schema < keys[j].schema
+		}
+		return keys[i].table < keys[j].table
+	})
+
+	for _, key := range keys {
+		if err := c.commitTable(ctx, key, pending[key], checkpointTs); err != nil {
+			delete(c.tables, key)
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *IcebergCommitter) listPendingFiles(ctx context.Context) (map[icebergTableKey][]string, error) {
+	pending := make(map[icebergTableKey][]string)
+	err := c.storage.WalkDir(ctx, &storage.WalkOption{SubDir: icebergPendingDir},
+		func(p string, _ int64) error {
+			// Skip the temporary files of the local storage.
+			if !strings.HasSuffix(p, ".json") {
+				return nil
+			}
+			elems := strings.Split(strings.TrimPrefix(p, icebergPendingDir+"/"), "/")
+			if len(elems) != 3 {
+				log.Warn("ignore invalid iceberg pending file",
+					zap.String("namespace", c.changefeedID.Namespace),
+					zap.String("changefeed", c.changefeedID.ID),
+					zap.String("path", p))
+				return nil
+			}
+			key := icebergTableKey{schema: elems[0], table: elems[1]}
+			pending[key] = append(pending[key], p)
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, paths := range pending {
+		sort.Strings(paths)
+	}
+	return pending, nil
+}
+
+func (c *IcebergCommitter) commitTable(
+	ctx context.Context, key icebergTableKey, paths []string, checkpointTs uint64,
+) error {
+	t, err := c.getTable(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var (
+		added         []*IcebergDataFile
+		finished      []string
+		schemaChanged bool
+	)
+	for _, p := range paths {
+		var f icebergPendingFile
+		if err := c.readJSON(ctx, p, &f); err != nil {
+			return err
+		}
+		// The file has been committed before the committer is restarted.
+		if _, ok := t.committed[f.DataFile.FilePath]; ok {
+			finished = append(finished, p)
+			continue
+		}
+		// All rows of the file are committed after the checkpoint ts.
+		if f.DataFile.MinCommitTs > checkpointTs {
+			continue
+		}
+		if t.updateSchema(f.Fields, f.TableVersion) {
+			schemaChanged = true
+		}
+		added = append(added, f.DataFile)
+		finished = append(finished, p)
+	}
+
+	if len(added) > 0 {
+		if err := c.addSnapshot(ctx, t, added, checkpointTs); err != nil {
+			return err
+		}
+	}
+	if len(added) > 0 || schemaChanged {
+		if err := c.writeMetadata(ctx, t); err != nil {
+			return err
+		}
+		log.Info("commit iceberg snapshot",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.String("schema", key.schema),
+			zap.String("table", key.table),
+			zap.Int("version", t.version),
+			zap.Int("addedFiles", len(added)),
+			zap.Uint64("checkpointTs", checkpointTs))
+	}
+	for _, f := range added {
+		t.committed[f.FilePath] = struct{}{}
+	}
+
+	if len(finished) > 0 {
+		return errors.Trace(c.storage.DeleteFiles(ctx, finished))
+	}
+	return nil
+}
+
+func (c *IcebergCommitter) addSnapshot(
+	ctx context.Context, t *icebergTable, files []*IcebergDataFile, checkpointTs uint64,
+) error {
+	meta := t.metadata
+	now := time.Now().UnixMilli()
+	seq := meta.LastSequenceNumber + 1
+	snapshotID := newIcebergSnapshotID()
+
+	manifest := &IcebergManifest{SchemaID: meta.CurrentSchemaID}
+	var rows int64
+	for _, f := range files {
+		manifest.Entries = append(manifest.Entries, &IcebergManifestEntry{
+			Status:         icebergStatusAdded,
+			SnapshotID:     snapshotID,
+			SequenceNumber: seq,
+			DataFile:       f,
+		})
+		rows += f.RecordCount
+	}
+	manifestPath := path.Join(t.dir, fmt.Sprintf(icebergManifestName, snapshotID))
+	length, err := c.writeJSON(ctx, manifestPath, manifest)
+	if err != nil {
+		return err
+	}
+
+	// The manifest list of a snapshot references the manifests of all
+	// previous snapshots, so it contains all data files of the table.
+	manifests := make([]*IcebergManifestFile, 0, len(t.manifests)+1)
+	manifests = append(manifests, t.manifests...)
+	manifests = append(manifests, &IcebergManifestFile{
+		ManifestPath:    absoluteStoragePath(c.storage, manifestPath),
+		ManifestLength:  int64(length),
+		AddedSnapshotID: snapshotID,
+		SequenceNumber:  seq,
+		AddedFilesCount: len(files),
+		AddedRowsCount:  rows,
+	})
+	listPath := path.Join(t.dir, fmt.Sprintf(icebergManifestListName, snapshotID))
+	if _, err := c.writeJSON(ctx, listPath, &IcebergManifestList{Manifests: manifests}); err != nil {
+		return err
+	}
+
+	snapshot := &IcebergSnapshot{
+		SnapshotID:     snapshotID,
+		SequenceNumber: seq,
+		TimestampMs:    now,
+		ManifestList:   absoluteStoragePath(c.storage, listPath),
+		Summary: map[string]string{
+			"operation":                "append",
+			"added-data-files":         strconv.Itoa(len(files)),
+			"added-records":            strconv.FormatInt(rows, 10),
+			icebergSummaryCheckpointTs: strconv.FormatUint(checkpointTs, 10),
+		},
+		SchemaID: meta.CurrentSchemaID,
+	}
+	if meta.CurrentSnapshotID != icebergNoSnapshot {
+		parent := meta.CurrentSnapshotID
+		snapshot.ParentSnapshotID = &parent
+	}
+	meta.Snapshots = append(meta.Snapshots, snapshot)
+	meta.CurrentSnapshotID = snapshotID
+	meta.LastSequenceNumber = seq
+	meta.Refs["main"] = &IcebergSnapshotRef{SnapshotID: snapshotID, Type: "branch"}
+	meta.SnapshotLog = append(meta.SnapshotLog,
+		&IcebergSnapshotLogEntry{SnapshotID: snapshotID, TimestampMs: now})
+	t.manifests = manifests
+	return nil
+}
+
+// writeMetadata writes a new version of the metadata. The version hint is
+// written at last, it makes the new version visible to readers atomically.
+func (c *IcebergCommitter) writeMetadata(ctx context.Context, t *icebergTable) error {
+	meta := t.metadata
+	now := time.Now().UnixMilli()
+	if t.version > 0 {
+		meta.MetadataLog = append(meta.MetadataLog, &IcebergMetadataLogEntry{
+			MetadataFile: absoluteStoragePath(c.storage,
+				path.Join(t.dir, fmt.Sprintf(icebergMetadataFileName, t.version))),
+			TimestampMs: meta.LastUpdatedMs,
+		})
+	}
+	meta.LastUpdatedMs = now
+
+	version := t.version + 1
+	metadataPath := path.Join(t.dir, fmt.Sprintf(icebergMetadataFileName, version))
+	if _, err := c.writeJSON(ctx, metadataPath, meta); err != nil {
+		return err
+	}
+	err := c.storage.WriteFile(ctx,
+		path.Join(t.dir, icebergVersionHintFile), []byte(strconv.Itoa(version)))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	t.version = version
+	return nil
+}
+
+// getTable returns the cached table, the table is loaded from the storage if
+// it is not cached.
+func (c *IcebergCommitter) getTable(ctx context.Context, key icebergTableKey) (*icebergTable, error) {
+	if t, ok := c.tables[key]; ok {
+		return t, nil
+	}
+
+	t := &icebergTable{
+		dir:       path.Join(key.schema, key.table, icebergMetadataDir),
+		committed: make(map[string]struct{}),
+	}
+	hintPath := path.Join(t.dir, icebergVersionHintFile)
+	exists, err := c.storage.FileExists(ctx, hintPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !exists {
+		t.metadata = c.newTableMetadata(key)
+		c.tables[key] = t
+		return t, nil
+	}
+
+	hint, err := c.storage.ReadFile(ctx, hintPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	t.version, err = strconv.Atoi(strings.TrimSpace(string(hint)))
+	if err != nil || t.version <= 0 {
+		return nil, errors.ErrStorageSinkTableFormat.GenWithStack(
+			"invalid version hint %q of table %s.%s", hint, key.schema, key.table)
+	}
+	t.metadata = &IcebergTableMetadata{}
+	metadataPath := path.Join(t.dir, fmt.Sprintf(icebergMetadataFileName, t.version))
+	if err := c.readJSON(ctx, metadataPath, t.metadata); err != nil {
+		return nil, err
+	}
+	if t.metadata.Properties == nil {
+		t.metadata.Properties = make(map[string]string)
+	}
+	if t.metadata.Refs == nil {
+		t.metadata.Refs = make(map[string]*IcebergSnapshotRef)
+	}
+
+	if snapshot := t.metadata.CurrentSnapshot(); snapshot != nil {
+		var list IcebergManifestList
+		if err := c.readJSON(ctx, c.relativePath(snapshot.ManifestList), &list); err != nil {
+			return nil, err
+		}
+		t.manifests = list.Manifests
+		for _, m := range list.Manifests {
+			var manifest IcebergManifest
+			if err := c.readJSON(ctx, c.relativePath(m.ManifestPath), &manifest); err != nil {
+				return nil, err
+			}
+			for _, e := range manifest.Entries {
+				t.committed[e.DataFile.FilePath] = struct{}{}
+			}
+		}
+	}
+	c.tables[key] = t
+	return t, nil
+}
+
+func (c *IcebergCommitter) newTableMetadata(key icebergTableKey) *IcebergTableMetadata {
+	return &IcebergTableMetadata{
+		FormatVersion:     icebergFormatVersion,
+		TableUUID:         uuid.NewString(),
+		Location:          absoluteStoragePath(c.storage, path.Join(key.schema, key.table)),
+		PartitionSpecs:    []*IcebergPartitionSpec{{Fields: []json.RawMessage{}}},
+		SortOrders:        []*IcebergSortOrder{{Fields: []json.RawMessage{}}},
+		Properties:        map[string]string{"write.format.default": "parquet"},
+		CurrentSnapshotID: icebergNoSnapshot,
+		Snapshots:         []*IcebergSnapshot{},
+		Refs:              make(map[string]*IcebergSnapshotRef),
+		SnapshotLog:       []*IcebergSnapshotLogEntry{},
+		MetadataLog:       []*IcebergMetadataLogEntry{},
+	}
+}
+
+// relativePath returns the path relative to the storage of an absolute path
+// written by the committer.
+func (c *IcebergCommitter) relativePath(p string) string {
+	return strings.TrimPrefix(p, strings.TrimSuffix(c.storage.URI(), "/")+"/")
+}
+
+func (c *IcebergCommitter) readJSON(ctx context.Context, p string, v interface{}) error {
+	data, err := c.storage.ReadFile(ctx, p)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.WrapError(errors.ErrStorageSinkTableFormat, err)
+	}
+	return nil
+}
+
+func (c *IcebergCommitter) writeJSON(ctx context.Context, p string, v interface{}) (int, error) {
+	data, err := json.MarshalIndent(v, marshalPrefix, marshalIndent)
+	if err != nil {
+		return 0, errors.WrapError(errors.ErrMarshalFailed, err)
+	}
+	if err := c.storage.WriteFile(ctx, p, data); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return len(data), nil
+}
+
+// newIcebergSnapshotID returns a random positive snapshot ID.
+func newIcebergSnapshotID() int64 {
+	id := uuid.New()
+	return int64(binary.BigEndian.Uint64(id[:8]) >> 1)
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newIcebergTestTableInfo(version uint64, columns ...string) *model.TableInfo {
+	var cols []*timodel.ColumnInfo
+	for i, name := range columns {
+		ft := types.NewFieldType(mysql.TypeLong)
+		if i > 0 {
+			ft = types.NewFieldType(mysql.TypeVarchar)
+		}
+		cols = append(cols, &timodel.ColumnInfo{
+			ID:        int64(i + 1),
+			Name:      timodel.NewCIStr(name),
+			FieldType: *ft,
+		})
+	}
+	return &model.TableInfo{
+		TableInfo: &timodel.TableInfo{Columns: cols},
+		Version:   version,
+		TableName: model.TableName{Schema: "test", Table: "t1", TableID: 20},
+	}
+}
+
+func readIcebergMetadata(t *testing.T, dir string) (int, *IcebergTableMetadata) {
+	hint, err := os.ReadFile(filepath.Join(dir, "test/t1/metadata", icebergVersionHintFile))
+	require.NoError(t, err)
+	version, err := strconv.Atoi(string(hint))
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "test/t1/metadata",
+		fmt.Sprintf(icebergMetadataFileName, version)))
+	require.NoError(t, err)
+	meta := &IcebergTableMetadata{}
+	require.NoError(t, json.Unmarshal(data, meta))
+	return version, meta
+}
+
+func newIcebergTestStorage(ctx context.Context, t *testing.T) (string, storage.ExternalStorage) {
+	dir := t.TempDir()
+	s, err := util.GetExternalStorageFromURI(ctx, "file://"+dir)
+	require.NoError(t, err)
+	return dir, s
+}
+
+func TestIcebergCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir, s := newIcebergTestStorage(ctx, t)
+	info := newIcebergTestTableInfo(100, "id", "name")
+
+	writePending := func(p string, minTs, maxTs uint64) {
+		err := WriteIcebergPendingFile(ctx, s, info, p, &IcebergDataFile{
+			RecordCount: 2, FileSizeInBytes: 100, MinCommitTs: minTs, MaxCommitTs: maxTs,
+		})
+		require.NoError(t, err)
+	}
+	writePending("test/t1/100/CDC000001.parquet", 10, 20)
+	writePending("test/t1/100/CDC000002.parquet", 50, 60)
+
+	// Only the first file has rows committed before the checkpoint.
+	c := NewIcebergCommitter(model.DefaultChangeFeedID("test"), s)
+	require.NoError(t, c.Commit(ctx, 30))
+	version, meta := readIcebergMetadata(t, dir)
+	require.Equal(t, 1, version)
+	require.Len(t, meta.Snapshots, 1)
+	require.Nil(t, meta.Snapshots[0].ParentSnapshotID)
+	require.Equal(t, "30", meta.CurrentSnapshot().Summary[icebergSummaryCheckpointTs])
+	require.Equal(t, s.URI()+"/test/t1", meta.Location)
+	require.Len(t, meta.Schemas, 1)
+	require.Equal(t, []*IcebergField{
+		{ID: 1, Name: icebergOpFieldName, Type: "string"},
+		{ID: 2, Name: icebergCommitTsFieldName, Type: "long"},
+		{ID: 3, Name: "id", Type: "int"},
+		{ID: 4, Name: "name", Type: "string"},
+	}, meta.Schemas[0].Fields)
+	pending, err := os.ReadDir(filepath.Join(dir, icebergPendingDir, "test/t1"))
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	// A restarted committer continues from the committed metadata.
+	c = NewIcebergCommitter(model.DefaultChangeFeedID("test"), s)
+	require.NoError(t, c.Commit(ctx, 60))
+	version, meta = readIcebergMetadata(t, dir)
+	require.Equal(t, 2, version)
+	require.Len(t, meta.Snapshots, 2)
+	require.Equal(t, meta.Snapshots[0].SnapshotID, *meta.Snapshots[1].ParentSnapshotID)
+	require.Equal(t, meta.Snapshots[1].SnapshotID, meta.Refs["main"].SnapshotID)
+	require.Len(t, meta.MetadataLog, 1)
+
+	var list IcebergManifestList
+	require.NoError(t, c.readJSON(ctx, c.relativePath(meta.CurrentSnapshot().ManifestList), &list))
+	require.Len(t, list.Manifests, 2)
+	var paths []string
+	for _, m := range list.Manifests {
+		var manifest IcebergManifest
+		require.NoError(t, c.readJSON(ctx, c.relativePath(m.ManifestPath), &manifest))
+		for _, e := range manifest.Entries {
+			paths = append(paths, e.DataFile.FilePath)
+		}
+	}
+	require.Equal(t, []string{
+		s.URI() + "/test/t1/100/CDC000001.parquet",
+		s.URI() + "/test/t1/100/CDC000002.parquet",
+	}, paths)
+
+	// A file recorded again after it is committed is not committed twice.
+	writePending("test/t1/100/CDC000001.parquet", 10, 20)
+	c = NewIcebergCommitter(model.DefaultChangeFeedID("test"), s)
+	require.NoError(t, c.Commit(ctx, 70))
+	version, _ = readIcebergMetadata(t, dir)
+	require.Equal(t, 2, version)
+	pending, err = os.ReadDir(filepath.Join(dir, icebergPendingDir, "test/t1"))
+	require.NoError(t, err)
+	require.Len(t, pending, 0)
+}
+
+func TestIcebergSchemaEvolution(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir, s := newIcebergTestStorage(ctx, t)
+	c := NewIcebergCommitter(model.DefaultChangeFeedID("test"), s)
+
+	require.NoError(t, c.UpdateSchema(ctx, newIcebergTestTableInfo(100, "id", "name")))
+	version, meta := readIcebergMetadata(t, dir)
+	require.Equal(t, 1, version)
+	require.Equal(t, 0, meta.CurrentSchemaID)
+	require.Equal(t, 4, meta.LastColumnID)
+
+	// Add a column.
+	require.NoError(t, c.UpdateSchema(ctx, newIcebergTestTableInfo(110, "id", "name", "age")))
+	version, meta = readIcebergMetadata(t, dir)
+	require.Equal(t, 2, version)
+	require.Len(t, meta.Schemas, 2)
+	require.Equal(t, 1, meta.CurrentSchemaID)
+	require.Equal(t, 5, meta.LastColumnID)
+
+	// Rename a column, the field ID is kept.
+	require.NoError(t, c.UpdateSchema(ctx, newIcebergTestTableInfo(120, "id", "full_name", "age")))
+	_, meta = readIcebergMetadata(t, dir)
+	require.Len(t, meta.Schemas, 3)
+	require.Equal(t, 2, meta.CurrentSchemaID)
+	require.Equal(t, 4, meta.Schemas[2].Fields[3].ID)
+	require.Contains(t, meta.Properties[icebergPropNameMapping],
+		`{"field-id":4,"names":["name","full_name"]}`)
+
+	// The data files of an old table version don't change the current schema.
+	oldInfo := newIcebergTestTableInfo(100, "id", "name")
+	err := WriteIcebergPendingFile(ctx, s, oldInfo, "test/t1/100/CDC000001.parquet",
+		&IcebergDataFile{RecordCount: 1, MinCommitTs: 105, MaxCommitTs: 105})
+	require.NoError(t, err)
+	require.NoError(t, c.Commit(ctx, 130))
+	version, meta = readIcebergMetadata(t, dir)
+	require.Equal(t, 4, version)
+	require.Len(t, meta.Schemas, 3)
+	require.Equal(t, 2, meta.CurrentSchemaID)
+	require.Equal(t, "120", meta.Properties[icebergPropTableVersion])
+}
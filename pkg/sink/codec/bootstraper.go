@@ -34,7 +34,7 @@ const (
 )
 
 // bootstrapWorker is used to send bootstrap message to the MQ sink worker.
-// It will be only used in simple protocol.
+// It will be only used in simple and protobuf protocol.
 type bootstrapWorker struct {
 	changefeedID                model.ChangeFeedID
 	activeTables                sync.Map
@@ -57,7 +57,7 @@ func newBootstrapWorker(
 	sendBootstrapToAllPartition bool,
 	maxInactiveDuration time.Duration,
 ) *bootstrapWorker {
-	log.Info("Sending bootstrap event is enabled for simple protocol. "+
+	log.Info("Sending bootstrap event is enabled for simple and protobuf protocol. "+
 		"Both send-bootstrap-interval-in-sec and send-bootstrap-in-msg-count are > 0.",
 		zap.Stringer("changefeed", changefeedID),
 		zap.Int64("sendBootstrapIntervalInSec", sendBootstrapInterval),
@@ -372,7 +372,7 @@ func (c *JSONRowEventEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message,
 	}
 
 	value, err = common.Compress(
-		c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		c.config.ChangefeedID, c.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -394,7 +394,7 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 	}
 
 	value, err = common.Compress(
-		c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		c.config.ChangefeedID, c.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return errors.Trace(err)
@@ -428,7 +428,7 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 				return cerror.ErrMessageTooLarge.GenWithStackByArgs()
 			}
 			value, err = common.Compress(
-				c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+				c.config.ChangefeedID, c.config.LargeMessageHandle, value,
 			)
 			if err != nil {
 				return errors.Trace(err)
@@ -478,7 +478,7 @@ func (c *JSONRowEventEncoder) newClaimCheckLocationMessage(
 	}
 
 	value, err = common.Compress(
-		c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		c.config.ChangefeedID, c.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -518,7 +518,7 @@ func (c *JSONRowEventEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
 	value, err = common.Compress(
-		c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		c.config.ChangefeedID, c.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -16,13 +16,18 @@ package common
 import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/compression"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/errors"
 )
 
-// Compress the given data by the given compression, also record the compression ratio metric.
-func Compress(changefeedID model.ChangeFeedID, cc string, data []byte) ([]byte, error) {
+// Compress the given data by the compression of the large message handle config,
+// also record the compression ratio metric.
+func Compress(
+	changefeedID model.ChangeFeedID, c *config.LargeMessageHandleConfig, data []byte,
+) ([]byte, error) {
 	oldSize := len(data)
-	compressed, err := compression.Encode(cc, data)
+	compressed, err := compression.EncodeWithLevel(
+		c.LargeMessageHandleCompression, c.LargeMessageHandleCompressionLevel, data)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -396,6 +396,13 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Protocol == config.ProtocolProtobuf && c.AvroGlueSchemaRegistry != nil {
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`Protobuf protocol only supports "%s" to specify the schema registry`,
+			codecOPTAvroSchemaRegistry,
+		)
+	}
+
 	if c.MaxMessageBytes <= 0 {
 		return cerror.ErrCodecInvalidConfig.Wrap(
 			errors.Errorf("invalid max-message-bytes %d", c.MaxMessageBytes),
@@ -54,7 +54,7 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	// TODO: Use a streaming compression is better.
 	value, err := common.Compress(
 		d.config.ChangefeedID,
-		d.config.LargeMessageHandle.LargeMessageHandleCompression,
+		d.config.LargeMessageHandle,
 		valueBuf.Bytes(),
 	)
 	if err != nil {
@@ -156,7 +156,7 @@ func (g *encoderGroup) AddEvents(
 	key model.TopicPartitionKey,
 	events ...*dmlsink.RowChangeCallbackableEvent,
 ) error {
-	// bootstrapWorker only not nil when the protocol is simple
+	// bootstrapWorker only not nil when the protocol is simple or protobuf
 	if g.bootstrapWorker != nil {
 		err := g.bootstrapWorker.addEvent(ctx, key, events[0].Event)
 		if err != nil {
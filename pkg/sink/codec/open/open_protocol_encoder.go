@@ -56,7 +56,7 @@ func (d *BatchEncoder) buildMessageOnlyHandleKeyColumns(e *model.RowChangedEvent
 	}
 
 	value, err = common.Compress(
-		d.config.ChangefeedID, d.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		d.config.ChangefeedID, d.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -98,7 +98,7 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	}
 
 	value, err = common.Compress(
-		d.config.ChangefeedID, d.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		d.config.ChangefeedID, d.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return errors.Trace(err)
@@ -213,7 +213,7 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 	}
 
 	value, err = common.Compress(
-		d.config.ChangefeedID, d.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		d.config.ChangefeedID, d.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -310,7 +310,7 @@ func (d *BatchEncoder) newClaimCheckLocationMessage(
 	}
 
 	value, err = common.Compress(
-		d.config.ChangefeedID, d.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+		d.config.ChangefeedID, d.config.LargeMessageHandle, value,
 	)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
@@ -0,0 +1,162 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+type batchDecoder struct {
+	schema    *schema
+	tableInfo *model.TableInfo
+	loc       *time.Location
+
+	// columns holds the values of the file by column.
+	columns [][]any
+	numRows int
+	next    int
+}
+
+// NewBatchDecoder creates a decoder of a parquet file written by WriteFile,
+// the file must be written with the schema of the table definition.
+func NewBatchDecoder(
+	codecConfig *common.Config,
+	def *cloudstorage.TableDefinition,
+	value []byte,
+) (codec.RowEventDecoder, error) {
+	s, err := newSchema(def)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetDecodeFailed, err)
+	}
+	tableInfo, err := def.ToTableInfo()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetDecodeFailed, err)
+	}
+
+	r, err := reader.NewParquetColumnReader(&memoryFile{data: value}, 1)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetDecodeFailed, err)
+	}
+	defer r.ReadStop()
+	if numColumns := len(r.SchemaHandler.ValueColumns); numColumns != len(s.fields) {
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+			"the file has %d columns, but the table %s.%s has %d columns",
+			numColumns, def.Schema, def.Table, len(s.fields))
+	}
+
+	numRows := r.GetNumRows()
+	columns := make([][]any, len(s.fields))
+	for i := range s.fields {
+		values, _, dls, err := r.ReadColumnByIndex(int64(i), numRows)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrParquetDecodeFailed, err)
+		}
+		if int64(len(values)) != numRows {
+			return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+				"column %s has %d values, expect %d", s.fields[i].name, len(values), numRows)
+		}
+		// All columns are optional, the definition level is 0 for null values.
+		for j := range values {
+			if dls[j] == 0 {
+				values[j] = nil
+			}
+		}
+		columns[i] = values
+	}
+
+	return &batchDecoder{
+		schema:    s,
+		tableInfo: tableInfo,
+		loc:       codecConfig.TimeZone,
+		columns:   columns,
+		numRows:   int(numRows),
+	}, nil
+}
+
+// AddKeyValue implements the RowEventDecoder interface.
+func (b *batchDecoder) AddKeyValue(_, _ []byte) error {
+	return nil
+}
+
+// HasNext implements the RowEventDecoder interface.
+func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
+	if b.next >= b.numRows {
+		return model.MessageTypeUnknown, false, nil
+	}
+	return model.MessageTypeRow, true, nil
+}
+
+// NextResolvedEvent implements the RowEventDecoder interface.
+func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
+	return 0, nil
+}
+
+// NextRowChangedEvent implements the RowEventDecoder interface.
+func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if b.next >= b.numRows {
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack("no parquet row can be found")
+	}
+	row := b.next
+	b.next++
+
+	op, ok := b.columns[0][row].(string)
+	if !ok {
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+			"invalid operation type %v", b.columns[0][row])
+	}
+	commitTs, ok := b.columns[1][row].(int64)
+	if !ok {
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+			"invalid commit ts %v", b.columns[1][row])
+	}
+
+	cols := make([]*model.ColumnData, 0, len(b.tableInfo.Columns))
+	for i, f := range b.schema.columns() {
+		v, err := f.fromParquetValue(b.columns[numMetaColumns+i][row], b.loc)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cols = append(cols, &model.ColumnData{
+			ColumnID: b.tableInfo.Columns[i].ID,
+			Value:    v,
+		})
+	}
+
+	e := &model.RowChangedEvent{
+		CommitTs:  uint64(commitTs),
+		TableInfo: b.tableInfo,
+	}
+	switch op {
+	case opInsert, opUpdate:
+		e.Columns = cols
+	case opDelete:
+		e.PreColumns = cols
+	default:
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack("invalid operation type %s", op)
+	}
+	return e, nil
+}
+
+// NextDDLEvent implements the RowEventDecoder interface.
+func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
+	return nil, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+)
+
+// BatchEncoder encodes the rows of the transactions for the parquet file
+// format. The encoded messages are not parquet files, they must be written
+// into a parquet file by WriteFile.
+type BatchEncoder struct {
+	valueBuf  []byte
+	callback  func()
+	batchSize int
+	commitTs  uint64
+	config    *common.Config
+}
+
+// AppendTxnEvent implements the TxnEventEncoder interface
+func (b *BatchEncoder) AppendTxnEvent(
+	e *model.SingleTableTxn,
+	callback func(),
+) error {
+	b.callback = callback
+	b.commitTs = e.CommitTs
+	if len(e.Rows) == 0 {
+		return nil
+	}
+	s, err := newSchemaFromTableInfo(e.TableInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, row := range e.Rows {
+		b.valueBuf, err = s.appendRow(b.valueBuf, row, b.config.TimeZone)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		b.batchSize++
+	}
+	return nil
+}
+
+// Build implements the RowEventEncoder interface
+func (b *BatchEncoder) Build() (messages []*common.Message) {
+	if b.batchSize == 0 {
+		return nil
+	}
+
+	ret := common.NewMsg(config.ProtocolParquet, nil,
+		b.valueBuf, b.commitTs, model.MessageTypeRow, nil, nil)
+	ret.SetRowsCount(b.batchSize)
+	ret.Callback = b.callback
+	b.valueBuf = nil
+	b.callback = nil
+	b.batchSize = 0
+
+	return []*common.Message{ret}
+}
+
+// newBatchEncoder creates a new parquet BatchEncoder.
+func newBatchEncoder(config *common.Config) codec.TxnEventEncoder {
+	return &BatchEncoder{
+		config: config,
+	}
+}
+
+type batchEncoderBuilder struct {
+	config *common.Config
+}
+
+// NewTxnEventEncoderBuilder creates a parquet batchEncoderBuilder.
+func NewTxnEventEncoderBuilder(config *common.Config) codec.TxnEventEncoderBuilder {
+	return &batchEncoderBuilder{config: config}
+}
+
+// Build a parquet BatchEncoder
+func (b *batchEncoderBuilder) Build() codec.TxnEventEncoder {
+	return newBatchEncoder(b.config)
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+const createTableSQL = `create table test.t(
+	id int primary key,
+	c_tinyint tinyint,
+	c_uint int unsigned,
+	c_bigint bigint,
+	c_ubigint bigint unsigned,
+	c_float float,
+	c_double double,
+	c_decimal decimal(10, 2),
+	c_neg_decimal decimal(20, 4),
+	c_varchar varchar(10),
+	c_varbinary varbinary(10),
+	c_blob blob,
+	c_text text,
+	c_date date,
+	c_datetime datetime,
+	c_timestamp timestamp(3),
+	c_time time,
+	c_json json,
+	c_enum enum('a', 'b'),
+	c_set set('a', 'b'),
+	c_bit bit(8),
+	c_year year,
+	c_null int)`
+
+const insertSQL = `insert into test.t values (
+	1, -8, 4294967295, -3, 18446744073709551615, 1.5, 2.25, 12.34, -123456.7891,
+	'varchar', x'0102', x'0304', 'text', '1969-12-31', '2024-01-02 03:04:05',
+	'2024-01-02 03:04:05.123', '-12:34:56', '{"a": 1}', 'b', 'a,b', b'101', 2024, null)`
+
+func requireRowEqual(t *testing.T, expected, actual *model.RowChangedEvent) {
+	require.Equal(t, expected.CommitTs, actual.CommitTs)
+	require.Equal(t, expected.IsDelete(), actual.IsDelete())
+
+	expectedColumns, actualColumns := expected.Columns, actual.Columns
+	if expected.IsDelete() {
+		expectedColumns, actualColumns = expected.PreColumns, actual.PreColumns
+	}
+	require.Len(t, actualColumns, len(expectedColumns))
+	values := make(map[string]any, len(actualColumns))
+	for _, col := range actualColumns {
+		values[actual.TableInfo.ForceGetColumnName(col.ColumnID)] = col.Value
+	}
+	for _, col := range expectedColumns {
+		name := expected.TableInfo.ForceGetColumnName(col.ColumnID)
+		value, ok := values[name]
+		require.True(t, ok, name)
+		switch name {
+		// ENUM and SET values are decoded as their names.
+		case "c_enum":
+			require.Equal(t, "b", value, name)
+		case "c_set":
+			require.Equal(t, "a,b", value, name)
+		default:
+			require.Equal(t, col.Value, value, name)
+		}
+	}
+}
+
+func TestEncodeAndDecode(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event(createTableSQL)
+	insert := helper.DML2Event(insertSQL, "test", "t")
+	deleteEvent := *insert
+	deleteEvent.PreColumns, deleteEvent.Columns = insert.Columns, nil
+	update := *insert
+	update.PreColumns = insert.Columns
+
+	codecConfig := common.NewConfig(config.ProtocolParquet)
+	encoder := NewTxnEventEncoderBuilder(codecConfig).Build()
+
+	var msgs []*common.Message
+	for _, row := range []*model.RowChangedEvent{insert, &update, &deleteEvent} {
+		err := encoder.AppendTxnEvent(&model.SingleTableTxn{
+			TableInfo: ddl.TableInfo,
+			Rows:      []*model.RowChangedEvent{row},
+		}, nil)
+		require.NoError(t, err)
+		messages := encoder.Build()
+		require.Len(t, messages, 1)
+		require.Equal(t, 1, messages[0].GetRowsCount())
+		msgs = append(msgs, messages...)
+	}
+
+	var tableDef cloudstorage.TableDefinition
+	tableDef.FromTableInfo(ddl.TableInfo, ddl.TableInfo.Version, false)
+	data, err := WriteFile(&tableDef, msgs)
+	require.NoError(t, err)
+
+	// All rows are written into one row group.
+	r, err := reader.NewParquetReader(&memoryFile{data: data}, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, r.Footer.RowGroups, 1)
+	require.Equal(t, int64(3), r.GetNumRows())
+	r.ReadStop()
+
+	decoder, err := NewBatchDecoder(codecConfig, &tableDef, data)
+	require.NoError(t, err)
+	for _, expected := range []*model.RowChangedEvent{insert, &update, &deleteEvent} {
+		tp, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+		row, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+		requireRowEqual(t, expected, row)
+	}
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
+
+func TestBuildEmptyTxn(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event(createTableSQL)
+	encoder := NewTxnEventEncoderBuilder(common.NewConfig(config.ProtocolParquet)).Build()
+	err := encoder.AppendTxnEvent(&model.SingleTableTxn{TableInfo: ddl.TableInfo}, nil)
+	require.NoError(t, err)
+	require.Nil(t, encoder.Build())
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"io"
+	"math"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// WriteFile writes the rows carried by the messages into a parquet file and
+// returns its content. All rows are written into one row group.
+func WriteFile(def *cloudstorage.TableDefinition, msgs []*common.Message) ([]byte, error) {
+	s, err := newSchema(def)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	file := &memoryFile{}
+	w, err := writer.NewCSVWriter(s.metadata(), file, 1)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	// The row group is only flushed by WriteStop.
+	w.RowGroupSize = math.MaxInt64
+	for _, msg := range msgs {
+		buf := msg.Value
+		for len(buf) > 0 {
+			var values []any
+			values, buf, err = s.readRow(buf)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err = w.Write(values); err != nil {
+				return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+			}
+		}
+	}
+	if err = w.WriteStop(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	return file.data, nil
+}
+
+var _ source.ParquetFile = (*memoryFile)(nil)
+
+// memoryFile is an in-memory source.ParquetFile.
+type memoryFile struct {
+	data   []byte
+	offset int64
+}
+
+// Read implements io.Reader.
+func (f *memoryFile) Read(p []byte) (int, error) {
+	if f.offset >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+// Write implements io.Writer, the data is always appended to the file.
+func (f *memoryFile) Write(p []byte) (int, error) {
+	f.data = append(f.data, p...)
+	f.offset = int64(len(f.data))
+	return len(p), nil
+}
+
+// Seek implements io.Seeker.
+func (f *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.Errorf("negative offset %d", offset)
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Close implements io.Closer.
+func (f *memoryFile) Close() error {
+	return nil
+}
+
+// Open implements source.ParquetFile, it returns a new reader of the file.
+func (f *memoryFile) Open(_ string) (source.ParquetFile, error) {
+	return &memoryFile{data: f.data}, nil
+}
+
+// Create implements source.ParquetFile.
+func (f *memoryFile) Create(_ string) (source.ParquetFile, error) {
+	return &memoryFile{}, nil
+}
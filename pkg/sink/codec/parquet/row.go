@@ -0,0 +1,148 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// The rows encoded by the encoder are buffered in the messages in a compact
+// row format, and are written into a parquet file when the messages of a table
+// are flushed, so that a file contains exactly one row group.
+//
+// A row is a sequence of values of the columns in the schema. Each value starts
+// with a byte indicating whether it is null, and is followed by the value:
+//   - INT32 and INT64: zigzag varint
+//   - FLOAT and DOUBLE: little-endian IEEE 754 bits
+//   - BYTE_ARRAY: uvarint length followed by the bytes
+const (
+	nullValue    byte = 0
+	nonNullValue byte = 1
+)
+
+// appendRow converts the row changed event to the parquet values and appends
+// them to buf.
+func (s *schema) appendRow(buf []byte, row *model.RowChangedEvent, loc *time.Location) ([]byte, error) {
+	op, cols := opInsert, row.Columns
+	if row.IsDelete() {
+		op, cols = opDelete, row.PreColumns
+	} else if row.IsUpdate() {
+		op = opUpdate
+	}
+
+	values := make([]any, len(s.fields))
+	values[0], values[1] = op, int64(row.CommitTs)
+	for _, col := range cols {
+		// column could be nil in a condition described in
+		// https://github.com/pingcap/tiflow/issues/6198#issuecomment-1191132951
+		if col == nil {
+			continue
+		}
+		offset, ok := s.offsets[col.ColumnID]
+		if !ok {
+			return nil, cerror.ErrParquetEncodeFailed.GenWithStack(
+				"column %d not found in table %s", col.ColumnID, row.TableInfo.TableName)
+		}
+		colInfo := row.TableInfo.Columns[offset]
+		v, err := s.columns()[offset].toParquetValue(col.Value, &colInfo.FieldType, loc)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		values[numMetaColumns+offset] = v
+	}
+
+	for i, f := range s.fields {
+		buf = appendValue(buf, f.kind, values[i])
+	}
+	return buf, nil
+}
+
+// readRow reads the values of a row from buf and returns the rest of buf.
+func (s *schema) readRow(buf []byte) ([]any, []byte, error) {
+	values := make([]any, len(s.fields))
+	for i, f := range s.fields {
+		var err error
+		values[i], buf, err = readValue(buf, f.kind)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return values, buf, nil
+}
+
+func appendValue(buf []byte, kind valueKind, value any) []byte {
+	if value == nil {
+		return append(buf, nullValue)
+	}
+	buf = append(buf, nonNullValue)
+	switch kind {
+	case kindInt32:
+		return binary.AppendVarint(buf, int64(value.(int32)))
+	case kindInt64:
+		return binary.AppendVarint(buf, value.(int64))
+	case kindFloat:
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.(float32)))
+	case kindDouble:
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(value.(float64)))
+	default:
+		v := value.(string)
+		buf = binary.AppendUvarint(buf, uint64(len(v)))
+		return append(buf, v...)
+	}
+}
+
+func readValue(buf []byte, kind valueKind) (any, []byte, error) {
+	if len(buf) == 0 {
+		return nil, nil, cerror.ErrParquetEncodeFailed.GenWithStack("unexpected end of row")
+	}
+	if buf[0] == nullValue {
+		return nil, buf[1:], nil
+	}
+	buf = buf[1:]
+
+	switch kind {
+	case kindInt32, kindInt64:
+		v, n := binary.Varint(buf)
+		if n <= 0 {
+			return nil, nil, cerror.ErrParquetEncodeFailed.GenWithStack("invalid varint")
+		}
+		if kind == kindInt32 {
+			return int32(v), buf[n:], nil
+		}
+		return v, buf[n:], nil
+	case kindFloat:
+		if len(buf) < 4 {
+			return nil, nil, cerror.ErrParquetEncodeFailed.GenWithStack("unexpected end of row")
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(buf)), buf[4:], nil
+	case kindDouble:
+		if len(buf) < 8 {
+			return nil, nil, cerror.ErrParquetEncodeFailed.GenWithStack("unexpected end of row")
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf)), buf[8:], nil
+	default:
+		l, n := binary.Uvarint(buf)
+		if n <= 0 || uint64(len(buf)-n) < l {
+			return nil, nil, cerror.ErrParquetEncodeFailed.GenWithStack("unexpected end of row")
+		}
+		buf = buf[n:]
+		return string(buf[:l]), buf[l:], nil
+	}
+}
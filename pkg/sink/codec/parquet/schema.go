@@ -0,0 +1,246 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+)
+
+const (
+	// opColumnName is the name of the column carrying the operation type of
+	// the row, which is one of I, U and D.
+	opColumnName = "_tidb_op"
+	// commitTsColumnName is the name of the column carrying the commit ts of
+	// the row.
+	commitTsColumnName = "_tidb_commit_ts"
+	// numMetaColumns is the number of the meta columns above.
+	numMetaColumns = 2
+
+	opInsert = "I"
+	opUpdate = "U"
+	opDelete = "D"
+)
+
+// valueKind is the parquet physical type of a column.
+type valueKind int
+
+const (
+	kindInt32 valueKind = iota
+	kindInt64
+	kindFloat
+	kindDouble
+	kindByteArray
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindInt32:
+		return "INT32"
+	case kindInt64:
+		return "INT64"
+	case kindFloat:
+		return "FLOAT"
+	case kindDouble:
+		return "DOUBLE"
+	case kindByteArray:
+		return "BYTE_ARRAY"
+	default:
+		return "unknown"
+	}
+}
+
+// field is a column of the parquet file.
+type field struct {
+	name string
+	kind valueKind
+	// convertedType is the parquet converted type of the column, it is empty
+	// if the column is not annotated.
+	convertedType string
+
+	// tp is the MySQL type of the column, it is 0 for the meta columns.
+	tp       byte
+	unsigned bool
+	binary   bool
+	// precision and scale are set for decimal columns, scale is the
+	// fractional seconds precision for time columns.
+	precision int
+	scale     int
+}
+
+// metadata returns the metadata of the column used by the parquet writer.
+func (f *field) metadata() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name=%s, type=%s", f.name, f.kind)
+	if f.convertedType != "" {
+		fmt.Fprintf(&b, ", convertedtype=%s", f.convertedType)
+	}
+	if f.convertedType == "DECIMAL" {
+		fmt.Fprintf(&b, ", precision=%d, scale=%d", f.precision, f.scale)
+	}
+	// All columns are optional, the value of a NOT NULL column may be absent
+	// in the row changed event, for example the column is being added.
+	b.WriteString(", repetitiontype=OPTIONAL")
+	return b.String()
+}
+
+// schema is the parquet schema of a table, the meta columns come first and
+// are followed by the columns of the table.
+type schema struct {
+	fields []*field
+	// offsets maps the column IDs to the offsets of the table columns, it is
+	// only set if the schema is created from a table info.
+	offsets map[int64]int
+}
+
+// newSchemaFromTableInfo creates the schema of the table info, which is the
+// same as the schema of its table definition.
+func newSchemaFromTableInfo(info *model.TableInfo) (*schema, error) {
+	var def cloudstorage.TableDefinition
+	def.FromTableInfo(info, info.Version, false)
+	s, err := newSchema(&def)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.offsets = make(map[int64]int, len(info.Columns))
+	for i, col := range info.Columns {
+		s.offsets[col.ID] = i
+	}
+	return s, nil
+}
+
+// newSchema maps the columns of the table definition to parquet columns.
+//
+// The TiDB types are mapped to the parquet types as follows:
+//   - TINYINT, SMALLINT, MEDIUMINT, INT and YEAR: INT32 with INT_x or UINT_x
+//   - BIGINT and BIT: INT64 with INT_64 or UINT_64
+//   - FLOAT and DOUBLE: FLOAT and DOUBLE
+//   - DECIMAL: BYTE_ARRAY with DECIMAL, the value is the big-endian two's
+//     complement of the unscaled value
+//   - DATE: INT32 with DATE
+//   - DATETIME and TIMESTAMP: INT64 with TIMESTAMP_MICROS. DATETIME is
+//     stored as if it is in UTC, TIMESTAMP is converted to UTC
+//   - CHAR, VARCHAR, TEXT, SET and TIME: BYTE_ARRAY with UTF8
+//   - ENUM: BYTE_ARRAY with ENUM
+//   - JSON: BYTE_ARRAY with JSON
+//   - BINARY, VARBINARY and BLOB: BYTE_ARRAY
+func newSchema(def *cloudstorage.TableDefinition) (*schema, error) {
+	s := &schema{
+		fields: make([]*field, 0, len(def.Columns)+numMetaColumns),
+	}
+	s.fields = append(s.fields,
+		&field{name: opColumnName, kind: kindByteArray, convertedType: "UTF8"},
+		&field{name: commitTsColumnName, kind: kindInt64, convertedType: "UINT_64"},
+	)
+	for i, col := range def.Columns {
+		colInfo, err := col.ToTiColumnInfo(int64(i))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+		}
+		f, err := newField(col.Name, &colInfo.FieldType)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		s.fields = append(s.fields, f)
+	}
+	return s, nil
+}
+
+func newField(name string, ft *types.FieldType) (*field, error) {
+	f := &field{
+		// The metadata of the parquet writer is separated by ',' and '='.
+		name:     strings.NewReplacer(",", "_", "=", "_").Replace(name),
+		tp:       ft.GetType(),
+		unsigned: mysql.HasUnsignedFlag(ft.GetFlag()),
+		binary:   ft.GetCharset() == charset.CharsetBin,
+	}
+	intType := func(bits int) string {
+		if f.unsigned {
+			return fmt.Sprintf("UINT_%d", bits)
+		}
+		return fmt.Sprintf("INT_%d", bits)
+	}
+	switch f.tp {
+	case mysql.TypeTiny:
+		f.kind, f.convertedType = kindInt32, intType(8)
+	case mysql.TypeShort:
+		f.kind, f.convertedType = kindInt32, intType(16)
+	case mysql.TypeInt24, mysql.TypeLong:
+		f.kind, f.convertedType = kindInt32, intType(32)
+	case mysql.TypeYear:
+		f.kind, f.convertedType = kindInt32, "INT_16"
+	case mysql.TypeLonglong:
+		f.kind, f.convertedType = kindInt64, intType(64)
+	case mysql.TypeBit:
+		f.unsigned = true
+		f.kind, f.convertedType = kindInt64, "UINT_64"
+	case mysql.TypeFloat:
+		f.kind = kindFloat
+	case mysql.TypeDouble:
+		f.kind = kindDouble
+	case mysql.TypeNewDecimal:
+		f.kind, f.convertedType = kindByteArray, "DECIMAL"
+		f.precision, f.scale = ft.GetFlen(), ft.GetDecimal()
+		if f.precision <= 0 || f.scale < 0 || f.scale > f.precision {
+			return nil, cerror.ErrParquetEncodeFailed.GenWithStack(
+				"invalid precision %d or scale %d of decimal column %s", f.precision, f.scale, name)
+		}
+	case mysql.TypeDate:
+		f.kind, f.convertedType = kindInt32, "DATE"
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		f.kind, f.convertedType = kindInt64, "TIMESTAMP_MICROS"
+		f.scale = ft.GetDecimal()
+		if f.scale < 0 {
+			f.scale = 0
+		}
+	case mysql.TypeDuration, mysql.TypeSet:
+		f.kind, f.convertedType = kindByteArray, "UTF8"
+	case mysql.TypeEnum:
+		f.kind, f.convertedType = kindByteArray, "ENUM"
+	case mysql.TypeJSON:
+		f.kind, f.convertedType = kindByteArray, "JSON"
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		f.kind = kindByteArray
+		if !f.binary {
+			f.convertedType = "UTF8"
+		}
+	default:
+		return nil, cerror.ErrParquetEncodeFailed.GenWithStack(
+			"unsupported type %s of column %s", types.TypeToStr(f.tp, ft.GetCharset()), name)
+	}
+	return f, nil
+}
+
+// metadata returns the metadata of all columns used by the parquet writer.
+func (s *schema) metadata() []string {
+	md := make([]string, 0, len(s.fields))
+	for _, f := range s.fields {
+		md = append(md, f.metadata())
+	}
+	return md
+}
+
+// columns returns the fields of the table columns.
+func (s *schema) columns() []*field {
+	return s.fields[numMetaColumns:]
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchema(t *testing.T) {
+	t.Parallel()
+
+	def := &cloudstorage.TableDefinition{
+		Schema: "test",
+		Table:  "t",
+		Columns: []cloudstorage.TableCol{
+			{Name: "id", Tp: "INT", IsPK: "true", Precision: "11"},
+			{Name: "c_utiny", Tp: "TINYINT UNSIGNED", Precision: "3"},
+			{Name: "c_ubigint", Tp: "BIGINT UNSIGNED", Precision: "20"},
+			{Name: "c_decimal", Tp: "DECIMAL", Precision: "10", Scale: "2"},
+			{Name: "c_datetime", Tp: "DATETIME", Scale: "6"},
+			{Name: "c_date", Tp: "DATE"},
+			{Name: "c_varchar", Tp: "VARCHAR", Precision: "10"},
+			{Name: "c_varbinary", Tp: "VARBINARY", Precision: "10"},
+			{Name: "c_json", Tp: "JSON"},
+			{Name: "c,d=e", Tp: "DOUBLE"},
+		},
+		TotalColumns: 10,
+	}
+	s, err := newSchema(def)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"name=_tidb_op, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		"name=_tidb_commit_ts, type=INT64, convertedtype=UINT_64, repetitiontype=OPTIONAL",
+		"name=id, type=INT32, convertedtype=INT_32, repetitiontype=OPTIONAL",
+		"name=c_utiny, type=INT32, convertedtype=UINT_8, repetitiontype=OPTIONAL",
+		"name=c_ubigint, type=INT64, convertedtype=UINT_64, repetitiontype=OPTIONAL",
+		"name=c_decimal, type=BYTE_ARRAY, convertedtype=DECIMAL, precision=10, scale=2, repetitiontype=OPTIONAL",
+		"name=c_datetime, type=INT64, convertedtype=TIMESTAMP_MICROS, repetitiontype=OPTIONAL",
+		"name=c_date, type=INT32, convertedtype=DATE, repetitiontype=OPTIONAL",
+		"name=c_varchar, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL",
+		"name=c_varbinary, type=BYTE_ARRAY, repetitiontype=OPTIONAL",
+		"name=c_json, type=BYTE_ARRAY, convertedtype=JSON, repetitiontype=OPTIONAL",
+		"name=c_d_e, type=DOUBLE, repetitiontype=OPTIONAL",
+	}, s.metadata())
+	require.Equal(t, 6, s.columns()[4].scale)
+}
+
+func TestDecimal(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value    string
+		scale    int
+		expected []byte
+		decoded  string
+	}{
+		{value: "0", scale: 0, expected: []byte{0}, decoded: "0"},
+		{value: "127", scale: 0, expected: []byte{0x7f}, decoded: "127"},
+		{value: "128", scale: 0, expected: []byte{0, 0x80}, decoded: "128"},
+		{value: "-1", scale: 0, expected: []byte{0xff}, decoded: "-1"},
+		{value: "-128", scale: 0, expected: []byte{0x80}, decoded: "-128"},
+		{value: "-129", scale: 0, expected: []byte{0xff, 0x7f}, decoded: "-129"},
+		{value: "12.34", scale: 2, expected: []byte{0x04, 0xd2}, decoded: "12.34"},
+		{value: "-0.05", scale: 2, expected: []byte{0xfb}, decoded: "-0.05"},
+		{value: "1.5", scale: 3, expected: []byte{0x05, 0xdc}, decoded: "1.500"},
+	}
+	for _, tc := range testCases {
+		b, err := decimalToBytes(tc.value, tc.scale)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.expected, []byte(b), tc.value)
+		require.Equal(t, tc.decoded, bytesToDecimal([]byte(b), tc.scale), tc.value)
+	}
+
+	_, err := decimalToBytes("1.234", 2)
+	require.Error(t, err)
+}
+
+func TestZeroDate(t *testing.T) {
+	t.Parallel()
+
+	date := &field{name: "c_date", kind: kindInt32, tp: mysql.TypeDate}
+	datetime := &field{name: "c_datetime", kind: kindInt64, tp: mysql.TypeDatetime}
+	timestamp := &field{name: "c_timestamp", kind: kindInt64, tp: mysql.TypeTimestamp}
+	testCases := []struct {
+		f        *field
+		value    string
+		expected any
+	}{
+		{f: date, value: "0000-00-00", expected: nil},
+		{f: date, value: "2024-01-00", expected: nil},
+		{f: date, value: "1970-01-02", expected: int32(1)},
+		{f: datetime, value: "0000-00-00 00:00:00", expected: nil},
+		{f: datetime, value: "2024-00-01 00:00:00.123", expected: nil},
+		{f: datetime, value: "1970-01-01 00:00:01", expected: int64(1000000)},
+		{f: timestamp, value: "0000-00-00 00:00:00", expected: nil},
+	}
+	for _, tc := range testCases {
+		v, err := tc.f.toParquetValue(tc.value, nil, time.UTC)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.expected, v, tc.value)
+	}
+}
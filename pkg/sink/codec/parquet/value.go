@@ -0,0 +1,271 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	datetimeLayout = "2006-01-02 15:04:05"
+	microsPerDay   = int64(24 * time.Hour / time.Microsecond)
+)
+
+// toParquetValue converts the value of a row changed event column to the value
+// of the parquet physical type, ft is used to resolve ENUM and SET values and
+// loc is the time zone of TIMESTAMP values.
+func (f *field) toParquetValue(value any, ft *types.FieldType, loc *time.Location) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch f.tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeYear,
+		mysql.TypeLonglong, mysql.TypeBit:
+		v, ok := toInt64(value)
+		if !ok {
+			break
+		}
+		if f.kind == kindInt32 {
+			return int32(v), nil
+		}
+		return v, nil
+	case mysql.TypeFloat:
+		switch v := value.(type) {
+		case float32:
+			return v, nil
+		case float64:
+			return float32(v), nil
+		}
+	case mysql.TypeDouble:
+		switch v := value.(type) {
+		case float32:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case mysql.TypeNewDecimal:
+		if v, ok := value.(string); ok {
+			return decimalToBytes(v, f.scale)
+		}
+	case mysql.TypeDate:
+		if v, ok := value.(string); ok {
+			if isZeroDate(v) {
+				return nil, nil
+			}
+			t, err := parseTime(dateLayout, v, time.UTC)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return int32(t.UnixMicro() / microsPerDay), nil
+		}
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		if v, ok := value.(string); ok {
+			if isZeroDate(v) {
+				return nil, nil
+			}
+			if f.tp == mysql.TypeDatetime {
+				loc = time.UTC
+			}
+			t, err := parseTime(datetimeLayout, v, loc)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return t.UnixMicro(), nil
+		}
+	case mysql.TypeEnum:
+		if v, ok := value.(uint64); ok {
+			enum, err := types.ParseEnumValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+			}
+			return enum.Name, nil
+		}
+	case mysql.TypeSet:
+		if v, ok := value.(uint64); ok {
+			set, err := types.ParseSetValue(ft.GetElems(), v)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+			}
+			return set.Name, nil
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		if f.kind == kindByteArray {
+			return v, nil
+		}
+	case []byte:
+		if f.kind == kindByteArray {
+			return string(v), nil
+		}
+	}
+	return nil, cerror.ErrParquetEncodeFailed.GenWithStack(
+		"unexpected value %v of type %T for column %s", value, value, f.name)
+}
+
+// fromParquetValue converts the value read from the parquet file to the value
+// of a row changed event column, loc is the time zone of TIMESTAMP values.
+func (f *field) fromParquetValue(value any, loc *time.Location) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch v := value.(type) {
+	case int32:
+		switch {
+		case f.tp == mysql.TypeDate:
+			return time.UnixMicro(int64(v) * microsPerDay).UTC().Format(dateLayout), nil
+		case f.unsigned:
+			return uint64(uint32(v)), nil
+		default:
+			return int64(v), nil
+		}
+	case int64:
+		switch {
+		case f.tp == mysql.TypeDatetime:
+			return formatTime(time.UnixMicro(v).UTC(), f.scale), nil
+		case f.tp == mysql.TypeTimestamp:
+			return formatTime(time.UnixMicro(v).In(loc), f.scale), nil
+		case f.unsigned:
+			return uint64(v), nil
+		default:
+			return v, nil
+		}
+	case float32:
+		return v, nil
+	case float64:
+		return v, nil
+	case string:
+		switch f.tp {
+		case mysql.TypeNewDecimal:
+			return bytesToDecimal([]byte(v), f.scale), nil
+		case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+			return []byte(v), nil
+		default:
+			return v, nil
+		}
+	}
+	return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+		"unexpected value %v of type %T for column %s", value, value, f.name)
+}
+
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// isZeroDate returns whether the month or day of the time string formatted by
+// TiDB is zero, such as `0000-00-00` and `2024-01-00 00:00:00`. They can't be
+// represented by the parquet time types, so they are written as NULL.
+func isZeroDate(value string) bool {
+	date, _, _ := strings.Cut(value, " ")
+	parts := strings.Split(date, "-")
+	return len(parts) == 3 && (parts[1] == "00" || parts[2] == "00")
+}
+
+// parseTime parses the time string formatted by TiDB.
+func parseTime(layout, value string, loc *time.Location) (time.Time, error) {
+	// The fractional seconds are accepted even if the layout does not contain them.
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	return t, nil
+}
+
+// formatTime formats the time as TiDB does, with fsp fractional digits.
+func formatTime(t time.Time, fsp int) string {
+	if fsp <= 0 {
+		return t.Format(datetimeLayout)
+	}
+	return t.Format(datetimeLayout + "." + strings.Repeat("0", fsp))
+}
+
+// decimalToBytes returns the big-endian two's complement of the unscaled value
+// of the decimal string.
+func decimalToBytes(value string, scale int) (string, error) {
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	if len(fracPart) > scale {
+		return "", cerror.ErrParquetEncodeFailed.GenWithStack(
+			"the scale of decimal %s is larger than %d", value, scale)
+	}
+	fracPart += strings.Repeat("0", scale-len(fracPart))
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return "", cerror.ErrParquetEncodeFailed.GenWithStack("invalid decimal %s", value)
+	}
+
+	if unscaled.Sign() >= 0 {
+		b := unscaled.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return string(b), nil
+	}
+	// The two's complement of -x is the bitwise not of x-1.
+	b := unscaled.Neg(unscaled).Sub(unscaled, big.NewInt(1)).Bytes()
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	if len(b) == 0 || b[0]&0x80 == 0 {
+		b = append([]byte{0xff}, b...)
+	}
+	return string(b), nil
+}
+
+// bytesToDecimal is the inverse of decimalToBytes.
+func bytesToDecimal(b []byte, scale int) string {
+	unscaled := new(big.Int)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		inverted := make([]byte, len(b))
+		for i := range b {
+			inverted[i] = ^b[i]
+		}
+		unscaled.SetBytes(inverted).Add(unscaled, big.NewInt(1)).Neg(unscaled)
+	} else {
+		unscaled.SetBytes(b)
+	}
+
+	digits := unscaled.String()
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
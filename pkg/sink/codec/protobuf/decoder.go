@@ -0,0 +1,269 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"container/list"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var _ codec.RowEventDecoder = (*Decoder)(nil)
+
+type tableVersionKey struct {
+	schema  string
+	table   string
+	version uint64
+}
+
+// decodedTable is a table schema restored from a DDL or BOOTSTRAP envelope.
+type decodedTable struct {
+	schema    *tableSchema
+	tableInfo *model.TableInfo
+}
+
+// Decoder decodes the protobuf envelopes. It works like a schema cache, the
+// row messages are restored from the descriptors carried by the DDL and
+// BOOTSTRAP envelopes, and rows are decoded with the table version they are
+// encoded with.
+type Decoder struct {
+	// delimited indicates the value contains varint length-delimited envelopes,
+	// which is the format written by the storage sink.
+	delimited bool
+
+	value []byte
+	msg   *envelope
+
+	tables map[tableVersionKey]*decodedTable
+
+	// cachedMessages is used to store the messages which does not have received corresponding table schema yet.
+	cachedMessages *list.List
+	// CachedRowChangedEvents are events just decoded from the cachedMessages
+	CachedRowChangedEvents []*model.RowChangedEvent
+}
+
+// NewDecoder returns a new Decoder for the messages sent to the message queue.
+func NewDecoder() *Decoder {
+	return newDecoder(false)
+}
+
+// NewStorageDecoder returns a new Decoder for the files written by the storage sink.
+func NewStorageDecoder() *Decoder {
+	return newDecoder(true)
+}
+
+func newDecoder(delimited bool) *Decoder {
+	return &Decoder{
+		delimited:      delimited,
+		tables:         make(map[tableVersionKey]*decodedTable),
+		cachedMessages: list.New(),
+	}
+}
+
+// AddKeyValue add the received key and values to the Decoder,
+func (d *Decoder) AddKeyValue(_, value []byte) error {
+	if len(d.value) != 0 {
+		return cerror.ErrCodecDecode.GenWithStack(
+			"Decoder value already exists, not consumed yet")
+	}
+	d.value = value
+	return nil
+}
+
+// HasNext returns whether there is any event need to be consumed
+func (d *Decoder) HasNext() (model.MessageType, bool, error) {
+	if len(d.value) == 0 {
+		d.value = nil
+		return model.MessageTypeUnknown, false, nil
+	}
+
+	data := d.value
+	if d.delimited {
+		length, n := protowire.ConsumeVarint(d.value)
+		if n < 0 || length > uint64(len(d.value)-n) {
+			return model.MessageTypeUnknown, false, cerror.ErrDecodeFailed.GenWithStackByArgs(
+				"invalid length-delimited envelope")
+		}
+		data = d.value[n : n+int(length)]
+		d.value = d.value[n+int(length):]
+	} else {
+		d.value = nil
+	}
+
+	m := new(envelope)
+	if err := m.unmarshal(data); err != nil {
+		return model.MessageTypeUnknown, false, cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	d.msg = m
+
+	switch m.Type {
+	case EventTypeInsert, EventTypeUpdate, EventTypeDelete:
+		return model.MessageTypeRow, true, nil
+	case EventTypeDDL, EventTypeBootstrap:
+		return model.MessageTypeDDL, true, nil
+	case EventTypeWatermark:
+		return model.MessageTypeResolved, true, nil
+	default:
+		return model.MessageTypeUnknown, false, cerror.ErrDecodeFailed.GenWithStackByArgs(
+			"unknown event type")
+	}
+}
+
+// NextResolvedEvent returns the next resolved event if exists
+func (d *Decoder) NextResolvedEvent() (uint64, error) {
+	if d.msg == nil || d.msg.Type != EventTypeWatermark {
+		return 0, cerror.ErrCodecDecode.GenWithStack(
+			"not found resolved event message")
+	}
+
+	ts := d.msg.CommitTs
+	d.msg = nil
+
+	return ts, nil
+}
+
+// NextRowChangedEvent returns the next row changed event if exists.
+// If the schema of the row is not received yet, nil is returned, and the row
+// is returned by GetCachedEvents after the schema is received.
+func (d *Decoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if d.msg == nil || d.msg.Before == nil && d.msg.After == nil {
+		return nil, cerror.ErrCodecDecode.GenWithStack(
+			"invalid row changed event message")
+	}
+	msg := d.msg
+	d.msg = nil
+
+	table, ok := d.tables[tableVersionKeyOf(msg)]
+	if !ok {
+		log.Debug("table schema not found for the event, "+
+			"the consumer should cache this event temporarily, and update the schema after it's received",
+			zap.String("schema", msg.Schema),
+			zap.String("table", msg.Table),
+			zap.Uint64("version", msg.TableVersion))
+		d.cachedMessages.PushBack(msg)
+		return nil, nil
+	}
+	return buildRowChangedEvent(msg, table)
+}
+
+// NextDDLEvent returns the next DDL event if exists
+func (d *Decoder) NextDDLEvent() (*model.DDLEvent, error) {
+	if d.msg == nil || (d.msg.Type != EventTypeDDL && d.msg.Type != EventTypeBootstrap) {
+		return nil, cerror.ErrCodecDecode.GenWithStack(
+			"no message found when decode DDL event")
+	}
+	msg := d.msg
+	d.msg = nil
+
+	result := &model.DDLEvent{
+		StartTs:     msg.CommitTs,
+		CommitTs:    msg.CommitTs,
+		Query:       msg.Query,
+		Type:        timodel.ActionType(msg.DDLType),
+		IsBootstrap: msg.Type == EventTypeBootstrap,
+	}
+	if len(msg.Descriptor) == 0 {
+		result.TableInfo = &model.TableInfo{
+			TableName: model.TableName{Schema: msg.Schema, Table: msg.Table},
+		}
+		return result, nil
+	}
+
+	key := tableVersionKeyOf(msg)
+	table, ok := d.tables[key]
+	if !ok {
+		s, err := newTableSchemaFromEnvelope(msg)
+		if err != nil {
+			return nil, err
+		}
+		table = &decodedTable{schema: s, tableInfo: newTableInfo(s, msg.TableID)}
+		d.tables[key] = table
+	}
+	result.TableInfo = table.tableInfo
+
+	for ele := d.cachedMessages.Front(); ele != nil; {
+		next := ele.Next()
+		m := ele.Value.(*envelope)
+		if cached, ok := d.tables[tableVersionKeyOf(m)]; ok {
+			event, err := buildRowChangedEvent(m, cached)
+			if err != nil {
+				return nil, err
+			}
+			d.CachedRowChangedEvents = append(d.CachedRowChangedEvents, event)
+			d.cachedMessages.Remove(ele)
+		}
+		ele = next
+	}
+	return result, nil
+}
+
+// GetCachedEvents returns the cached events
+func (d *Decoder) GetCachedEvents() []*model.RowChangedEvent {
+	result := d.CachedRowChangedEvents
+	d.CachedRowChangedEvents = nil
+	return result
+}
+
+func tableVersionKeyOf(msg *envelope) tableVersionKey {
+	return tableVersionKey{schema: msg.Schema, table: msg.Table, version: msg.TableVersion}
+}
+
+// newTableInfo builds the table info from the column metadata of the schema.
+// Note that the column IDs of the result are mocked.
+func newTableInfo(s *tableSchema, tableID int64) *model.TableInfo {
+	columns := make([]*model.Column, 0, len(s.columns))
+	for _, col := range s.columns {
+		columns = append(columns, &model.Column{
+			Name:      col.Name,
+			Type:      col.Type,
+			Charset:   col.Charset,
+			Collation: col.Collation,
+			Flag:      model.ColumnFlagType(col.Flag),
+		})
+	}
+	tableInfo := model.BuildTableInfo(s.schema, s.table, columns,
+		model.GetHandleAndUniqueIndexOffsets4Test(columns))
+	tableInfo.TableName.TableID = tableID
+	tableInfo.UpdateTS = s.version
+	return tableInfo
+}
+
+func buildRowChangedEvent(msg *envelope, table *decodedTable) (*model.RowChangedEvent, error) {
+	result := &model.RowChangedEvent{
+		CommitTs:        msg.CommitTs,
+		PhysicalTableID: msg.TableID,
+		TableInfo:       table.tableInfo,
+	}
+	if msg.Type != EventTypeInsert {
+		columns, err := table.schema.decodeRow(msg.Before)
+		if err != nil {
+			return nil, err
+		}
+		result.PreColumns = model.Columns2ColumnDatas(columns, table.tableInfo)
+	}
+	if msg.Type != EventTypeDelete {
+		columns, err := table.schema.decodeRow(msg.After)
+		if err != nil {
+			return nil, err
+		}
+		result.Columns = model.Columns2ColumnDatas(columns, table.tableInfo)
+	}
+	return result, nil
+}
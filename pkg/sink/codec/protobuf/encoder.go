@@ -0,0 +1,252 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"go.uber.org/zap"
+)
+
+type schemaKey struct {
+	schema string
+	table  string
+}
+
+// schemaCache caches the latest row message of each table, it is shared by
+// all encoders created by the same builder.
+type schemaCache struct {
+	// registry is nil if the schema registry is not configured.
+	registry *schemaRegistry
+
+	mu      sync.Mutex
+	schemas map[schemaKey]*tableSchema
+}
+
+func newSchemaCache(registry *schemaRegistry) *schemaCache {
+	return &schemaCache{
+		registry: registry,
+		schemas:  make(map[schemaKey]*tableSchema),
+	}
+}
+
+// get returns the row message of the table and its schema ID in the registry.
+func (c *schemaCache) get(
+	ctx context.Context, tableInfo *model.TableInfo,
+) (*tableSchema, int32, error) {
+	key := schemaKey{schema: tableInfo.GetSchemaName(), table: tableInfo.GetTableName()}
+	c.mu.Lock()
+	s, ok := c.schemas[key]
+	c.mu.Unlock()
+	if !ok || s.version != tableInfo.UpdateTS {
+		var err error
+		s, err = newTableSchema(tableInfo)
+		if err != nil {
+			return nil, 0, err
+		}
+		c.mu.Lock()
+		c.schemas[key] = s
+		c.mu.Unlock()
+	}
+	if c.registry == nil {
+		return s, 0, nil
+	}
+	id, err := c.registry.register(ctx, s)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s, id, nil
+}
+
+// rowEnvelope builds the envelope of the row changed event.
+func rowEnvelope(e *model.RowChangedEvent, s *tableSchema, schemaID int32) (*envelope, error) {
+	result := &envelope{
+		CommitTs:     e.CommitTs,
+		Schema:       e.TableInfo.GetSchemaName(),
+		Table:        e.TableInfo.GetTableName(),
+		TableID:      e.GetTableID(),
+		TableVersion: s.version,
+		MessageName:  s.messageName(),
+		SchemaID:     schemaID,
+	}
+	var err error
+	switch {
+	case e.IsDelete():
+		result.Type = EventTypeDelete
+		result.Before, err = s.encodeRow(e.PreColumns)
+	case e.IsUpdate():
+		result.Type = EventTypeUpdate
+		if result.Before, err = s.encodeRow(e.PreColumns); err == nil {
+			result.After, err = s.encodeRow(e.Columns)
+		}
+	default:
+		result.Type = EventTypeInsert
+		result.After, err = s.encodeRow(e.Columns)
+	}
+	return result, err
+}
+
+// schemaEnvelope builds the envelope which carries the row message descriptor.
+func schemaEnvelope(ty EventType, s *tableSchema, tableID int64, schemaID int32) *envelope {
+	return &envelope{
+		Type:         ty,
+		Schema:       s.schema,
+		Table:        s.table,
+		TableID:      tableID,
+		TableVersion: s.version,
+		MessageName:  s.messageName(),
+		SchemaID:     schemaID,
+		Descriptor:   s.descriptor,
+		Columns:      s.columns,
+	}
+}
+
+// BatchEncoder encodes the events into protobuf envelopes.
+type BatchEncoder struct {
+	ctx      context.Context
+	messages []*common.Message
+	config   *common.Config
+	schemas  *schemaCache
+}
+
+// AppendRowChangedEvent implement the RowEventEncoder interface
+func (e *BatchEncoder) AppendRowChangedEvent(
+	ctx context.Context, _ string, event *model.RowChangedEvent, callback func(),
+) error {
+	s, schemaID, err := e.schemas.get(ctx, event.TableInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := rowEnvelope(event, s, schemaID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	result := &common.Message{
+		Value:    env.marshal(nil),
+		Ts:       event.CommitTs,
+		Schema:   event.TableInfo.GetSchemaNamePtr(),
+		Table:    event.TableInfo.GetTableNamePtr(),
+		Type:     model.MessageTypeRow,
+		Protocol: config.ProtocolProtobuf,
+		Callback: callback,
+	}
+	result.IncRowsCount()
+
+	if length := result.Length(); length > e.config.MaxMessageBytes {
+		log.Error("Single message is too large for protobuf",
+			zap.Int("maxMessageBytes", e.config.MaxMessageBytes),
+			zap.Int("length", length),
+			zap.Any("table", event.TableInfo.TableName))
+		return cerror.ErrMessageTooLarge.GenWithStackByArgs()
+	}
+	e.messages = append(e.messages, result)
+	return nil
+}
+
+// Build implement the RowEventEncoder interface
+func (e *BatchEncoder) Build() []*common.Message {
+	var result []*common.Message
+	if len(e.messages) != 0 {
+		result = e.messages
+		e.messages = nil
+	}
+	return result
+}
+
+// EncodeCheckpointEvent implement the DDLEventBatchEncoder interface
+func (e *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
+	env := &envelope{Type: EventTypeWatermark, CommitTs: ts}
+	return common.NewResolvedMsg(config.ProtocolProtobuf, nil, env.marshal(nil), ts), nil
+}
+
+// EncodeDDLEvent implement the DDLEventBatchEncoder interface.
+// The envelope carries the descriptor of the table after the DDL,
+// bootstrap events are encoded as BOOTSTRAP envelopes.
+func (e *BatchEncoder) EncodeDDLEvent(event *model.DDLEvent) (*common.Message, error) {
+	var env *envelope
+	if event.TableInfo.TableName.Table != "" {
+		s, schemaID, err := e.schemas.get(e.ctx, event.TableInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		env = schemaEnvelope(EventTypeDDL, s, event.TableInfo.ID, schemaID)
+	} else {
+		// schema level DDLs don't carry any descriptor.
+		env = &envelope{Type: EventTypeDDL, Schema: event.TableInfo.TableName.Schema}
+	}
+	if event.IsBootstrap {
+		env.Type = EventTypeBootstrap
+	}
+	env.CommitTs = event.CommitTs
+	env.Query = event.Query
+	env.DDLType = int32(event.Type)
+
+	result := common.NewDDLMsg(config.ProtocolProtobuf, nil, env.marshal(nil), event)
+	if result.Length() > e.config.MaxMessageBytes {
+		log.Error("DDL message is too large for protobuf",
+			zap.Int("maxMessageBytes", e.config.MaxMessageBytes),
+			zap.Int("length", result.Length()),
+			zap.Any("table", event.TableInfo.TableName))
+		return nil, cerror.ErrMessageTooLarge.GenWithStackByArgs()
+	}
+	return result, nil
+}
+
+type batchEncoderBuilder struct {
+	ctx     context.Context
+	config  *common.Config
+	schemas *schemaCache
+}
+
+// NewBatchEncoderBuilder creates a protobuf batchEncoderBuilder.
+// The row messages are registered to the Confluent schema registry if it is configured.
+func NewBatchEncoderBuilder(
+	ctx context.Context, config *common.Config,
+) (codec.RowEventEncoderBuilder, error) {
+	var registry *schemaRegistry
+	if config.AvroConfluentSchemaRegistry != "" {
+		var err error
+		registry, err = newSchemaRegistry(config.AvroConfluentSchemaRegistry)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &batchEncoderBuilder{
+		ctx:     ctx,
+		config:  config,
+		schemas: newSchemaCache(registry),
+	}, nil
+}
+
+// Build a protobuf BatchEncoder
+func (b *batchEncoderBuilder) Build() codec.RowEventEncoder {
+	return &BatchEncoder{
+		ctx:      b.ctx,
+		messages: make([]*common.Message, 0, 1),
+		config:   b.config,
+		schemas:  b.schemas,
+	}
+}
+
+// CleanMetrics is a no-op for protobuf batchEncoderBuilder.
+func (b *batchEncoderBuilder) CleanMetrics() {}
@@ -0,0 +1,280 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+const createTableSQL = `create table test.t(
+	id int primary key,
+	c_uint int unsigned,
+	c_bigint bigint,
+	c_float float,
+	c_double double,
+	c_decimal decimal(10, 2),
+	c_varchar varchar(10),
+	c_varbinary varbinary(10),
+	c_blob blob,
+	c_text text,
+	c_date date,
+	c_datetime datetime,
+	c_json json,
+	c_enum enum('a', 'b'),
+	c_set set('a', 'b'),
+	c_bit bit(8),
+	c_year year,
+	c_null int)`
+
+const insertSQL = `insert into test.t values (
+	1, 2, -3, 1.5, 2.25, 12.34, 'varchar', x'0102', x'0304', 'text',
+	'2024-01-02', '2024-01-02 03:04:05', '{"a": 1}', 'b', 'a,b', b'101', 2024, null)`
+
+func requireColumnsEqual(
+	t *testing.T,
+	expected *model.RowChangedEvent, expectedColumns []*model.ColumnData,
+	actual *model.RowChangedEvent, actualColumns []*model.ColumnData,
+) {
+	require.Len(t, actualColumns, len(expectedColumns))
+	values := make(map[string]interface{}, len(actualColumns))
+	for _, col := range actualColumns {
+		values[actual.TableInfo.ForceGetColumnName(col.ColumnID)] = col.Value
+	}
+	for _, col := range expectedColumns {
+		name := expected.TableInfo.ForceGetColumnName(col.ColumnID)
+		value, ok := values[name]
+		require.True(t, ok, name)
+		require.Equal(t, col.Value, value, name)
+	}
+}
+
+func TestEncodeDDLAndRowChangedEvent(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event(createTableSQL)
+	insert := helper.DML2Event(insertSQL, "test", "t")
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolProtobuf)
+	b, err := NewBatchEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	enc := b.Build()
+	dec := NewDecoder()
+
+	m, err := enc.EncodeDDLEvent(ddl)
+	require.NoError(t, err)
+	require.NoError(t, dec.AddKeyValue(m.Key, m.Value))
+	messageType, hasNext, err := dec.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, messageType)
+	decodedDDL, err := dec.NextDDLEvent()
+	require.NoError(t, err)
+	require.Equal(t, ddl.Query, decodedDDL.Query)
+	require.Equal(t, ddl.CommitTs, decodedDDL.CommitTs)
+	require.Equal(t, ddl.Type, decodedDDL.Type)
+	require.False(t, decodedDDL.IsBootstrap)
+	require.Equal(t, "test", decodedDDL.TableInfo.GetSchemaName())
+	require.Equal(t, "t", decodedDDL.TableInfo.GetTableName())
+	require.Equal(t, []string{"id"}, decodedDDL.TableInfo.GetPrimaryKeyColumnNames())
+
+	update := *insert
+	update.PreColumns = insert.Columns
+	deleted := *insert
+	deleted.PreColumns = insert.Columns
+	deleted.Columns = nil
+
+	for _, event := range []*model.RowChangedEvent{insert, &update, &deleted} {
+		err = enc.AppendRowChangedEvent(ctx, "", event, nil)
+		require.NoError(t, err)
+		messages := enc.Build()
+		require.Len(t, messages, 1)
+
+		require.NoError(t, dec.AddKeyValue(messages[0].Key, messages[0].Value))
+		messageType, hasNext, err = dec.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, messageType)
+
+		decoded, err := dec.NextRowChangedEvent()
+		require.NoError(t, err)
+		require.NotNil(t, decoded)
+		require.Equal(t, event.CommitTs, decoded.CommitTs)
+		require.Equal(t, event.PhysicalTableID, decoded.PhysicalTableID)
+		require.Equal(t, event.IsInsert(), decoded.IsInsert())
+		require.Equal(t, event.IsUpdate(), decoded.IsUpdate())
+		require.Equal(t, event.IsDelete(), decoded.IsDelete())
+		requireColumnsEqual(t, event, event.Columns, decoded, decoded.Columns)
+		requireColumnsEqual(t, event, event.PreColumns, decoded, decoded.PreColumns)
+	}
+}
+
+func TestRowChangedEventBeforeBootstrap(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.DDL2Event(createTableSQL)
+	insert := helper.DML2Event(insertSQL, "test", "t")
+
+	ctx := context.Background()
+	b, err := NewBatchEncoderBuilder(ctx, common.NewConfig(config.ProtocolProtobuf))
+	require.NoError(t, err)
+	enc := b.Build()
+	dec := NewDecoder()
+
+	err = enc.AppendRowChangedEvent(ctx, "", insert, nil)
+	require.NoError(t, err)
+	messages := enc.Build()
+	require.NoError(t, dec.AddKeyValue(messages[0].Key, messages[0].Value))
+	_, _, err = dec.HasNext()
+	require.NoError(t, err)
+	row, err := dec.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Nil(t, row)
+
+	m, err := enc.EncodeDDLEvent(model.NewBootstrapDDLEvent(insert.TableInfo))
+	require.NoError(t, err)
+	require.NoError(t, dec.AddKeyValue(m.Key, m.Value))
+	messageType, _, err := dec.HasNext()
+	require.NoError(t, err)
+	require.Equal(t, model.MessageTypeDDL, messageType)
+	ddl, err := dec.NextDDLEvent()
+	require.NoError(t, err)
+	require.True(t, ddl.IsBootstrap)
+	require.Empty(t, ddl.Query)
+
+	cached := dec.GetCachedEvents()
+	require.Len(t, cached, 1)
+	requireColumnsEqual(t, insert, insert.Columns, cached[0], cached[0].Columns)
+	require.Empty(t, dec.GetCachedEvents())
+}
+
+func TestEncodeCheckpointEvent(t *testing.T) {
+	b, err := NewBatchEncoderBuilder(context.Background(), common.NewConfig(config.ProtocolProtobuf))
+	require.NoError(t, err)
+	enc := b.Build()
+
+	checkpoint := uint64(446266400629063682)
+	m, err := enc.EncodeCheckpointEvent(checkpoint)
+	require.NoError(t, err)
+	require.Equal(t, model.MessageTypeResolved, m.Type)
+
+	dec := NewDecoder()
+	require.NoError(t, dec.AddKeyValue(m.Key, m.Value))
+	messageType, hasNext, err := dec.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeResolved, messageType)
+	ts, err := dec.NextResolvedEvent()
+	require.NoError(t, err)
+	require.Equal(t, checkpoint, ts)
+
+	_, hasNext, err = dec.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
+
+func TestEncodeSchemaLevelDDL(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event("create database abc")
+	b, err := NewBatchEncoderBuilder(context.Background(), common.NewConfig(config.ProtocolProtobuf))
+	require.NoError(t, err)
+	m, err := b.Build().EncodeDDLEvent(ddl)
+	require.NoError(t, err)
+
+	dec := NewDecoder()
+	require.NoError(t, dec.AddKeyValue(m.Key, m.Value))
+	_, _, err = dec.HasNext()
+	require.NoError(t, err)
+	decoded, err := dec.NextDDLEvent()
+	require.NoError(t, err)
+	require.Equal(t, ddl.Query, decoded.Query)
+	require.Equal(t, "abc", decoded.TableInfo.TableName.Schema)
+}
+
+func TestMessageTooLarge(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.DDL2Event(createTableSQL)
+	insert := helper.DML2Event(insertSQL, "test", "t")
+
+	codecConfig := common.NewConfig(config.ProtocolProtobuf)
+	codecConfig.MaxMessageBytes = 10
+	b, err := NewBatchEncoderBuilder(context.Background(), codecConfig)
+	require.NoError(t, err)
+	err = b.Build().AppendRowChangedEvent(context.Background(), "", insert, nil)
+	require.ErrorIs(t, err, errors.ErrMessageTooLarge)
+}
+
+func TestTxnEventEncoder(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.DDL2Event(createTableSQL)
+	event1 := helper.DML2Event(insertSQL, "test", "t")
+	event2 := helper.DML2Event(
+		"insert into test.t(id, c_varchar) values (2, 'second')", "test", "t")
+
+	enc := NewTxnEventEncoderBuilder(common.NewConfig(config.ProtocolProtobuf)).Build()
+	require.NoError(t, enc.AppendTxnEvent(&model.SingleTableTxn{TableInfo: event1.TableInfo}, nil))
+	require.Nil(t, enc.Build())
+
+	called := 0
+	txn := &model.SingleTableTxn{
+		PhysicalTableID: event1.PhysicalTableID,
+		TableInfo:       event1.TableInfo,
+		Rows:            []*model.RowChangedEvent{event1, event2},
+	}
+	require.NoError(t, enc.AppendTxnEvent(txn, func() { called++ }))
+	messages := enc.Build()
+	require.Len(t, messages, 1)
+	require.Equal(t, 2, messages[0].GetRowsCount())
+	messages[0].Callback()
+	require.Equal(t, 1, called)
+
+	dec := NewStorageDecoder()
+	require.NoError(t, dec.AddKeyValue(nil, messages[0].Value))
+	messageType, hasNext, err := dec.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, messageType)
+	ddl, err := dec.NextDDLEvent()
+	require.NoError(t, err)
+	require.True(t, ddl.IsBootstrap)
+
+	for _, event := range txn.Rows {
+		messageType, hasNext, err = dec.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, messageType)
+		decoded, err := dec.NextRowChangedEvent()
+		require.NoError(t, err)
+		requireColumnsEqual(t, event, event.Columns, decoded, decoded.Columns)
+	}
+	_, hasNext, err = dec.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
@@ -0,0 +1,259 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// EventType is the type of the event carried by an envelope.
+type EventType int32
+
+// Enum types of the EventType, see proto/TiCDCProtobufProtocol.proto.
+const (
+	EventTypeUnspecified EventType = iota
+	EventTypeInsert
+	EventTypeUpdate
+	EventTypeDelete
+	EventTypeDDL
+	EventTypeBootstrap
+	EventTypeWatermark
+)
+
+// field numbers of the `Event` message.
+const (
+	eventFieldType         protowire.Number = 1
+	eventFieldCommitTs     protowire.Number = 2
+	eventFieldSchema       protowire.Number = 3
+	eventFieldTable        protowire.Number = 4
+	eventFieldTableID      protowire.Number = 5
+	eventFieldTableVersion protowire.Number = 6
+	eventFieldMessageName  protowire.Number = 7
+	eventFieldSchemaID     protowire.Number = 8
+	eventFieldBefore       protowire.Number = 9
+	eventFieldAfter        protowire.Number = 10
+	eventFieldQuery        protowire.Number = 11
+	eventFieldDDLType      protowire.Number = 12
+	eventFieldDescriptor   protowire.Number = 13
+	eventFieldColumns      protowire.Number = 14
+)
+
+// field numbers of the `Column` message.
+const (
+	columnFieldName      protowire.Number = 1
+	columnFieldID        protowire.Number = 2
+	columnFieldNumber    protowire.Number = 3
+	columnFieldMySQLType protowire.Number = 4
+	columnFieldFlag      protowire.Number = 5
+	columnFieldCharset   protowire.Number = 6
+	columnFieldCollation protowire.Number = 7
+)
+
+// columnMeta is the `Column` message, it describes how a column is encoded
+// in the row message.
+type columnMeta struct {
+	Name      string
+	ID        int64
+	Number    int32
+	Type      byte
+	Flag      uint64
+	Charset   string
+	Collation string
+}
+
+// envelope is the `Event` message.
+type envelope struct {
+	Type         EventType
+	CommitTs     uint64
+	Schema       string
+	Table        string
+	TableID      int64
+	TableVersion uint64
+	MessageName  string
+	SchemaID     int32
+	Before       []byte
+	After        []byte
+	Query        string
+	DDLType      int32
+	Descriptor   []byte
+	Columns      []*columnMeta
+}
+
+func (e *envelope) marshal(b []byte) []byte {
+	b = appendVarint(b, eventFieldType, uint64(e.Type))
+	b = appendVarint(b, eventFieldCommitTs, e.CommitTs)
+	b = appendString(b, eventFieldSchema, e.Schema)
+	b = appendString(b, eventFieldTable, e.Table)
+	b = appendVarint(b, eventFieldTableID, uint64(e.TableID))
+	b = appendVarint(b, eventFieldTableVersion, e.TableVersion)
+	b = appendString(b, eventFieldMessageName, e.MessageName)
+	b = appendVarint(b, eventFieldSchemaID, uint64(int64(e.SchemaID)))
+	b = appendBytes(b, eventFieldBefore, e.Before)
+	b = appendBytes(b, eventFieldAfter, e.After)
+	b = appendString(b, eventFieldQuery, e.Query)
+	b = appendVarint(b, eventFieldDDLType, uint64(int64(e.DDLType)))
+	b = appendBytes(b, eventFieldDescriptor, e.Descriptor)
+	for _, col := range e.Columns {
+		b = protowire.AppendTag(b, eventFieldColumns, protowire.BytesType)
+		b = protowire.AppendBytes(b, col.marshal(nil))
+	}
+	return b
+}
+
+func (e *envelope) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v uint64) {
+		switch num {
+		case eventFieldType:
+			e.Type = EventType(v)
+		case eventFieldCommitTs:
+			e.CommitTs = v
+		case eventFieldTableID:
+			e.TableID = int64(v)
+		case eventFieldTableVersion:
+			e.TableVersion = v
+		case eventFieldSchemaID:
+			e.SchemaID = int32(v)
+		case eventFieldDDLType:
+			e.DDLType = int32(v)
+		}
+	}, func(num protowire.Number, v []byte) error {
+		switch num {
+		case eventFieldSchema:
+			e.Schema = string(v)
+		case eventFieldTable:
+			e.Table = string(v)
+		case eventFieldMessageName:
+			e.MessageName = string(v)
+		case eventFieldBefore:
+			e.Before = v
+		case eventFieldAfter:
+			e.After = v
+		case eventFieldQuery:
+			e.Query = string(v)
+		case eventFieldDescriptor:
+			e.Descriptor = v
+		case eventFieldColumns:
+			col := new(columnMeta)
+			if err := col.unmarshal(v); err != nil {
+				return err
+			}
+			e.Columns = append(e.Columns, col)
+		}
+		return nil
+	})
+}
+
+func (c *columnMeta) marshal(b []byte) []byte {
+	b = appendString(b, columnFieldName, c.Name)
+	b = appendVarint(b, columnFieldID, uint64(c.ID))
+	b = appendVarint(b, columnFieldNumber, uint64(int64(c.Number)))
+	b = appendVarint(b, columnFieldMySQLType, uint64(c.Type))
+	b = appendVarint(b, columnFieldFlag, c.Flag)
+	b = appendString(b, columnFieldCharset, c.Charset)
+	b = appendString(b, columnFieldCollation, c.Collation)
+	return b
+}
+
+func (c *columnMeta) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v uint64) {
+		switch num {
+		case columnFieldID:
+			c.ID = int64(v)
+		case columnFieldNumber:
+			c.Number = int32(v)
+		case columnFieldMySQLType:
+			c.Type = byte(v)
+		case columnFieldFlag:
+			c.Flag = v
+		}
+	}, func(num protowire.Number, v []byte) error {
+		switch num {
+		case columnFieldName:
+			c.Name = string(v)
+		case columnFieldCharset:
+			c.Charset = string(v)
+		case columnFieldCollation:
+			c.Collation = string(v)
+		}
+		return nil
+	})
+}
+
+// appendVarint appends a varint field, zero values are omitted as proto3 does.
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// appendBytes appends a bytes field, an empty but non-nil value is kept,
+// since an empty row message is valid if all columns are NULL.
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if v == nil {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// consumeFields walks through the fields of a message, unknown fields and
+// fields of unexpected wire types are skipped.
+func consumeFields(
+	b []byte,
+	onVarint func(protowire.Number, uint64),
+	onBytes func(protowire.Number, []byte) error,
+) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			onVarint(num, v)
+			b = b[n:]
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			if err := onBytes(num, v); err != nil {
+				return err
+			}
+			b = b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	// packagePrefix is the prefix of the package of the generated row messages,
+	// the package of a table is `ticdc.<schema>`.
+	packagePrefix = "ticdc"
+
+	// field numbers in this range are reserved by the protobuf implementation.
+	firstReservedNumber = int64(protowire.FirstReservedNumber)
+	lastReservedNumber  = int64(protowire.LastReservedNumber)
+)
+
+// tableSchema is the row message of one version of a table.
+type tableSchema struct {
+	schema  string
+	table   string
+	version uint64
+
+	columns []*columnMeta
+	// file is the generated file descriptor, it contains only the row message.
+	file *descriptorpb.FileDescriptorProto
+	// descriptor is the serialized FileDescriptorSet of the file.
+	descriptor []byte
+	message    protoreflect.MessageDescriptor
+
+	fieldsByID     map[int64]*fieldSchema
+	fieldsByNumber map[protowire.Number]*fieldSchema
+}
+
+type fieldSchema struct {
+	column *columnMeta
+	field  protoreflect.FieldDescriptor
+}
+
+// newTableSchema generates the row message of the table.
+func newTableSchema(tableInfo *model.TableInfo) (*tableSchema, error) {
+	columns := make([]*columnMeta, 0, len(tableInfo.Columns))
+	for _, col := range tableInfo.Columns {
+		if !model.IsColCDCVisible(col) {
+			continue
+		}
+		number, err := fieldNumber(col.ID)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, &columnMeta{
+			Name:      col.Name.O,
+			ID:        col.ID,
+			Number:    int32(number),
+			Type:      col.GetType(),
+			Flag:      uint64(*tableInfo.ForceGetColumnFlagType(col.ID)),
+			Charset:   col.GetCharset(),
+			Collation: col.GetCollate(),
+		})
+	}
+	file := newFileDescriptor(tableInfo.GetSchemaName(), tableInfo.GetTableName(), columns)
+	descriptor, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	s, err := buildTableSchema(tableInfo.GetSchemaName(), tableInfo.GetTableName(),
+		tableInfo.UpdateTS, columns, descriptor, messageFullName(file))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	return s, nil
+}
+
+// newTableSchemaFromEnvelope restores the row message from the descriptor
+// carried by a DDL or BOOTSTRAP envelope.
+func newTableSchemaFromEnvelope(e *envelope) (*tableSchema, error) {
+	if len(e.Descriptor) == 0 || e.MessageName == "" {
+		return nil, cerror.ErrDecodeFailed.GenWithStack(
+			"no descriptor found for table %s.%s", e.Schema, e.Table)
+	}
+	s, err := buildTableSchema(e.Schema, e.Table, e.TableVersion,
+		e.Columns, e.Descriptor, protoreflect.FullName(e.MessageName))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	return s, nil
+}
+
+func buildTableSchema(
+	schema, table string, version uint64,
+	columns []*columnMeta, descriptor []byte, messageName protoreflect.FullName,
+) (*tableSchema, error) {
+	set := new(descriptorpb.FileDescriptorSet)
+	if err := proto.Unmarshal(descriptor, set); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(set.File) != 1 {
+		return nil, errors.Errorf("expect exactly one file in the descriptor, got %d", len(set.File))
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	desc, err := files.FindDescriptorByName(messageName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	message, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, errors.Errorf("%s is not a message", messageName)
+	}
+
+	s := &tableSchema{
+		schema:         schema,
+		table:          table,
+		version:        version,
+		columns:        columns,
+		file:           set.File[0],
+		descriptor:     descriptor,
+		message:        message,
+		fieldsByID:     make(map[int64]*fieldSchema, len(columns)),
+		fieldsByNumber: make(map[protowire.Number]*fieldSchema, len(columns)),
+	}
+	for _, col := range columns {
+		number := protowire.Number(col.Number)
+		field := message.Fields().ByNumber(number)
+		if field == nil {
+			return nil, errors.Errorf("field %d of column %s not found in %s",
+				col.Number, col.Name, messageName)
+		}
+		f := &fieldSchema{column: col, field: field}
+		s.fieldsByID[col.ID] = f
+		s.fieldsByNumber[number] = f
+	}
+	return s, nil
+}
+
+// messageName returns the fully qualified name of the row message.
+func (s *tableSchema) messageName() string {
+	return string(s.message.FullName())
+}
+
+// text renders the row message as a .proto file, it is the schema registered
+// to the schema registry.
+func (s *tableSchema) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "syntax = %q;\n", s.file.GetSyntax())
+	fmt.Fprintf(&b, "package %s;\n", s.file.GetPackage())
+	for _, msg := range s.file.GetMessageType() {
+		fmt.Fprintf(&b, "\nmessage %s {\n", msg.GetName())
+		for _, f := range msg.GetField() {
+			typeName := strings.ToLower(strings.TrimPrefix(f.GetType().String(), "TYPE_"))
+			fmt.Fprintf(&b, "  optional %s %s = %d;\n", typeName, f.GetName(), f.GetNumber())
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// encodeRow encodes the columns into the row message, NULL columns are absent.
+func (s *tableSchema) encodeRow(columns []*model.ColumnData) ([]byte, error) {
+	msg := dynamicpb.NewMessage(s.message)
+	for _, col := range columns {
+		if col == nil || col.Value == nil {
+			continue
+		}
+		f, ok := s.fieldsByID[col.ColumnID]
+		if !ok {
+			return nil, cerror.ErrEncodeFailed.GenWithStack(
+				"column %d not found in the schema of table %s.%s version %d",
+				col.ColumnID, s.schema, s.table, s.version)
+		}
+		v, err := toProtoValue(f.field.Kind(), col.Value)
+		if err != nil {
+			return nil, cerror.ErrEncodeFailed.GenWithStack(
+				"encode column %s of table %s.%s failed: %s",
+				f.column.Name, s.schema, s.table, err.Error())
+		}
+		msg.Set(f.field, v)
+	}
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	if value == nil {
+		// keep the row image present even if all columns are NULL.
+		value = []byte{}
+	}
+	return value, nil
+}
+
+// decodeRow decodes the row message into columns in the order of the schema.
+func (s *tableSchema) decodeRow(value []byte) ([]*model.Column, error) {
+	msg := dynamicpb.NewMessage(s.message)
+	if err := proto.Unmarshal(value, msg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	result := make([]*model.Column, 0, len(s.columns))
+	for _, col := range s.columns {
+		f := s.fieldsByNumber[protowire.Number(col.Number)]
+		var value interface{}
+		if msg.Has(f.field) {
+			value = fromProtoValue(col, msg.Get(f.field))
+		}
+		result = append(result, &model.Column{
+			Name:      col.Name,
+			Type:      col.Type,
+			Charset:   col.Charset,
+			Collation: col.Collation,
+			Flag:      model.ColumnFlagType(col.Flag),
+			Value:     value,
+		})
+	}
+	return result, nil
+}
+
+// fieldNumber returns the field number of the column. Column IDs are never
+// reused in a table, so the field numbers keep stable across DDLs.
+func fieldNumber(columnID int64) (protowire.Number, error) {
+	n := columnID
+	if n >= firstReservedNumber {
+		n += lastReservedNumber - firstReservedNumber + 1
+	}
+	if n < int64(protowire.MinValidNumber) || n > int64(protowire.MaxValidNumber) {
+		return 0, cerror.ErrEncodeFailed.GenWithStack(
+			"column ID %d can't be mapped to a protobuf field number", columnID)
+	}
+	return protowire.Number(n), nil
+}
+
+func newFileDescriptor(schema, table string, columns []*columnMeta) *descriptorpb.FileDescriptorProto {
+	fields := make([]*descriptorpb.FieldDescriptorProto, 0, len(columns))
+	names := make(map[string]struct{}, len(columns))
+	for _, col := range columns {
+		name := sanitizeName(col.Name)
+		if _, ok := names[name]; ok {
+			name = name + "_" + strconv.Itoa(int(col.Number))
+		}
+		names[name] = struct{}{}
+		fields = append(fields, &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(col.Number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   fieldType(col.Type, model.ColumnFlagType(col.Flag)).Enum(),
+		})
+	}
+	pkg, name := sanitizeName(schema), sanitizeName(table)
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(pkg + "/" + name + ".proto"),
+		Package: proto.String(packagePrefix + "." + pkg),
+		Syntax:  proto.String("proto2"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:  proto.String(name),
+			Field: fields,
+		}},
+	}
+}
+
+func messageFullName(file *descriptorpb.FileDescriptorProto) protoreflect.FullName {
+	return protoreflect.FullName(file.GetPackage() + "." + file.GetMessageType()[0].GetName())
+}
+
+// sanitizeName converts the name into a valid protobuf identifier, all
+// characters except letters, digits and underscores are replaced by underscores.
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// fieldType returns the protobuf type of the column, it follows the value
+// types produced by the mounter.
+func fieldType(tp byte, flag model.ColumnFlagType) descriptorpb.FieldDescriptorProto_Type {
+	switch tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		if flag.IsUnsigned() {
+			return descriptorpb.FieldDescriptorProto_TYPE_UINT64
+		}
+		return descriptorpb.FieldDescriptorProto_TYPE_SINT64
+	case mysql.TypeYear:
+		return descriptorpb.FieldDescriptorProto_TYPE_SINT64
+	case mysql.TypeBit, mysql.TypeEnum, mysql.TypeSet:
+		return descriptorpb.FieldDescriptorProto_TYPE_UINT64
+	case mysql.TypeFloat:
+		return descriptorpb.FieldDescriptorProto_TYPE_FLOAT
+	case mysql.TypeDouble:
+		return descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if flag.IsBinary() {
+			return descriptorpb.FieldDescriptorProto_TYPE_BYTES
+		}
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING
+	default:
+		// decimal, temporal and json values are formatted as strings.
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING
+	}
+}
+
+// isBytesValue returns whether the mounter produces []byte for the column type.
+func isBytesValue(tp byte) bool {
+	switch tp {
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		return true
+	default:
+		return false
+	}
+}
+
+func toProtoValue(kind protoreflect.Kind, value interface{}) (protoreflect.Value, error) {
+	switch kind {
+	case protoreflect.Sint64Kind:
+		switch v := value.(type) {
+		case int64:
+			return protoreflect.ValueOfInt64(v), nil
+		case uint64:
+			return protoreflect.ValueOfInt64(int64(v)), nil
+		case int:
+			return protoreflect.ValueOfInt64(int64(v)), nil
+		case int32:
+			return protoreflect.ValueOfInt64(int64(v)), nil
+		}
+	case protoreflect.Uint64Kind:
+		switch v := value.(type) {
+		case uint64:
+			return protoreflect.ValueOfUint64(v), nil
+		case int64:
+			return protoreflect.ValueOfUint64(uint64(v)), nil
+		case int:
+			return protoreflect.ValueOfUint64(uint64(v)), nil
+		case uint32:
+			return protoreflect.ValueOfUint64(uint64(v)), nil
+		}
+	case protoreflect.FloatKind:
+		switch v := value.(type) {
+		case float32:
+			return protoreflect.ValueOfFloat32(v), nil
+		case float64:
+			return protoreflect.ValueOfFloat32(float32(v)), nil
+		}
+	case protoreflect.DoubleKind:
+		switch v := value.(type) {
+		case float64:
+			return protoreflect.ValueOfFloat64(v), nil
+		case float32:
+			return protoreflect.ValueOfFloat64(float64(v)), nil
+		}
+	case protoreflect.StringKind:
+		switch v := value.(type) {
+		case string:
+			return protoreflect.ValueOfString(v), nil
+		case []byte:
+			return protoreflect.ValueOfString(string(v)), nil
+		default:
+			return protoreflect.ValueOfString(fmt.Sprintf("%v", v)), nil
+		}
+	case protoreflect.BytesKind:
+		switch v := value.(type) {
+		case []byte:
+			return protoreflect.ValueOfBytes(v), nil
+		case string:
+			return protoreflect.ValueOfBytes([]byte(v)), nil
+		}
+	}
+	return protoreflect.Value{}, errors.Errorf("unexpected value type %T for %s field", value, kind)
+}
+
+func fromProtoValue(col *columnMeta, v protoreflect.Value) interface{} {
+	switch fieldType(col.Type, model.ColumnFlagType(col.Flag)) {
+	case descriptorpb.FieldDescriptorProto_TYPE_SINT64:
+		return v.Int()
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT64:
+		return v.Uint()
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		return float32(v.Float())
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return v.Float()
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return v.Bytes()
+	default:
+		if isBytesValue(col.Type) {
+			return []byte(v.String())
+		}
+		return v.String()
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+)
+
+const (
+	registryBackoffBaseDelayInMs = 500
+	registryBackoffMaxDelayInMs  = 30 * 1000
+	registryMaxTries             = 10
+
+	registryContentType = "application/vnd.schemaregistry.v1+json"
+)
+
+type registerRequest struct {
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+type registerResponse struct {
+	SchemaID int32 `json:"id"`
+}
+
+type registryKey struct {
+	subject string
+	version uint64
+}
+
+// schemaRegistry registers the row messages to the Confluent schema registry.
+// The subject of a table is the fully qualified name of its row message,
+// which is the same as the RecordNameStrategy of the Confluent serializers.
+type schemaRegistry struct {
+	registryURL string
+	client      *httputil.Client
+
+	mu sync.Mutex
+	// ids caches the registered schema ID of each table version.
+	ids map[registryKey]int32
+}
+
+func newSchemaRegistry(registryURL string) (*schemaRegistry, error) {
+	client, err := httputil.NewClient(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &schemaRegistry{
+		registryURL: strings.TrimRight(registryURL, "/"),
+		client:      client,
+		ids:         make(map[registryKey]int32),
+	}, nil
+}
+
+// register registers the row message of the table schema and returns the
+// schema ID, a registered table version is not registered again.
+func (r *schemaRegistry) register(ctx context.Context, s *tableSchema) (int32, error) {
+	key := registryKey{subject: s.messageName(), version: s.version}
+	r.mu.Lock()
+	id, ok := r.ids[key]
+	r.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	payload, err := json.Marshal(&registerRequest{SchemaType: "PROTOBUF", Schema: s.text()})
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrProtobufSchemaAPIError, err)
+	}
+	uri := r.registryURL + "/subjects/" + url.PathEscape(key.subject) + "/versions"
+	err = retry.Do(ctx, func() error {
+		id, err = r.post(ctx, uri, payload)
+		return err
+	}, retry.WithBackoffBaseDelay(registryBackoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(registryBackoffMaxDelayInMs),
+		retry.WithMaxTries(registryMaxTries),
+		retry.WithIsRetryableErr(isRetryableRegistryError))
+	if err != nil {
+		if e, ok := errors.Cause(err).(*registryStatusError); ok {
+			return 0, e.err
+		}
+		return 0, err
+	}
+
+	log.Info("Registered protobuf schema",
+		zap.String("subject", key.subject),
+		zap.Uint64("version", key.version),
+		zap.Int32("schemaID", id))
+	r.mu.Lock()
+	r.ids[key] = id
+	r.mu.Unlock()
+	return id, nil
+}
+
+func (r *schemaRegistry) post(ctx context.Context, uri string, payload []byte) (int32, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(payload))
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrProtobufSchemaAPIError, err)
+	}
+	req.Header.Add("Accept", registryContentType+", application/json")
+	req.Header.Add("Content-Type", registryContentType)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		log.Warn("Register protobuf schema failed, retry later",
+			zap.String("uri", uri), zap.Error(err))
+		return 0, cerror.WrapError(cerror.ErrProtobufSchemaAPIError, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrProtobufSchemaAPIError, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// 409 for incompatible schema and 422 for invalid schema,
+		// they can't be fixed by retrying.
+		log.Warn("Register protobuf schema failed, HTTP error",
+			zap.String("uri", uri),
+			zap.Int("status", resp.StatusCode),
+			zap.ByteString("responseBody", body))
+		return 0, &registryStatusError{
+			code: resp.StatusCode,
+			err: cerror.ErrProtobufSchemaAPIError.GenWithStackByArgs(
+				"unexpected status " + resp.Status + ": " + string(body)),
+		}
+	}
+
+	var result registerResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, cerror.WrapError(cerror.ErrProtobufSchemaAPIError, err)
+	}
+	if result.SchemaID == 0 {
+		return 0, cerror.ErrProtobufSchemaAPIError.GenWithStackByArgs(
+			"illegal schema ID returned from registry")
+	}
+	return result.SchemaID, nil
+}
+
+// registryStatusError is returned when the registry responds with a non-200 status.
+type registryStatusError struct {
+	code int
+	err  error
+}
+
+func (e *registryStatusError) Error() string {
+	return e.err.Error()
+}
+
+func isRetryableRegistryError(err error) bool {
+	if e, ok := errors.Cause(err).(*registryStatusError); ok {
+		return e.code >= http.StatusInternalServerError
+	}
+	return cerror.IsRetryableError(err)
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaRegistryRegister(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	helper.DDL2Event("create table test.t(id int primary key, name varchar(10))")
+	insert := helper.DML2Event("insert into test.t values (1, 'a')", "test", "t")
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/subjects/ticdc.test.t/versions", r.URL.Path)
+
+		var req registerRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "PROTOBUF", req.SchemaType)
+		require.Contains(t, req.Schema, "message t {")
+		_, _ = w.Write([]byte(`{"id": 7}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolProtobuf)
+	codecConfig.AvroConfluentSchemaRegistry = server.URL
+	b, err := NewBatchEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	enc := b.Build()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, enc.AppendRowChangedEvent(ctx, "", insert, nil))
+	}
+	messages := enc.Build()
+	require.Len(t, messages, 2)
+	// the same table version is registered only once.
+	require.Equal(t, int32(1), requests.Load())
+
+	env := new(envelope)
+	require.NoError(t, env.unmarshal(messages[0].Value))
+	require.Equal(t, int32(7), env.SchemaID)
+}
+
+func TestSchemaRegistryRetry(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event("create table test.t(id int primary key)")
+	s, err := newTableSchema(ddl.TableInfo)
+	require.NoError(t, err)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 3}`))
+	}))
+	defer server.Close()
+
+	registry, err := newSchemaRegistry(server.URL)
+	require.NoError(t, err)
+	id, err := registry.register(context.Background(), s)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), id)
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestSchemaRegistryIncompatible(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event("create table test.t(id int primary key)")
+	s, err := newTableSchema(ddl.TableInfo)
+	require.NoError(t, err)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	registry, err := newSchemaRegistry(server.URL)
+	require.NoError(t, err)
+	_, err = registry.register(context.Background(), s)
+	require.ErrorIs(t, err, errors.ErrProtobufSchemaAPIError)
+	// the incompatible schema is not retried.
+	require.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestFieldNumber(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		columnID int64
+		expected protowire.Number
+	}{
+		{columnID: 1, expected: 1},
+		{columnID: 18999, expected: 18999},
+		{columnID: 19000, expected: 20000},
+		{columnID: 19999, expected: 20999},
+	}
+	for _, tc := range testCases {
+		number, err := fieldNumber(tc.columnID)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, number)
+	}
+
+	_, err := fieldNumber(0)
+	require.Error(t, err)
+	_, err = fieldNumber(int64(protowire.MaxValidNumber))
+	require.Error(t, err)
+}
+
+func TestSanitizeName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "abc_1", sanitizeName("abc_1"))
+	require.Equal(t, "a_b_c", sanitizeName("a-b c"))
+	require.Equal(t, "_1abc", sanitizeName("1abc"))
+	require.Equal(t, "___", sanitizeName("列名字"))
+	require.Equal(t, "_", sanitizeName(""))
+}
+
+func TestNewTableSchema(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	ddl := helper.DDL2Event("create table test.`my-table`(" +
+		"id bigint unsigned primary key, `a-b` varchar(10), `a b` blob, c double)")
+	s, err := newTableSchema(ddl.TableInfo)
+	require.NoError(t, err)
+	require.Equal(t, "ticdc.test.my_table", s.messageName())
+	require.Equal(t, ddl.TableInfo.UpdateTS, s.version)
+	require.Len(t, s.columns, 4)
+
+	fields := s.message.Fields()
+	require.Equal(t, 4, fields.Len())
+	require.Equal(t, "id", string(fields.Get(0).Name()))
+	require.Equal(t, "a_b", string(fields.Get(1).Name()))
+	require.Equal(t, "a_b_3", string(fields.Get(2).Name()))
+	require.Equal(t, "c", string(fields.Get(3).Name()))
+
+	expected := `syntax = "proto2";
+package ticdc.test;
+
+message my_table {
+  optional uint64 id = 1;
+  optional string a_b = 2;
+  optional bytes a_b_3 = 3;
+  optional double c = 4;
+}
+`
+	require.Equal(t, expected, s.text())
+
+	// the schema restored from the envelope is the same as the original one.
+	env := schemaEnvelope(EventTypeBootstrap, s, ddl.TableInfo.ID, 0)
+	decoded := new(envelope)
+	require.NoError(t, decoded.unmarshal(env.marshal(nil)))
+	restored, err := newTableSchemaFromEnvelope(decoded)
+	require.NoError(t, err)
+	require.Equal(t, s.messageName(), restored.messageName())
+	require.Equal(t, s.columns, restored.columns)
+	require.Equal(t, s.text(), restored.text())
+}
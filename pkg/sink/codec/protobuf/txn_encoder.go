@@ -0,0 +1,112 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TxnEventEncoder encodes the transactions into varint length-delimited
+// envelopes for the storage sink. The rows of a transaction are preceded by a
+// BOOTSTRAP envelope of the table, so that every file is self-describing.
+type TxnEventEncoder struct {
+	valueBuf  *bytes.Buffer
+	callback  func()
+	batchSize int
+	config    *common.Config
+	schemas   *schemaCache
+}
+
+// AppendTxnEvent implements the TxnEventEncoder interface
+func (b *TxnEventEncoder) AppendTxnEvent(
+	txn *model.SingleTableTxn,
+	callback func(),
+) error {
+	b.callback = callback
+	if len(txn.Rows) == 0 {
+		return nil
+	}
+	// the schema registry is not used by the storage sink.
+	s, _, err := b.schemas.get(context.Background(), txn.TableInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.write(schemaEnvelope(EventTypeBootstrap, s, txn.PhysicalTableID, 0))
+	for _, row := range txn.Rows {
+		env, err := rowEnvelope(row, s, 0)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		b.write(env)
+		b.batchSize++
+	}
+	return nil
+}
+
+func (b *TxnEventEncoder) write(env *envelope) {
+	value := env.marshal(nil)
+	b.valueBuf.Write(protowire.AppendVarint(nil, uint64(len(value))))
+	b.valueBuf.Write(value)
+}
+
+// Build implements the TxnEventEncoder interface
+func (b *TxnEventEncoder) Build() (messages []*common.Message) {
+	if b.batchSize == 0 {
+		return nil
+	}
+
+	ret := common.NewMsg(config.ProtocolProtobuf, nil,
+		b.valueBuf.Bytes(), 0, model.MessageTypeRow, nil, nil)
+	ret.SetRowsCount(b.batchSize)
+	ret.Callback = b.callback
+	if b.valueBuf.Cap() > codec.MemBufShrinkThreshold {
+		b.valueBuf = &bytes.Buffer{}
+	} else {
+		b.valueBuf.Reset()
+	}
+	b.callback = nil
+	b.batchSize = 0
+
+	return []*common.Message{ret}
+}
+
+type txnEventEncoderBuilder struct {
+	config  *common.Config
+	schemas *schemaCache
+}
+
+// NewTxnEventEncoderBuilder creates a protobuf txnEventEncoderBuilder.
+func NewTxnEventEncoderBuilder(config *common.Config) codec.TxnEventEncoderBuilder {
+	return &txnEventEncoderBuilder{
+		config:  config,
+		schemas: newSchemaCache(nil),
+	}
+}
+
+// Build a protobuf TxnEventEncoder
+func (b *txnEventEncoderBuilder) Build() codec.TxnEventEncoder {
+	return &TxnEventEncoder{
+		valueBuf: &bytes.Buffer{},
+		config:   b.config,
+		schemas:  b.schemas,
+	}
+}
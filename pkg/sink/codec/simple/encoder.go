@@ -45,7 +45,7 @@ func (e *encoder) AppendRowChangedEvent(
 	}
 
 	value, err = common.Compress(e.config.ChangefeedID,
-		e.config.LargeMessageHandle.LargeMessageHandleCompression, value)
+		e.config.LargeMessageHandle, value)
 	if err != nil {
 		return err
 	}
@@ -89,7 +89,7 @@ func (e *encoder) AppendRowChangedEvent(
 		return err
 	}
 	value, err = common.Compress(e.config.ChangefeedID,
-		e.config.LargeMessageHandle.LargeMessageHandleCompression, value)
+		e.config.LargeMessageHandle, value)
 	if err != nil {
 		return err
 	}
@@ -130,7 +130,7 @@ func (e *encoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
 	}
 
 	value, err = common.Compress(e.config.ChangefeedID,
-		e.config.LargeMessageHandle.LargeMessageHandleCompression, value)
+		e.config.LargeMessageHandle, value)
 	return common.NewResolvedMsg(config.ProtocolSimple, nil, value, ts), err
 }
 
@@ -142,7 +142,7 @@ func (e *encoder) EncodeDDLEvent(event *model.DDLEvent) (*common.Message, error)
 	}
 
 	value, err = common.Compress(e.config.ChangefeedID,
-		e.config.LargeMessageHandle.LargeMessageHandleCompression, value)
+		e.config.LargeMessageHandle, value)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +54,8 @@ func TestEncodeCheckpoint(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
 			b, err := NewBuilder(ctx, codecConfig)
@@ -103,6 +105,8 @@ func TestEncodeDMLEnableChecksum(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
 
@@ -276,6 +280,8 @@ func TestEncodeDDLSequence(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
 
@@ -786,6 +792,8 @@ func TestEncodeDDLEvent(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
 			b, err := NewBuilder(ctx, codecConfig)
@@ -1235,6 +1243,8 @@ func TestEncodeBootstrapEvent(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
 			b, err := NewBuilder(ctx, codecConfig)
@@ -1307,6 +1317,8 @@ func TestEncodeLargeEventsNormal(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
 
@@ -1482,6 +1494,8 @@ func TestLargerMessageHandleClaimCheck(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.MaxMessageBytes = config.DefaultMaxMessageBytes
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
@@ -1585,6 +1599,8 @@ func TestLargeMessageHandleKeyOnly(t *testing.T) {
 			compression.None,
 			compression.Snappy,
 			compression.LZ4,
+			compression.Zstd,
+			compression.Gzip,
 		} {
 			codecConfig.MaxMessageBytes = config.DefaultMaxMessageBytes
 			codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionType
@@ -15,6 +15,7 @@ package kafka
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -114,6 +115,19 @@ func (a *saramaAdminClient) GetTopicConfig(
 		"cannot find the `%s` from the topic's configuration", configName)
 }
 
+func (a *saramaAdminClient) GetAllTopics(_ context.Context) ([]string, error) {
+	topics, err := a.admin.ListTopics()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]string, 0, len(topics))
+	for name := range topics {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
 func (a *saramaAdminClient) GetTopicsMeta(
 	_ context.Context, topics []string, ignoreTopicError bool,
 ) (map[string]TopicDetail, error) {
@@ -41,6 +41,9 @@ type ClusterAdminClient interface {
 	// GetTopicConfig return the topic level configuration with the `configName`
 	GetTopicConfig(ctx context.Context, topicName string, configName string) (string, error)
 
+	// GetAllTopics return the names of all topics among the cluster
+	GetAllTopics(ctx context.Context) ([]string, error)
+
 	// GetTopicsMeta return all target topics' metadata
 	// if `ignoreTopicError` is true, ignore the topic error and return the metadata of valid topics
 	GetTopicsMeta(ctx context.Context,
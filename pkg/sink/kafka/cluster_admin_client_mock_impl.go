@@ -16,6 +16,7 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/IBM/sarama"
@@ -141,6 +142,19 @@ func (c *ClusterAdminClientMockImpl) SetRemainingFetchesUntilTopicVisible(
 	return nil
 }
 
+// GetAllTopics implement the ClusterAdminClient interface
+func (c *ClusterAdminClientMockImpl) GetAllTopics(context.Context) ([]string, error) {
+	result := make([]string, 0, len(c.topics))
+	for name, details := range c.topics {
+		if details.fetchesRemainingUntilVisible > 0 {
+			continue
+		}
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
 // GetTopicsMeta implement the ClusterAdminClient interface
 func (c *ClusterAdminClientMockImpl) GetTopicsMeta(
 	_ context.Context,
@@ -37,6 +37,11 @@ type Factory interface {
 	AsyncProducer(ctx context.Context, failpointCh chan error) (AsyncProducer, error)
 	// MetricsCollector returns the kafka metrics collector
 	MetricsCollector(role util.Role, adminClient ClusterAdminClient) MetricsCollector
+	// TransactionalProducer creates a producer which sends messages inside
+	// kafka transactions, the transactionalID fences the zombie producers.
+	TransactionalProducer(ctx context.Context, transactionalID string) (TransactionalProducer, error)
+	// TransactionMarkers returns the last committed marker of each key in the topic.
+	TransactionMarkers(ctx context.Context, topic string) (map[string]TransactionMarker, error)
 }
 
 // FactoryCreator defines the type of factory creator.
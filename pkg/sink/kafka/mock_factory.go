@@ -15,6 +15,7 @@ package kafka
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/IBM/sarama"
@@ -30,6 +31,7 @@ import (
 type MockFactory struct {
 	o            *Options
 	changefeedID model.ChangeFeedID
+	txnStore     *MockTransactionStore
 }
 
 // NewMockFactory constructs a Factory with mock implementation.
@@ -39,9 +41,23 @@ func NewMockFactory(
 	return &MockFactory{
 		o:            o,
 		changefeedID: changefeedID,
+		txnStore:     NewMockTransactionStore(),
 	}, nil
 }
 
+// NewMockFactoryCreatorWithTransactionStore returns a FactoryCreator, the
+// created factories share the store, so the committed transactions survive
+// the restart of the sink.
+func NewMockFactoryCreatorWithTransactionStore(store *MockTransactionStore) FactoryCreator {
+	return func(o *Options, changefeedID model.ChangeFeedID) (Factory, error) {
+		return &MockFactory{
+			o:            o,
+			changefeedID: changefeedID,
+			txnStore:     store,
+		}, nil
+	}
+}
+
 // AdminClient return a mocked admin client
 func (f *MockFactory) AdminClient(_ context.Context) (ClusterAdminClient, error) {
 	return NewClusterAdminClientMockImpl(), nil
@@ -85,6 +101,144 @@ func (f *MockFactory) MetricsCollector(
 	return &mockMetricsCollector{}
 }
 
+// TransactionalProducer creates a transactional producer backed by the
+// transaction store of the factory.
+func (f *MockFactory) TransactionalProducer(
+	_ context.Context, transactionalID string,
+) (TransactionalProducer, error) {
+	return f.txnStore.newProducer(transactionalID), nil
+}
+
+// TransactionMarkers returns the committed markers in the transaction store.
+func (f *MockFactory) TransactionMarkers(
+	_ context.Context, topic string,
+) (map[string]TransactionMarker, error) {
+	markers := make(map[string]TransactionMarker)
+	for _, m := range f.txnStore.CommittedMessages(topic) {
+		marker, err := DecodeTransactionMarker(m.Message.Value)
+		if err != nil {
+			return nil, err
+		}
+		markers[string(m.Message.Key)] = marker
+	}
+	return markers, nil
+}
+
+// MockTransactionStore is an in-memory Kafka cluster which only keeps
+// the messages of committed transactions, like a `read_committed` consumer sees.
+type MockTransactionStore struct {
+	mu        sync.Mutex
+	committed []*TransactionalMessage
+	// epochs records the latest epoch of each transactional id,
+	// producers with a stale epoch are fenced.
+	epochs    map[string]int
+	commitErr error
+}
+
+// NewMockTransactionStore creates a new MockTransactionStore.
+func NewMockTransactionStore() *MockTransactionStore {
+	return &MockTransactionStore{epochs: make(map[string]int)}
+}
+
+// CommittedMessages returns the committed messages of the topic.
+func (s *MockTransactionStore) CommittedMessages(topic string) []*TransactionalMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []*TransactionalMessage
+	for _, m := range s.committed {
+		if m.Topic == topic {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+// SetCommitError makes the next commit fail with the given error.
+func (s *MockTransactionStore) SetCommitError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commitErr = err
+}
+
+func (s *MockTransactionStore) newProducer(transactionalID string) *mockTransactionalProducer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epochs[transactionalID]++
+	return &mockTransactionalProducer{
+		store:           s,
+		transactionalID: transactionalID,
+		epoch:           s.epochs[transactionalID],
+	}
+}
+
+type mockTransactionalProducer struct {
+	store           *MockTransactionStore
+	transactionalID string
+	epoch           int
+
+	inTxn   bool
+	pending []*TransactionalMessage
+}
+
+// checkFenced must be called with the lock of the store held.
+func (p *mockTransactionalProducer) checkFenced() error {
+	if p.store.epochs[p.transactionalID] != p.epoch {
+		return cerror.ErrKafkaTransaction.GenWithStack(
+			"producer %s is fenced", p.transactionalID)
+	}
+	return nil
+}
+
+func (p *mockTransactionalProducer) BeginTxn() error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+	if err := p.checkFenced(); err != nil {
+		return err
+	}
+	if p.inTxn {
+		return cerror.ErrKafkaTransaction.GenWithStack("transaction already begun")
+	}
+	p.inTxn = true
+	return nil
+}
+
+func (p *mockTransactionalProducer) SendMessages(
+	_ context.Context, messages []*TransactionalMessage,
+) error {
+	if !p.inTxn {
+		return cerror.ErrKafkaTransaction.GenWithStack("transaction not begun")
+	}
+	p.pending = append(p.pending, messages...)
+	return nil
+}
+
+func (p *mockTransactionalProducer) CommitTxn() error {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+	if err := p.checkFenced(); err != nil {
+		return err
+	}
+	if !p.inTxn {
+		return cerror.ErrKafkaTransaction.GenWithStack("transaction not begun")
+	}
+	if err := p.store.commitErr; err != nil {
+		p.store.commitErr = nil
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	p.store.committed = append(p.store.committed, p.pending...)
+	p.pending = nil
+	p.inTxn = false
+	return nil
+}
+
+func (p *mockTransactionalProducer) AbortTxn() error {
+	p.pending = nil
+	p.inTxn = false
+	return nil
+}
+
+func (p *mockTransactionalProducer) Close() {}
+
 // MockSaramaSyncProducer is a mock implementation of SyncProducer interface.
 type MockSaramaSyncProducer struct {
 	Producer *mocks.SyncProducer
@@ -138,6 +138,8 @@ type urlConfig struct {
 	Cert                         *string `form:"cert"`
 	Key                          *string `form:"key"`
 	InsecureSkipVerify           *bool   `form:"insecure-skip-verify"`
+	EnableTransaction            *bool   `form:"enable-transaction"`
+	TransactionMarkerTopic       *string `form:"transaction-marker-topic"`
 }
 
 // Options stores user specified configurations
@@ -170,6 +172,13 @@ type Options struct {
 	DialTimeout  time.Duration
 	WriteTimeout time.Duration
 	ReadTimeout  time.Duration
+
+	// EnableTransaction makes the producer wrap every table sink flush
+	// in a Kafka transaction.
+	EnableTransaction bool
+	// TransactionMarkerTopic records the last committed flush of each table,
+	// it's only used when EnableTransaction is true.
+	TransactionMarkerTopic string
 }
 
 // NewOptions returns a default Kafka configuration
@@ -308,6 +317,36 @@ func (o *Options) Apply(changefeedID model.ChangeFeedID,
 		o.RequiredAcks = r
 	}
 
+	if urlParameter.EnableTransaction != nil {
+		o.EnableTransaction = *urlParameter.EnableTransaction
+	}
+	if o.EnableTransaction {
+		if urlParameter.TransactionMarkerTopic != nil && *urlParameter.TransactionMarkerTopic != "" {
+			o.TransactionMarkerTopic = *urlParameter.TransactionMarkerTopic
+		} else {
+			o.TransactionMarkerTopic = DefaultTransactionMarkerTopic(changefeedID)
+		}
+		// Idempotent producers, which transactions rely on,
+		// require acknowledgements from all in-sync replicas.
+		if o.RequiredAcks != WaitForAll {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"required-acks must be -1 when enable-transaction is true, but got %d",
+				o.RequiredAcks)
+		}
+		// The kafka-go client used by the sink v2 has no transactional producer.
+		if replicaConfig.Sink != nil && replicaConfig.Sink.EnableKafkaSinkV2 != nil &&
+			*replicaConfig.Sink.EnableKafkaSinkV2 {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"enable-transaction is not supported when enable-kafka-sink-v2 is true")
+		}
+		// Markers and transactional ids are bound to tables, a table split
+		// into spans on different captures would overwrite its own markers.
+		if replicaConfig.Scheduler != nil && replicaConfig.Scheduler.EnableTableAcrossNodes {
+			return cerror.ErrKafkaInvalidConfig.GenWithStack(
+				"enable-transaction is not supported when enable-table-across-nodes is true")
+		}
+	}
+
 	err = o.applySASL(urlParameter, replicaConfig)
 	if err != nil {
 		return err
@@ -355,6 +394,8 @@ func mergeConfig(
 		dest.Cert = fileConifg.Cert
 		dest.Key = fileConifg.Key
 		dest.InsecureSkipVerify = fileConifg.InsecureSkipVerify
+		dest.EnableTransaction = fileConifg.EnableTransaction
+		dest.TransactionMarkerTopic = fileConifg.TransactionMarkerTopic
 	}
 	if err := mergo.Merge(dest, urlParameters, mergo.WithOverride); err != nil {
 		return nil, err
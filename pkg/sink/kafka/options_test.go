@@ -191,6 +191,57 @@ func TestTimeout(t *testing.T) {
 	require.Equal(t, 2*time.Minute, options.WriteTimeout)
 }
 
+func TestApplyTransaction(t *testing.T) {
+	changefeedID := model.DefaultChangeFeedID("test")
+
+	options := NewOptions()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/kafka-test")
+	require.NoError(t, err)
+	err = options.Apply(changefeedID, sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.False(t, options.EnableTransaction)
+	require.Empty(t, options.TransactionMarkerTopic)
+
+	options = NewOptions()
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?enable-transaction=true")
+	require.NoError(t, err)
+	err = options.Apply(changefeedID, sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.True(t, options.EnableTransaction)
+	require.Equal(t, "ticdc-txn-marker-default-test", options.TransactionMarkerTopic)
+
+	options = NewOptions()
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?enable-transaction=true" +
+		"&transaction-marker-topic=markers")
+	require.NoError(t, err)
+	err = options.Apply(changefeedID, sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Equal(t, "markers", options.TransactionMarkerTopic)
+
+	options = NewOptions()
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?enable-transaction=true" +
+		"&required-acks=1")
+	require.NoError(t, err)
+	err = options.Apply(changefeedID, sinkURI, config.GetDefaultReplicaConfig())
+	require.ErrorContains(t, err, "required-acks must be -1")
+
+	options = NewOptions()
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?enable-transaction=true")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.EnableKafkaSinkV2 = aws.Bool(true)
+	err = options.Apply(changefeedID, sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "not supported when enable-kafka-sink-v2 is true")
+
+	options = NewOptions()
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/kafka-test?enable-transaction=true")
+	require.NoError(t, err)
+	replicaConfig = config.GetDefaultReplicaConfig()
+	replicaConfig.Scheduler.EnableTableAcrossNodes = true
+	err = options.Apply(changefeedID, sinkURI, replicaConfig)
+	require.ErrorContains(t, err, "not supported when enable-table-across-nodes is true")
+}
+
 func TestAdjustConfigTopicNotExist(t *testing.T) {
 	// When the topic does not exist, use the broker's configuration to create the topic.
 	adminClient := NewClusterAdminClientMockImpl()
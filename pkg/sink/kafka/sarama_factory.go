@@ -117,6 +117,53 @@ func (f *saramaFactory) AsyncProducer(
 	}, nil
 }
 
+// TransactionalProducer returns a transactional producer,
+// it should be the caller's responsibility to close the producer
+func (f *saramaFactory) TransactionalProducer(
+	ctx context.Context, transactionalID string,
+) (TransactionalProducer, error) {
+	config, err := NewSaramaConfig(ctx, f.option)
+	if err != nil {
+		return nil, err
+	}
+	if err := completeSaramaTransactionConfig(config, transactionalID); err != nil {
+		return nil, err
+	}
+	config.MetricRegistry = f.registry
+
+	client, err := sarama.NewClient(f.option.BrokerEndpoints, config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	p, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, errors.Trace(err)
+	}
+	return &saramaTransactionalProducer{
+		changefeedID:    f.changefeedID,
+		transactionalID: transactionalID,
+		client:          client,
+		producer:        p,
+	}, nil
+}
+
+// TransactionMarkers reads the committed markers from the topic.
+func (f *saramaFactory) TransactionMarkers(
+	ctx context.Context, topic string,
+) (map[string]TransactionMarker, error) {
+	config, err := NewSaramaConfig(ctx, f.option)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sarama.NewClient(f.option.BrokerEndpoints, config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+	return readSaramaTransactionMarkers(ctx, client, topic)
+}
+
 func (f *saramaFactory) MetricsCollector(
 	role util.Role,
 	adminClient ClusterAdminClient,
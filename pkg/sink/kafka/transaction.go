@@ -0,0 +1,349 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"go.uber.org/zap"
+)
+
+// TransactionalMessage is a message sent inside a Kafka transaction.
+type TransactionalMessage struct {
+	Topic     string
+	Partition int32
+	Message   *common.Message
+}
+
+// TransactionalProducer sends messages inside Kafka transactions, so that
+// consumers with `isolation.level=read_committed` only see the messages of
+// committed transactions.
+type TransactionalProducer interface {
+	// BeginTxn starts a new transaction.
+	BeginTxn() error
+	// SendMessages sends the messages in the ongoing transaction, it returns
+	// only when all messages are acknowledged by the brokers.
+	SendMessages(ctx context.Context, messages []*TransactionalMessage) error
+	// CommitTxn commits the ongoing transaction.
+	CommitTxn() error
+	// AbortTxn aborts the ongoing transaction.
+	AbortTxn() error
+	// Close shuts down the producer.
+	Close()
+}
+
+// TransactionMarker records the last flush of a table committed to Kafka.
+// The flush contains all events whose commit ts is less than CommitTs,
+// and the first Txns transactions whose commit ts equals to CommitTs.
+type TransactionMarker struct {
+	CommitTs uint64 `json:"commit-ts"`
+	Txns     uint64 `json:"txns"`
+}
+
+// Covers returns true if the transaction with the given commit ts,
+// which is the seq-th one (start from 1) among the transactions with
+// the same commit ts, is already committed by the marker.
+func (m TransactionMarker) Covers(commitTs uint64, seq uint64) bool {
+	if commitTs != m.CommitTs {
+		return commitTs < m.CommitTs
+	}
+	return seq <= m.Txns
+}
+
+// Encode encodes the marker to the value of a Kafka message.
+func (m TransactionMarker) Encode() []byte {
+	value, _ := json.Marshal(m)
+	return value
+}
+
+// DecodeTransactionMarker decodes the marker from the value of a Kafka message.
+func DecodeTransactionMarker(value []byte) (TransactionMarker, error) {
+	var m TransactionMarker
+	if err := json.Unmarshal(value, &m); err != nil {
+		return m, cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	return m, nil
+}
+
+// TransactionMarkerKey returns the key of the marker message of the table.
+func TransactionMarkerKey(changefeedID model.ChangeFeedID, tableID model.TableID) string {
+	return fmt.Sprintf("%s/%s/%d", changefeedID.Namespace, changefeedID.ID, tableID)
+}
+
+// DefaultTransactionMarkerTopic returns the marker topic used by the changefeed
+// if the user does not specify one.
+func DefaultTransactionMarkerTopic(changefeedID model.ChangeFeedID) string {
+	topic := fmt.Sprintf("ticdc-txn-marker-%s-%s", changefeedID.Namespace, changefeedID.ID)
+	return commonInvalidChar.ReplaceAllString(topic, "_")
+}
+
+// TransactionalID returns the transactional id used by the producer of the
+// table. It doesn't depend on the capture replicating the table, so that the
+// brokers fence the zombie producer left on the previous capture once the
+// table is moved or the capture restarts.
+func TransactionalID(changefeedID model.ChangeFeedID, tableID model.TableID) string {
+	id := fmt.Sprintf("ticdc-%s-%s-%d", changefeedID.Namespace, changefeedID.ID, tableID)
+	return commonInvalidChar.ReplaceAllString(id, "_")
+}
+
+// completeSaramaTransactionConfig enables the transactional producer,
+// which requires the idempotent producer.
+func completeSaramaTransactionConfig(config *sarama.Config, transactionalID string) error {
+	if !config.Version.IsAtLeast(sarama.V0_11_0_0) {
+		return cerror.ErrKafkaTransactionNotSupported.GenWithStackByArgs(
+			"kafka version " + config.Version.String())
+	}
+	config.Producer.Transaction.ID = transactionalID
+	config.Producer.Idempotent = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Net.MaxOpenRequests = 1
+	return nil
+}
+
+type saramaTransactionalProducer struct {
+	changefeedID    model.ChangeFeedID
+	transactionalID string
+	client          sarama.Client
+	producer        sarama.SyncProducer
+}
+
+func (p *saramaTransactionalProducer) BeginTxn() error {
+	if err := p.producer.BeginTxn(); err != nil {
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	return nil
+}
+
+func (p *saramaTransactionalProducer) SendMessages(
+	_ context.Context, messages []*TransactionalMessage,
+) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	msgs := make([]*sarama.ProducerMessage, 0, len(messages))
+	for _, m := range messages {
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic:     m.Topic,
+			Partition: m.Partition,
+			Key:       sarama.ByteEncoder(m.Message.Key),
+			Value:     sarama.ByteEncoder(m.Message.Value),
+		})
+	}
+	if err := p.producer.SendMessages(msgs); err != nil {
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	return nil
+}
+
+func (p *saramaTransactionalProducer) CommitTxn() error {
+	if err := p.producer.CommitTxn(); err != nil {
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	return nil
+}
+
+func (p *saramaTransactionalProducer) AbortTxn() error {
+	if err := p.producer.AbortTxn(); err != nil {
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	return nil
+}
+
+func (p *saramaTransactionalProducer) Close() {
+	// The ongoing transaction, if any, is aborted by the brokers once the
+	// transaction times out, or fenced by the next producer with the same id.
+	start := time.Now()
+	if err := p.producer.Close(); err != nil {
+		log.Warn("Close kafka transactional producer with error",
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.String("transactionalID", p.transactionalID),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err))
+	}
+	if err := p.client.Close(); err != nil {
+		log.Warn("Close kafka transactional producer client with error",
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.String("transactionalID", p.transactionalID),
+			zap.Error(err))
+	}
+}
+
+const (
+	// The max bytes of a fetch of the marker topic, it's doubled if a
+	// record batch is larger than it.
+	defaultMarkerFetchBytes = 1024 * 1024
+	maxMarkerFetchBytes     = 64 * 1024 * 1024
+	// markerFetchMaxWait is the max time the brokers wait for the open
+	// transactions to be decided before responding a fetch.
+	markerFetchMaxWait = 500 * time.Millisecond
+)
+
+// readSaramaTransactionMarkers reads the marker topic with `read_committed`
+// isolation level and returns the last committed marker of each key.
+func readSaramaTransactionMarkers(
+	ctx context.Context, client sarama.Client, topic string,
+) (map[string]TransactionMarker, error) {
+	markers := make(map[string]TransactionMarker)
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		if errors.Cause(err) == sarama.ErrUnknownTopicOrPartition {
+			return markers, nil
+		}
+		return nil, cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	for _, partition := range partitions {
+		if err := fetchTransactionMarkers(ctx, client, topic, partition, markers); err != nil {
+			return nil, err
+		}
+	}
+	return markers, nil
+}
+
+// fetchTransactionMarkers reads the partition from the oldest offset up to
+// the high-water mark at the time it's called. The brokers only return the
+// records before the last stable offset to a `read_committed` fetch, so it
+// waits until all transactions started before are decided, then no marker
+// committed before is missed. Control records and aborted records are
+// skipped by their offsets, since they are never visible.
+func fetchTransactionMarkers(
+	ctx context.Context,
+	client sarama.Client,
+	topic string, partition int32,
+	markers map[string]TransactionMarker,
+) error {
+	offset, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+	highWaterMark, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+	}
+
+	fetchBytes := int32(defaultMarkerFetchBytes)
+	for offset < highWaterMark {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		broker, err := client.Leader(topic, partition)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+		}
+		request := &sarama.FetchRequest{
+			// Version 4 adds the isolation level and the aborted transactions.
+			Version:     4,
+			MaxWaitTime: int32(markerFetchMaxWait.Milliseconds()),
+			MinBytes:    1,
+			MaxBytes:    fetchBytes,
+			Isolation:   sarama.ReadCommitted,
+		}
+		request.AddBlock(topic, partition, offset, fetchBytes, -1)
+		response, err := broker.Fetch(request)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaTransaction, err)
+		}
+		block := response.GetBlock(topic, partition)
+		if block == nil {
+			return cerror.WrapError(cerror.ErrKafkaTransaction, sarama.ErrIncompleteResponse)
+		}
+		if block.Err != sarama.ErrNoError {
+			return cerror.WrapError(cerror.ErrKafkaTransaction, block.Err)
+		}
+
+		next, err := applyTransactionMarkerBatches(block, offset, markers)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch {
+		case next > offset:
+			offset = next
+		case block.LastStableOffset > offset:
+			// The record batch at the offset is larger than the fetch size.
+			if fetchBytes >= maxMarkerFetchBytes {
+				return cerror.ErrKafkaTransaction.GenWithStack(
+					"the record batch at offset %d of %s[%d] is too large", offset, topic, partition)
+			}
+			fetchBytes *= 2
+		default:
+			// The last stable offset is held by an open transaction,
+			// wait for it to be committed or aborted.
+			log.Debug("wait for open transactions of the marker topic",
+				zap.String("topic", topic), zap.Int32("partition", partition),
+				zap.Int64("offset", offset), zap.Int64("lastStableOffset", block.LastStableOffset),
+				zap.Int64("highWaterMark", highWaterMark))
+		}
+	}
+	return nil
+}
+
+// applyTransactionMarkerBatches decodes the committed markers in the fetched
+// block, and returns the offset next to the last record batch.
+func applyTransactionMarkerBatches(
+	block *sarama.FetchResponseBlock, offset int64, markers map[string]TransactionMarker,
+) (int64, error) {
+	aborted := block.AbortedTransactions
+	sort.Slice(aborted, func(i, j int) bool { return aborted[i].FirstOffset < aborted[j].FirstOffset })
+	abortedProducers := make(map[int64]struct{})
+
+	next := offset
+	for _, records := range block.RecordsSet {
+		batch := records.RecordBatch
+		if batch == nil || batch.PartialTrailingRecord {
+			// Markers are always written in record batches.
+			break
+		}
+		for len(aborted) > 0 && aborted[0].FirstOffset <= batch.LastOffset() {
+			abortedProducers[aborted[0].ProducerID] = struct{}{}
+			aborted = aborted[1:]
+		}
+		next = batch.LastOffset() + 1
+
+		if batch.Control {
+			// The key of a control record is its version and type,
+			// type 0 means the transaction is aborted.
+			if len(batch.Records) > 0 && len(batch.Records[0].Key) >= 4 &&
+				binary.BigEndian.Uint16(batch.Records[0].Key[2:]) == 0 {
+				delete(abortedProducers, batch.ProducerID)
+			}
+			continue
+		}
+		if _, ok := abortedProducers[batch.ProducerID]; ok && batch.IsTransactional {
+			continue
+		}
+		for _, record := range batch.Records {
+			if batch.FirstOffset+record.OffsetDelta < offset {
+				continue
+			}
+			marker, err := DecodeTransactionMarker(record.Value)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			markers[string(record.Key)] = marker
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionMarker(t *testing.T) {
+	t.Parallel()
+
+	marker := TransactionMarker{CommitTs: 100, Txns: 2}
+	decoded, err := DecodeTransactionMarker(marker.Encode())
+	require.NoError(t, err)
+	require.Equal(t, marker, decoded)
+
+	require.True(t, marker.Covers(99, 5))
+	require.True(t, marker.Covers(100, 1))
+	require.True(t, marker.Covers(100, 2))
+	require.False(t, marker.Covers(100, 3))
+	require.False(t, marker.Covers(101, 1))
+
+	_, err = DecodeTransactionMarker([]byte("invalid"))
+	require.Error(t, err)
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	require.Equal(t, "default/test/1", TransactionMarkerKey(changefeedID, 1))
+	require.Equal(t, "ticdc-default-test-1", TransactionalID(changefeedID, 1))
+}
+
+func TestApplyTransactionMarkerBatches(t *testing.T) {
+	t.Parallel()
+
+	markerBatch := func(offset int64, producerID int64, key string, marker TransactionMarker) *sarama.Records {
+		return &sarama.Records{RecordBatch: &sarama.RecordBatch{
+			FirstOffset:     offset,
+			ProducerID:      producerID,
+			IsTransactional: true,
+			Records:         []*sarama.Record{{Key: []byte(key), Value: marker.Encode()}},
+		}}
+	}
+	controlBatch := func(offset int64, producerID int64, commit bool) *sarama.Records {
+		key := []byte{0, 0, 0, 0}
+		if commit {
+			key[3] = 1
+		}
+		return &sarama.Records{RecordBatch: &sarama.RecordBatch{
+			FirstOffset:     offset,
+			ProducerID:      producerID,
+			IsTransactional: true,
+			Control:         true,
+			Records:         []*sarama.Record{{Key: key}},
+		}}
+	}
+
+	block := &sarama.FetchResponseBlock{
+		AbortedTransactions: []*sarama.AbortedTransaction{{ProducerID: 2, FirstOffset: 2}},
+		RecordsSet: []*sarama.Records{
+			markerBatch(0, 1, "a", TransactionMarker{CommitTs: 1, Txns: 1}),
+			controlBatch(1, 1, true),
+			markerBatch(2, 2, "b", TransactionMarker{CommitTs: 2, Txns: 1}),
+			controlBatch(3, 2, false),
+			markerBatch(4, 1, "a", TransactionMarker{CommitTs: 3, Txns: 2}),
+			controlBatch(5, 1, true),
+		},
+	}
+	markers := make(map[string]TransactionMarker)
+	next, err := applyTransactionMarkerBatches(block, 0, markers)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), next)
+	// The markers of aborted transactions are skipped.
+	require.Equal(t, map[string]TransactionMarker{"a": {CommitTs: 3, Txns: 2}}, markers)
+}
+
+func TestMockTransactionalProducerFenced(t *testing.T) {
+	t.Parallel()
+
+	store := NewMockTransactionStore()
+	old := store.newProducer("id")
+	require.NoError(t, old.BeginTxn())
+
+	// A new producer with the same transactional id fences the old one.
+	p := store.newProducer("id")
+	require.Error(t, old.CommitTxn())
+	require.NoError(t, p.BeginTxn())
+	require.NoError(t, p.CommitTxn())
+}
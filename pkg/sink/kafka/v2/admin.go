@@ -15,6 +15,7 @@ package v2
 
 import (
 	"context"
+	"sort"
 	"strconv"
 
 	"github.com/pingcap/log"
@@ -153,6 +154,23 @@ func (a *admin) GetTopicConfig(ctx context.Context, topicName string, configName
 		"cannot find the `%s` from the topic's configuration", configName)
 }
 
+func (a *admin) GetAllTopics(ctx context.Context) ([]string, error) {
+	response, err := a.clusterMetadata(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	result := make([]string, 0, len(response.Topics))
+	for _, topic := range response.Topics {
+		if topic.Internal {
+			continue
+		}
+		result = append(result, topic.Name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
 func (a *admin) GetTopicsMeta(
 	ctx context.Context,
 	topics []string,
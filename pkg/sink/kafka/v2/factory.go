@@ -268,6 +268,23 @@ func (f *factory) MetricsCollector(
 	return NewMetricsCollector(f.changefeedID, role, f.writer)
 }
 
+// TransactionalProducer is not supported, since kafka-go does not implement
+// the transactional producer. The config is rejected by Options.Apply, so it
+// is never called by the sink.
+func (f *factory) TransactionalProducer(
+	_ context.Context, _ string,
+) (pkafka.TransactionalProducer, error) {
+	return nil, errors.ErrKafkaTransactionNotSupported.GenWithStackByArgs("kafka-go client")
+}
+
+// TransactionMarkers is not supported, since kafka-go
+// does not implement the transactional producer.
+func (f *factory) TransactionMarkers(
+	_ context.Context, _ string,
+) (map[string]pkafka.TransactionMarker, error) {
+	return nil, errors.ErrKafkaTransactionNotSupported.GenWithStackByArgs("kafka-go client")
+}
+
 type syncWriter struct {
 	changefeedID model.ChangeFeedID
 	w            Writer
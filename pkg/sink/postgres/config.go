@@ -0,0 +1,167 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultWorkerCount is the default number of workers.
+	DefaultWorkerCount = 16
+	// DefaultMaxTxnRow is the default max number of rows in a transaction.
+	DefaultMaxTxnRow = 256
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max txn rows.
+	maxMaxTxnRow = 2048
+
+	defaultWriteTimeout = "2m"
+	defaultSafeMode     = false
+
+	defaultSkipUnsupportedDDL = false
+
+	// BackoffBaseDelay indicates the base delay time for retrying.
+	BackoffBaseDelay = 500 * time.Millisecond
+	// BackoffMaxDelay indicates the max delay time for retrying.
+	BackoffMaxDelay = 60 * time.Second
+)
+
+// cdcParams are the sink URI parameters consumed by TiCDC, all other
+// parameters are passed to the PostgreSQL driver as is.
+var cdcParams = []string{
+	"worker-count", "max-txn-row", "safe-mode", "write-timeout", "skip-unsupported-ddl",
+}
+
+type urlConfig struct {
+	WorkerCount  *int    `form:"worker-count"`
+	MaxTxnRow    *int    `form:"max-txn-row"`
+	SafeMode     *bool   `form:"safe-mode"`
+	WriteTimeout *string `form:"write-timeout"`
+
+	SkipUnsupportedDDL *bool `form:"skip-unsupported-ddl"`
+}
+
+// Config is the configs for PostgreSQL backend.
+type Config struct {
+	WorkerCount    int
+	MaxTxnRow      int
+	WriteTimeout   string
+	SafeMode       bool
+	ForceReplicate bool
+	// SkipUnsupportedDDL makes the DDL sink skip the DDLs that can not be
+	// translated to PostgreSQL instead of failing the changefeed.
+	SkipUnsupportedDDL bool
+}
+
+// NewConfig returns the default PostgreSQL backend config.
+func NewConfig() *Config {
+	return &Config{
+		WorkerCount:  DefaultWorkerCount,
+		MaxTxnRow:    DefaultMaxTxnRow,
+		WriteTimeout: defaultWriteTimeout,
+		SafeMode:     defaultSafeMode,
+
+		SkipUnsupportedDDL: defaultSkipUnsupportedDDL,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) error {
+	if sinkURI == nil {
+		return cerror.ErrPostgresInvalidConfig.GenWithStack("fail to open PostgreSQL sink, empty SinkURI")
+	}
+
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !sink.IsPostgresScheme(scheme) {
+		return cerror.ErrPostgresInvalidConfig.GenWithStack(
+			"can't create PostgreSQL sink with unsupported scheme: %s", scheme)
+	}
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrPostgresInvalidConfig, err)
+	}
+	if urlParameter.SafeMode == nil && replicaConfig.Sink != nil {
+		urlParameter.SafeMode = replicaConfig.Sink.SafeMode
+	}
+
+	if urlParameter.WorkerCount != nil {
+		v := *urlParameter.WorkerCount
+		if v <= 0 {
+			return cerror.WrapError(cerror.ErrPostgresInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", v))
+		}
+		if v > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", v), zap.Int("override", maxWorkerCount))
+			v = maxWorkerCount
+		}
+		c.WorkerCount = v
+	}
+	if urlParameter.MaxTxnRow != nil {
+		v := *urlParameter.MaxTxnRow
+		if v <= 0 {
+			return cerror.WrapError(cerror.ErrPostgresInvalidConfig,
+				fmt.Errorf("invalid max-txn-row %d, which must be greater than 0", v))
+		}
+		if v > maxMaxTxnRow {
+			log.Warn("max-txn-row too large",
+				zap.Int("original", v), zap.Int("override", maxMaxTxnRow))
+			v = maxMaxTxnRow
+		}
+		c.MaxTxnRow = v
+	}
+	if urlParameter.SafeMode != nil {
+		c.SafeMode = *urlParameter.SafeMode
+	}
+	if urlParameter.WriteTimeout != nil {
+		if _, err := time.ParseDuration(*urlParameter.WriteTimeout); err != nil {
+			return cerror.WrapError(cerror.ErrPostgresInvalidConfig, err)
+		}
+		c.WriteTimeout = *urlParameter.WriteTimeout
+	}
+	if urlParameter.SkipUnsupportedDDL != nil {
+		c.SkipUnsupportedDDL = *urlParameter.SkipUnsupportedDDL
+	}
+	c.ForceReplicate = replicaConfig.ForceReplicate
+	return nil
+}
+
+// GenerateDSN generates the connection string accepted by the PostgreSQL
+// driver, TiCDC specific parameters are removed from the sink URI.
+func GenerateDSN(sinkURI *url.URL) string {
+	dsn := *sinkURI
+	dsn.Scheme = "postgres"
+	query := dsn.Query()
+	for _, param := range cdcParams {
+		query.Del(param)
+	}
+	dsn.RawQuery = query.Encode()
+	return dsn.String()
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	// register the "postgres" driver.
+	_ "github.com/lib/pq"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Factory is the factory for creating db connection.
+type Factory func(ctx context.Context, dsnStr string) (*sql.DB, error)
+
+// CreatePostgresDBConn creates a PostgreSQL database connection with the given dsn.
+func CreatePostgresDBConn(ctx context.Context, dsnStr string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsnStr)
+	if err != nil {
+		return nil, cerror.ErrPostgresConnectionError.Wrap(err).GenWithStack("fail to open PostgreSQL connection")
+	}
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		// close db to recycle resources
+		if closeErr := db.Close(); closeErr != nil {
+			log.Warn("close db failed", zap.Error(err))
+		}
+		return nil, cerror.ErrPostgresConnectionError.Wrap(err).GenWithStack("fail to open PostgreSQL connection")
+	}
+
+	return db, nil
+}
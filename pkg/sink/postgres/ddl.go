@@ -0,0 +1,279 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/quotes"
+)
+
+// TranslateDDL translates a TiDB DDL event into PostgreSQL statements.
+// Only a safe subset of DDLs is supported, the statements are built from
+// the table info carried by the event instead of rewriting the original
+// query. ok is false if the DDL type is not supported and should be skipped.
+func TranslateDDL(ddl *model.DDLEvent) (stmts []string, ok bool) {
+	if ddl.TableInfo == nil {
+		return nil, false
+	}
+	schema := ddl.TableInfo.TableName.Schema
+	table := quotes.QuotePostgresSchema(schema, ddl.TableInfo.TableName.Table)
+
+	switch ddl.Type {
+	case timodel.ActionCreateSchema:
+		return []string{"CREATE SCHEMA IF NOT EXISTS " + quotes.QuotePostgresName(schema)}, true
+	case timodel.ActionDropSchema:
+		return []string{"DROP SCHEMA IF EXISTS " + quotes.QuotePostgresName(schema) + " CASCADE"}, true
+	case timodel.ActionCreateTable:
+		return []string{genCreateTable(ddl.TableInfo)}, true
+	case timodel.ActionDropTable:
+		return []string{"DROP TABLE IF EXISTS " + table}, true
+	case timodel.ActionTruncateTable:
+		return []string{"TRUNCATE TABLE " + table}, true
+	case timodel.ActionRenameTable:
+		if ddl.PreTableInfo == nil {
+			return nil, false
+		}
+		pre := ddl.PreTableInfo.TableName
+		if pre.Schema != schema {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s",
+				quotes.QuotePostgresSchema(pre.Schema, pre.Table), quotes.QuotePostgresName(schema)))
+		}
+		if pre.Table != ddl.TableInfo.TableName.Table {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s RENAME TO %s",
+				quotes.QuotePostgresSchema(schema, pre.Table),
+				quotes.QuotePostgresName(ddl.TableInfo.TableName.Table)))
+		}
+		return stmts, true
+	case timodel.ActionAddColumn, timodel.ActionAddColumns:
+		if ddl.PreTableInfo == nil {
+			return nil, false
+		}
+		for _, col := range diffColumns(ddl.TableInfo, ddl.PreTableInfo) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s",
+				table, genColumnDef(col)))
+		}
+		return stmts, true
+	case timodel.ActionDropColumn, timodel.ActionDropColumns:
+		if ddl.PreTableInfo == nil {
+			return nil, false
+		}
+		for _, col := range diffColumns(ddl.PreTableInfo, ddl.TableInfo) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS %s",
+				table, quotes.QuotePostgresName(col.Name.O)))
+		}
+		return stmts, true
+	case timodel.ActionAddIndex:
+		if ddl.PreTableInfo == nil {
+			return nil, false
+		}
+		for _, idx := range diffIndices(ddl.TableInfo, ddl.PreTableInfo) {
+			stmts = append(stmts, genCreateIndex(ddl.TableInfo, idx))
+		}
+		return stmts, true
+	case timodel.ActionDropIndex:
+		if ddl.PreTableInfo == nil {
+			return nil, false
+		}
+		for _, idx := range diffIndices(ddl.PreTableInfo, ddl.TableInfo) {
+			stmts = append(stmts, "DROP INDEX IF EXISTS "+
+				quotes.QuotePostgresSchema(schema, indexName(ddl.TableInfo, idx)))
+		}
+		return stmts, true
+	}
+	return nil, false
+}
+
+// writableColumns returns the columns that are not generated, generated
+// columns are never replicated to PostgreSQL.
+func writableColumns(tableInfo *model.TableInfo) []*timodel.ColumnInfo {
+	cols := make([]*timodel.ColumnInfo, 0, len(tableInfo.Columns))
+	for _, col := range tableInfo.Columns {
+		if col.Hidden || col.IsGenerated() {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// diffColumns returns the columns that exist in a but not in b.
+func diffColumns(a, b *model.TableInfo) []*timodel.ColumnInfo {
+	var result []*timodel.ColumnInfo
+	for _, col := range writableColumns(a) {
+		if timodel.FindColumnInfo(b.Columns, col.Name.L) == nil {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
+// diffIndices returns the public non-primary indices that exist in a but not in b.
+func diffIndices(a, b *model.TableInfo) []*timodel.IndexInfo {
+	var result []*timodel.IndexInfo
+	for _, idx := range a.Indices {
+		if idx.Primary || idx.State != timodel.StatePublic {
+			continue
+		}
+		if b.FindIndexByName(idx.Name.L) == nil {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
+
+// indexName returns the name of the index in PostgreSQL. Index names are
+// unique per schema in PostgreSQL, so it is prefixed with the table name.
+func indexName(tableInfo *model.TableInfo, idx *timodel.IndexInfo) string {
+	return tableInfo.TableName.Table + "_" + idx.Name.O
+}
+
+func genCreateIndex(tableInfo *model.TableInfo, idx *timodel.IndexInfo) string {
+	var buf strings.Builder
+	buf.WriteString("CREATE ")
+	if idx.Unique {
+		buf.WriteString("UNIQUE ")
+	}
+	buf.WriteString("INDEX IF NOT EXISTS ")
+	buf.WriteString(quotes.QuotePostgresName(indexName(tableInfo, idx)))
+	buf.WriteString(" ON ")
+	buf.WriteString(quotes.QuotePostgresSchema(tableInfo.TableName.Schema, tableInfo.TableName.Table))
+	buf.WriteString(" (")
+	for i, col := range idx.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(quotes.QuotePostgresName(col.Name.O))
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+func genCreateTable(tableInfo *model.TableInfo) string {
+	var buf strings.Builder
+	buf.WriteString("CREATE TABLE IF NOT EXISTS ")
+	buf.WriteString(quotes.QuotePostgresSchema(tableInfo.TableName.Schema, tableInfo.TableName.Table))
+	buf.WriteString(" (")
+	for i, col := range writableColumns(tableInfo) {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(genColumnDef(col))
+	}
+	if pks := tableInfo.GetPrimaryKeyColumnNames(); len(pks) > 0 {
+		buf.WriteString(", PRIMARY KEY (")
+		for i, name := range pks {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(quotes.QuotePostgresName(name))
+		}
+		buf.WriteByte(')')
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+func genColumnDef(col *timodel.ColumnInfo) string {
+	def := quotes.QuotePostgresName(col.Name.O) + " " + ColumnType(&col.FieldType)
+	if mysql.HasNotNullFlag(col.GetFlag()) {
+		def += " NOT NULL"
+	}
+	return def
+}
+
+// ColumnType maps a TiDB column type to the closest PostgreSQL type.
+func ColumnType(ft *types.FieldType) string {
+	unsigned := mysql.HasUnsignedFlag(ft.GetFlag())
+	binary := ft.GetCharset() == charset.CharsetBin
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeYear:
+		return "SMALLINT"
+	case mysql.TypeShort:
+		if unsigned {
+			return "INTEGER"
+		}
+		return "SMALLINT"
+	case mysql.TypeInt24, mysql.TypeLong:
+		if unsigned {
+			return "BIGINT"
+		}
+		return "INTEGER"
+	case mysql.TypeLonglong:
+		if unsigned {
+			return "NUMERIC(20,0)"
+		}
+		return "BIGINT"
+	case mysql.TypeBit:
+		// BIT(64) values do not fit into BIGINT.
+		return "NUMERIC(20,0)"
+	case mysql.TypeEnum, mysql.TypeSet:
+		// enum and set values are replicated as their names, PostgreSQL
+		// has no equivalent of the MySQL index representation.
+		return "TEXT"
+	case mysql.TypeFloat:
+		return "REAL"
+	case mysql.TypeDouble:
+		return "DOUBLE PRECISION"
+	case mysql.TypeNewDecimal:
+		if ft.GetFlen() > 0 {
+			decimal := ft.GetDecimal()
+			if decimal < 0 {
+				decimal = 0
+			}
+			return fmt.Sprintf("NUMERIC(%d,%d)", ft.GetFlen(), decimal)
+		}
+		return "NUMERIC"
+	case mysql.TypeDate:
+		return "DATE"
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		if fsp := ft.GetDecimal(); fsp > 0 {
+			return fmt.Sprintf("TIMESTAMP(%d)", fsp)
+		}
+		return "TIMESTAMP"
+	case mysql.TypeDuration:
+		// MySQL TIME can be negative or greater than 24 hours.
+		return "INTERVAL"
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		if binary {
+			return "BYTEA"
+		}
+		if ft.GetFlen() > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", ft.GetFlen())
+		}
+		return "TEXT"
+	case mysql.TypeString:
+		if binary {
+			return "BYTEA"
+		}
+		if ft.GetFlen() > 0 {
+			return fmt.Sprintf("CHAR(%d)", ft.GetFlen())
+		}
+		return "TEXT"
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if binary {
+			return "BYTEA"
+		}
+		return "TEXT"
+	case mysql.TypeJSON:
+		return "JSONB"
+	}
+	return "TEXT"
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/ddl"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	_ "github.com/pingcap/tidb/pkg/planner/core" // init expression.EvalSimpleAst related function
+	timock "github.com/pingcap/tidb/pkg/util/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func mockTableInfo(t *testing.T, schema string, sql string) *model.TableInfo {
+	p := parser.New()
+	se := timock.NewContext()
+	node, err := p.ParseOneStmt(sql, "", "")
+	require.NoError(t, err)
+	dbChs, dbColl := charset.GetDefaultCharsetAndCollate()
+	ti, err := ddl.BuildTableInfoWithStmt(se, node.(*ast.CreateTableStmt), dbChs, dbColl, nil)
+	require.NoError(t, err)
+	return model.WrapTableInfo(1, schema, 1, ti)
+}
+
+func TestTranslateCreateTable(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := mockTableInfo(t, "test", "CREATE TABLE t1 (id BIGINT UNSIGNED PRIMARY KEY, "+
+		"name VARCHAR(32) NOT NULL, data BLOB, price DECIMAL(10,2), created DATETIME(3), "+
+		"doc JSON, e ENUM('a','b'), s SET('x','y'), flags BIT(64), gen INT AS (id + 1) VIRTUAL)")
+	stmts, ok := TranslateDDL(&model.DDLEvent{
+		Type:      timodel.ActionCreateTable,
+		TableInfo: tableInfo,
+	})
+	require.True(t, ok)
+	require.Equal(t, []string{`CREATE TABLE IF NOT EXISTS "test"."t1" (` +
+		`"id" NUMERIC(20,0) NOT NULL, "name" VARCHAR(32) NOT NULL, "data" BYTEA, ` +
+		`"price" NUMERIC(10,2), "created" TIMESTAMP(3), "doc" JSONB, "e" TEXT, "s" TEXT, ` +
+		`"flags" NUMERIC(20,0), PRIMARY KEY ("id"))`}, stmts)
+}
+
+func TestTranslateAlterTable(t *testing.T) {
+	t.Parallel()
+
+	pre := mockTableInfo(t, "test", "CREATE TABLE t1 (id INT PRIMARY KEY, a INT)")
+	post := mockTableInfo(t, "test", "CREATE TABLE t1 (id INT PRIMARY KEY, a INT, b TEXT, KEY idx_b (b(10)))")
+
+	stmts, ok := TranslateDDL(&model.DDLEvent{
+		Type: timodel.ActionAddColumn, TableInfo: post, PreTableInfo: pre,
+	})
+	require.True(t, ok)
+	require.Equal(t, []string{`ALTER TABLE "test"."t1" ADD COLUMN IF NOT EXISTS "b" TEXT`}, stmts)
+
+	stmts, ok = TranslateDDL(&model.DDLEvent{
+		Type: timodel.ActionDropColumn, TableInfo: pre, PreTableInfo: post,
+	})
+	require.True(t, ok)
+	require.Equal(t, []string{`ALTER TABLE "test"."t1" DROP COLUMN IF EXISTS "b"`}, stmts)
+
+	stmts, ok = TranslateDDL(&model.DDLEvent{
+		Type: timodel.ActionAddIndex, TableInfo: post, PreTableInfo: pre,
+	})
+	require.True(t, ok)
+	require.Equal(t, []string{`CREATE INDEX IF NOT EXISTS "t1_idx_b" ON "test"."t1" ("b")`}, stmts)
+
+	stmts, ok = TranslateDDL(&model.DDLEvent{
+		Type: timodel.ActionDropIndex, TableInfo: pre, PreTableInfo: post,
+	})
+	require.True(t, ok)
+	require.Equal(t, []string{`DROP INDEX IF EXISTS "test"."t1_idx_b"`}, stmts)
+
+	renamed := mockTableInfo(t, "test2", "CREATE TABLE t2 (id INT PRIMARY KEY, a INT)")
+	stmts, ok = TranslateDDL(&model.DDLEvent{
+		Type: timodel.ActionRenameTable, TableInfo: renamed, PreTableInfo: pre,
+	})
+	require.True(t, ok)
+	require.Equal(t, []string{
+		`ALTER TABLE "test"."t1" SET SCHEMA "test2"`,
+		`ALTER TABLE "test2"."t1" RENAME TO "t2"`,
+	}, stmts)
+
+	_, ok = TranslateDDL(&model.DDLEvent{
+		Type: timodel.ActionModifyColumn, TableInfo: post, PreTableInfo: pre,
+	})
+	require.False(t, ok)
+}
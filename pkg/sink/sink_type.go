@@ -76,12 +76,21 @@ const (
 	PulsarHTTPScheme = "pulsar+http"
 	// PulsarHTTPSScheme indicates the schema is pulsar with https protocol
 	PulsarHTTPSScheme = "pulsar+https"
+	// PostgresScheme indicates the scheme is PostgreSQL.
+	PostgresScheme = "postgres"
+	// PostgreSQLScheme is an alias for "postgres".
+	PostgreSQLScheme = "postgresql"
+	// HTTPScheme indicates the scheme is a webhook endpoint over http.
+	HTTPScheme = "http"
+	// HTTPSScheme indicates the scheme is a webhook endpoint over https.
+	HTTPSScheme = "https"
 )
 
 // IsMQScheme returns true if the scheme belong to mq scheme.
+// The webhook sink shares the MQ sink pipeline, so it is treated as MQ.
 func IsMQScheme(scheme string) bool {
 	return scheme == KafkaScheme || scheme == KafkaSSLScheme ||
-		scheme == PulsarScheme || scheme == PulsarSSLScheme
+		scheme == PulsarScheme || scheme == PulsarSSLScheme || IsHTTPScheme(scheme)
 }
 
 // IsMySQLCompatibleScheme returns true if the scheme is compatible with MySQL.
@@ -90,6 +99,11 @@ func IsMySQLCompatibleScheme(scheme string) bool {
 		scheme == TiDBScheme || scheme == TiDBSSLScheme
 }
 
+// IsPostgresScheme returns true if the scheme belong to PostgreSQL scheme.
+func IsPostgresScheme(scheme string) bool {
+	return scheme == PostgresScheme || scheme == PostgreSQLScheme
+}
+
 // IsStorageScheme returns true if the scheme belong to storage scheme.
 func IsStorageScheme(scheme string) bool {
 	return scheme == FileScheme || scheme == S3Scheme || scheme == GCSScheme ||
@@ -101,6 +115,11 @@ func IsPulsarScheme(scheme string) bool {
 	return scheme == PulsarScheme || scheme == PulsarSSLScheme || scheme == PulsarHTTPScheme || scheme == PulsarHTTPSScheme
 }
 
+// IsHTTPScheme returns true if the scheme belong to webhook scheme.
+func IsHTTPScheme(scheme string) bool {
+	return scheme == HTTPScheme || scheme == HTTPSScheme
+}
+
 // IsBlackHoleScheme returns true if the scheme belong to blackhole scheme.
 func IsBlackHoleScheme(scheme string) bool {
 	return scheme == BlackHoleScheme
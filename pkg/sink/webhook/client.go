@@ -0,0 +1,180 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderChangefeed is the header carrying the changefeed ID.
+	HeaderChangefeed = "X-Ticdc-Changefeed"
+	// HeaderCommitTs is the header carrying the max commit ts of the batch.
+	HeaderCommitTs = "X-Ticdc-Commit-Ts"
+	// HeaderBatchSeq is the header carrying the sequence number of the batch.
+	// The sequence is increased for each batch sent by one client and is not
+	// persisted, so it restarts from 1 after the changefeed is restarted.
+	HeaderBatchSeq = "X-Ticdc-Batch-Seq"
+	// HeaderIdempotencyKey is the header carrying a digest of the changefeed,
+	// the path and the payload. A batch that is retried, or re-sent after the
+	// changefeed is restarted, keeps the same key.
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	contentType = "application/x-ndjson"
+)
+
+// Client sends batches of encoded messages to the webhook endpoint.
+// The messages of a batch are sent as newline-delimited payloads in one
+// POST request.
+type Client struct {
+	id         model.ChangeFeedID
+	cfg        *Config
+	httpClient *http.Client
+	seq        atomic.Uint64
+}
+
+// NewClient creates a new Client.
+func NewClient(changefeedID model.ChangeFeedID, cfg *Config) *Client {
+	return &Client{
+		id:         changefeedID,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Send posts the messages to the given path with retries.
+func (c *Client) Send(ctx context.Context, path string, messages []*common.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var (
+		body     bytes.Buffer
+		commitTs uint64
+	)
+	for _, msg := range messages {
+		body.Write(msg.Value)
+		body.WriteByte('\n')
+		if msg.Ts > commitTs {
+			commitTs = msg.Ts
+		}
+	}
+	seq := c.seq.Add(1)
+	key := c.idempotencyKey(path, body.Bytes())
+
+	err := retry.Do(ctx, func() error {
+		return c.post(ctx, path, body.Bytes(), commitTs, seq, key)
+	}, retry.WithBackoffBaseDelay(BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(uint64(c.cfg.MaxRetries)+1),
+		retry.WithIsRetryableErr(isRetryableError))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrWebhookSendMessage, err)
+	}
+	return nil
+}
+
+func (c *Client) post(
+	ctx context.Context, path string, body []byte, commitTs, seq uint64, key string,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.cfg.URLForPath(path), bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(HeaderChangefeed, c.id.String())
+	req.Header.Set(HeaderCommitTs, strconv.FormatUint(commitTs, 10))
+	req.Header.Set(HeaderBatchSeq, strconv.FormatUint(seq, 10))
+	req.Header.Set(HeaderIdempotencyKey, key)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Warn("Webhook request failed, retry later",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.String("path", path), zap.Uint64("seq", seq), zap.Error(err))
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	log.Warn("Webhook endpoint responded with error status",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.String("path", path), zap.Uint64("seq", seq),
+		zap.Int("status", resp.StatusCode))
+	return &statusError{code: resp.StatusCode}
+}
+
+// Close closes the idle connections of the client.
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+func (c *Client) idempotencyKey(path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.id.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// statusError is returned when the endpoint responds with a non-2xx status.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("webhook endpoint responded with status %d", e.code)
+}
+
+// isRetryableError returns false for client errors except timeouts and rate
+// limiting, they can't be fixed by retrying the same request.
+func isRetryableError(err error) bool {
+	if !cerror.IsRetryableError(err) {
+		return false
+	}
+	if e, ok := errors.Cause(err).(*statusError); ok {
+		switch {
+		case e.code == http.StatusRequestTimeout, e.code == http.StatusTooManyRequests:
+			return true
+		case e.code >= 400 && e.code < 500:
+			return false
+		}
+	}
+	return true
+}
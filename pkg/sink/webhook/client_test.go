@@ -0,0 +1,119 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+func newTestClient(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *Client {
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	sinkURI, err := url.Parse(server.URL + "/default?max-retries=2")
+	require.NoError(t, err)
+	cfg := NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI))
+	client := NewClient(model.DefaultChangeFeedID("test"), cfg)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClientSend(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests []request
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, request{path: r.URL.Path, header: r.Header, body: string(body)})
+		// The first request fails and must be retried with the same headers.
+		if len(requests) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	messages := []*common.Message{
+		{Value: []byte(`{"a":1}`), Ts: 10},
+		{Value: []byte(`{"a":2}`), Ts: 12},
+	}
+	require.NoError(t, client.Send(context.Background(), "test/t1", messages))
+	require.NoError(t, client.Send(context.Background(), "test/t1", messages[:1]))
+
+	require.Len(t, requests, 3)
+	require.Equal(t, requests[0].header, requests[1].header)
+	for _, r := range requests {
+		require.Equal(t, "/test/t1", r.path)
+		require.Equal(t, "default/test", r.header.Get(HeaderChangefeed))
+	}
+	require.Equal(t, "{\"a\":1}\n{\"a\":2}\n", requests[1].body)
+	require.Equal(t, "12", requests[1].header.Get(HeaderCommitTs))
+	require.Equal(t, "1", requests[1].header.Get(HeaderBatchSeq))
+	require.Equal(t, "{\"a\":1}\n", requests[2].body)
+	require.Equal(t, "10", requests[2].header.Get(HeaderCommitTs))
+	require.Equal(t, "2", requests[2].header.Get(HeaderBatchSeq))
+	require.NotEqual(t, requests[1].header.Get(HeaderIdempotencyKey),
+		requests[2].header.Get(HeaderIdempotencyKey))
+}
+
+func TestClientSendError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		status   int
+		attempts int
+	}{
+		{status: http.StatusInternalServerError, attempts: 3},
+		{status: http.StatusTooManyRequests, attempts: 3},
+		{status: http.StatusBadRequest, attempts: 1},
+	}
+	for _, tc := range testCases {
+		var (
+			mu       sync.Mutex
+			attempts int
+		)
+		status := tc.status
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			attempts++
+			mu.Unlock()
+			w.WriteHeader(status)
+		})
+		err := client.Send(context.Background(), "t",
+			[]*common.Message{{Value: []byte(`{}`), Ts: 1}})
+		require.ErrorContains(t, err, "ErrWebhookSendMessage")
+		require.Equal(t, tc.attempts, attempts)
+	}
+}
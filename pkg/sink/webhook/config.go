@@ -0,0 +1,164 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxBatchSize is the default max number of messages in one request.
+	DefaultMaxBatchSize = 256
+	// DefaultFlushInterval is the default interval to flush the buffered messages.
+	DefaultFlushInterval = 200 * time.Millisecond
+	// DefaultMaxRetries is the default max retry times of one request.
+	DefaultMaxRetries = 10
+	// DefaultTimeout is the default timeout of one request.
+	DefaultTimeout = 10 * time.Second
+
+	// The upper limit of max batch size.
+	maxMaxBatchSize = 4096
+
+	// BackoffBaseDelay indicates the base delay time for retrying.
+	BackoffBaseDelay = 500 * time.Millisecond
+	// BackoffMaxDelay indicates the max delay time for retrying.
+	BackoffMaxDelay = 30 * time.Second
+)
+
+type urlConfig struct {
+	MaxBatchSize  *int    `form:"max-batch-size"`
+	FlushInterval *string `form:"flush-interval"`
+	MaxRetries    *int    `form:"max-retries"`
+	Timeout       *string `form:"timeout"`
+}
+
+// Config is the configs for the webhook sink.
+type Config struct {
+	// Endpoint is the scheme, user info and host of the sink URI.
+	// The path of each request is derived from the dispatch rules.
+	Endpoint      *url.URL
+	MaxBatchSize  int
+	FlushInterval time.Duration
+	MaxRetries    int
+	Timeout       time.Duration
+}
+
+// NewConfig returns the default webhook sink config.
+func NewConfig() *Config {
+	return &Config{
+		MaxBatchSize:  DefaultMaxBatchSize,
+		FlushInterval: DefaultFlushInterval,
+		MaxRetries:    DefaultMaxRetries,
+		Timeout:       DefaultTimeout,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL) error {
+	if sinkURI == nil {
+		return cerror.ErrWebhookInvalidConfig.GenWithStack("fail to open webhook sink, empty SinkURI")
+	}
+	scheme := sink.GetScheme(sinkURI)
+	if !sink.IsHTTPScheme(scheme) {
+		return cerror.ErrWebhookInvalidConfig.GenWithStack(
+			"can't create webhook sink with unsupported scheme: %s", scheme)
+	}
+	if sinkURI.Host == "" {
+		return cerror.ErrWebhookInvalidConfig.GenWithStack("no host is specified in sink-uri")
+	}
+
+	req := &http.Request{URL: sinkURI}
+	urlParameter := &urlConfig{}
+	if err := binding.Query.Bind(req, urlParameter); err != nil {
+		return cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+
+	if urlParameter.MaxBatchSize != nil {
+		v := *urlParameter.MaxBatchSize
+		if v <= 0 {
+			return cerror.WrapError(cerror.ErrWebhookInvalidConfig,
+				fmt.Errorf("invalid max-batch-size %d, which must be greater than 0", v))
+		}
+		if v > maxMaxBatchSize {
+			log.Warn("max-batch-size too large",
+				zap.Int("original", v), zap.Int("override", maxMaxBatchSize))
+			v = maxMaxBatchSize
+		}
+		c.MaxBatchSize = v
+	}
+	if urlParameter.FlushInterval != nil {
+		v, err := parsePositiveDuration("flush-interval", *urlParameter.FlushInterval)
+		if err != nil {
+			return err
+		}
+		c.FlushInterval = v
+	}
+	if urlParameter.MaxRetries != nil {
+		v := *urlParameter.MaxRetries
+		if v < 0 {
+			return cerror.WrapError(cerror.ErrWebhookInvalidConfig,
+				fmt.Errorf("invalid max-retries %d, which must not be negative", v))
+		}
+		c.MaxRetries = v
+	}
+	if urlParameter.Timeout != nil {
+		v, err := parsePositiveDuration("timeout", *urlParameter.Timeout)
+		if err != nil {
+			return err
+		}
+		c.Timeout = v
+	}
+
+	c.Endpoint = &url.URL{
+		Scheme: scheme,
+		User:   sinkURI.User,
+		Host:   sinkURI.Host,
+	}
+	return nil
+}
+
+// URLForPath returns the request URL for the given path.
+func (c *Config) URLForPath(path string) string {
+	u := *c.Endpoint
+	u.Path = "/" + strings.TrimLeft(path, "/")
+	return u.String()
+}
+
+// GetDefaultPath returns the default request path of the sink URI, it is used
+// when an event does not match any dispatch rule.
+func GetDefaultPath(sinkURI *url.URL) string {
+	return strings.Trim(sinkURI.Path, "/")
+}
+
+func parsePositiveDuration(name, s string) (time.Duration, error) {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+	if v <= 0 {
+		return 0, cerror.WrapError(cerror.ErrWebhookInvalidConfig,
+			fmt.Errorf("invalid %s %s, which must be greater than 0", name, s))
+	}
+	return v, nil
+}
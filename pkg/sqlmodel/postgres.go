@@ -0,0 +1,213 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/quotes"
+)
+
+// postgresPlaceholders keeps track of the `$n` placeholders used by PostgreSQL.
+type postgresPlaceholders struct {
+	n int
+}
+
+func (p *postgresPlaceholders) next() string {
+	p.n++
+	return "$" + strconv.Itoa(p.n)
+}
+
+func postgresTargetTable(r *RowChange) string {
+	return quotes.QuotePostgresSchema(r.targetTable.Schema, r.targetTable.Table)
+}
+
+// genPostgresWhere generates WHERE clause for DELETE to identify the row.
+// the SQL part is written to `buf` and the args part is returned.
+func (r *RowChange) genPostgresWhere(buf *strings.Builder, holders *postgresPlaceholders) []interface{} {
+	whereColumns, whereValues := r.whereColumnsAndValues()
+
+	args := make([]interface{}, 0, len(whereValues))
+	for i, col := range whereColumns {
+		if i != 0 {
+			buf.WriteString(" AND ")
+		}
+		buf.WriteString(quotes.QuotePostgresName(col))
+		if whereValues[i] == nil {
+			// PostgreSQL doesn't accept a placeholder after IS.
+			buf.WriteString(" IS NULL")
+			continue
+		}
+		buf.WriteString(" = ")
+		buf.WriteString(holders.next())
+		args = append(args, whereValues[i])
+	}
+	return args
+}
+
+// GenPostgresDeleteSQL generates the PostgreSQL DELETE SQL and its arguments.
+// Input `changes` should have same target table and same columns for WHERE
+// (typically same PK/NOT NULL UK), otherwise the behaviour is undefined.
+// A change without a unique WHERE (see RowChange.HasUniqueWhere) deletes only
+// one of the matching rows by its ctid, so at most one such change can be
+// passed in one call, otherwise duplicated rows are deleted only once.
+func GenPostgresDeleteSQL(changes ...*RowChange) (string, []interface{}) {
+	if len(changes) == 0 {
+		log.L().DPanic("row changes is empty")
+		return "", nil
+	}
+
+	first := changes[0]
+	target := postgresTargetTable(first)
+
+	var buf strings.Builder
+	buf.Grow(1024)
+	buf.WriteString("DELETE FROM ")
+	buf.WriteString(target)
+	buf.WriteString(" WHERE (")
+
+	holders := &postgresPlaceholders{}
+	allArgs := make([]interface{}, 0, len(changes)*CommonIndexColumnsCount)
+	for i, c := range changes {
+		if i > 0 {
+			buf.WriteString(") OR (")
+		}
+		if c.HasUniqueWhere() {
+			allArgs = append(allArgs, c.genPostgresWhere(&buf, holders)...)
+			continue
+		}
+		// The table has no key to identify the row, delete only one of the
+		// duplicated rows like MySQL `DELETE ... LIMIT 1` does.
+		buf.WriteString("ctid = (SELECT ctid FROM ")
+		buf.WriteString(target)
+		buf.WriteString(" WHERE ")
+		allArgs = append(allArgs, c.genPostgresWhere(&buf, holders)...)
+		buf.WriteString(" LIMIT 1)")
+	}
+	buf.WriteString(")")
+	return buf.String(), allArgs
+}
+
+// GenPostgresUpsertSQL generates the PostgreSQL INSERT SQL and its arguments.
+// If the target table has a PK or a NOT NULL unique key, the SQL is suffixed
+// with `ON CONFLICT (...) DO UPDATE SET ...`, so it can be replayed safely
+// like a MySQL REPLACE. Otherwise a plain INSERT is generated.
+// Input `changes` should have same target table and same modifiable columns,
+// otherwise the behaviour is undefined.
+func GenPostgresUpsertSQL(changes ...*RowChange) (string, []interface{}) {
+	return genPostgresInsertSQL(true, changes...)
+}
+
+// GenPostgresInsertSQL generates the PostgreSQL INSERT SQL without any
+// conflict handling and its arguments.
+func GenPostgresInsertSQL(changes ...*RowChange) (string, []interface{}) {
+	return genPostgresInsertSQL(false, changes...)
+}
+
+func genPostgresInsertSQL(upsert bool, changes ...*RowChange) (string, []interface{}) {
+	if len(changes) == 0 {
+		log.L().DPanic("row changes is empty")
+		return "", nil
+	}
+
+	first := changes[0]
+
+	var buf strings.Builder
+	buf.Grow(1024)
+	buf.WriteString("INSERT INTO ")
+	buf.WriteString(postgresTargetTable(first))
+	buf.WriteString(" (")
+
+	generatedColumns := generatedColumnsNameSet(first.targetTableInfo.Columns)
+	columnNames := make([]string, 0, len(first.sourceTableInfo.Columns))
+	var skipColIdx []int
+	for i, col := range first.sourceTableInfo.Columns {
+		if _, ok := generatedColumns[col.Name.L]; ok {
+			skipColIdx = append(skipColIdx, i)
+			continue
+		}
+		if len(columnNames) != 0 {
+			buf.WriteByte(',')
+		}
+		name := quotes.QuotePostgresName(col.Name.O)
+		columnNames = append(columnNames, name)
+		buf.WriteString(name)
+	}
+	buf.WriteString(") VALUES ")
+
+	holders := &postgresPlaceholders{}
+	args := make([]interface{}, 0, len(changes)*len(columnNames))
+	for i, change := range changes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('(')
+		skip := 0 // used as index of skipColIdx
+		written := 0
+		for j, val := range change.postValues {
+			if skip < len(skipColIdx) && skipColIdx[skip] == j {
+				skip++
+				continue
+			}
+			if written > 0 {
+				buf.WriteByte(',')
+			}
+			written++
+			buf.WriteString(holders.next())
+			args = append(args, val)
+		}
+		buf.WriteByte(')')
+	}
+
+	if !upsert {
+		return buf.String(), args
+	}
+	idx := first.UniqueNotNullIdx()
+	if idx == nil {
+		return buf.String(), args
+	}
+
+	buf.WriteString(" ON CONFLICT (")
+	conflictCols := make(map[string]struct{}, len(idx.Columns))
+	for i, col := range idx.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name := quotes.QuotePostgresName(col.Name.O)
+		conflictCols[name] = struct{}{}
+		buf.WriteString(name)
+	}
+	buf.WriteString(") DO ")
+
+	writtenFirstCol := false
+	for _, name := range columnNames {
+		if _, ok := conflictCols[name]; ok {
+			continue
+		}
+		if !writtenFirstCol {
+			buf.WriteString("UPDATE SET ")
+		} else {
+			buf.WriteByte(',')
+		}
+		writtenFirstCol = true
+		buf.WriteString(name + "=EXCLUDED." + name)
+	}
+	if !writtenFirstCol {
+		// all columns are part of the conflict target, nothing to update.
+		buf.WriteString("NOTHING")
+	}
+	return buf.String(), args
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"testing"
+
+	cdcmodel "github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenPostgresDeleteSQL(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb"}
+	ti := mockTableInfo(t, "CREATE TABLE tb (c INT, c2 INT, c3 INT, UNIQUE KEY (c, c2))")
+
+	change1 := NewRowChange(source, nil, []interface{}{1, 2, 3}, nil, ti, nil, nil)
+	change2 := NewRowChange(source, nil, []interface{}{4, nil, 6}, nil, ti, nil, nil)
+	sql, args := GenPostgresDeleteSQL(change1, change2)
+
+	require.Equal(t, `DELETE FROM "db"."tb" WHERE ("c" = $1 AND "c2" = $2) OR `+
+		`(ctid = (SELECT ctid FROM "db"."tb" WHERE "c" = $3 AND "c2" IS NULL AND "c3" = $4 LIMIT 1))`, sql)
+	require.Equal(t, []interface{}{1, 2, 4, 6}, args)
+	require.True(t, change1.HasUniqueWhere())
+	require.False(t, change2.HasUniqueWhere())
+
+	ti = mockTableInfo(t, "CREATE TABLE tb (c INT, c2 INT)")
+	change := NewRowChange(source, nil, []interface{}{1, 2}, nil, ti, nil, nil)
+	sql, args = GenPostgresDeleteSQL(change)
+	require.Equal(t, `DELETE FROM "db"."tb" WHERE `+
+		`(ctid = (SELECT ctid FROM "db"."tb" WHERE "c" = $1 AND "c2" = $2 LIMIT 1))`, sql)
+	require.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestGenPostgresUpsertSQL(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb"}
+	ti := mockTableInfo(t, "CREATE TABLE tb (id INT PRIMARY KEY, name VARCHAR(10), "+
+		"gen INT AS (id + 1) VIRTUAL)")
+
+	change1 := NewRowChange(source, nil, nil, []interface{}{1, "a", 2}, ti, nil, nil)
+	change2 := NewRowChange(source, nil, nil, []interface{}{2, "b", 3}, ti, nil, nil)
+
+	sql, args := GenPostgresUpsertSQL(change1, change2)
+	require.Equal(t, `INSERT INTO "db"."tb" ("id","name") VALUES ($1,$2),($3,$4) `+
+		`ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name"`, sql)
+	require.Equal(t, []interface{}{1, "a", 2, "b"}, args)
+
+	sql, args = GenPostgresInsertSQL(change1)
+	require.Equal(t, `INSERT INTO "db"."tb" ("id","name") VALUES ($1,$2)`, sql)
+	require.Equal(t, []interface{}{1, "a"}, args)
+}
+
+func TestGenPostgresUpsertSQLWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb"}
+	ti := mockTableInfo(t, "CREATE TABLE tb (c INT, c2 INT)")
+	change := NewRowChange(source, nil, nil, []interface{}{1, 2}, ti, nil, nil)
+	sql, args := GenPostgresUpsertSQL(change)
+	require.Equal(t, `INSERT INTO "db"."tb" ("c","c2") VALUES ($1,$2)`, sql)
+	require.Equal(t, []interface{}{1, 2}, args)
+
+	ti = mockTableInfo(t, "CREATE TABLE tb (c INT, c2 INT, PRIMARY KEY (c, c2))")
+	change = NewRowChange(source, nil, nil, []interface{}{1, 2}, ti, nil, nil)
+	sql, _ = GenPostgresUpsertSQL(change)
+	require.Equal(t, `INSERT INTO "db"."tb" ("c","c2") VALUES ($1,$2) `+
+		`ON CONFLICT ("c","c2") DO NOTHING`, sql)
+}
@@ -200,6 +200,14 @@ func (r *RowChange) UniqueNotNullIdx() *timodel.IndexInfo {
 	return r.whereHandle.UniqueNotNullIdx
 }
 
+// HasUniqueWhere returns whether the row is identified by the PK or a unique
+// key whose columns are all not NULL in the old values. Otherwise all columns
+// are used to identify the row, which may match duplicated rows.
+func (r *RowChange) HasUniqueWhere() bool {
+	r.lazyInitWhereHandle()
+	return r.whereHandle.getWhereIdxByData(r.preValues) != nil
+}
+
 // SetWhereHandle can be used when caller has cached whereHandle, to avoid every
 // RowChange lazily initialize it.
 func (r *RowChange) SetWhereHandle(whereHandle *WhereHandle) {
@@ -33,8 +33,18 @@ const (
 	EnsureGCServiceResuming = "-resuming-"
 	// EnsureGCServiceInitializing is a tag of GC service id for changefeed initialization
 	EnsureGCServiceInitializing = "-initializing-"
+	// EnsureGCServiceExporting is a tag of GC service id for changefeed exportation
+	EnsureGCServiceExporting = "-exporting-"
 )
 
+// EnsureChangefeedServiceID returns the GC service id that is used to keep
+// the start ts of a changefeed safe.
+func EnsureChangefeedServiceID(
+	gcServiceIDPrefix string, changefeedID model.ChangeFeedID,
+) string {
+	return gcServiceIDPrefix + changefeedID.Namespace + "_" + changefeedID.ID
+}
+
 // EnsureChangefeedStartTsSafety checks if the startTs less than the minimum of
 // service GC safepoint and this function will update the service GC to startTs
 func EnsureChangefeedStartTsSafety(
@@ -45,7 +55,7 @@ func EnsureChangefeedStartTsSafety(
 ) error {
 	minServiceGCTs, err := SetServiceGCSafepoint(
 		ctx, pdCli,
-		gcServiceIDPrefix+changefeedID.Namespace+"_"+changefeedID.ID,
+		EnsureChangefeedServiceID(gcServiceIDPrefix, changefeedID),
 		TTL, startTs)
 	if err != nil {
 		return errors.Trace(err)
@@ -59,6 +69,28 @@ func EnsureChangefeedStartTsSafety(
 	return nil
 }
 
+// EnsureChangefeedResumeSafety checks if the checkpoint of a changefeed to be
+// resumed is still safe from GC, and holds a service GC safepoint at the
+// checkpoint until the changefeed is initialized.
+func EnsureChangefeedResumeSafety(
+	ctx context.Context, pdCli pd.Client,
+	gcServiceIDPrefix string,
+	changefeedID model.ChangeFeedID,
+	checkpointTs uint64,
+) error {
+	// 1h is enough for resuming a changefeed.
+	gcTTL := int64(60 * 60)
+	err := EnsureChangefeedStartTsSafety(
+		ctx, pdCli, gcServiceIDPrefix, changefeedID, gcTTL, checkpointTs)
+	if err != nil {
+		if !cerrors.ErrStartTsBeforeGC.Equal(err) {
+			return cerrors.ErrPDEtcdAPIError.Wrap(err)
+		}
+		return err
+	}
+	return nil
+}
+
 // UndoEnsureChangefeedStartTsSafety cleans the service GC safepoint of a changefeed
 // if something goes wrong after successfully calling EnsureChangefeedStartTsSafety().
 func UndoEnsureChangefeedStartTsSafety(
@@ -69,7 +101,7 @@ func UndoEnsureChangefeedStartTsSafety(
 	err := RemoveServiceGCSafepoint(
 		ctx,
 		pdCli,
-		gcServiceIDPrefix+changefeedID.Namespace+"_"+changefeedID.ID)
+		EnsureChangefeedServiceID(gcServiceIDPrefix, changefeedID))
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -95,22 +95,41 @@ var sensitiveQueryParameterNames = []string{
 	"client",
 }
 
+// IsSensitiveQueryParameter returns true if the query parameter of an uri
+// may carry sensitive infos.
+func IsSensitiveQueryParameter(name string) bool {
+	lower := strings.ToLower(name)
+	for _, secretKey := range sensitiveQueryParameterNames {
+		if strings.Contains(lower, secretKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // MaskSensitiveDataInURI returns an uri that sensitive infos has been masked.
 func MaskSensitiveDataInURI(uri string) string {
+	masked, err := MaskSinkURICredentials(uri)
+	if err != nil {
+		return ""
+	}
+	return masked
+}
+
+// MaskSinkURICredentials returns a sink uri whose password and all sensitive
+// query parameters are masked.
+func MaskSinkURICredentials(uri string) (string, error) {
 	uriParsed, err := url.Parse(uri)
 	if err != nil {
 		log.Error("failed to parse sink URI", zap.Error(err))
-		return ""
+		return "", err
 	}
 	queries := uriParsed.Query()
 	for key := range queries {
-		lower := strings.ToLower(key)
-		for _, secretKey := range sensitiveQueryParameterNames {
-			if strings.Contains(lower, secretKey) {
-				queries.Set(key, "xxxxx")
-			}
+		if IsSensitiveQueryParameter(key) {
+			queries.Set(key, "xxxxx")
 		}
 	}
 	uriParsed.RawQuery = queries.Encode()
-	return uriParsed.Redacted()
+	return uriParsed.Redacted(), nil
 }